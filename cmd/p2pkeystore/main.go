@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	p2pCrypto "github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p/crypto"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p/crypto/keystore"
+	logger "github.com/TerraDharitri/drt-go-chain-logger"
+	libp2pCrypto "github.com/libp2p/go-libp2p/core/crypto"
+)
+
+const (
+	pemFormat  = "pem"
+	jsonFormat = "json"
+	hexFormat  = "hex"
+
+	passphraseEnvVariable = "P2P_KEYSTORE_PASSPHRASE"
+)
+
+const usage = `p2pkeystore manages the P2P identity keys
+
+Usage:
+  p2pkeystore generate [-key-type secp256k1|ed25519] [-format pem|json] [-kdf scrypt|argon2id] [-out file]
+  p2pkeystore import -in file [-format pem|json] [-kdf scrypt|argon2id] [-out file]
+  p2pkeystore export -in file [-format pem|json|hex] [-kdf scrypt|argon2id] [-out file]
+  p2pkeystore inspect -in file
+
+The import command accepts the raw private key bytes, hex encoded or not, as well as the PEM and JSON key files.
+The passphrase of the JSON key files is read from the file provided with -passphrase-file or, if not set, from the
+` + passphraseEnvVariable + ` environment variable. Existing output files are never overwritten.
+`
+
+var log = logger.GetOrCreate("p2pkeystore")
+
+type commandArgs struct {
+	keyType        string
+	format         string
+	kdf            string
+	in             string
+	out            string
+	passphraseFile string
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Print(usage)
+		os.Exit(1)
+	}
+
+	err := run(os.Args[1], os.Args[2:])
+	if err != nil {
+		log.Error("p2pkeystore", "command", os.Args[1], "error", err.Error())
+		os.Exit(1)
+	}
+}
+
+func run(command string, arguments []string) error {
+	args := commandArgs{}
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.StringVar(&args.keyType, "key-type", string(p2p.Secp256k1KeyType), "the type of the generated key")
+	flags.StringVar(&args.format, "format", pemFormat, "the output format")
+	flags.StringVar(&args.kdf, "kdf", string(keystore.ScryptKDF), "the key derivation function of the JSON key files")
+	flags.StringVar(&args.in, "in", "", "the input key file")
+	flags.StringVar(&args.out, "out", "", "the output key file, the standard output being used if not set")
+	flags.StringVar(&args.passphraseFile, "passphrase-file", "", "the file holding the passphrase of the JSON key files")
+	err := flags.Parse(arguments)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "generate":
+		return generate(args)
+	case "import":
+		return importKey(args)
+	case "export":
+		return exportKey(args)
+	case "inspect":
+		return inspect(args)
+	default:
+		fmt.Print(usage)
+		return fmt.Errorf("unknown command %s", command)
+	}
+}
+
+func generate(args commandArgs) error {
+	generator, err := p2pCrypto.NewIdentityGeneratorWithKeyType(log, p2p.KeyType(args.keyType))
+	if err != nil {
+		return err
+	}
+
+	sk, err := generator.CreateP2PPrivateKey(nil)
+	if err != nil {
+		return err
+	}
+
+	return writeKey(sk, args)
+}
+
+func importKey(args commandArgs) error {
+	data, err := readInput(args)
+	if err != nil {
+		return err
+	}
+
+	sk, err := loadKey(data, args)
+	if errors.Is(err, keystore.ErrUnknownKeyFileFormat) {
+		sk, err = p2pCrypto.UnmarshalRawLibp2pPrivateKey(decodeRawKey(data))
+	}
+	if err != nil {
+		return err
+	}
+
+	return writeKey(sk, args)
+}
+
+func exportKey(args commandArgs) error {
+	data, err := readInput(args)
+	if err != nil {
+		return err
+	}
+
+	sk, err := loadKey(data, args)
+	if err != nil {
+		return err
+	}
+
+	return writeKey(sk, args)
+}
+
+func inspect(args commandArgs) error {
+	data, err := readInput(args)
+	if err != nil {
+		return err
+	}
+
+	info, err := keystore.InspectKey(data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("key type: %s\npeer ID: %s\n", info.KeyType, info.PeerID.Pretty())
+
+	return nil
+}
+
+func readInput(args commandArgs) ([]byte, error) {
+	if len(args.in) == 0 {
+		return nil, errors.New("the -in flag is mandatory")
+	}
+
+	return os.ReadFile(args.in)
+}
+
+func loadKey(data []byte, args commandArgs) (libp2pCrypto.PrivKey, error) {
+	passphrase, err := readPassphrase(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return keystore.LoadKey(data, passphrase)
+}
+
+// decodeRawKey returns the raw private key bytes, decoding them first if they are hex encoded
+func decodeRawKey(data []byte) []byte {
+	decoded, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return data
+	}
+
+	return decoded
+}
+
+func readPassphrase(args commandArgs) ([]byte, error) {
+	if len(args.passphraseFile) == 0 {
+		return []byte(os.Getenv(passphraseEnvVariable)), nil
+	}
+
+	passphrase, err := os.ReadFile(args.passphraseFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimRight(passphrase, "\r\n"), nil
+}
+
+func writeKey(sk libp2pCrypto.PrivKey, args commandArgs) error {
+	info, err := keystore.GetKeyInfo(sk)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	switch args.format {
+	case pemFormat:
+		data, err = keystore.EncodePEM(sk)
+	case jsonFormat:
+		var passphrase []byte
+		passphrase, err = readPassphrase(args)
+		if err != nil {
+			return err
+		}
+		data, err = keystore.EncryptKey(sk, passphrase, keystore.DefaultEncryptionParams(keystore.KDF(args.kdf)))
+		data = append(data, '\n')
+	case hexFormat:
+		var skBytes []byte
+		skBytes, err = sk.Raw()
+		data = []byte(hex.EncodeToString(skBytes) + "\n")
+	default:
+		return fmt.Errorf("unknown format %s", args.format)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(args.out) == 0 {
+		fmt.Print(string(data))
+	} else {
+		err = keystore.WriteKeyFile(args.out, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "key type: %s\npeer ID: %s\n", info.KeyType, info.PeerID.Pretty())
+
+	return nil
+}
@@ -20,6 +20,7 @@ require (
 	github.com/multiformats/go-multiaddr v0.9.0
 	github.com/stretchr/testify v1.10.0
 	github.com/whyrusleeping/timecache v0.0.0-20160911033111-cfcb2f1abfee
+	golang.org/x/crypto v0.35.0
 )
 
 require (
@@ -112,7 +113,6 @@ require (
 	go.uber.org/fx v1.23.0 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
 	go.uber.org/zap v1.27.0 // indirect
-	golang.org/x/crypto v0.35.0 // indirect
 	golang.org/x/exp v0.0.0-20230321023759-10a507213a29 // indirect
 	golang.org/x/mod v0.23.0 // indirect
 	golang.org/x/net v0.35.0 // indirect
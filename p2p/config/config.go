@@ -5,6 +5,10 @@ type P2PConfig struct {
 	Node                NodeConfig
 	KadDhtPeerDiscovery KadDhtPeerDiscoveryConfig
 	Sharding            ShardingConfig
+	PubSub              PubSubConfig
+	PeerAuthentication  PeerAuthenticationConfig
+	NetworkIdentity     NetworkIdentityConfig
+	DirectMessages      DirectMessagesConfig
 }
 
 // NodeConfig will hold basic p2p settings
@@ -15,6 +19,7 @@ type NodeConfig struct {
 	MinNumPeersToWaitForOnBootstrap uint32
 	Transports                      TransportConfig
 	ResourceLimiter                 ResourceLimiterConfig
+	ConnectionGater                 ConnectionGaterConfig
 }
 
 // TransportConfig specifies the supported protocols by the node
@@ -38,6 +43,25 @@ type ResourceLimiterConfig struct {
 	ManualMaximumFD        int
 }
 
+// ConnectionGaterConfig will hold the settings of the connection gater that filters the connections by IP address.
+// The limits are applied on the inbound connections, the deny list also on the outbound ones
+type ConnectionGaterConfig struct {
+	// AllowList holds the CIDRs of the addresses that are never gated
+	AllowList []string
+	// DenyList holds the CIDRs of the addresses whose connections are always refused
+	DenyList []string
+	// MaxConnectionsPerIP is the maximum number of concurrent inbound connections, including the ones being
+	// established, with an IP address. 0 means no limit
+	MaxConnectionsPerIP int
+	// MaxConnectionsPerSubnet is the maximum number of concurrent inbound connections, including the ones being
+	// established, with the addresses of the same /24 IPv4 or /48 IPv6 subnet. 0 means no limit
+	MaxConnectionsPerSubnet int
+	// MaxInboundAttemptsPerIP is the maximum number of inbound connection attempts accepted from an IP address in
+	// an interval. 0 means no limit
+	MaxInboundAttemptsPerIP      int
+	InboundAttemptsIntervalInSec uint32
+}
+
 // KadDhtPeerDiscoveryConfig will hold the kad-dht discovery config settings
 type KadDhtPeerDiscoveryConfig struct {
 	Enabled                          bool
@@ -59,3 +83,176 @@ type ShardingConfig struct {
 	MaxSeeders              uint32
 	Type                    string
 }
+
+// PubSubConfig will hold the gossipsub settings
+type PubSubConfig struct {
+	Router           RouterConfig
+	PeerScoring      PeerScoringConfig
+	TopicValidators  []TopicValidatorConfig
+	Subscriptions    SubscriptionsConfig
+	OutgoingChannels OutgoingChannelsConfig
+	Batching         []TopicBatchingConfig
+	// BatchedMessagesEnabled is the network-wide activation flag of the batched messages and should be set on all
+	// the nodes at the same time, as the pubsub relays forward the batches to all their peers. When set, the node
+	// accepts batched messages and rejects the peers that do not advertise them in the network identity handshake,
+	// which must be enabled, or that do not complete it in time. When not set, the node neither sends nor accepts
+	// batched messages
+	BatchedMessagesEnabled bool
+	// AttestationEnabled is the network-wide activation flag of the attestation attached to the broadcast messages
+	// and should be set on all the nodes at the same time, as the peers not supporting the attestation fields
+	// blacklist both the originator and the relayer of such messages. When set, the node accepts the attested
+	// messages and rejects the peers that do not advertise the attestation in the network identity handshake, which
+	// must be enabled, or that do not complete it in time. When not set, the node neither attaches nor accepts the
+	// attestation
+	AttestationEnabled bool
+}
+
+// RouterConfig will hold the pubsub router settings. Each messenger instance applies its own settings, so the
+// main and the full archive networks can be tuned independently. The zero values keep the library defaults
+type RouterConfig struct {
+	Type                 string
+	RandomSubSize        int
+	SeenMessagesTTLInSec uint32
+	GossipSub            GossipSubConfig
+}
+
+// GossipSubConfig will hold the gossipsub router parameters
+type GossipSubConfig struct {
+	D                     int
+	Dlo                   int
+	Dhi                   int
+	Dscore                int
+	Dout                  int
+	Dlazy                 int
+	HistoryLength         int
+	HistoryGossip         int
+	GossipFactor          float64
+	HeartbeatIntervalInMs uint32
+	FanoutTTLInSec        uint32
+}
+
+// PeerScoringConfig will hold the gossipsub peer scoring settings. A message rejected by any of the registered
+// message processors counts as an invalid message delivery for the peer that sent it
+type PeerScoringConfig struct {
+	Enabled                     bool
+	GossipThreshold             float64
+	PublishThreshold            float64
+	GraylistThreshold           float64
+	AcceptPXThreshold           float64
+	OpportunisticGraftThreshold float64
+	TopicScoreCap               float64
+	IPColocationFactorWeight    float64
+	IPColocationFactorThreshold int
+	IPColocationFactorWhitelist []string
+	BehaviourPenaltyWeight      float64
+	BehaviourPenaltyThreshold   float64
+	BehaviourPenaltyDecay       float64
+	DecayIntervalInSec          uint32
+	DecayToZero                 float64
+	RetainScoreInSec            uint32
+	InspectIntervalInSec        uint32
+	Topics                      []TopicScoringConfig
+}
+
+// TopicScoringConfig will hold the gossipsub peer scoring settings of a topic
+type TopicScoringConfig struct {
+	Topic                          string
+	TopicWeight                    float64
+	TimeInMeshWeight               float64
+	TimeInMeshQuantumInSec         uint32
+	TimeInMeshCap                  float64
+	FirstMessageDeliveriesWeight   float64
+	FirstMessageDeliveriesDecay    float64
+	FirstMessageDeliveriesCap      float64
+	InvalidMessageDeliveriesWeight float64
+	InvalidMessageDeliveriesDecay  float64
+}
+
+// TopicValidatorConfig will hold the validation settings of a topic. The topics without settings use the library defaults
+type TopicValidatorConfig struct {
+	Topic string
+	// Concurrency is the maximum number of messages validated at the same time on the topic. 0 keeps the library default
+	Concurrency int
+	// TimeoutInMs is the time after which a validation is abandoned and the message ignored. 0 means no timeout.
+	// With the 'ignore' overload policy, an abandoned validation keeps its concurrency slot until the processors return
+	TimeoutInMs uint32
+	// Inline runs the validation on the pubsub validation worker instead of a new go routine
+	Inline bool
+	// OverloadPolicy decides what happens with the messages received while the topic is at full concurrency:
+	// 'drop' lets pubsub throttle them and 'ignore' marks them as ignored. If empty, 'drop' will be used
+	OverloadPolicy string
+}
+
+// SubscriptionsConfig will hold the settings of the channels returned by the Subscribe calls
+type SubscriptionsConfig struct {
+	// BufferSize is the number of messages each subscription channel can hold. 0 means the default size
+	BufferSize int
+	// DropPolicy selects the message dropped when a subscription channel is full: "drop-newest" (default) or "drop-oldest"
+	DropPolicy string
+}
+
+// OutgoingChannelsConfig will hold the scheduling settings of the channels used when broadcasting messages.
+// The channels without settings use the default queue size and drop policy, with priority 0 and weight 1
+type OutgoingChannelsConfig struct {
+	// DefaultQueueSize is the number of messages a channel can hold while waiting to be sent. 0 means the default size
+	DefaultQueueSize int
+	// DefaultDropPolicy is applied when a channel queue is full: "block" (default), "drop-newest" or "drop-oldest"
+	DefaultDropPolicy string
+	Channels          []OutgoingChannelConfig
+}
+
+// OutgoingChannelConfig will hold the scheduling settings of an outgoing channel
+type OutgoingChannelConfig struct {
+	Name string
+	// Priority defines the priority class of the channel. The messages of a channel are sent only when all the
+	// channels with a higher priority are empty
+	Priority uint32
+	// Weight is the share of the sending slots the channel gets among the channels with the same priority. 0 means 1
+	Weight     uint32
+	QueueSize  int
+	DropPolicy string
+}
+
+// TopicBatchingConfig will hold the batching settings of a topic. The small messages broadcast on the topic are
+// collected and published as a single message. The batching requires PubSubConfig.BatchedMessagesEnabled to be set
+type TopicBatchingConfig struct {
+	Topic string
+	// WindowInMs is the maximum time a message waits for other messages before the batch is published
+	WindowInMs uint32
+	// MaxSizeInBytes is the size after which the batch is published right away. 0 means the default size
+	MaxSizeInBytes int
+	// MaxNumMessages is the number of messages after which the batch is published right away. 0 means no limit
+	MaxNumMessages int
+}
+
+// PeerAuthenticationConfig will hold the settings of the protocol run by 2 peers after connecting, in which they
+// exchange signed statements about their role, shard and node version
+type PeerAuthenticationConfig struct {
+	Enabled bool
+	// TimeoutInSec is the maximum duration of an authentication. 0 means the default value
+	TimeoutInSec uint32
+	// MaxTimeDifferenceInSec is the maximum accepted difference between the statement's timestamp and the local
+	// time. 0 means the default value
+	MaxTimeDifferenceInSec uint32
+}
+
+// NetworkIdentityConfig will hold the settings of the handshake run by 2 peers right after connecting, in which they
+// check they belong to the same chain and speak compatible protocol versions
+type NetworkIdentityConfig struct {
+	Enabled bool
+	ChainID string
+	// MinProtocolVersion is the lowest handshake protocol version accepted from the peers. 0 means the current version
+	MinProtocolVersion uint32
+	// TimeoutInSec is the maximum duration of a handshake. 0 means the default value
+	TimeoutInSec uint32
+}
+
+// DirectMessagesConfig will hold the settings of the messages sent directly to a connected peer
+type DirectMessagesConfig struct {
+	// SignaturePolicy decides what happens with the unsigned direct messages: 'off' accepts them, 'warn' accepts them
+	// and logs a warning at most once a minute and 'enforce' rejects them. If empty, 'off' will be used. The messages
+	// with an invalid signature are always rejected
+	SignaturePolicy string
+	// BlacklistUnsignedPeers blacklists the peers that send unsigned direct messages while in the 'enforce' mode
+	BlacklistUnsignedPeers bool
+}
@@ -37,6 +37,58 @@ const (
 
 	// DefaultWithScaleResourceLimiter defines the default resource limiter that scales with the provided values
 	DefaultWithScaleResourceLimiter = "default with manual scale"
+
+	// GossipSubRouter defines the gossipsub pubsub router, used if no router type is provided
+	GossipSubRouter = "gossipsub"
+
+	// FloodSubRouter defines the floodsub pubsub router, that sends every message to all the peers on the topic.
+	// Should be used only on small private networks
+	FloodSubRouter = "floodsub"
+
+	// DropOnOverload defines the overload policy for which the messages that exceed the validation concurrency of a
+	// topic are dropped by pubsub as throttled
+	DropOnOverload = "drop"
+
+	// IgnoreOnOverload defines the overload policy for which the messages that exceed the validation concurrency of a
+	// topic are validated as ignored
+	IgnoreOnOverload = "ignore"
+
+	// RandomSubRouter defines the randomsub pubsub router, that sends every message to a random subset of peers.
+	// Should be used only on small private networks
+	RandomSubRouter = "randomsub"
+
+	// DropNewestPolicy defines the subscription drop policy for which the received message is dropped if the
+	// subscription channel is full
+	DropNewestPolicy = "drop-newest"
+
+	// DropOldestPolicy defines the subscription drop policy for which the oldest message from a full subscription
+	// channel is dropped to make room for the received one
+	DropOldestPolicy = "drop-oldest"
+
+	// BlockOnFullQueuePolicy defines the outgoing channel drop policy for which the broadcast calls wait until the
+	// channel queue has room for the message
+	BlockOnFullQueuePolicy = "block"
+
+	// DisabledSignaturePolicy defines the direct messages signature policy for which the unsigned messages are accepted
+	DisabledSignaturePolicy = "off"
+
+	// WarnSignaturePolicy defines the direct messages signature policy for which the unsigned messages are accepted
+	// and logged. Should be used while migrating the network to signed direct messages
+	WarnSignaturePolicy = "warn"
+
+	// EnforceSignaturePolicy defines the direct messages signature policy for which the unsigned messages are rejected
+	EnforceSignaturePolicy = "enforce"
+)
+
+// KeyType defines the signature algorithm of a p2p identity
+type KeyType string
+
+const (
+	// Secp256k1KeyType defines the secp256k1 identities, used if no key type is provided
+	Secp256k1KeyType KeyType = "secp256k1"
+
+	// Ed25519KeyType defines the ed25519 identities
+	Ed25519KeyType KeyType = "ed25519"
 )
 
 // BroadcastMethod defines the broadcast method of the message
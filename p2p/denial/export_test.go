@@ -0,0 +1,18 @@
+package denial
+
+import "time"
+
+// NumEntries -
+func (pde *peerDenialEvaluator) NumEntries() int {
+	pde.mut.RLock()
+	defer pde.mut.RUnlock()
+
+	return len(pde.peers) + len(pde.ips)
+}
+
+// SetTimeHandler -
+func (pde *peerDenialEvaluator) SetTimeHandler(handler func() time.Time) {
+	pde.mut.Lock()
+	pde.getTimeHandler = handler
+	pde.mut.Unlock()
+}
@@ -0,0 +1,493 @@
+package denial
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+	"github.com/TerraDharitri/drt-go-chain-core/core/check"
+)
+
+const (
+	noEscalationFactor    = 1
+	defaultMaxBanDuration = time.Hour * 24
+	persistenceFileMode   = 0600
+	unknownReason         = "unknown"
+	unknownSource         = "unknown"
+)
+
+// ArgsPeerDenialEvaluator is the DTO used to create a new peer denial evaluator
+type ArgsPeerDenialEvaluator struct {
+	Logger p2p.Logger
+	// EscalationFactor multiplies the ban duration for each repeated offense. 0 or 1 disables the escalation
+	EscalationFactor uint32
+	// MaxBanDuration caps the escalated ban durations, a longer requested duration being kept as it is. 0 means the
+	// default of 24 hours
+	MaxBanDuration time.Duration
+	// OffensesRetention is the time after the last offense when the offenses counter is reset. 0 means the
+	// offenses are forgotten as soon as the ban expires
+	OffensesRetention time.Duration
+	// PersistenceFilePath is the optional file used to keep the denial list across restarts
+	PersistenceFilePath string
+}
+
+// DenialInfo holds the information about a denied peer or IP address
+type DenialInfo struct {
+	Key          string    `json:"key"`
+	Reason       string    `json:"reason"`
+	Source       string    `json:"source"`
+	FirstOffense time.Time `json:"firstOffense"`
+	LastOffense  time.Time `json:"lastOffense"`
+	NumOffenses  uint32    `json:"numOffenses"`
+	BannedUntil  time.Time `json:"bannedUntil"`
+}
+
+type persistedDenials struct {
+	Peers []DenialInfo `json:"peers"`
+	IPs   []DenialInfo `json:"ips"`
+}
+
+type peerDenialEvaluator struct {
+	log                 p2p.Logger
+	escalationFactor    uint32
+	maxBanDuration      time.Duration
+	offensesRetention   time.Duration
+	persistenceFilePath string
+	mut                 sync.RWMutex
+	peers               map[core.PeerID]*DenialInfo
+	ips                 map[string]*DenialInfo
+	getTimeHandler      func() time.Time
+}
+
+// NewPeerDenialEvaluator creates a new peer denial evaluator backed by a time cache
+func NewPeerDenialEvaluator(args ArgsPeerDenialEvaluator) (*peerDenialEvaluator, error) {
+	err := checkArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	pde := &peerDenialEvaluator{
+		log:                 args.Logger,
+		escalationFactor:    args.EscalationFactor,
+		maxBanDuration:      args.MaxBanDuration,
+		offensesRetention:   args.OffensesRetention,
+		persistenceFilePath: args.PersistenceFilePath,
+		peers:               make(map[core.PeerID]*DenialInfo),
+		ips:                 make(map[string]*DenialInfo),
+		getTimeHandler:      time.Now,
+	}
+	if pde.escalationFactor == 0 {
+		pde.escalationFactor = noEscalationFactor
+	}
+	if pde.maxBanDuration == 0 {
+		pde.maxBanDuration = defaultMaxBanDuration
+	}
+
+	err = pde.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return pde, nil
+}
+
+func checkArgs(args ArgsPeerDenialEvaluator) error {
+	if check.IfNil(args.Logger) {
+		return p2p.ErrNilLogger
+	}
+	if args.MaxBanDuration < 0 {
+		return fmt.Errorf("%w for MaxBanDuration", p2p.ErrInvalidDurationProvided)
+	}
+	if args.OffensesRetention < 0 {
+		return fmt.Errorf("%w for OffensesRetention", p2p.ErrInvalidDurationProvided)
+	}
+
+	return nil
+}
+
+// IsDenied returns true if the provided peer is currently banned
+func (pde *peerDenialEvaluator) IsDenied(pid core.PeerID) bool {
+	pde.mut.RLock()
+	defer pde.mut.RUnlock()
+
+	info, found := pde.peers[pid]
+
+	return found && pde.isActive(info)
+}
+
+// UpsertPeerID bans the provided peer for the provided duration without recording a reason
+func (pde *peerDenialEvaluator) UpsertPeerID(pid core.PeerID, duration time.Duration) error {
+	return pde.UpsertPeerIDWithReason(pid, duration, unknownReason, unknownSource)
+}
+
+// UpsertPeerIDWithReason bans the provided peer, recording the reason and the component that issued the ban.
+// Repeated offenses escalate the ban duration, the ones reported while the peer is still banned not being counted
+func (pde *peerDenialEvaluator) UpsertPeerIDWithReason(pid core.PeerID, duration time.Duration, reason string, source string) error {
+	if len(pid) == 0 {
+		return p2p.ErrEmptyPeerID
+	}
+	if duration <= 0 {
+		return p2p.ErrInvalidDurationProvided
+	}
+
+	pde.mut.Lock()
+	// the entries are only added here, so sweeping on each upsert keeps the maps bounded
+	pde.sweep()
+	info, found := pde.peers[pid]
+	if !found {
+		info = &DenialInfo{Key: pid.Pretty()}
+		pde.peers[pid] = info
+	}
+	pde.recordOffense(info, duration, reason, source)
+	numOffenses, bannedUntil := info.NumOffenses, info.BannedUntil
+	pde.mut.Unlock()
+
+	pde.log.Debug("peer denied",
+		"pid", pid.Pretty(),
+		"reason", reason,
+		"source", source,
+		"num offenses", numOffenses,
+		"banned until", bannedUntil)
+	pde.save()
+
+	return nil
+}
+
+// UnbanPeerID lifts the ban of the provided peer, also resetting its offenses. Returns true if the peer was banned
+func (pde *peerDenialEvaluator) UnbanPeerID(pid core.PeerID) bool {
+	pde.mut.Lock()
+	info, found := pde.peers[pid]
+	wasDenied := found && pde.isActive(info)
+	delete(pde.peers, pid)
+	pde.mut.Unlock()
+
+	if found {
+		pde.log.Debug("peer unbanned", "pid", pid.Pretty())
+		pde.save()
+	}
+
+	return wasDenied
+}
+
+// UpsertIP bans the provided IP address, recording the reason and the component that issued the ban.
+// Repeated offenses escalate the ban duration, the ones reported while the IP is still banned not being counted
+func (pde *peerDenialEvaluator) UpsertIP(ip string, duration time.Duration, reason string, source string) error {
+	parsedIP := net.ParseIP(ip)
+	if parsedIP == nil {
+		return fmt.Errorf("%w: %s", p2p.ErrInvalidIPAddress, ip)
+	}
+	if duration <= 0 {
+		return p2p.ErrInvalidDurationProvided
+	}
+
+	key := parsedIP.String()
+	pde.mut.Lock()
+	pde.sweep()
+	info, found := pde.ips[key]
+	if !found {
+		info = &DenialInfo{Key: key}
+		pde.ips[key] = info
+	}
+	pde.recordOffense(info, duration, reason, source)
+	numOffenses, bannedUntil := info.NumOffenses, info.BannedUntil
+	pde.mut.Unlock()
+
+	pde.log.Debug("IP denied",
+		"ip", key,
+		"reason", reason,
+		"source", source,
+		"num offenses", numOffenses,
+		"banned until", bannedUntil)
+	pde.save()
+
+	return nil
+}
+
+// IsIPDenied returns true if the provided IP address is currently banned
+func (pde *peerDenialEvaluator) IsIPDenied(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+
+	pde.mut.RLock()
+	defer pde.mut.RUnlock()
+
+	info, found := pde.ips[ip.String()]
+
+	return found && pde.isActive(info)
+}
+
+// UnbanIP lifts the ban of the provided IP address, also resetting its offenses. Returns true if the IP was banned
+func (pde *peerDenialEvaluator) UnbanIP(ip string) bool {
+	parsedIP := net.ParseIP(ip)
+	if parsedIP == nil {
+		return false
+	}
+
+	key := parsedIP.String()
+	pde.mut.Lock()
+	info, found := pde.ips[key]
+	wasDenied := found && pde.isActive(info)
+	delete(pde.ips, key)
+	pde.mut.Unlock()
+
+	if found {
+		pde.log.Debug("IP unbanned", "ip", key)
+		pde.save()
+	}
+
+	return wasDenied
+}
+
+// GetDeniedPeers returns the currently banned peers, sorted by their pretty peer ID
+func (pde *peerDenialEvaluator) GetDeniedPeers() []DenialInfo {
+	pde.mut.Lock()
+	defer pde.mut.Unlock()
+
+	pde.sweep()
+
+	result := make([]DenialInfo, 0, len(pde.peers))
+	for _, info := range pde.peers {
+		if pde.isActive(info) {
+			result = append(result, *info)
+		}
+	}
+
+	return sortDenials(result)
+}
+
+// GetDeniedIPs returns the currently banned IP addresses, sorted by their string representation
+func (pde *peerDenialEvaluator) GetDeniedIPs() []DenialInfo {
+	pde.mut.Lock()
+	defer pde.mut.Unlock()
+
+	pde.sweep()
+
+	result := make([]DenialInfo, 0, len(pde.ips))
+	for _, info := range pde.ips {
+		if pde.isActive(info) {
+			result = append(result, *info)
+		}
+	}
+
+	return sortDenials(result)
+}
+
+// recordOffense must be called under mutex protection
+func (pde *peerDenialEvaluator) recordOffense(info *DenialInfo, duration time.Duration, reason string, source string) {
+	now := pde.getTimeHandler()
+	if info.NumOffenses > 0 && pde.isActive(info) {
+		// the messages already in flight when the ban started can still be reported, so they are not new offenses.
+		// The ban is only extended if the requested one ends later
+		bannedUntil := now.Add(pde.computeBanDuration(duration, info.NumOffenses))
+		if bannedUntil.After(info.BannedUntil) {
+			info.BannedUntil = bannedUntil
+		}
+		return
+	}
+	if info.NumOffenses == 0 || pde.areOffensesExpired(info) {
+		info.FirstOffense = now
+		info.NumOffenses = 0
+	}
+
+	info.NumOffenses++
+	info.LastOffense = now
+	info.Reason = reason
+	info.Source = source
+
+	bannedUntil := now.Add(pde.computeBanDuration(duration, info.NumOffenses))
+	if bannedUntil.After(info.BannedUntil) {
+		info.BannedUntil = bannedUntil
+	}
+}
+
+func (pde *peerDenialEvaluator) computeBanDuration(duration time.Duration, numOffenses uint32) time.Duration {
+	maxBanDuration := pde.maxBanDuration
+	if duration > maxBanDuration {
+		maxBanDuration = duration
+	}
+
+	banDuration := duration
+	factor := time.Duration(pde.escalationFactor)
+	for i := uint32(1); i < numOffenses && factor > noEscalationFactor; i++ {
+		// checking before multiplying also prevents the overflow
+		if banDuration > maxBanDuration/factor {
+			return maxBanDuration
+		}
+		banDuration *= factor
+	}
+
+	return banDuration
+}
+
+// isActive must be called under mutex protection
+func (pde *peerDenialEvaluator) isActive(info *DenialInfo) bool {
+	return pde.getTimeHandler().Before(info.BannedUntil)
+}
+
+// sweep removes the expired bans whose offenses are no longer retained. Must be called under mutex protection
+func (pde *peerDenialEvaluator) sweep() {
+	for pid, info := range pde.peers {
+		if pde.canBeRemoved(info) {
+			delete(pde.peers, pid)
+		}
+	}
+	for ip, info := range pde.ips {
+		if pde.canBeRemoved(info) {
+			delete(pde.ips, ip)
+		}
+	}
+}
+
+func (pde *peerDenialEvaluator) areOffensesExpired(info *DenialInfo) bool {
+	if pde.offensesRetention == 0 {
+		return !pde.isActive(info)
+	}
+
+	return pde.getTimeHandler().Sub(info.LastOffense) > pde.offensesRetention
+}
+
+func (pde *peerDenialEvaluator) canBeRemoved(info *DenialInfo) bool {
+	return !pde.isActive(info) && pde.areOffensesExpired(info)
+}
+
+func sortDenials(result []DenialInfo) []DenialInfo {
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+
+	return result
+}
+
+func (pde *peerDenialEvaluator) load() error {
+	if len(pde.persistenceFilePath) == 0 {
+		return nil
+	}
+
+	buff, err := os.ReadFile(pde.persistenceFilePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	persisted := &persistedDenials{}
+	err = json.Unmarshal(buff, persisted)
+	if err != nil {
+		return fmt.Errorf("%w while loading the denial list from %s", err, pde.persistenceFilePath)
+	}
+
+	for _, info := range persisted.Peers {
+		pid, errDecode := core.NewPeerID(info.Key)
+		if errDecode != nil {
+			pde.log.Warn("invalid persisted denied peer", "pid", info.Key, "error", errDecode.Error())
+			continue
+		}
+
+		pde.addLoadedInfo(info, func(loadedInfo *DenialInfo) {
+			pde.peers[pid] = loadedInfo
+		})
+	}
+	for _, info := range persisted.IPs {
+		ip := net.ParseIP(info.Key)
+		if ip == nil {
+			pde.log.Warn("invalid persisted denied IP", "ip", info.Key)
+			continue
+		}
+
+		info.Key = ip.String()
+		pde.addLoadedInfo(info, func(loadedInfo *DenialInfo) {
+			pde.ips[loadedInfo.Key] = loadedInfo
+		})
+	}
+
+	pde.log.Debug("loaded the denial list",
+		"file", pde.persistenceFilePath,
+		"num peers", len(pde.peers),
+		"num IPs", len(pde.ips))
+
+	return nil
+}
+
+func (pde *peerDenialEvaluator) addLoadedInfo(info DenialInfo, handler func(loadedInfo *DenialInfo)) {
+	loadedInfo := info
+	if pde.canBeRemoved(&loadedInfo) {
+		return
+	}
+
+	handler(&loadedInfo)
+}
+
+func (pde *peerDenialEvaluator) save() {
+	if len(pde.persistenceFilePath) == 0 {
+		return
+	}
+
+	pde.mut.Lock()
+	pde.sweep()
+	persisted := persistedDenials{
+		Peers: make([]DenialInfo, 0, len(pde.peers)),
+		IPs:   make([]DenialInfo, 0, len(pde.ips)),
+	}
+	for _, info := range pde.peers {
+		persisted.Peers = append(persisted.Peers, *info)
+	}
+	for _, info := range pde.ips {
+		persisted.IPs = append(persisted.IPs, *info)
+	}
+	sortDenials(persisted.Peers)
+	sortDenials(persisted.IPs)
+	err := pde.writePersistenceFile(persisted)
+	pde.mut.Unlock()
+
+	if err != nil {
+		pde.log.Warn("could not save the denial list", "file", pde.persistenceFilePath, "error", err.Error())
+	}
+}
+
+// writePersistenceFile must be called under mutex protection so the concurrent saves will not overwrite each other
+func (pde *peerDenialEvaluator) writePersistenceFile(persisted persistedDenials) error {
+	buff, err := json.MarshalIndent(persisted, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(pde.persistenceFilePath), filepath.Base(pde.persistenceFilePath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpFileName := tmpFile.Name()
+	defer func() {
+		_ = os.Remove(tmpFileName)
+	}()
+
+	_, err = tmpFile.Write(buff)
+	if err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	err = tmpFile.Chmod(persistenceFileMode)
+	if err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	err = tmpFile.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFileName, pde.persistenceFilePath)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (pde *peerDenialEvaluator) IsInterfaceNil() bool {
+	return pde == nil
+}
@@ -0,0 +1,342 @@
+package denial_test
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/denial"
+	"github.com/TerraDharitri/drt-go-chain-communication/testscommon"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+	"github.com/TerraDharitri/drt-go-chain-core/core/check"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const providedPid = core.PeerID("16Uiu2HAm6yvbp1oZ6zjnWsn9FdRqBSaQkbhELyaThuq48ybdorrr")
+
+func createMockArgsPeerDenialEvaluator() denial.ArgsPeerDenialEvaluator {
+	return denial.ArgsPeerDenialEvaluator{
+		Logger: &testscommon.LoggerStub{},
+	}
+}
+
+type timeHandler struct {
+	now time.Time
+}
+
+func (th *timeHandler) getTime() time.Time {
+	return th.now
+}
+
+func TestNewPeerDenialEvaluator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil logger should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsPeerDenialEvaluator()
+		args.Logger = nil
+		pde, err := denial.NewPeerDenialEvaluator(args)
+		assert.Equal(t, p2p.ErrNilLogger, err)
+		assert.True(t, check.IfNil(pde))
+	})
+	t.Run("invalid MaxBanDuration should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsPeerDenialEvaluator()
+		args.MaxBanDuration = -time.Second
+		pde, err := denial.NewPeerDenialEvaluator(args)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidDurationProvided))
+		assert.True(t, check.IfNil(pde))
+	})
+	t.Run("invalid OffensesRetention should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsPeerDenialEvaluator()
+		args.OffensesRetention = -time.Second
+		pde, err := denial.NewPeerDenialEvaluator(args)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidDurationProvided))
+		assert.True(t, check.IfNil(pde))
+	})
+	t.Run("corrupted persistence file should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsPeerDenialEvaluator()
+		args.PersistenceFilePath = filepath.Join(t.TempDir(), "denied.json")
+		require.Nil(t, os.WriteFile(args.PersistenceFilePath, []byte("not a json"), 0600))
+		pde, err := denial.NewPeerDenialEvaluator(args)
+		assert.NotNil(t, err)
+		assert.True(t, check.IfNil(pde))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		pde, err := denial.NewPeerDenialEvaluator(createMockArgsPeerDenialEvaluator())
+		assert.Nil(t, err)
+		assert.False(t, check.IfNil(pde))
+	})
+}
+
+func TestPeerDenialEvaluator_UpsertPeerIDWithReason(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid arguments should error", func(t *testing.T) {
+		t.Parallel()
+
+		pde, _ := denial.NewPeerDenialEvaluator(createMockArgsPeerDenialEvaluator())
+		err := pde.UpsertPeerIDWithReason("", time.Second, "reason", "source")
+		assert.Equal(t, p2p.ErrEmptyPeerID, err)
+
+		err = pde.UpsertPeerIDWithReason(providedPid, 0, "reason", "source")
+		assert.Equal(t, p2p.ErrInvalidDurationProvided, err)
+		assert.False(t, pde.IsDenied(providedPid))
+	})
+	t.Run("should record the reason and expire the ban", func(t *testing.T) {
+		t.Parallel()
+
+		th := &timeHandler{now: time.Unix(1000, 0)}
+		pde, _ := denial.NewPeerDenialEvaluator(createMockArgsPeerDenialEvaluator())
+		pde.SetTimeHandler(th.getTime)
+
+		err := pde.UpsertPeerIDWithReason(providedPid, time.Minute, "invalid message", "interceptor")
+		assert.Nil(t, err)
+		assert.True(t, pde.IsDenied(providedPid))
+		assert.False(t, pde.IsDenied("other pid"))
+
+		denied := pde.GetDeniedPeers()
+		require.Equal(t, 1, len(denied))
+		assert.Equal(t, providedPid.Pretty(), denied[0].Key)
+		assert.Equal(t, "invalid message", denied[0].Reason)
+		assert.Equal(t, "interceptor", denied[0].Source)
+		assert.Equal(t, uint32(1), denied[0].NumOffenses)
+		assert.Equal(t, th.now, denied[0].FirstOffense)
+		assert.Equal(t, th.now, denied[0].LastOffense)
+		assert.Equal(t, th.now.Add(time.Minute), denied[0].BannedUntil)
+
+		th.now = th.now.Add(time.Minute)
+		assert.False(t, pde.IsDenied(providedPid))
+		assert.Empty(t, pde.GetDeniedPeers())
+	})
+	t.Run("UpsertPeerID should record an unknown reason", func(t *testing.T) {
+		t.Parallel()
+
+		pde, _ := denial.NewPeerDenialEvaluator(createMockArgsPeerDenialEvaluator())
+		err := pde.UpsertPeerID(providedPid, time.Minute)
+		assert.Nil(t, err)
+
+		denied := pde.GetDeniedPeers()
+		require.Equal(t, 1, len(denied))
+		assert.Equal(t, "unknown", denied[0].Reason)
+		assert.Equal(t, "unknown", denied[0].Source)
+	})
+	t.Run("repeated offenses should escalate the ban up to the maximum", func(t *testing.T) {
+		t.Parallel()
+
+		th := &timeHandler{now: time.Unix(1000, 0)}
+		args := createMockArgsPeerDenialEvaluator()
+		args.EscalationFactor = 2
+		args.MaxBanDuration = time.Minute * 3
+		args.OffensesRetention = time.Hour
+		pde, _ := denial.NewPeerDenialEvaluator(args)
+		pde.SetTimeHandler(th.getTime)
+		firstOffense := th.now
+
+		_ = pde.UpsertPeerIDWithReason(providedPid, time.Minute, "reason", "source")
+		assert.Equal(t, th.now.Add(time.Minute), pde.GetDeniedPeers()[0].BannedUntil)
+
+		th.now = th.now.Add(time.Minute * 2)
+		_ = pde.UpsertPeerIDWithReason(providedPid, time.Minute, "reason", "source")
+		assert.Equal(t, th.now.Add(time.Minute*2), pde.GetDeniedPeers()[0].BannedUntil)
+
+		th.now = th.now.Add(time.Minute * 3)
+		_ = pde.UpsertPeerIDWithReason(providedPid, time.Minute, "other reason", "other source")
+		denied := pde.GetDeniedPeers()
+		require.Equal(t, 1, len(denied))
+		assert.Equal(t, th.now.Add(time.Minute*3), denied[0].BannedUntil)
+		assert.Equal(t, uint32(3), denied[0].NumOffenses)
+		assert.Equal(t, firstOffense, denied[0].FirstOffense)
+		assert.Equal(t, th.now, denied[0].LastOffense)
+		assert.Equal(t, "other reason", denied[0].Reason)
+		assert.Equal(t, "other source", denied[0].Source)
+	})
+	t.Run("offenses reported while banned should not be counted", func(t *testing.T) {
+		t.Parallel()
+
+		th := &timeHandler{now: time.Unix(1000, 0)}
+		args := createMockArgsPeerDenialEvaluator()
+		args.EscalationFactor = 2
+		args.OffensesRetention = time.Hour
+		pde, _ := denial.NewPeerDenialEvaluator(args)
+		pde.SetTimeHandler(th.getTime)
+
+		_ = pde.UpsertPeerIDWithReason(providedPid, time.Minute, "reason", "source")
+		th.now = th.now.Add(time.Second * 30)
+		_ = pde.UpsertPeerIDWithReason(providedPid, time.Minute, "other reason", "other source")
+
+		denied := pde.GetDeniedPeers()
+		require.Equal(t, 1, len(denied))
+		assert.Equal(t, uint32(1), denied[0].NumOffenses)
+		assert.Equal(t, "reason", denied[0].Reason)
+		// the requested ban ends later, so it is extended without escalating
+		assert.Equal(t, th.now.Add(time.Minute), denied[0].BannedUntil)
+	})
+	t.Run("escalation should be capped by default and should not overflow", func(t *testing.T) {
+		t.Parallel()
+
+		th := &timeHandler{now: time.Unix(1000, 0)}
+		args := createMockArgsPeerDenialEvaluator()
+		args.EscalationFactor = 2
+		args.OffensesRetention = time.Hour * 48
+		pde, _ := denial.NewPeerDenialEvaluator(args)
+		pde.SetTimeHandler(th.getTime)
+
+		for i := 0; i < 100; i++ {
+			_ = pde.UpsertPeerIDWithReason(providedPid, time.Minute, "reason", "source")
+			denied := pde.GetDeniedPeers()
+			require.Equal(t, 1, len(denied))
+			banDuration := denied[0].BannedUntil.Sub(th.now)
+			require.True(t, banDuration >= time.Minute && banDuration <= time.Hour*24)
+
+			th.now = denied[0].BannedUntil.Add(time.Second)
+		}
+
+		_ = pde.UpsertPeerIDWithReason(providedPid, time.Minute, "reason", "source")
+		assert.Equal(t, th.now.Add(time.Hour*24), pde.GetDeniedPeers()[0].BannedUntil)
+	})
+	t.Run("a requested ban longer than the maximum should be kept", func(t *testing.T) {
+		t.Parallel()
+
+		th := &timeHandler{now: time.Unix(1000, 0)}
+		args := createMockArgsPeerDenialEvaluator()
+		args.EscalationFactor = 2
+		args.MaxBanDuration = time.Hour
+		pde, _ := denial.NewPeerDenialEvaluator(args)
+		pde.SetTimeHandler(th.getTime)
+
+		_ = pde.UpsertPeerIDWithReason(providedPid, time.Hour*2, "reason", "source")
+		assert.Equal(t, th.now.Add(time.Hour*2), pde.GetDeniedPeers()[0].BannedUntil)
+	})
+	t.Run("offenses should reset after the retention time", func(t *testing.T) {
+		t.Parallel()
+
+		th := &timeHandler{now: time.Unix(1000, 0)}
+		args := createMockArgsPeerDenialEvaluator()
+		args.EscalationFactor = 2
+		args.OffensesRetention = time.Hour
+		pde, _ := denial.NewPeerDenialEvaluator(args)
+		pde.SetTimeHandler(th.getTime)
+
+		_ = pde.UpsertPeerIDWithReason(providedPid, time.Minute, "reason", "source")
+		_ = pde.UpsertPeerIDWithReason(providedPid, time.Minute, "reason", "source")
+
+		th.now = th.now.Add(time.Hour * 2)
+		_ = pde.UpsertPeerIDWithReason(providedPid, time.Minute, "reason", "source")
+		denied := pde.GetDeniedPeers()
+		require.Equal(t, 1, len(denied))
+		assert.Equal(t, uint32(1), denied[0].NumOffenses)
+		assert.Equal(t, th.now, denied[0].FirstOffense)
+		assert.Equal(t, th.now.Add(time.Minute), denied[0].BannedUntil)
+	})
+}
+
+func TestPeerDenialEvaluator_UpsertShouldRemoveTheExpiredEntries(t *testing.T) {
+	t.Parallel()
+
+	th := &timeHandler{now: time.Unix(1000, 0)}
+	args := createMockArgsPeerDenialEvaluator()
+	args.OffensesRetention = time.Hour
+	pde, _ := denial.NewPeerDenialEvaluator(args)
+	pde.SetTimeHandler(th.getTime)
+
+	_ = pde.UpsertPeerIDWithReason(providedPid, time.Minute, "reason", "source")
+	_ = pde.UpsertIP("10.0.0.1", time.Minute, "reason", "source")
+	assert.Equal(t, 2, pde.NumEntries())
+
+	// the bans expired, but the offenses are still retained
+	th.now = th.now.Add(time.Minute * 30)
+	_ = pde.UpsertIP("10.0.0.2", time.Minute, "reason", "source")
+	assert.Equal(t, 3, pde.NumEntries())
+
+	th.now = th.now.Add(time.Hour * 2)
+	_ = pde.UpsertPeerIDWithReason("other pid", time.Minute, "reason", "source")
+	assert.Equal(t, 1, pde.NumEntries())
+}
+
+func TestPeerDenialEvaluator_UnbanPeerID(t *testing.T) {
+	t.Parallel()
+
+	pde, _ := denial.NewPeerDenialEvaluator(createMockArgsPeerDenialEvaluator())
+	assert.False(t, pde.UnbanPeerID(providedPid))
+
+	_ = pde.UpsertPeerIDWithReason(providedPid, time.Hour, "reason", "source")
+	assert.True(t, pde.IsDenied(providedPid))
+
+	assert.True(t, pde.UnbanPeerID(providedPid))
+	assert.False(t, pde.IsDenied(providedPid))
+	assert.Empty(t, pde.GetDeniedPeers())
+}
+
+func TestPeerDenialEvaluator_IPBans(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid arguments should error", func(t *testing.T) {
+		t.Parallel()
+
+		pde, _ := denial.NewPeerDenialEvaluator(createMockArgsPeerDenialEvaluator())
+		err := pde.UpsertIP("not an IP", time.Minute, "reason", "source")
+		assert.True(t, errors.Is(err, p2p.ErrInvalidIPAddress))
+
+		err = pde.UpsertIP("10.0.0.1", 0, "reason", "source")
+		assert.Equal(t, p2p.ErrInvalidDurationProvided, err)
+		assert.False(t, pde.UnbanIP("not an IP"))
+		assert.False(t, pde.IsIPDenied(nil))
+	})
+	t.Run("should ban and unban", func(t *testing.T) {
+		t.Parallel()
+
+		pde, _ := denial.NewPeerDenialEvaluator(createMockArgsPeerDenialEvaluator())
+		err := pde.UpsertIP("10.0.0.1", time.Hour, "too many connections", "connection gater")
+		assert.Nil(t, err)
+		assert.True(t, pde.IsIPDenied(net.ParseIP("10.0.0.1")))
+		assert.False(t, pde.IsIPDenied(net.ParseIP("10.0.0.2")))
+
+		denied := pde.GetDeniedIPs()
+		require.Equal(t, 1, len(denied))
+		assert.Equal(t, "10.0.0.1", denied[0].Key)
+		assert.Equal(t, "too many connections", denied[0].Reason)
+		assert.Equal(t, "connection gater", denied[0].Source)
+
+		assert.True(t, pde.UnbanIP("10.0.0.1"))
+		assert.False(t, pde.IsIPDenied(net.ParseIP("10.0.0.1")))
+		assert.Empty(t, pde.GetDeniedIPs())
+	})
+}
+
+func TestPeerDenialEvaluator_PersistenceShouldSurviveRestarts(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsPeerDenialEvaluator()
+	args.PersistenceFilePath = filepath.Join(t.TempDir(), "denied.json")
+	pde, _ := denial.NewPeerDenialEvaluator(args)
+	_ = pde.UpsertPeerIDWithReason(providedPid, time.Hour, "reason", "source")
+	_ = pde.UpsertPeerIDWithReason("expiring pid", time.Nanosecond, "reason", "source")
+	_ = pde.UpsertIP("10.0.0.1", time.Hour, "reason", "source")
+
+	time.Sleep(time.Millisecond * 10)
+	reloaded, err := denial.NewPeerDenialEvaluator(args)
+	require.Nil(t, err)
+	assert.True(t, reloaded.IsDenied(providedPid))
+	assert.False(t, reloaded.IsDenied("expiring pid"))
+	assert.True(t, reloaded.IsIPDenied(net.ParseIP("10.0.0.1")))
+	assert.Equal(t, pde.GetDeniedPeers()[0].Reason, reloaded.GetDeniedPeers()[0].Reason)
+
+	reloaded.UnbanPeerID(providedPid)
+	reloadedAgain, _ := denial.NewPeerDenialEvaluator(args)
+	assert.False(t, reloadedAgain.IsDenied(providedPid))
+	assert.True(t, reloadedAgain.IsIPDenied(net.ParseIP("10.0.0.1")))
+}
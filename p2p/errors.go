@@ -212,3 +212,123 @@ var ErrNoTransportsDefined = errors.New("no transports defined")
 
 // ErrUnknownResourceLimiterType signals that an unknown resource limiter type was provided
 var ErrUnknownResourceLimiterType = errors.New("unknown resource limiter type")
+
+// ErrNilMessageHandler signals that a nil message handler has been provided
+var ErrNilMessageHandler = errors.New("nil message handler")
+
+// ErrNilWebSocketSender signals that a nil websocket sender has been provided
+var ErrNilWebSocketSender = errors.New("nil websocket sender")
+
+// ErrNoTopicsToBridge signals that no topics were provided to be bridged
+var ErrNoTopicsToBridge = errors.New("no topics to bridge")
+
+// ErrDuplicatedTopic signals that the same topic was provided more than once
+var ErrDuplicatedTopic = errors.New("duplicated topic")
+
+// ErrInvalidQueueSize signals that an invalid queue size has been provided
+var ErrInvalidQueueSize = errors.New("invalid queue size")
+
+// ErrUnknownPubSubRouterType signals that an unknown pubsub router type was provided
+var ErrUnknownPubSubRouterType = errors.New("unknown pubsub router type")
+
+// ErrUnknownOverloadPolicy signals that an unknown validator overload policy was provided
+var ErrUnknownOverloadPolicy = errors.New("unknown validator overload policy")
+
+// ErrNilValidatorMetrics signals that a nil validator metrics component has been provided
+var ErrNilValidatorMetrics = errors.New("nil validator metrics")
+
+// ErrUnknownDropPolicy signals that an unknown subscription drop policy was provided
+var ErrUnknownDropPolicy = errors.New("unknown subscription drop policy")
+
+// ErrDuplicatedChannel signals that a channel was configured more than once
+var ErrDuplicatedChannel = errors.New("duplicated channel")
+
+// ErrEmptyChannelName signals that an empty channel name has been provided
+var ErrEmptyChannelName = errors.New("empty channel name")
+
+// ErrMessageDropped signals that a message was dropped before being published
+var ErrMessageDropped = errors.New("message dropped")
+
+// ErrTopicNotJoined signals that a message was sent on a topic that the node did not join
+var ErrTopicNotJoined = errors.New("topic not joined")
+
+// ErrCannotCreateMessageBytes signals that the message to be published could not be created
+var ErrCannotCreateMessageBytes = errors.New("cannot create message bytes")
+
+// ErrEmptyMessagesBatch signals that a batched message without payloads was received
+var ErrEmptyMessagesBatch = errors.New("empty messages batch")
+
+// ErrInvalidAttestation signals that an invalid public key attestation was provided or received
+var ErrInvalidAttestation = errors.New("invalid attestation")
+
+// ErrNilAttestationVerifier signals that a nil attestation verifier was provided
+var ErrNilAttestationVerifier = errors.New("nil attestation verifier")
+
+// ErrInvalidPeerAuthentication signals that an invalid peer authentication statement was provided or received
+var ErrInvalidPeerAuthentication = errors.New("invalid peer authentication")
+
+// ErrPeerAuthenticationInfoNotSet signals that the peer authentication info was not set
+var ErrPeerAuthenticationInfoNotSet = errors.New("peer authentication info not set")
+
+// ErrPeerAuthenticationDisabled signals that the peer authentication is disabled
+var ErrPeerAuthenticationDisabled = errors.New("peer authentication disabled")
+
+// ErrNilPeersInfoHandler signals that a nil peers info handler was provided
+var ErrNilPeersInfoHandler = errors.New("nil peers info handler")
+
+// ErrIncompatiblePeer signals that the peer does not belong to the same network or speaks incompatible protocol versions
+var ErrIncompatiblePeer = errors.New("incompatible peer")
+
+// ErrNilIncompatiblePeersHandler signals that a nil incompatible peers handler was provided
+var ErrNilIncompatiblePeersHandler = errors.New("nil incompatible peers handler")
+
+// ErrEmptyIdentityName signals that an empty identity name was provided
+var ErrEmptyIdentityName = errors.New("empty identity name")
+
+// ErrIdentityNotFound signals that the identity was not registered
+var ErrIdentityNotFound = errors.New("identity not found")
+
+// ErrPeerIDMismatch signals that the provided peer ID does not match the one derived from the private key
+var ErrPeerIDMismatch = errors.New("peer ID does not match the private key")
+
+// ErrUnknownSignaturePolicy signals that an unknown direct messages signature policy was provided
+var ErrUnknownSignaturePolicy = errors.New("unknown direct messages signature policy")
+
+// ErrUnsignedDirectMessage signals that an unsigned direct message was rejected
+var ErrUnsignedDirectMessage = errors.New("unsigned direct message")
+
+// ErrNilMessageVerifier signals that a nil message verifier was provided
+var ErrNilMessageVerifier = errors.New("nil message verifier")
+
+// ErrNilEvidenceHandler signals that a nil misbehavior evidence handler was provided
+var ErrNilEvidenceHandler = errors.New("nil evidence handler")
+
+// ErrNilContentValidator signals that a nil content validator was provided
+var ErrNilContentValidator = errors.New("nil content validator")
+
+// ErrNilMessenger signals that a nil messenger was provided
+var ErrNilMessenger = errors.New("nil messenger")
+
+// ErrInvalidEvidence signals that the misbehavior evidence does not prove the claimed misbehavior
+var ErrInvalidEvidence = errors.New("invalid misbehavior evidence")
+
+// ErrEmptyPeerID signals that an empty peer ID was provided
+var ErrEmptyPeerID = errors.New("empty peer ID")
+
+// ErrInvalidIPAddress signals that an invalid IP address was provided
+var ErrInvalidIPAddress = errors.New("invalid IP address")
+
+// ErrInvalidCIDR signals that an invalid CIDR notation was provided
+var ErrInvalidCIDR = errors.New("invalid CIDR")
+
+// ErrBatchedMessagesNotEnabled signals that the batched messages are not enabled
+var ErrBatchedMessagesNotEnabled = errors.New("batched messages are not enabled")
+
+// ErrAttestationNotEnabled signals that the messages attestation is not enabled
+var ErrAttestationNotEnabled = errors.New("messages attestation is not enabled")
+
+// ErrNilMessageObserver signals that a nil message observer was provided
+var ErrNilMessageObserver = errors.New("nil message observer")
+
+// ErrNilDuplicateMessagesTracer signals that a nil duplicate messages tracer was provided
+var ErrNilDuplicateMessagesTracer = errors.New("nil duplicate messages tracer")
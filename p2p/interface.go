@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/hex"
 	"io"
+	"net"
 	"time"
 
 	"github.com/TerraDharitri/drt-go-chain-core/core"
@@ -38,6 +39,8 @@ type MessageHandler interface {
 	MessageProcessor
 
 	CreateTopic(name string, createChannelForTopic bool) error
+	CreatePublishOnlyTopic(name string, createChannelForTopic bool) error
+	LeaveTopic(name string) error
 	HasTopic(name string) bool
 	RegisterMessageProcessor(topic string, identifier string, handler MessageProcessor) error
 	UnregisterAllMessageProcessors() error
@@ -46,9 +49,19 @@ type MessageHandler interface {
 	BroadcastOnChannel(channel string, topic string, buff []byte)
 	BroadcastUsingPrivateKey(topic string, buff []byte, pid core.PeerID, skBytes []byte)
 	BroadcastOnChannelUsingPrivateKey(channel string, topic string, buff []byte, pid core.PeerID, skBytes []byte)
+	BroadcastCtx(ctx context.Context, channel string, topic string, buff []byte) error
+	BroadcastUsingPrivateKeyCtx(ctx context.Context, channel string, topic string, buff []byte, pid core.PeerID, skBytes []byte) error
+	AddIdentity(name string, skBytes []byte, pid core.PeerID) error
+	RemoveIdentity(name string) error
+	BroadcastAs(identityName string, topic string, buff []byte) error
+	BroadcastOnChannelAs(channel string, identityName string, topic string, buff []byte) error
 	SendToConnectedPeer(topic string, buff []byte, peerID core.PeerID) error
+	Subscribe(topic string) (<-chan MessageP2P, func())
 	UnJoinAllTopics() error
 	SetDebugger(debugger Debugger) error
+	SetAttestation(pk []byte, signatureOnPid []byte) error
+	SetAttestationVerifier(verifier AttestationVerifier) error
+	SetMessageObserver(observer MessageObserver) error
 	IsInterfaceNil() bool
 }
 
@@ -87,6 +100,7 @@ type Messenger interface {
 	Sign(payload []byte) ([]byte, error)
 	Verify(payload []byte, pid core.PeerID, signature []byte) error
 	SignUsingPrivateKey(skBytes []byte, payload []byte) ([]byte, error)
+	SetPeerAuthenticationInfo(info PeerAuthenticationInfo) error
 	IsInterfaceNil() bool
 }
 
@@ -102,6 +116,8 @@ type MessageP2P interface {
 	Peer() core.PeerID
 	Timestamp() int64
 	BroadcastMethod() BroadcastMethod
+	AttestedPk() []byte
+	SignatureOnPid() []byte
 	IsInterfaceNil() bool
 }
 
@@ -110,6 +126,7 @@ type DirectSender interface {
 	NextSequenceNumber() []byte
 	Send(topic string, buff []byte, peer core.PeerID) error
 	RegisterDirectMessageProcessor(handler MessageHandler) error
+	SignSelfMessage(topic string, buff []byte, seqNo []byte) ([]byte, error)
 	IsInterfaceNil() bool
 }
 
@@ -149,6 +166,17 @@ type PeerShardResolver interface {
 	IsInterfaceNil() bool
 }
 
+// PeerAuthenticationInfo represents the data a node states about itself to the peers it connects to. A validator
+// should also provide the public key attested for its peer ID
+type PeerAuthenticationInfo struct {
+	PeerType       core.P2PPeerType
+	PeerSubType    core.P2PPeerSubType
+	ShardID        uint32
+	NodeVersion    string
+	Pk             []byte
+	SignatureOnPid []byte
+}
+
 // ConnectedPeersInfo represents the DTO structure used to output the metrics for connected peers
 type ConnectedPeersInfo struct {
 	SelfShardID              uint32
@@ -167,6 +195,54 @@ type ConnectedPeersInfo struct {
 	NumCrossShardObservers   int
 }
 
+// PeerScore holds the gossipsub score of a peer, along with its components
+type PeerScore struct {
+	Score              float64
+	AppSpecificScore   float64
+	IPColocationFactor float64
+	BehaviourPenalty   float64
+	Topics             map[string]TopicScore
+}
+
+// TopicScore holds the gossipsub score counters of a peer on a topic
+type TopicScore struct {
+	TimeInMesh               time.Duration
+	FirstMessageDeliveries   float64
+	MeshMessageDeliveries    float64
+	InvalidMessageDeliveries float64
+}
+
+// OutgoingChannelMetrics holds the scheduling counters of an outgoing channel
+type OutgoingChannelMetrics struct {
+	QueueDepth      int
+	NumSent         uint64
+	NumDropped      uint64
+	LastWaitTime    time.Duration
+	AverageWaitTime time.Duration
+}
+
+// ValidatorMetrics holds the counters of the overloaded validations of a topic
+type ValidatorMetrics struct {
+	NumThrottled         uint64
+	NumIgnoredOnOverload uint64
+	NumTimeouts          uint64
+}
+
+// DirectMessageSignatureMetrics holds the counters of the direct messages signature checks
+type DirectMessageSignatureMetrics struct {
+	NumUnsignedAccepted  uint64
+	NumUnsignedRejected  uint64
+	NumInvalidSignatures uint64
+}
+
+// ConnectionGaterMetrics holds the counters of the connection attempts refused by the connection gater
+type ConnectionGaterMetrics struct {
+	NumDenied             uint64
+	NumIPLimitReached     uint64
+	NumSubnetLimitReached uint64
+	NumInboundRateLimited uint64
+}
+
 // NetworkShardingCollector defines the updating methods used by the network sharding component
 // The interface assures that the collected data will be used by the p2p network sharding components
 type NetworkShardingCollector interface {
@@ -222,6 +298,17 @@ type PeerDenialEvaluator interface {
 	IsInterfaceNil() bool
 }
 
+// PeerDenialReasonHandler defines a PeerDenialEvaluator able to record why and by which component a peer was denied
+type PeerDenialReasonHandler interface {
+	PeerDenialEvaluator
+	UpsertPeerIDWithReason(pid core.PeerID, duration time.Duration, reason string, source string) error
+}
+
+// IPDenialEvaluator defines a component able to tell if the connections with an IP address are denied
+type IPDenialEvaluator interface {
+	IsIPDenied(ip net.IP) bool
+}
+
 // Debugger represent a p2p debugger able to print p2p statistics (messages received/sent per topic)
 type Debugger interface {
 	AddIncomingMessage(topic string, size uint64, isRejected bool)
@@ -275,3 +362,19 @@ type Logger interface {
 	GetLevel() logger.LogLevel
 	IsInterfaceNil() bool
 }
+
+// AttestationVerifier defines a component able to verify that a public key was attested by a peer. It is called for
+// each received message that carries an attestation
+type AttestationVerifier interface {
+	VerifyAttestation(pk []byte, pid core.PeerID, signatureOnPid []byte) error
+	IsInterfaceNil() bool
+}
+
+// MessageObserver defines a component able to detect the equivocations of the received broadcast messages. The
+// validated messages are provided to ObserveMessage while the messages dropped by pubsub as duplicates, which are not
+// validated, are provided to ObserveDuplicateMessage
+type MessageObserver interface {
+	ObserveMessage(msg MessageP2P) error
+	ObserveDuplicateMessage(msg MessageP2P) error
+	IsInterfaceNil() bool
+}
@@ -0,0 +1,71 @@
+package libp2p
+
+import (
+	"github.com/libp2p/go-libp2p/core/connmgr"
+	"github.com/libp2p/go-libp2p/core/control"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+)
+
+var _ connmgr.ConnectionGater = (connectionGaters)(nil)
+
+// connectionGaters chains the connection gaters installed on the host. A connection is allowed only if all the
+// gaters allow it
+type connectionGaters []connmgr.ConnectionGater
+
+// InterceptPeerDial returns false if any of the gaters refuses the dialed peer
+func (gaters connectionGaters) InterceptPeerDial(p peer.ID) bool {
+	for _, gater := range gaters {
+		if !gater.InterceptPeerDial(p) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// InterceptAddrDial returns false if any of the gaters refuses the dialed address
+func (gaters connectionGaters) InterceptAddrDial(p peer.ID, address multiaddr.Multiaddr) bool {
+	for _, gater := range gaters {
+		if !gater.InterceptAddrDial(p, address) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// InterceptAccept returns false if any of the gaters refuses the inbound connection
+func (gaters connectionGaters) InterceptAccept(addresses network.ConnMultiaddrs) bool {
+	for _, gater := range gaters {
+		if !gater.InterceptAccept(addresses) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// InterceptSecured returns false if any of the gaters refuses the secured connection
+func (gaters connectionGaters) InterceptSecured(direction network.Direction, p peer.ID, addresses network.ConnMultiaddrs) bool {
+	for _, gater := range gaters {
+		if !gater.InterceptSecured(direction, p, addresses) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// InterceptUpgraded returns false and the disconnect reason of the first gater that refuses the upgraded connection
+func (gaters connectionGaters) InterceptUpgraded(conn network.Conn) (bool, control.DisconnectReason) {
+	for _, gater := range gaters {
+		allow, reason := gater.InterceptUpgraded(conn)
+		if !allow {
+			return false, reason
+		}
+	}
+
+	return true, 0
+}
@@ -6,7 +6,8 @@ import (
 	libp2pCrypto "github.com/libp2p/go-libp2p/core/crypto"
 )
 
-// ConvertPrivateKeyToLibp2pPrivateKey will convert common private key to libp2p private key
+// ConvertPrivateKeyToLibp2pPrivateKey will convert common private key to libp2p private key. The key type is given by
+// the key's suite or, if the suite is not set, inferred from the key bytes
 func ConvertPrivateKeyToLibp2pPrivateKey(privateKey crypto.PrivateKey) (libp2pCrypto.PrivKey, error) {
 	if check.IfNil(privateKey) {
 		return nil, ErrNilPrivateKey
@@ -17,5 +18,7 @@ func ConvertPrivateKeyToLibp2pPrivateKey(privateKey crypto.PrivateKey) (libp2pCr
 		return nil, err
 	}
 
-	return libp2pCrypto.UnmarshalSecp256k1PrivateKey(p2pPrivateKeyBytes)
+	keyType := keyTypeFromSuite(privateKey.Suite(), p2pPrivateKeyBytes, KeyTypeFromRawPrivateKey)
+
+	return UnmarshalLibp2pPrivateKey(keyType, p2pPrivateKeyBytes)
 }
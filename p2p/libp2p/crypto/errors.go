@@ -16,3 +16,6 @@ var ErrNilKeyGenerator = errors.New("nil key generator")
 
 // ErrNilP2PKeyConverter signals that a nil key converter was provided
 var ErrNilP2PKeyConverter = errors.New("nil key converter")
+
+// ErrUnsupportedKeyType signals that a key of an unsupported type was provided
+var ErrUnsupportedKeyType = errors.New("unsupported key type")
@@ -2,6 +2,7 @@ package crypto
 
 import (
 	"crypto/rand"
+	"fmt"
 
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
 	"github.com/TerraDharitri/drt-go-chain-core/core"
@@ -13,17 +14,27 @@ import (
 var emptyPrivateKeyBytes = []byte("")
 
 type identityGenerator struct {
-	log p2p.Logger
+	log     p2p.Logger
+	keyType p2p.KeyType
 }
 
-// NewIdentityGenerator creates a new identity generator
+// NewIdentityGenerator creates a new identity generator that works with secp256k1 identities
 func NewIdentityGenerator(logger p2p.Logger) (*identityGenerator, error) {
+	return NewIdentityGeneratorWithKeyType(logger, p2p.Secp256k1KeyType)
+}
+
+// NewIdentityGeneratorWithKeyType creates a new identity generator that works with identities of the provided key type
+func NewIdentityGeneratorWithKeyType(logger p2p.Logger, keyType p2p.KeyType) (*identityGenerator, error) {
 	if check.IfNil(logger) {
 		return nil, p2p.ErrNilLogger
 	}
+	if keyType != p2p.Secp256k1KeyType && keyType != p2p.Ed25519KeyType {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedKeyType, keyType)
+	}
 
 	return &identityGenerator{
-		log: logger,
+		log:     logger,
+		keyType: keyType,
 	}, nil
 }
 
@@ -53,23 +64,22 @@ func (generator *identityGenerator) CreateRandomP2PIdentity() ([]byte, core.Peer
 // This is useful when we want a private key that never changes, such as in the network seeders
 func (generator *identityGenerator) CreateP2PPrivateKey(privateKeyBytes []byte) (libp2pCrypto.PrivKey, error) {
 	if len(privateKeyBytes) == 0 {
-		randReader := rand.Reader
-		prvKey, _, err := libp2pCrypto.GenerateSecp256k1Key(randReader)
+		prvKey, err := generateLibp2pPrivateKey(generator.keyType, rand.Reader)
 		if err != nil {
 			return nil, err
 		}
 
-		generator.log.Info("createP2PPrivateKey: generated a new private key for p2p signing")
+		generator.log.Info("createP2PPrivateKey: generated a new private key for p2p signing", "key type", generator.keyType)
 
 		return prvKey, nil
 	}
 
-	prvKey, err := libp2pCrypto.UnmarshalSecp256k1PrivateKey(privateKeyBytes)
+	prvKey, err := UnmarshalLibp2pPrivateKey(generator.keyType, privateKeyBytes)
 	if err != nil {
 		return nil, err
 	}
 
-	generator.log.Info("createP2PPrivateKey: using the provided private key for p2p signing")
+	generator.log.Info("createP2PPrivateKey: using the provided private key for p2p signing", "key type", generator.keyType)
 
 	return prvKey, nil
 }
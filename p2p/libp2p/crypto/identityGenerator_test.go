@@ -2,12 +2,15 @@ package crypto_test
 
 import (
 	"crypto/rand"
+	"errors"
 	"testing"
 
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p/crypto"
 	"github.com/TerraDharitri/drt-go-chain-communication/testscommon"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
 	libp2pCrypto "github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -31,6 +34,32 @@ func TestNewIdentityGenerator(t *testing.T) {
 	})
 }
 
+func TestNewIdentityGeneratorWithKeyType(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil logger should error", func(t *testing.T) {
+		t.Parallel()
+
+		generator, err := crypto.NewIdentityGeneratorWithKeyType(nil, p2p.Ed25519KeyType)
+		assert.Equal(t, p2p.ErrNilLogger, err)
+		assert.Nil(t, generator)
+	})
+	t.Run("unsupported key type should error", func(t *testing.T) {
+		t.Parallel()
+
+		generator, err := crypto.NewIdentityGeneratorWithKeyType(&testscommon.LoggerStub{}, "rsa")
+		assert.True(t, errors.Is(err, crypto.ErrUnsupportedKeyType))
+		assert.Nil(t, generator)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		generator, err := crypto.NewIdentityGeneratorWithKeyType(&testscommon.LoggerStub{}, p2p.Ed25519KeyType)
+		assert.NoError(t, err)
+		assert.NotNil(t, generator)
+	})
+}
+
 func TestIdentityGenerator_CreateP2PPrivateKey(t *testing.T) {
 	t.Parallel()
 
@@ -103,6 +132,24 @@ func TestIdentityGenerator_CreateRandomP2PIdentity(t *testing.T) {
 	assert.Equal(t, 39, len(pid2))
 }
 
+func TestIdentityGenerator_Ed25519Identities(t *testing.T) {
+	t.Parallel()
+
+	generator, _ := crypto.NewIdentityGeneratorWithKeyType(&testscommon.LoggerStub{}, p2p.Ed25519KeyType)
+	skBytes, pid, err := generator.CreateRandomP2PIdentity()
+	require.Nil(t, err)
+	assert.Equal(t, 64, len(skBytes))
+
+	keyType, err := crypto.KeyTypeFromPeerID(pid)
+	assert.Nil(t, err)
+	assert.Equal(t, p2p.Ed25519KeyType, keyType)
+
+	sk, err := generator.CreateP2PPrivateKey(skBytes)
+	require.Nil(t, err)
+	recoveredPid, _ := peer.IDFromPublicKey(sk.GetPublic())
+	assert.Equal(t, pid, core.PeerID(recoveredPid))
+}
+
 func TestIdentityGenerator_IsInterfaceNil(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,115 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"io"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+	crypto "github.com/TerraDharitri/drt-go-chain-crypto"
+	edSigning "github.com/TerraDharitri/drt-go-chain-crypto/signing/ed25519"
+	"github.com/TerraDharitri/drt-go-chain-crypto/signing/secp256k1"
+	libp2pCrypto "github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/crypto/pb"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// KeyTypeFromPeerID returns the type of the public key embedded in the provided peer ID
+func KeyTypeFromPeerID(pid core.PeerID) (p2p.KeyType, error) {
+	libp2pPid, err := peer.IDFromBytes(pid.Bytes())
+	if err != nil {
+		return "", err
+	}
+
+	pubKey, err := libp2pPid.ExtractPublicKey()
+	if err != nil {
+		return "", fmt.Errorf("cannot extract signing key: %s", err.Error())
+	}
+
+	switch pubKey.Type() {
+	case pb.KeyType_Secp256k1:
+		return p2p.Secp256k1KeyType, nil
+	case pb.KeyType_Ed25519:
+		return p2p.Ed25519KeyType, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedKeyType, pubKey.Type().String())
+	}
+}
+
+// KeyTypeFromRawPrivateKey returns the type of the provided raw private key bytes. The ed25519 private keys are
+// 64 bytes long (the seed followed by the public key) while the secp256k1 ones are 32 bytes long
+func KeyTypeFromRawPrivateKey(privateKeyBytes []byte) p2p.KeyType {
+	if len(privateKeyBytes) == ed25519.PrivateKeySize {
+		return p2p.Ed25519KeyType
+	}
+
+	return p2p.Secp256k1KeyType
+}
+
+func keyTypeFromRawPublicKey(publicKeyBytes []byte) p2p.KeyType {
+	if len(publicKeyBytes) == ed25519.PublicKeySize {
+		return p2p.Ed25519KeyType
+	}
+
+	return p2p.Secp256k1KeyType
+}
+
+// keyTypeFromSuite returns the key type of the provided suite. If the suite is not set, the key type is inferred
+// from the raw key bytes
+func keyTypeFromSuite(suite crypto.Suite, keyBytes []byte, inferFunc func(keyBytes []byte) p2p.KeyType) p2p.KeyType {
+	if suite == nil {
+		return inferFunc(keyBytes)
+	}
+
+	switch suite.String() {
+	case edSigning.ED25519:
+		return p2p.Ed25519KeyType
+	case secp256k1.Secp256k1Str:
+		return p2p.Secp256k1KeyType
+	default:
+		return inferFunc(keyBytes)
+	}
+}
+
+// UnmarshalLibp2pPrivateKey creates a libp2p private key of the provided type from the raw private key bytes
+func UnmarshalLibp2pPrivateKey(keyType p2p.KeyType, privateKeyBytes []byte) (libp2pCrypto.PrivKey, error) {
+	switch keyType {
+	case p2p.Secp256k1KeyType:
+		return libp2pCrypto.UnmarshalSecp256k1PrivateKey(privateKeyBytes)
+	case p2p.Ed25519KeyType:
+		return libp2pCrypto.UnmarshalEd25519PrivateKey(privateKeyBytes)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedKeyType, keyType)
+	}
+}
+
+// UnmarshalRawLibp2pPrivateKey creates a libp2p private key from the raw private key bytes, the key type being
+// inferred from the bytes length
+func UnmarshalRawLibp2pPrivateKey(privateKeyBytes []byte) (libp2pCrypto.PrivKey, error) {
+	return UnmarshalLibp2pPrivateKey(KeyTypeFromRawPrivateKey(privateKeyBytes), privateKeyBytes)
+}
+
+func unmarshalLibp2pPublicKey(keyType p2p.KeyType, publicKeyBytes []byte) (libp2pCrypto.PubKey, error) {
+	switch keyType {
+	case p2p.Secp256k1KeyType:
+		return libp2pCrypto.UnmarshalSecp256k1PublicKey(publicKeyBytes)
+	case p2p.Ed25519KeyType:
+		return libp2pCrypto.UnmarshalEd25519PublicKey(publicKeyBytes)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedKeyType, keyType)
+	}
+}
+
+func generateLibp2pPrivateKey(keyType p2p.KeyType, randReader io.Reader) (libp2pCrypto.PrivKey, error) {
+	switch keyType {
+	case p2p.Secp256k1KeyType:
+		prvKey, _, err := libp2pCrypto.GenerateSecp256k1Key(randReader)
+		return prvKey, err
+	case p2p.Ed25519KeyType:
+		prvKey, _, err := libp2pCrypto.GenerateEd25519Key(randReader)
+		return prvKey, err
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedKeyType, keyType)
+	}
+}
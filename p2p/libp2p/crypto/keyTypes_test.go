@@ -0,0 +1,131 @@
+package crypto_test
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	p2pCrypto "github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p/crypto"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+	"github.com/TerraDharitri/drt-go-chain-crypto/signing"
+	"github.com/TerraDharitri/drt-go-chain-crypto/signing/ed25519"
+	libp2pCrypto "github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyTypeFromPeerID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid peer ID should error", func(t *testing.T) {
+		t.Parallel()
+
+		keyType, err := p2pCrypto.KeyTypeFromPeerID("invalid peer ID")
+		assert.NotNil(t, err)
+		assert.Empty(t, keyType)
+	})
+	t.Run("secp256k1 peer ID should work", func(t *testing.T) {
+		t.Parallel()
+
+		sk, _, _ := libp2pCrypto.GenerateSecp256k1Key(rand.Reader)
+		pid, _ := peer.IDFromPublicKey(sk.GetPublic())
+
+		keyType, err := p2pCrypto.KeyTypeFromPeerID(core.PeerID(pid))
+		assert.Nil(t, err)
+		assert.Equal(t, p2p.Secp256k1KeyType, keyType)
+	})
+	t.Run("ed25519 peer ID should work", func(t *testing.T) {
+		t.Parallel()
+
+		sk, _, _ := libp2pCrypto.GenerateEd25519Key(rand.Reader)
+		pid, _ := peer.IDFromPublicKey(sk.GetPublic())
+
+		keyType, err := p2pCrypto.KeyTypeFromPeerID(core.PeerID(pid))
+		assert.Nil(t, err)
+		assert.Equal(t, p2p.Ed25519KeyType, keyType)
+	})
+	t.Run("peer ID without an embedded public key should error", func(t *testing.T) {
+		t.Parallel()
+
+		sk, _, _ := libp2pCrypto.GenerateECDSAKeyPair(rand.Reader)
+		pid, _ := peer.IDFromPublicKey(sk.GetPublic())
+
+		keyType, err := p2pCrypto.KeyTypeFromPeerID(core.PeerID(pid))
+		assert.NotNil(t, err)
+		assert.Empty(t, keyType)
+	})
+}
+
+func TestKeyTypeFromRawPrivateKey(t *testing.T) {
+	t.Parallel()
+
+	secpKey, _, _ := libp2pCrypto.GenerateSecp256k1Key(rand.Reader)
+	secpKeyBytes, _ := secpKey.Raw()
+	assert.Equal(t, p2p.Secp256k1KeyType, p2pCrypto.KeyTypeFromRawPrivateKey(secpKeyBytes))
+
+	edKey, _, _ := libp2pCrypto.GenerateEd25519Key(rand.Reader)
+	edKeyBytes, _ := edKey.Raw()
+	assert.Equal(t, p2p.Ed25519KeyType, p2pCrypto.KeyTypeFromRawPrivateKey(edKeyBytes))
+}
+
+func TestUnmarshalLibp2pPrivateKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unsupported key type should error", func(t *testing.T) {
+		t.Parallel()
+
+		sk, err := p2pCrypto.UnmarshalLibp2pPrivateKey("rsa", []byte("key"))
+		assert.True(t, errors.Is(err, p2pCrypto.ErrUnsupportedKeyType))
+		assert.Nil(t, sk)
+	})
+	t.Run("raw keys should be unmarshalled with the inferred type", func(t *testing.T) {
+		t.Parallel()
+
+		secpKey, _, _ := libp2pCrypto.GenerateSecp256k1Key(rand.Reader)
+		secpKeyBytes, _ := secpKey.Raw()
+		sk, err := p2pCrypto.UnmarshalRawLibp2pPrivateKey(secpKeyBytes)
+		assert.Nil(t, err)
+		assert.True(t, secpKey.Equals(sk))
+
+		edKey, _, _ := libp2pCrypto.GenerateEd25519Key(rand.Reader)
+		edKeyBytes, _ := edKey.Raw()
+		sk, err = p2pCrypto.UnmarshalRawLibp2pPrivateKey(edKeyBytes)
+		assert.Nil(t, err)
+		assert.True(t, edKey.Equals(sk))
+	})
+}
+
+func TestConvertPrivateKeyToLibp2pPrivateKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil private key should error", func(t *testing.T) {
+		t.Parallel()
+
+		sk, err := p2pCrypto.ConvertPrivateKeyToLibp2pPrivateKey(nil)
+		assert.Equal(t, p2pCrypto.ErrNilPrivateKey, err)
+		assert.Nil(t, sk)
+	})
+	t.Run("secp256k1 private key should work", func(t *testing.T) {
+		t.Parallel()
+
+		privateKey, _ := generatePrivateKey()
+		sk, err := p2pCrypto.ConvertPrivateKeyToLibp2pPrivateKey(privateKey)
+		require.Nil(t, err)
+		assert.Equal(t, libp2pCrypto.Secp256k1, int(sk.Type()))
+	})
+	t.Run("ed25519 private key should work", func(t *testing.T) {
+		t.Parallel()
+
+		keyGen := signing.NewKeyGenerator(ed25519.NewEd25519())
+		privateKey, publicKey := keyGen.GeneratePair()
+		sk, err := p2pCrypto.ConvertPrivateKeyToLibp2pPrivateKey(privateKey)
+		require.Nil(t, err)
+		assert.Equal(t, libp2pCrypto.Ed25519, int(sk.Type()))
+
+		pkBytes, _ := publicKey.ToByteArray()
+		libp2pPkBytes, _ := sk.GetPublic().Raw()
+		assert.Equal(t, pkBytes, libp2pPkBytes)
+	})
+}
@@ -0,0 +1,33 @@
+package keystore
+
+import "errors"
+
+// ErrNilPrivateKey signals that a nil private key was provided
+var ErrNilPrivateKey = errors.New("nil private key")
+
+// ErrEmptyPassphrase signals that an empty passphrase was provided
+var ErrEmptyPassphrase = errors.New("empty passphrase")
+
+// ErrInvalidPEMBlock signals that the provided data does not contain a valid P2P private key PEM block
+var ErrInvalidPEMBlock = errors.New("invalid P2P private key PEM block")
+
+// ErrUnknownKeyFileFormat signals that the provided data is neither a PEM block nor an encrypted JSON key file
+var ErrUnknownKeyFileFormat = errors.New("unknown key file format")
+
+// ErrUnsupportedKeyFileVersion signals that the encrypted JSON key file has an unsupported version
+var ErrUnsupportedKeyFileVersion = errors.New("unsupported key file version")
+
+// ErrUnsupportedKDF signals that an unsupported key derivation function was provided
+var ErrUnsupportedKDF = errors.New("unsupported key derivation function")
+
+// ErrKDFParamsTooHigh signals that the key derivation parameters exceed the accepted maximums
+var ErrKDFParamsTooHigh = errors.New("key derivation parameters too high")
+
+// ErrUnsupportedCipher signals that the encrypted JSON key file uses an unsupported cipher
+var ErrUnsupportedCipher = errors.New("unsupported cipher")
+
+// ErrWrongPassphrase signals that the key file could not be decrypted with the provided passphrase
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted key file")
+
+// ErrPeerIDMismatch signals that the stored peer ID does not match the one derived from the decrypted key
+var ErrPeerIDMismatch = errors.New("peer ID mismatch")
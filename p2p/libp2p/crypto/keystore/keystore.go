@@ -0,0 +1,481 @@
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	p2pCrypto "github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p/crypto"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+	crypto "github.com/TerraDharitri/drt-go-chain-crypto"
+	"github.com/TerraDharitri/drt-go-chain-crypto/signing"
+	"github.com/TerraDharitri/drt-go-chain-crypto/signing/ed25519"
+	"github.com/TerraDharitri/drt-go-chain-crypto/signing/secp256k1"
+	libp2pCrypto "github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// PEMBlockType is the type of the PEM blocks holding a P2P private key
+const PEMBlockType = "P2P PRIVATE KEY"
+
+const (
+	pemPeerIDHeader  = "Peer-Id"
+	pemKeyTypeHeader = "Key-Type"
+	keyFileVersion   = 1
+	aesGCMCipher     = "aes-256-gcm"
+	derivedKeyLength = 32
+	saltLength       = 32
+	keyFilePerm      = 0600
+)
+
+// the key derivation parameters are read from the key file, so they are bounded in order to prevent a crafted file
+// from exhausting the memory or the CPU of the node while loading it
+const (
+	maxScryptN      = 1 << 20
+	maxScryptR      = 16
+	maxScryptP      = 16
+	maxScryptMemory = 1 << 30 // bytes, scrypt uses 128 * N * R bytes
+	maxArgon2Time   = 16
+	maxArgon2Memory = 1 << 20 // KiB
+)
+
+// KDF defines the key derivation function used to obtain the encryption key from the passphrase
+type KDF string
+
+const (
+	// ScryptKDF defines the scrypt key derivation function
+	ScryptKDF KDF = "scrypt"
+
+	// Argon2idKDF defines the argon2id key derivation function
+	Argon2idKDF KDF = "argon2id"
+)
+
+// EncryptionParams holds the key derivation parameters used when a key is encrypted
+type EncryptionParams struct {
+	KDF           KDF
+	ScryptN       int
+	ScryptR       int
+	ScryptP       int
+	Argon2Time    uint32
+	Argon2Memory  uint32
+	Argon2Threads uint8
+}
+
+// DefaultEncryptionParams returns the recommended parameters for the provided key derivation function
+func DefaultEncryptionParams(kdf KDF) EncryptionParams {
+	return EncryptionParams{
+		KDF:           kdf,
+		ScryptN:       1 << 18,
+		ScryptR:       8,
+		ScryptP:       1,
+		Argon2Time:    3,
+		Argon2Memory:  64 * 1024,
+		Argon2Threads: 4,
+	}
+}
+
+// KeyInfo holds the public details of a P2P private key
+type KeyInfo struct {
+	KeyType p2p.KeyType
+	PeerID  core.PeerID
+}
+
+type encryptedKeyFile struct {
+	Version int           `json:"version"`
+	KeyType p2p.KeyType   `json:"keyType"`
+	PeerID  string        `json:"peerId"`
+	Crypto  cryptoSection `json:"crypto"`
+}
+
+type cryptoSection struct {
+	Cipher     string    `json:"cipher"`
+	CipherText string    `json:"ciphertext"`
+	Nonce      string    `json:"nonce"`
+	KDF        KDF       `json:"kdf"`
+	KDFParams  kdfParams `json:"kdfparams"`
+}
+
+type kdfParams struct {
+	Salt    string `json:"salt"`
+	DKLen   int    `json:"dklen"`
+	N       int    `json:"n,omitempty"`
+	R       int    `json:"r,omitempty"`
+	P       int    `json:"p,omitempty"`
+	Time    uint32 `json:"time,omitempty"`
+	Memory  uint32 `json:"memory,omitempty"`
+	Threads uint8  `json:"threads,omitempty"`
+}
+
+// GetKeyInfo returns the key type and the peer ID of the provided private key
+func GetKeyInfo(sk libp2pCrypto.PrivKey) (KeyInfo, error) {
+	if sk == nil {
+		return KeyInfo{}, ErrNilPrivateKey
+	}
+
+	pid, err := peer.IDFromPublicKey(sk.GetPublic())
+	if err != nil {
+		return KeyInfo{}, err
+	}
+
+	keyType, err := p2pCrypto.KeyTypeFromPeerID(core.PeerID(pid))
+	if err != nil {
+		return KeyInfo{}, err
+	}
+
+	return KeyInfo{
+		KeyType: keyType,
+		PeerID:  core.PeerID(pid),
+	}, nil
+}
+
+// EncodePEM encodes the provided private key as a plaintext PEM block. The peer ID and the key type are added as
+// informative headers
+func EncodePEM(sk libp2pCrypto.PrivKey) ([]byte, error) {
+	info, err := GetKeyInfo(sk)
+	if err != nil {
+		return nil, err
+	}
+
+	skBytes, err := libp2pCrypto.MarshalPrivateKey(sk)
+	if err != nil {
+		return nil, err
+	}
+
+	block := &pem.Block{
+		Type: PEMBlockType,
+		Headers: map[string]string{
+			pemPeerIDHeader:  info.PeerID.Pretty(),
+			pemKeyTypeHeader: string(info.KeyType),
+		},
+		Bytes: skBytes,
+	}
+
+	return pem.EncodeToMemory(block), nil
+}
+
+// DecodePEM decodes a private key from the provided PEM block
+func DecodePEM(data []byte) (libp2pCrypto.PrivKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != PEMBlockType {
+		return nil, ErrInvalidPEMBlock
+	}
+
+	sk, err := libp2pCrypto.UnmarshalPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPEMBlock, err.Error())
+	}
+
+	_, err = GetKeyInfo(sk)
+	if err != nil {
+		return nil, err
+	}
+
+	return sk, nil
+}
+
+// EncryptKey encrypts the provided private key with a key derived from the passphrase and returns the JSON key file
+func EncryptKey(sk libp2pCrypto.PrivKey, passphrase []byte, params EncryptionParams) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrEmptyPassphrase
+	}
+	info, err := GetKeyInfo(sk)
+	if err != nil {
+		return nil, err
+	}
+
+	skBytes, err := libp2pCrypto.MarshalPrivateKey(sk)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, saltLength)
+	_, err = rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	keyFile := encryptedKeyFile{
+		Version: keyFileVersion,
+		KeyType: info.KeyType,
+		PeerID:  info.PeerID.Pretty(),
+		Crypto: cryptoSection{
+			Cipher: aesGCMCipher,
+			KDF:    params.KDF,
+			KDFParams: kdfParams{
+				Salt:  hex.EncodeToString(salt),
+				DKLen: derivedKeyLength,
+			},
+		},
+	}
+	switch params.KDF {
+	case ScryptKDF:
+		keyFile.Crypto.KDFParams.N = params.ScryptN
+		keyFile.Crypto.KDFParams.R = params.ScryptR
+		keyFile.Crypto.KDFParams.P = params.ScryptP
+	case Argon2idKDF:
+		keyFile.Crypto.KDFParams.Time = params.Argon2Time
+		keyFile.Crypto.KDFParams.Memory = params.Argon2Memory
+		keyFile.Crypto.KDFParams.Threads = params.Argon2Threads
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedKDF, params.KDF)
+	}
+
+	aead, err := createAEAD(passphrase, keyFile.Crypto)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	cipherText := aead.Seal(nil, nonce, skBytes, additionalData(keyFile))
+	keyFile.Crypto.Nonce = hex.EncodeToString(nonce)
+	keyFile.Crypto.CipherText = hex.EncodeToString(cipherText)
+
+	return json.MarshalIndent(keyFile, "", "  ")
+}
+
+// DecryptKey decrypts the private key from the provided JSON key file
+func DecryptKey(data []byte, passphrase []byte) (libp2pCrypto.PrivKey, error) {
+	keyFile, err := unmarshalKeyFile(data)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := createAEAD(passphrase, keyFile.Crypto)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := hex.DecodeString(keyFile.Crypto.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, ErrWrongPassphrase
+	}
+	cipherText, err := hex.DecodeString(keyFile.Crypto.CipherText)
+	if err != nil {
+		return nil, err
+	}
+
+	skBytes, err := aead.Open(nil, nonce, cipherText, additionalData(*keyFile))
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+
+	sk, err := libp2pCrypto.UnmarshalPrivateKey(skBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := GetKeyInfo(sk)
+	if err != nil {
+		return nil, err
+	}
+	if info.PeerID.Pretty() != keyFile.PeerID {
+		return nil, fmt.Errorf("%w: stored %s, derived %s", ErrPeerIDMismatch, keyFile.PeerID, info.PeerID.Pretty())
+	}
+
+	return sk, nil
+}
+
+func unmarshalKeyFile(data []byte) (*encryptedKeyFile, error) {
+	keyFile := &encryptedKeyFile{}
+	err := json.Unmarshal(data, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	if keyFile.Version != keyFileVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedKeyFileVersion, keyFile.Version)
+	}
+	if keyFile.Crypto.Cipher != aesGCMCipher {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedCipher, keyFile.Crypto.Cipher)
+	}
+
+	return keyFile, nil
+}
+
+// additionalData binds the public fields of the key file to the cipher text so they can not be altered
+func additionalData(keyFile encryptedKeyFile) []byte {
+	return []byte(fmt.Sprintf("%d/%s/%s", keyFile.Version, keyFile.KeyType, keyFile.PeerID))
+}
+
+func createAEAD(passphrase []byte, section cryptoSection) (cipher.AEAD, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrEmptyPassphrase
+	}
+
+	derivedKey, err := deriveKey(passphrase, section.KDF, section.KDFParams)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(derivedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func deriveKey(passphrase []byte, kdf KDF, params kdfParams) ([]byte, error) {
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if params.DKLen != derivedKeyLength {
+		return nil, fmt.Errorf("%w: derived key length %d", ErrUnsupportedKDF, params.DKLen)
+	}
+
+	switch kdf {
+	case ScryptKDF:
+		err = checkScryptParams(params)
+		if err != nil {
+			return nil, err
+		}
+		return scrypt.Key(passphrase, salt, params.N, params.R, params.P, params.DKLen)
+	case Argon2idKDF:
+		if params.Time == 0 || params.Threads == 0 {
+			return nil, fmt.Errorf("%w: invalid argon2id parameters", ErrUnsupportedKDF)
+		}
+		if params.Time > maxArgon2Time || params.Memory > maxArgon2Memory {
+			return nil, fmt.Errorf("%w: argon2id time %d, memory %d, maximum time %d, maximum memory %d",
+				ErrKDFParamsTooHigh, params.Time, params.Memory, maxArgon2Time, maxArgon2Memory)
+		}
+		return argon2.IDKey(passphrase, salt, params.Time, params.Memory, params.Threads, uint32(params.DKLen)), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedKDF, kdf)
+	}
+}
+
+func checkScryptParams(params kdfParams) error {
+	if params.N <= 0 || params.R <= 0 || params.P <= 0 {
+		return fmt.Errorf("%w: invalid scrypt parameters", ErrUnsupportedKDF)
+	}
+	isTooHigh := params.N > maxScryptN || params.R > maxScryptR || params.P > maxScryptP ||
+		128*params.N*params.R > maxScryptMemory
+	if isTooHigh {
+		return fmt.Errorf("%w: scrypt N %d, r %d, p %d, maximum N %d, r %d, p %d, memory %d bytes",
+			ErrKDFParamsTooHigh, params.N, params.R, params.P, maxScryptN, maxScryptR, maxScryptP, maxScryptMemory)
+	}
+
+	return nil
+}
+
+// InspectKey returns the public details of the provided PEM block or JSON key file without decrypting it
+func InspectKey(data []byte) (KeyInfo, error) {
+	if isPEM(data) {
+		sk, err := DecodePEM(data)
+		if err != nil {
+			return KeyInfo{}, err
+		}
+
+		return GetKeyInfo(sk)
+	}
+
+	keyFile, err := unmarshalKeyFile(data)
+	if err != nil {
+		return KeyInfo{}, fmt.Errorf("%w: %s", ErrUnknownKeyFileFormat, err.Error())
+	}
+
+	pid, err := peer.Decode(keyFile.PeerID)
+	if err != nil {
+		return KeyInfo{}, err
+	}
+
+	return KeyInfo{
+		KeyType: keyFile.KeyType,
+		PeerID:  core.PeerID(pid),
+	}, nil
+}
+
+// LoadKey loads the private key from the provided PEM block or JSON key file. The passphrase is only used for the
+// JSON key files
+func LoadKey(data []byte, passphrase []byte) (libp2pCrypto.PrivKey, error) {
+	if isPEM(data) {
+		return DecodePEM(data)
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return nil, ErrUnknownKeyFileFormat
+	}
+
+	return DecryptKey(data, passphrase)
+}
+
+// LoadKeyFile loads the private key from the provided PEM or JSON key file
+func LoadKeyFile(filename string, passphrase []byte) (libp2pCrypto.PrivKey, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return LoadKey(data, passphrase)
+}
+
+// WriteKeyFile writes the key file data readable only by its owner. Existing files are never overwritten
+func WriteKeyFile(filename string, data []byte) error {
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, keyFilePerm)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(data)
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// ConvertToP2PPrivateKey converts the provided private key into the private key expected by the network messenger
+// arguments. The key generator matching the key can be created from the returned key's suite
+func ConvertToP2PPrivateKey(sk libp2pCrypto.PrivKey) (crypto.PrivateKey, error) {
+	info, err := GetKeyInfo(sk)
+	if err != nil {
+		return nil, err
+	}
+
+	skBytes, err := sk.Raw()
+	if err != nil {
+		return nil, err
+	}
+
+	var keyGen crypto.KeyGenerator
+	switch info.KeyType {
+	case p2p.Ed25519KeyType:
+		keyGen = signing.NewKeyGenerator(ed25519.NewEd25519())
+	default:
+		keyGen = signing.NewKeyGenerator(secp256k1.NewSecp256k1())
+	}
+
+	return keyGen.PrivateKeyFromByteArray(skBytes)
+}
+
+// LoadP2PPrivateKey loads the private key from the provided PEM or JSON key file so it can be used as the network
+// messenger's private key
+func LoadP2PPrivateKey(filename string, passphrase []byte) (crypto.PrivateKey, error) {
+	sk, err := LoadKeyFile(filename, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return ConvertToP2PPrivateKey(sk)
+}
+
+func isPEM(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("-----BEGIN"))
+}
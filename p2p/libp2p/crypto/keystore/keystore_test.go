@@ -0,0 +1,301 @@
+package keystore_test
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	p2pCrypto "github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p/crypto"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p/crypto/keystore"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+	libp2pCrypto "github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var passphrase = []byte("passphrase")
+
+func createTestEncryptionParams(kdf keystore.KDF) keystore.EncryptionParams {
+	params := keystore.DefaultEncryptionParams(kdf)
+	params.ScryptN = 1 << 10
+	params.Argon2Time = 1
+	params.Argon2Memory = 1024
+	params.Argon2Threads = 1
+
+	return params
+}
+
+func generateKeys() []libp2pCrypto.PrivKey {
+	secpKey, _, _ := libp2pCrypto.GenerateSecp256k1Key(rand.Reader)
+	edKey, _, _ := libp2pCrypto.GenerateEd25519Key(rand.Reader)
+
+	return []libp2pCrypto.PrivKey{secpKey, edKey}
+}
+
+func TestGetKeyInfo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil private key should error", func(t *testing.T) {
+		t.Parallel()
+
+		info, err := keystore.GetKeyInfo(nil)
+		assert.Equal(t, keystore.ErrNilPrivateKey, err)
+		assert.Empty(t, info)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		keys := generateKeys()
+		expectedKeyTypes := []p2p.KeyType{p2p.Secp256k1KeyType, p2p.Ed25519KeyType}
+		for i, sk := range keys {
+			info, err := keystore.GetKeyInfo(sk)
+			assert.Nil(t, err)
+			assert.Equal(t, expectedKeyTypes[i], info.KeyType)
+
+			pid, _ := peer.IDFromPublicKey(sk.GetPublic())
+			assert.Equal(t, core.PeerID(pid), info.PeerID)
+		}
+	})
+}
+
+func TestPEM(t *testing.T) {
+	t.Parallel()
+
+	t.Run("encode and decode should work", func(t *testing.T) {
+		t.Parallel()
+
+		for _, sk := range generateKeys() {
+			info, _ := keystore.GetKeyInfo(sk)
+			data, err := keystore.EncodePEM(sk)
+			require.Nil(t, err)
+			assert.True(t, strings.Contains(string(data), info.PeerID.Pretty()))
+
+			decoded, err := keystore.DecodePEM(data)
+			assert.Nil(t, err)
+			assert.True(t, sk.Equals(decoded))
+		}
+	})
+	t.Run("invalid PEM block should error", func(t *testing.T) {
+		t.Parallel()
+
+		sk, err := keystore.DecodePEM([]byte("not a pem"))
+		assert.Equal(t, keystore.ErrInvalidPEMBlock, err)
+		assert.Nil(t, sk)
+
+		sk, err = keystore.DecodePEM([]byte("-----BEGIN OTHER-----\nAAAA\n-----END OTHER-----\n"))
+		assert.Equal(t, keystore.ErrInvalidPEMBlock, err)
+		assert.Nil(t, sk)
+
+		sk, err = keystore.DecodePEM([]byte("-----BEGIN P2P PRIVATE KEY-----\nAAAA\n-----END P2P PRIVATE KEY-----\n"))
+		assert.True(t, errors.Is(err, keystore.ErrInvalidPEMBlock))
+		assert.Nil(t, sk)
+	})
+}
+
+func TestEncryptDecryptKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty passphrase should error", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := keystore.EncryptKey(generateKeys()[0], nil, createTestEncryptionParams(keystore.ScryptKDF))
+		assert.Equal(t, keystore.ErrEmptyPassphrase, err)
+		assert.Nil(t, data)
+	})
+	t.Run("unsupported KDF should error", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := keystore.EncryptKey(generateKeys()[0], passphrase, createTestEncryptionParams("pbkdf2"))
+		assert.True(t, errors.Is(err, keystore.ErrUnsupportedKDF))
+		assert.Nil(t, data)
+	})
+	t.Run("should work with scrypt and argon2id", func(t *testing.T) {
+		t.Parallel()
+
+		for _, kdf := range []keystore.KDF{keystore.ScryptKDF, keystore.Argon2idKDF} {
+			for _, sk := range generateKeys() {
+				data, err := keystore.EncryptKey(sk, passphrase, createTestEncryptionParams(kdf))
+				require.Nil(t, err)
+				assert.True(t, strings.Contains(string(data), string(kdf)))
+
+				decrypted, err := keystore.DecryptKey(data, passphrase)
+				assert.Nil(t, err)
+				assert.True(t, sk.Equals(decrypted))
+			}
+		}
+	})
+	t.Run("wrong passphrase should error", func(t *testing.T) {
+		t.Parallel()
+
+		data, _ := keystore.EncryptKey(generateKeys()[0], passphrase, createTestEncryptionParams(keystore.ScryptKDF))
+
+		sk, err := keystore.DecryptKey(data, []byte("wrong passphrase"))
+		assert.Equal(t, keystore.ErrWrongPassphrase, err)
+		assert.Nil(t, sk)
+
+		sk, err = keystore.DecryptKey(data, nil)
+		assert.Equal(t, keystore.ErrEmptyPassphrase, err)
+		assert.Nil(t, sk)
+	})
+	t.Run("altered peer ID should error", func(t *testing.T) {
+		t.Parallel()
+
+		keys := generateKeys()
+		data, _ := keystore.EncryptKey(keys[0], passphrase, createTestEncryptionParams(keystore.ScryptKDF))
+		otherInfo, _ := keystore.GetKeyInfo(keys[1])
+
+		keyFile := make(map[string]interface{})
+		_ = json.Unmarshal(data, &keyFile)
+		keyFile["peerId"] = otherInfo.PeerID.Pretty()
+		data, _ = json.Marshal(keyFile)
+
+		sk, err := keystore.DecryptKey(data, passphrase)
+		assert.Equal(t, keystore.ErrWrongPassphrase, err)
+		assert.Nil(t, sk)
+	})
+	t.Run("too high key derivation parameters should error", func(t *testing.T) {
+		t.Parallel()
+
+		testTooHigh := func(kdf keystore.KDF, param string, value uint64) {
+			data, _ := keystore.EncryptKey(generateKeys()[0], passphrase, createTestEncryptionParams(kdf))
+
+			keyFile := make(map[string]interface{})
+			_ = json.Unmarshal(data, &keyFile)
+			keyFile["crypto"].(map[string]interface{})["kdfparams"].(map[string]interface{})[param] = value
+			data, _ = json.Marshal(keyFile)
+
+			sk, err := keystore.DecryptKey(data, passphrase)
+			assert.True(t, errors.Is(err, keystore.ErrKDFParamsTooHigh), "%s %s", kdf, param)
+			assert.Nil(t, sk)
+		}
+
+		testTooHigh(keystore.ScryptKDF, "n", 1<<30)
+		testTooHigh(keystore.ScryptKDF, "r", 1<<20)
+		testTooHigh(keystore.ScryptKDF, "p", 1<<20)
+		testTooHigh(keystore.Argon2idKDF, "time", 1<<31)
+		testTooHigh(keystore.Argon2idKDF, "memory", 1<<31)
+
+		params := createTestEncryptionParams(keystore.ScryptKDF)
+		params.ScryptN = 1 << 20
+		params.ScryptR = 16
+		data, err := keystore.EncryptKey(generateKeys()[0], passphrase, params)
+		assert.True(t, errors.Is(err, keystore.ErrKDFParamsTooHigh))
+		assert.Nil(t, data)
+	})
+	t.Run("unsupported version should error", func(t *testing.T) {
+		t.Parallel()
+
+		data, _ := keystore.EncryptKey(generateKeys()[0], passphrase, createTestEncryptionParams(keystore.ScryptKDF))
+		data = []byte(strings.Replace(string(data), `"version": 1`, `"version": 2`, 1))
+
+		sk, err := keystore.DecryptKey(data, passphrase)
+		assert.True(t, errors.Is(err, keystore.ErrUnsupportedKeyFileVersion))
+		assert.Nil(t, sk)
+	})
+}
+
+func TestInspectKey(t *testing.T) {
+	t.Parallel()
+
+	sk := generateKeys()[1]
+	expectedInfo, _ := keystore.GetKeyInfo(sk)
+
+	pemData, _ := keystore.EncodePEM(sk)
+	info, err := keystore.InspectKey(pemData)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedInfo, info)
+
+	jsonData, _ := keystore.EncryptKey(sk, passphrase, createTestEncryptionParams(keystore.ScryptKDF))
+	info, err = keystore.InspectKey(jsonData)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedInfo, info)
+
+	info, err = keystore.InspectKey([]byte("raw key"))
+	assert.True(t, errors.Is(err, keystore.ErrUnknownKeyFileFormat))
+	assert.Empty(t, info)
+}
+
+func TestLoadKeyFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown format should error", func(t *testing.T) {
+		t.Parallel()
+
+		sk, err := keystore.LoadKey([]byte("0123456789abcdef"), passphrase)
+		assert.Equal(t, keystore.ErrUnknownKeyFileFormat, err)
+		assert.Nil(t, sk)
+	})
+	t.Run("missing file should error", func(t *testing.T) {
+		t.Parallel()
+
+		sk, err := keystore.LoadKeyFile(filepath.Join(t.TempDir(), "missing"), passphrase)
+		assert.NotNil(t, err)
+		assert.Nil(t, sk)
+	})
+	t.Run("should load PEM and JSON key files", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		sk := generateKeys()[0]
+
+		pemData, _ := keystore.EncodePEM(sk)
+		pemFile := filepath.Join(dir, "key.pem")
+		require.Nil(t, keystore.WriteKeyFile(pemFile, pemData))
+
+		jsonData, _ := keystore.EncryptKey(sk, passphrase, createTestEncryptionParams(keystore.Argon2idKDF))
+		jsonFile := filepath.Join(dir, "key.json")
+		require.Nil(t, keystore.WriteKeyFile(jsonFile, jsonData))
+
+		loaded, err := keystore.LoadKeyFile(pemFile, nil)
+		assert.Nil(t, err)
+		assert.True(t, sk.Equals(loaded))
+
+		loaded, err = keystore.LoadKeyFile(jsonFile, passphrase)
+		assert.Nil(t, err)
+		assert.True(t, sk.Equals(loaded))
+	})
+}
+
+func TestWriteKeyFile(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "key.pem")
+	err := keystore.WriteKeyFile(filename, []byte("data"))
+	require.Nil(t, err)
+
+	stat, err := os.Stat(filename)
+	require.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), stat.Mode().Perm())
+
+	err = keystore.WriteKeyFile(filename, []byte("other data"))
+	assert.True(t, errors.Is(err, os.ErrExist))
+
+	data, _ := os.ReadFile(filename)
+	assert.Equal(t, []byte("data"), data)
+}
+
+func TestLoadP2PPrivateKey(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for i, sk := range generateKeys() {
+		expectedInfo, _ := keystore.GetKeyInfo(sk)
+		jsonData, _ := keystore.EncryptKey(sk, passphrase, createTestEncryptionParams(keystore.ScryptKDF))
+		filename := filepath.Join(dir, string(rune('a'+i)))
+		require.Nil(t, keystore.WriteKeyFile(filename, jsonData))
+
+		privateKey, err := keystore.LoadP2PPrivateKey(filename, passphrase)
+		require.Nil(t, err)
+
+		libp2pKey, err := p2pCrypto.ConvertPrivateKeyToLibp2pPrivateKey(privateKey)
+		require.Nil(t, err)
+		pid, _ := peer.IDFromPublicKey(libp2pKey.GetPublic())
+		assert.Equal(t, expectedInfo.PeerID, core.PeerID(pid))
+	}
+}
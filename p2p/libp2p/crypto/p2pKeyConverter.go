@@ -6,7 +6,6 @@ import (
 	"github.com/TerraDharitri/drt-go-chain-core/core"
 	"github.com/TerraDharitri/drt-go-chain-core/core/check"
 	crypto "github.com/TerraDharitri/drt-go-chain-crypto"
-	libp2pCrypto "github.com/libp2p/go-libp2p/core/crypto"
 	"github.com/libp2p/go-libp2p/core/peer"
 )
 
@@ -49,7 +48,8 @@ func (converter *p2pKeyConverter) ConvertPublicKeyToPeerID(pk crypto.PublicKey)
 		return "", err
 	}
 
-	libp2pPk, err := libp2pCrypto.UnmarshalSecp256k1PublicKey(pkBytes)
+	keyType := keyTypeFromSuite(pk.Suite(), pkBytes, keyTypeFromRawPublicKey)
+	libp2pPk, err := unmarshalLibp2pPublicKey(keyType, pkBytes)
 	if err != nil {
 		return "", err
 	}
@@ -7,8 +7,11 @@ import (
 	p2pCrypto "github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p/crypto"
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p/mock"
 	"github.com/TerraDharitri/drt-go-chain-communication/testscommon"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
 	"github.com/TerraDharitri/drt-go-chain-crypto/signing"
+	"github.com/TerraDharitri/drt-go-chain-crypto/signing/ed25519"
 	"github.com/TerraDharitri/drt-go-chain-crypto/signing/secp256k1"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -64,6 +67,24 @@ func TestConvertPublicKeyToPeerID(t *testing.T) {
 		assert.NotEmpty(t, pid)
 		assert.Nil(t, err)
 	})
+	t.Run("should work using a generated ed25519 key with the KeyGenerator", func(t *testing.T) {
+		t.Parallel()
+
+		keyGen := signing.NewKeyGenerator(ed25519.NewEd25519())
+		sk, pk := keyGen.GeneratePair()
+
+		conv := p2pCrypto.NewP2PKeyConverter()
+		pid, err := conv.ConvertPublicKeyToPeerID(pk)
+		assert.Nil(t, err)
+
+		recoveredPk, err := conv.ConvertPeerIDToPublicKey(keyGen, pid)
+		assert.Nil(t, err)
+		assert.Equal(t, pk, recoveredPk)
+
+		libp2pSk, _ := p2pCrypto.ConvertPrivateKeyToLibp2pPrivateKey(sk)
+		expectedPid, _ := peer.IDFromPublicKey(libp2pSk.GetPublic())
+		assert.Equal(t, core.PeerID(expectedPid), pid)
+	})
 	t.Run("should work using a generated identity", func(t *testing.T) {
 		t.Parallel()
 
@@ -2,11 +2,17 @@ package crypto
 
 import (
 	"crypto/sha256"
+	"fmt"
 
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
 	"github.com/TerraDharitri/drt-go-chain-core/core"
 	"github.com/TerraDharitri/drt-go-chain-core/core/check"
 	crypto "github.com/TerraDharitri/drt-go-chain-crypto"
+	"github.com/TerraDharitri/drt-go-chain-crypto/signing"
+	"github.com/TerraDharitri/drt-go-chain-crypto/signing/ed25519"
+	ed25519SingleSig "github.com/TerraDharitri/drt-go-chain-crypto/signing/ed25519/singlesig"
+	"github.com/TerraDharitri/drt-go-chain-crypto/signing/secp256k1"
+	secp256k1SingleSig "github.com/TerraDharitri/drt-go-chain-crypto/signing/secp256k1/singlesig"
 )
 
 // ArgsP2pSignerWrapper defines the arguments needed to create a p2p signer wrapper
@@ -17,14 +23,20 @@ type ArgsP2pSignerWrapper struct {
 	P2PKeyConverter p2p.P2PKeyConverter
 }
 
+type keyTypeHandler struct {
+	signer crypto.SingleSigner
+	keyGen crypto.KeyGenerator
+}
+
 type p2pSignerWrapper struct {
-	privateKey crypto.PrivateKey
-	signer     crypto.SingleSigner
-	keyGen     crypto.KeyGenerator
-	p2pKeyConv p2p.P2PKeyConverter
+	privateKey      crypto.PrivateKey
+	signer          crypto.SingleSigner
+	p2pKeyConv      p2p.P2PKeyConverter
+	keyTypeHandlers map[p2p.KeyType]keyTypeHandler
 }
 
-// NewP2PSignerWrapper creates a new p2pSigner instance
+// NewP2PSignerWrapper creates a new p2pSigner instance. The provided signer and key generator handle the keys of the
+// same type as the provided private key, the other supported key types being handled with their default implementations
 func NewP2PSignerWrapper(args ArgsP2pSignerWrapper) (*p2pSignerWrapper, error) {
 	err := checkArgs(args)
 	if err != nil {
@@ -32,10 +44,10 @@ func NewP2PSignerWrapper(args ArgsP2pSignerWrapper) (*p2pSignerWrapper, error) {
 	}
 
 	return &p2pSignerWrapper{
-		privateKey: args.PrivateKey,
-		signer:     args.Signer,
-		keyGen:     args.KeyGen,
-		p2pKeyConv: args.P2PKeyConverter,
+		privateKey:      args.PrivateKey,
+		signer:          args.Signer,
+		p2pKeyConv:      args.P2PKeyConverter,
+		keyTypeHandlers: createKeyTypeHandlers(args),
 	}, nil
 }
 
@@ -56,6 +68,28 @@ func checkArgs(args ArgsP2pSignerWrapper) error {
 	return nil
 }
 
+func createKeyTypeHandlers(args ArgsP2pSignerWrapper) map[p2p.KeyType]keyTypeHandler {
+	handlers := map[p2p.KeyType]keyTypeHandler{
+		p2p.Secp256k1KeyType: {
+			signer: &secp256k1SingleSig.Secp256k1Signer{},
+			keyGen: signing.NewKeyGenerator(secp256k1.NewSecp256k1()),
+		},
+		p2p.Ed25519KeyType: {
+			signer: &ed25519SingleSig.Ed25519Signer{},
+			keyGen: signing.NewKeyGenerator(ed25519.NewEd25519()),
+		},
+	}
+
+	skBytes, _ := args.PrivateKey.ToByteArray()
+	ownKeyType := keyTypeFromSuite(args.PrivateKey.Suite(), skBytes, KeyTypeFromRawPrivateKey)
+	handlers[ownKeyType] = keyTypeHandler{
+		signer: args.Signer,
+		keyGen: args.KeyGen,
+	}
+
+	return handlers
+}
+
 // Sign will sign the hash of the payload with the internal private key
 func (psw *p2pSignerWrapper) Sign(payload []byte) ([]byte, error) {
 	// added hash over the payload to comply with libp2p internal implementation
@@ -65,14 +99,23 @@ func (psw *p2pSignerWrapper) Sign(payload []byte) ([]byte, error) {
 
 // Verify will check that the (hash of the payload, peer ID, signature) tuple is valid or not
 func (psw *p2pSignerWrapper) Verify(payload []byte, pid core.PeerID, signature []byte) error {
-	pubKey, err := psw.p2pKeyConv.ConvertPeerIDToPublicKey(psw.keyGen, pid)
+	keyType, err := KeyTypeFromPeerID(pid)
+	if err != nil {
+		return err
+	}
+	handler, err := psw.getKeyTypeHandler(keyType)
+	if err != nil {
+		return err
+	}
+
+	pubKey, err := psw.p2pKeyConv.ConvertPeerIDToPublicKey(handler.keyGen, pid)
 	if err != nil {
 		return err
 	}
 
 	// added hash over the payload to comply with libp2p internal implementation
 	hash := sha256.Sum256(payload)
-	err = psw.signer.Verify(pubKey, hash[:], signature)
+	err = handler.signer.Verify(pubKey, hash[:], signature)
 	if err != nil {
 		return err
 	}
@@ -80,14 +123,29 @@ func (psw *p2pSignerWrapper) Verify(payload []byte, pid core.PeerID, signature [
 	return nil
 }
 
-// SignUsingPrivateKey will sign the hash of the payload with provided private key bytes
+// SignUsingPrivateKey will sign the hash of the payload with provided private key bytes. The key type is inferred from
+// the private key bytes length
 func (psw *p2pSignerWrapper) SignUsingPrivateKey(skBytes []byte, payload []byte) ([]byte, error) {
-	sk, err := psw.keyGen.PrivateKeyFromByteArray(skBytes)
+	handler, err := psw.getKeyTypeHandler(KeyTypeFromRawPrivateKey(skBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	sk, err := handler.keyGen.PrivateKeyFromByteArray(skBytes)
 	if err != nil {
 		return nil, err
 	}
 
 	// added hash over the payload to comply with libp2p internal implementation
 	hash := sha256.Sum256(payload)
-	return psw.signer.Sign(sk, hash[:])
+	return handler.signer.Sign(sk, hash[:])
+}
+
+func (psw *p2pSignerWrapper) getKeyTypeHandler(keyType p2p.KeyType) (keyTypeHandler, error) {
+	handler, found := psw.keyTypeHandlers[keyType]
+	if !found {
+		return keyTypeHandler{}, fmt.Errorf("%w: %s", ErrUnsupportedKeyType, keyType)
+	}
+
+	return handler, nil
 }
@@ -6,11 +6,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
 	p2pCrypto "github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p/crypto"
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p/mock"
+	"github.com/TerraDharitri/drt-go-chain-communication/testscommon"
 	"github.com/TerraDharitri/drt-go-chain-core/core"
 	crypto "github.com/TerraDharitri/drt-go-chain-crypto"
 	"github.com/TerraDharitri/drt-go-chain-crypto/signing"
+	"github.com/TerraDharitri/drt-go-chain-crypto/signing/ed25519"
+	edSingleSig "github.com/TerraDharitri/drt-go-chain-crypto/signing/ed25519/singlesig"
 	"github.com/TerraDharitri/drt-go-chain-crypto/signing/secp256k1"
 	"github.com/TerraDharitri/drt-go-chain-crypto/signing/secp256k1/singlesig"
 	libp2pCrypto "github.com/libp2p/go-libp2p/core/crypto"
@@ -290,6 +294,67 @@ func TestP2PSigner_FullTest(t *testing.T) {
 	})
 }
 
+func TestP2PSigner_MixedKeyTypes(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte("payload")
+
+	secpKeyGen := signing.NewKeyGenerator(secp256k1.NewSecp256k1())
+	edKeyGen := signing.NewKeyGenerator(ed25519.NewEd25519())
+
+	secpPrivateKey, _ := secpKeyGen.GeneratePair()
+	secpSigner, _ := p2pCrypto.NewP2PSignerWrapper(p2pCrypto.ArgsP2pSignerWrapper{
+		PrivateKey:      secpPrivateKey,
+		Signer:          &singlesig.Secp256k1Signer{},
+		KeyGen:          secpKeyGen,
+		P2PKeyConverter: p2pCrypto.NewP2PKeyConverter(),
+	})
+	secpLibp2pKey, _ := p2pCrypto.ConvertPrivateKeyToLibp2pPrivateKey(secpPrivateKey)
+	secpPid, _ := peer.IDFromPublicKey(secpLibp2pKey.GetPublic())
+
+	edPrivateKey, _ := edKeyGen.GeneratePair()
+	edSigner, _ := p2pCrypto.NewP2PSignerWrapper(p2pCrypto.ArgsP2pSignerWrapper{
+		PrivateKey:      edPrivateKey,
+		Signer:          &edSingleSig.Ed25519Signer{},
+		KeyGen:          edKeyGen,
+		P2PKeyConverter: p2pCrypto.NewP2PKeyConverter(),
+	})
+	edLibp2pKey, _ := p2pCrypto.ConvertPrivateKeyToLibp2pPrivateKey(edPrivateKey)
+	edPid, _ := peer.IDFromPublicKey(edLibp2pKey.GetPublic())
+
+	t.Run("signatures should be verified by a signer of the other key type", func(t *testing.T) {
+		t.Parallel()
+
+		secpSig, err := secpSigner.Sign(payload)
+		require.Nil(t, err)
+		edSig, err := edSigner.Sign(payload)
+		require.Nil(t, err)
+
+		assert.Nil(t, edSigner.Verify(payload, core.PeerID(secpPid), secpSig))
+		assert.Nil(t, secpSigner.Verify(payload, core.PeerID(edPid), edSig))
+		assert.NotNil(t, secpSigner.Verify(payload, core.PeerID(edPid), secpSig))
+	})
+	t.Run("sign using private keys of both types", func(t *testing.T) {
+		t.Parallel()
+
+		generator, _ := p2pCrypto.NewIdentityGeneratorWithKeyType(&testscommon.LoggerStub{}, p2p.Ed25519KeyType)
+		edSkBytes, edIdentityPid, err := generator.CreateRandomP2PIdentity()
+		require.Nil(t, err)
+
+		generator, _ = p2pCrypto.NewIdentityGenerator(&testscommon.LoggerStub{})
+		secpSkBytes, secpIdentityPid, err := generator.CreateRandomP2PIdentity()
+		require.Nil(t, err)
+
+		sig, err := secpSigner.SignUsingPrivateKey(edSkBytes, payload)
+		require.Nil(t, err)
+		assert.Nil(t, secpSigner.Verify(payload, edIdentityPid, sig))
+
+		sig, err = edSigner.SignUsingPrivateKey(secpSkBytes, payload)
+		require.Nil(t, err)
+		assert.Nil(t, edSigner.Verify(payload, secpIdentityPid, sig))
+	})
+}
+
 func TestP2pSigner_ConcurrentOperations(t *testing.T) {
 	t.Parallel()
 
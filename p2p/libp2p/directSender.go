@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/config"
 	"github.com/TerraDharitri/drt-go-chain-core/core"
 	"github.com/TerraDharitri/drt-go-chain-core/core/check"
 	ggio "github.com/gogo/protobuf/io"
@@ -29,18 +30,29 @@ const timeSeenMessages = time.Second * 120
 const maxMutexes = 10000
 const sequenceNumberSize = 8
 
+// any peer can send unsigned direct messages, so the warning about them is logged at most once in this interval
+const unsignedMessagesWarningInterval = time.Minute
+
 type directSender struct {
-	counter           uint64
-	ctx               context.Context
-	hostP2P           host.Host
-	mutMessageHandler sync.RWMutex
-	messageHandler    p2p.MessageHandler
-	mutSeenMessages   sync.Mutex
-	seenMessages      *timecache.TimeCache
-	mutexForPeer      *MutexHolder
-	signer            p2p.SignerVerifier
-	marshaller        p2p.Marshaller
-	log               p2p.Logger
+	counter                uint64
+	numUnsignedAccepted    uint64
+	numUnsignedRejected    uint64
+	numInvalidSignatures   uint64
+	lastUnsignedWarning    int64
+	ctx                    context.Context
+	hostP2P                host.Host
+	mutMessageHandler      sync.RWMutex
+	messageHandler         p2p.MessageHandler
+	mutSeenMessages        sync.Mutex
+	seenMessages           *timecache.TimeCache
+	mutexForPeer           *MutexHolder
+	signer                 p2p.SignerVerifier
+	marshaller             p2p.Marshaller
+	log                    p2p.Logger
+	mutSignaturePolicy     sync.RWMutex
+	signaturePolicy        string
+	blacklistUnsignedPeers bool
+	connMonitor            ConnectionMonitor
 }
 
 // NewDirectSender returns a new instance of direct sender object
@@ -74,14 +86,15 @@ func NewDirectSender(
 	}
 
 	ds := &directSender{
-		counter:      uint64(time.Now().UnixNano()),
-		ctx:          ctx,
-		hostP2P:      h,
-		seenMessages: timecache.NewTimeCache(timeSeenMessages),
-		mutexForPeer: mutexForPeer,
-		signer:       signer,
-		marshaller:   marshaller,
-		log:          logger,
+		counter:         uint64(time.Now().UnixNano()),
+		ctx:             ctx,
+		hostP2P:         h,
+		seenMessages:    timecache.NewTimeCache(timeSeenMessages),
+		mutexForPeer:    mutexForPeer,
+		signer:          signer,
+		marshaller:      marshaller,
+		log:             logger,
+		signaturePolicy: p2p.DisabledSignaturePolicy,
 	}
 
 	// wire-up a handler for direct messages
@@ -103,6 +116,41 @@ func (ds *directSender) RegisterDirectMessageProcessor(handler p2p.MessageHandle
 	return nil
 }
 
+// SetSignaturePolicy sets how the unsigned direct messages are handled. While in the enforce mode, the peers sending
+// unsigned messages can be blacklisted using the peer denial evaluator of the provided connection monitor
+func (ds *directSender) SetSignaturePolicy(cfg config.DirectMessagesConfig, connMonitor ConnectionMonitor) error {
+	policy := cfg.SignaturePolicy
+	if len(policy) == 0 {
+		policy = p2p.DisabledSignaturePolicy
+	}
+
+	switch policy {
+	case p2p.DisabledSignaturePolicy, p2p.WarnSignaturePolicy, p2p.EnforceSignaturePolicy:
+	default:
+		return fmt.Errorf("%w: %s", p2p.ErrUnknownSignaturePolicy, policy)
+	}
+	if cfg.BlacklistUnsignedPeers && check.IfNil(connMonitor) {
+		return p2p.ErrNilConnectionMonitor
+	}
+
+	ds.mutSignaturePolicy.Lock()
+	ds.signaturePolicy = policy
+	ds.blacklistUnsignedPeers = cfg.BlacklistUnsignedPeers
+	ds.connMonitor = connMonitor
+	ds.mutSignaturePolicy.Unlock()
+
+	return nil
+}
+
+// GetSignatureMetrics returns the counters of the direct messages signature checks
+func (ds *directSender) GetSignatureMetrics() p2p.DirectMessageSignatureMetrics {
+	return p2p.DirectMessageSignatureMetrics{
+		NumUnsignedAccepted:  atomic.LoadUint64(&ds.numUnsignedAccepted),
+		NumUnsignedRejected:  atomic.LoadUint64(&ds.numUnsignedRejected),
+		NumInvalidSignatures: atomic.LoadUint64(&ds.numInvalidSignatures),
+	}
+}
+
 func (ds *directSender) directStreamHandler(s network.Stream) {
 	reader := ggio.NewDelimitedReader(s, maxSendBuffSize)
 
@@ -221,7 +269,7 @@ func (ds *directSender) Send(topic string, buff []byte, peer core.PeerID) error
 		return err
 	}
 
-	msg, err := ds.createMessage(topic, buff, conn)
+	msg, err := ds.createMessage(topic, buff, conn.LocalPeer(), ds.NextSequenceNumber())
 	if err != nil {
 		return err
 	}
@@ -291,13 +339,28 @@ func (ds *directSender) getOrCreateStream(conn network.Conn) (network.Stream, er
 	return foundStream, nil
 }
 
-func (ds *directSender) createMessage(topic string, buff []byte, conn network.Conn) (*pubsubPb.Message, error) {
-	seqno := ds.NextSequenceNumber()
+// SignSelfMessage returns the signature of a direct message sent to self. The signature policy is applied as for the
+// messages received from the connected peers
+func (ds *directSender) SignSelfMessage(topic string, buff []byte, seqNo []byte) ([]byte, error) {
+	msg, err := ds.createMessage(topic, buff, ds.hostP2P.ID(), seqNo)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ds.checkSig(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return msg.Signature, nil
+}
+
+func (ds *directSender) createMessage(topic string, buff []byte, from peer.ID, seqNo []byte) (*pubsubPb.Message, error) {
 	mes := pubsubPb.Message{}
 	mes.Data = buff
 	mes.Topic = &topic
-	mes.From = []byte(conn.LocalPeer())
-	mes.Seqno = seqno
+	mes.From = []byte(from)
+	mes.Seqno = seqNo
 	mes.Key = nil
 
 	buff, err := mes.Marshal()
@@ -317,7 +380,7 @@ func (ds *directSender) createMessage(topic string, buff []byte, conn network.Co
 
 func (ds *directSender) checkSig(message *pubsubPb.Message) error {
 	if len(message.Signature) == 0 {
-		return nil // TODO will remove this in the future
+		return ds.checkUnsignedMessage(message)
 	}
 
 	copyMessage := *message
@@ -331,7 +394,83 @@ func (ds *directSender) checkSig(message *pubsubPb.Message) error {
 
 	buff = withSignPrefix(buff)
 
-	return ds.signer.Verify(buff, core.PeerID(message.From), message.Signature)
+	err = ds.signer.Verify(buff, core.PeerID(message.From), message.Signature)
+	if err != nil {
+		atomic.AddUint64(&ds.numInvalidSignatures, 1)
+	}
+
+	return err
+}
+
+func (ds *directSender) checkUnsignedMessage(message *pubsubPb.Message) error {
+	ds.mutSignaturePolicy.RLock()
+	policy := ds.signaturePolicy
+	blacklistUnsignedPeers := ds.blacklistUnsignedPeers
+	connMonitor := ds.connMonitor
+	ds.mutSignaturePolicy.RUnlock()
+
+	pid := core.PeerID(message.From)
+	switch policy {
+	case p2p.EnforceSignaturePolicy:
+		atomic.AddUint64(&ds.numUnsignedRejected, 1)
+		if blacklistUnsignedPeers {
+			ds.blacklistPeer(pid, connMonitor)
+		}
+
+		return fmt.Errorf("%w from peer %s", p2p.ErrUnsignedDirectMessage, pid.Pretty())
+	case p2p.WarnSignaturePolicy:
+		ds.log.Debug("unsigned direct message received", "from", pid.Pretty(), "topic", message.GetTopic())
+		ds.warnUnsignedMessages(pid, message.GetTopic())
+	}
+
+	atomic.AddUint64(&ds.numUnsignedAccepted, 1)
+
+	return nil
+}
+
+func (ds *directSender) warnUnsignedMessages(pid core.PeerID, topic string) {
+	now := time.Now().UnixNano()
+	lastWarning := atomic.LoadInt64(&ds.lastUnsignedWarning)
+	if now-lastWarning < int64(unsignedMessagesWarningInterval) {
+		return
+	}
+	if !atomic.CompareAndSwapInt64(&ds.lastUnsignedWarning, lastWarning, now) {
+		return
+	}
+
+	ds.log.Warn("unsigned direct messages received",
+		"last from", pid.Pretty(),
+		"last topic", topic,
+		"total accepted", atomic.LoadUint64(&ds.numUnsignedAccepted)+1,
+	)
+}
+
+func (ds *directSender) blacklistPeer(pid core.PeerID, connMonitor ConnectionMonitor) {
+	if pid == core.PeerID(ds.hostP2P.ID()) {
+		return
+	}
+	if connMonitor.PeerDenialEvaluator().IsDenied(pid) {
+		return
+	}
+
+	ds.log.Debug("blacklisted due to unsigned direct message",
+		"pid", pid.Pretty(),
+		"time", p2p.WrongP2PMessageBlacklistDuration,
+	)
+
+	err := upsertDeniedPeer(
+		connMonitor.PeerDenialEvaluator(),
+		pid,
+		p2p.WrongP2PMessageBlacklistDuration,
+		unsignedDirectMessageReason,
+		directSenderDenialSource,
+	)
+	if err != nil {
+		ds.log.Warn("error blacklisting peer ID in direct sender",
+			"pid", pid.Pretty(),
+			"error", err.Error(),
+		)
+	}
 }
 
 func withSignPrefix(bytes []byte) []byte {
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/config"
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p/data"
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p"
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p/mock"
@@ -929,3 +930,262 @@ func TestDirectSender_ProcessReceivedDirectMessageNewMessageFails(t *testing.T)
 
 	assert.True(t, strings.Contains(err.Error(), expectedErr.Error()))
 }
+
+func createUnsignedDirectMessage(id peer.ID, seqNo string) *pb.Message {
+	marshaller := &testscommon.MarshallerMock{}
+	innerMessage := &data.TopicMessage{
+		Payload:   []byte("data"),
+		Timestamp: time.Now().Unix(),
+		Version:   libp2p.CurrentTopicMessageVersion,
+	}
+	buff, _ := marshaller.Marshal(innerMessage)
+	topic := "topic"
+
+	return &pb.Message{
+		Data:  buff,
+		From:  []byte(id),
+		Seqno: []byte(seqNo),
+		Topic: &topic,
+	}
+}
+
+func TestDirectSender_SetSignaturePolicy(t *testing.T) {
+	t.Parallel()
+
+	ds, _ := libp2p.NewDirectSender(
+		context.Background(),
+		generateHostStub(),
+		&mock.P2PSignerStub{},
+		&testscommon.MarshallerMock{},
+		&testscommon.LoggerStub{},
+	)
+
+	t.Run("unknown policy should error", func(t *testing.T) {
+		err := ds.SetSignaturePolicy(config.DirectMessagesConfig{SignaturePolicy: "unknown"}, &mock.ConnectionMonitorStub{})
+		assert.True(t, errors.Is(err, p2p.ErrUnknownSignaturePolicy))
+	})
+	t.Run("blacklisting without connection monitor should error", func(t *testing.T) {
+		cfg := config.DirectMessagesConfig{
+			SignaturePolicy:        p2p.EnforceSignaturePolicy,
+			BlacklistUnsignedPeers: true,
+		}
+		err := ds.SetSignaturePolicy(cfg, nil)
+		assert.Equal(t, p2p.ErrNilConnectionMonitor, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		policies := []string{"", p2p.DisabledSignaturePolicy, p2p.WarnSignaturePolicy, p2p.EnforceSignaturePolicy}
+		for _, policy := range policies {
+			err := ds.SetSignaturePolicy(config.DirectMessagesConfig{SignaturePolicy: policy}, nil)
+			assert.Nil(t, err)
+		}
+	})
+}
+
+func TestDirectSender_ProcessReceivedDirectMessageSignaturePolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("off and warn policies should accept unsigned messages", func(t *testing.T) {
+		t.Parallel()
+
+		for _, policy := range []string{p2p.DisabledSignaturePolicy, p2p.WarnSignaturePolicy} {
+			numWarnings := 0
+			ds, _ := libp2p.NewDirectSender(
+				context.Background(),
+				generateHostStub(),
+				&mock.P2PSignerStub{},
+				&testscommon.MarshallerMock{},
+				&testscommon.LoggerStub{
+					WarnCalled: func(message string, args ...interface{}) {
+						numWarnings++
+					},
+				},
+			)
+			_ = ds.RegisterDirectMessageProcessor(blankMessageHandler)
+			_ = ds.SetSignaturePolicy(config.DirectMessagesConfig{SignaturePolicy: policy}, nil)
+
+			id, _ := createLibP2PCredentialsDirectSender()
+			err := ds.ProcessReceivedDirectMessage(createUnsignedDirectMessage(id, "111"), id)
+			assert.Nil(t, err)
+			err = ds.ProcessReceivedDirectMessage(createUnsignedDirectMessage(id, "112"), id)
+			assert.Nil(t, err)
+
+			// the warnings are rate limited, so a peer can not flood the logs
+			expectedNumWarnings := 0
+			if policy == p2p.WarnSignaturePolicy {
+				expectedNumWarnings = 1
+			}
+			assert.Equal(t, expectedNumWarnings, numWarnings)
+
+			expectedMetrics := p2p.DirectMessageSignatureMetrics{
+				NumUnsignedAccepted: 2,
+			}
+			assert.Equal(t, expectedMetrics, ds.GetSignatureMetrics())
+		}
+	})
+	t.Run("enforce policy should reject unsigned messages and blacklist the sender", func(t *testing.T) {
+		t.Parallel()
+
+		ds, _ := libp2p.NewDirectSender(
+			context.Background(),
+			generateHostStub(),
+			&mock.P2PSignerStub{},
+			&testscommon.MarshallerMock{},
+			&testscommon.LoggerStub{},
+		)
+		_ = ds.RegisterDirectMessageProcessor(blankMessageHandler)
+
+		id, _ := createLibP2PCredentialsDirectSender()
+		var blacklistedPid core.PeerID
+		connMonitor := &mock.ConnectionMonitorStub{
+			PeerDenialEvaluatorCalled: func() p2p.PeerDenialEvaluator {
+				return &mock.PeerDenialEvaluatorStub{
+					UpsertPeerIDCalled: func(pid core.PeerID, duration time.Duration) error {
+						blacklistedPid = pid
+						assert.Equal(t, p2p.WrongP2PMessageBlacklistDuration, duration)
+						return nil
+					},
+				}
+			},
+		}
+		cfg := config.DirectMessagesConfig{
+			SignaturePolicy:        p2p.EnforceSignaturePolicy,
+			BlacklistUnsignedPeers: true,
+		}
+		_ = ds.SetSignaturePolicy(cfg, connMonitor)
+
+		err := ds.ProcessReceivedDirectMessage(createUnsignedDirectMessage(id, "111"), id)
+		assert.True(t, errors.Is(err, p2p.ErrUnsignedDirectMessage))
+		assert.Equal(t, core.PeerID(id), blacklistedPid)
+
+		expectedMetrics := p2p.DirectMessageSignatureMetrics{
+			NumUnsignedRejected: 1,
+		}
+		assert.Equal(t, expectedMetrics, ds.GetSignatureMetrics())
+	})
+	t.Run("enforce policy without blacklisting should only reject unsigned messages", func(t *testing.T) {
+		t.Parallel()
+
+		ds, _ := libp2p.NewDirectSender(
+			context.Background(),
+			generateHostStub(),
+			&mock.P2PSignerStub{},
+			&testscommon.MarshallerMock{},
+			&testscommon.LoggerStub{},
+		)
+		_ = ds.RegisterDirectMessageProcessor(blankMessageHandler)
+		_ = ds.SetSignaturePolicy(config.DirectMessagesConfig{SignaturePolicy: p2p.EnforceSignaturePolicy}, nil)
+
+		id, _ := createLibP2PCredentialsDirectSender()
+		err := ds.ProcessReceivedDirectMessage(createUnsignedDirectMessage(id, "111"), id)
+		assert.True(t, errors.Is(err, p2p.ErrUnsignedDirectMessage))
+	})
+	t.Run("invalid signature should be counted", func(t *testing.T) {
+		t.Parallel()
+
+		ds, _ := libp2p.NewDirectSender(
+			context.Background(),
+			generateHostStub(),
+			&mock.P2PSignerStub{
+				VerifyCalled: func(payload []byte, pid core.PeerID, signature []byte) error {
+					return expectedErr
+				},
+			},
+			&testscommon.MarshallerMock{},
+			&testscommon.LoggerStub{},
+		)
+		_ = ds.RegisterDirectMessageProcessor(blankMessageHandler)
+
+		id, _ := createLibP2PCredentialsDirectSender()
+		msg := createUnsignedDirectMessage(id, "111")
+		msg.Signature = []byte("signature")
+		err := ds.ProcessReceivedDirectMessage(msg, id)
+		assert.Equal(t, expectedErr, err)
+
+		expectedMetrics := p2p.DirectMessageSignatureMetrics{
+			NumInvalidSignatures: 1,
+		}
+		assert.Equal(t, expectedMetrics, ds.GetSignatureMetrics())
+	})
+}
+
+func TestDirectSender_SignSelfMessage(t *testing.T) {
+	t.Parallel()
+
+	id, _ := createLibP2PCredentialsDirectSender()
+	hs := generateHostStub()
+	hs.IDCalled = func() peer.ID {
+		return id
+	}
+
+	t.Run("sign fails should error", func(t *testing.T) {
+		t.Parallel()
+
+		ds, _ := libp2p.NewDirectSender(
+			context.Background(),
+			hs,
+			&mock.P2PSignerStub{
+				SignCalled: func(payload []byte) ([]byte, error) {
+					return nil, expectedErr
+				},
+			},
+			&testscommon.MarshallerMock{},
+			&testscommon.LoggerStub{},
+		)
+
+		signature, err := ds.SignSelfMessage("topic", []byte("data"), []byte("111"))
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, signature)
+	})
+	t.Run("unsigned message with enforce policy should error", func(t *testing.T) {
+		t.Parallel()
+
+		ds, _ := libp2p.NewDirectSender(
+			context.Background(),
+			hs,
+			&mock.P2PSignerStub{},
+			&testscommon.MarshallerMock{},
+			&testscommon.LoggerStub{},
+		)
+		connMonitor := &mock.ConnectionMonitorStub{
+			PeerDenialEvaluatorCalled: func() p2p.PeerDenialEvaluator {
+				assert.Fail(t, "should have not blacklisted self")
+				return &mock.PeerDenialEvaluatorStub{}
+			},
+		}
+		cfg := config.DirectMessagesConfig{
+			SignaturePolicy:        p2p.EnforceSignaturePolicy,
+			BlacklistUnsignedPeers: true,
+		}
+		_ = ds.SetSignaturePolicy(cfg, connMonitor)
+
+		signature, err := ds.SignSelfMessage("topic", []byte("data"), []byte("111"))
+		assert.True(t, errors.Is(err, p2p.ErrUnsignedDirectMessage))
+		assert.Nil(t, signature)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		providedSignature := []byte("signature")
+		ds, _ := libp2p.NewDirectSender(
+			context.Background(),
+			hs,
+			&mock.P2PSignerStub{
+				SignCalled: func(payload []byte) ([]byte, error) {
+					return providedSignature, nil
+				},
+				VerifyCalled: func(payload []byte, pid core.PeerID, signature []byte) error {
+					assert.Equal(t, core.PeerID(id), pid)
+					assert.Equal(t, providedSignature, signature)
+					return nil
+				},
+			},
+			&testscommon.MarshallerMock{},
+			&testscommon.LoggerStub{},
+		)
+		_ = ds.SetSignaturePolicy(config.DirectMessagesConfig{SignaturePolicy: p2p.EnforceSignaturePolicy}, nil)
+
+		signature, err := ds.SignSelfMessage("topic", []byte("data"), []byte("111"))
+		assert.Nil(t, err)
+		assert.Equal(t, providedSignature, signature)
+	})
+}
@@ -0,0 +1,82 @@
+package libp2p
+
+import (
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+)
+
+// duplicateMessagesTracer is a pubsub tracer collecting the messages dropped by pubsub because their message ID was
+// already seen. pubsub computes the message ID from the originator and the sequence number, so a second message
+// signed with the same sequence number is dropped before being validated and only reaches this tracer
+type duplicateMessagesTracer struct {
+	messages chan *pubsub.Message
+}
+
+// NewDuplicateMessagesTracer returns a new duplicateMessagesTracer instance able to buffer the provided number of
+// duplicate messages
+func NewDuplicateMessagesTracer(capacity int) *duplicateMessagesTracer {
+	return &duplicateMessagesTracer{
+		messages: make(chan *pubsub.Message, capacity),
+	}
+}
+
+// DuplicateMessages returns the channel on which the duplicate messages are provided
+func (tracer *duplicateMessagesTracer) DuplicateMessages() <-chan *pubsub.Message {
+	return tracer.messages
+}
+
+// DuplicateMessage is called by pubsub on its event loop, so the message is dropped if the buffer is full
+func (tracer *duplicateMessagesTracer) DuplicateMessage(msg *pubsub.Message) {
+	select {
+	case tracer.messages <- msg:
+	default:
+	}
+}
+
+// AddPeer does nothing
+func (tracer *duplicateMessagesTracer) AddPeer(_ peer.ID, _ protocol.ID) {}
+
+// RemovePeer does nothing
+func (tracer *duplicateMessagesTracer) RemovePeer(_ peer.ID) {}
+
+// Join does nothing
+func (tracer *duplicateMessagesTracer) Join(_ string) {}
+
+// Leave does nothing
+func (tracer *duplicateMessagesTracer) Leave(_ string) {}
+
+// Graft does nothing
+func (tracer *duplicateMessagesTracer) Graft(_ peer.ID, _ string) {}
+
+// Prune does nothing
+func (tracer *duplicateMessagesTracer) Prune(_ peer.ID, _ string) {}
+
+// ValidateMessage does nothing
+func (tracer *duplicateMessagesTracer) ValidateMessage(_ *pubsub.Message) {}
+
+// DeliverMessage does nothing
+func (tracer *duplicateMessagesTracer) DeliverMessage(_ *pubsub.Message) {}
+
+// RejectMessage does nothing
+func (tracer *duplicateMessagesTracer) RejectMessage(_ *pubsub.Message, _ string) {}
+
+// ThrottlePeer does nothing
+func (tracer *duplicateMessagesTracer) ThrottlePeer(_ peer.ID) {}
+
+// RecvRPC does nothing
+func (tracer *duplicateMessagesTracer) RecvRPC(_ *pubsub.RPC) {}
+
+// SendRPC does nothing
+func (tracer *duplicateMessagesTracer) SendRPC(_ *pubsub.RPC, _ peer.ID) {}
+
+// DropRPC does nothing
+func (tracer *duplicateMessagesTracer) DropRPC(_ *pubsub.RPC, _ peer.ID) {}
+
+// UndeliverableMessage does nothing
+func (tracer *duplicateMessagesTracer) UndeliverableMessage(_ *pubsub.Message) {}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (tracer *duplicateMessagesTracer) IsInterfaceNil() bool {
+	return tracer == nil
+}
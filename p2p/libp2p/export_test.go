@@ -7,6 +7,7 @@ import (
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p/config"
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p/disabled"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/message"
 	"github.com/TerraDharitri/drt-go-chain-core/core"
 	"github.com/TerraDharitri/drt-go-chain-storage/types"
 	"github.com/libp2p/go-libp2p"
@@ -27,6 +28,10 @@ var AcceptMessagesInAdvanceDuration = acceptMessagesInAdvanceDuration
 var SequenceNumberSize = sequenceNumberSize
 
 const CurrentTopicMessageVersion = currentTopicMessageVersion
+
+// BatchTopicMessageVersion -
+const BatchTopicMessageVersion = batchTopicMessageVersion
+
 const PollWaitForConnectionsInterval = pollWaitForConnectionsInterval
 const KadProtocol = kadProtocol
 
@@ -252,8 +257,18 @@ func NewMessagesHandlerWithNoRoutine(args ArgMessagesHandler) *messagesHandler {
 		processors:         make(map[string]TopicProcessor),
 		topics:             make(map[string]PubSubTopic),
 		subscriptions:      make(map[string]PubSubSubscription),
+		publishOnlyTopics:  make(map[string]struct{}),
+		seenMessagesTTL:    args.SeenMessagesTTL,
+		topicValidators:    make(map[string]topicValidatorSettings),
+		validatorMetrics:   args.ValidatorMetrics,
+		duplicateMessages:  args.DuplicateMessages,
+		batchedMsgsEnabled: args.BatchedMessagesEnabled,
+		attestationEnabled: args.AttestationEnabled,
+		keys:               newKeysManager(),
 		log:                args.Logger,
 	}
+	handler.subscribers, _ = newTopicSubscribers(args.Subscriptions, args.Logger)
+	handler.batchers, _ = createTopicBatchers(args.Batching, handler.sendBatch)
 
 	_ = handler.directSender.RegisterDirectMessageProcessor(handler)
 	return handler
@@ -264,9 +279,9 @@ func (handler *messagesHandler) BlacklistPid(pid core.PeerID, banDuration time.D
 	handler.blacklistPid(pid, banDuration)
 }
 
-// TransformAndCheckMessage -
-func (handler *messagesHandler) TransformAndCheckMessage(pbMsg *pubsub.Message, pid core.PeerID, topic string) (p2p.MessageP2P, error) {
-	return handler.transformAndCheckMessage(pbMsg, pid, topic)
+// TransformAndCheckMessages -
+func (handler *messagesHandler) TransformAndCheckMessages(pbMsg *pubsub.Message, pid core.PeerID, topic string) ([]p2p.MessageP2P, error) {
+	return handler.transformAndCheckMessages(pbMsg, pid, topic)
 }
 
 // IncreaseRatingIfNeeded -
@@ -339,3 +354,65 @@ func (handler *connectionsHandler) PrintConnectionsStatus() {
 func ParseTransportOptions(configs config.TransportConfig, port int) ([]libp2p.Option, []string, error) {
 	return parseTransportOptions(configs, port)
 }
+
+// MessagesForSubscribers -
+func (handler *messagesHandler) MessagesForSubscribers(pbMsg *pubsub.Message) ([]p2p.MessageP2P, error) {
+	return handler.messagesForSubscribers(pbMsg)
+}
+
+// DeliverToSubscribers -
+func (handler *messagesHandler) DeliverToSubscribers(msg p2p.MessageP2P) {
+	handler.subscribers.deliver(msg)
+}
+
+// NewPeersInfoCollector -
+func NewPeersInfoCollector() *peersInfoCollector {
+	return newPeersInfoCollector()
+}
+
+// CreateStatement -
+func (pa *peerAuthenticator) CreateStatement() (*message.PeerAuthentication, error) {
+	return pa.createStatement()
+}
+
+// ProcessStatement -
+func (pa *peerAuthenticator) ProcessStatement(statement *message.PeerAuthentication, pid core.PeerID) {
+	pa.processStatement(statement, pid)
+}
+
+// SetPeerAuthenticatorSigner -
+func (netMes *networkMessenger) SetPeerAuthenticatorSigner(signer p2p.SignerVerifier) {
+	netMes.peerAuthenticator.signer = signer
+}
+
+// NewIncompatiblePeersGater -
+func NewIncompatiblePeersGater() *incompatiblePeersGater {
+	return newIncompatiblePeersGater()
+}
+
+// ProcessIdentity -
+func (nih *networkIdentityHandshaker) ProcessIdentity(remoteIdentity *message.NetworkIdentity, pid peer.ID) {
+	nih.processIdentity(remoteIdentity, pid)
+}
+
+// SelfIdentity -
+func (nih *networkIdentityHandshaker) SelfIdentity() *message.NetworkIdentity {
+	return nih.selfIdentity
+}
+
+// SetTimeout -
+func (nih *networkIdentityHandshaker) SetTimeout(timeout time.Duration) {
+	nih.timeout = timeout
+}
+
+// NewIPConnectionGater -
+func NewIPConnectionGater(cfg config.ConnectionGaterConfig, log p2p.Logger) (*ipConnectionGater, error) {
+	return newIPConnectionGater(cfg, log)
+}
+
+// SetTimeHandler -
+func (gater *ipConnectionGater) SetTimeHandler(handler func() time.Time) {
+	gater.mut.Lock()
+	gater.getTimeHandler = handler
+	gater.mut.Unlock()
+}
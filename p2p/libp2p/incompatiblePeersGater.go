@@ -0,0 +1,83 @@
+package libp2p
+
+import (
+	"sync"
+	"time"
+
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+	"github.com/libp2p/go-libp2p/core/connmgr"
+	"github.com/libp2p/go-libp2p/core/control"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+)
+
+var _ connmgr.ConnectionGater = (*incompatiblePeersGater)(nil)
+var _ IncompatiblePeersHandler = (*incompatiblePeersGater)(nil)
+
+// incompatiblePeersGater is the connection gater that refuses, for a while, the connections to and from the peers
+// found incompatible by the network identity handshake
+type incompatiblePeersGater struct {
+	mut   sync.RWMutex
+	peers map[core.PeerID]time.Time
+}
+
+func newIncompatiblePeersGater() *incompatiblePeersGater {
+	return &incompatiblePeersGater{
+		peers: make(map[core.PeerID]time.Time),
+	}
+}
+
+// AddIncompatiblePeer keeps out the provided peer for the provided duration
+func (gater *incompatiblePeersGater) AddIncompatiblePeer(pid core.PeerID, duration time.Duration) {
+	now := time.Now()
+
+	gater.mut.Lock()
+	defer gater.mut.Unlock()
+
+	for existingPid, expiry := range gater.peers {
+		if now.After(expiry) {
+			delete(gater.peers, existingPid)
+		}
+	}
+	gater.peers[pid] = now.Add(duration)
+}
+
+// IsIncompatible returns true if the provided peer was found incompatible and its ban did not expire
+func (gater *incompatiblePeersGater) IsIncompatible(pid core.PeerID) bool {
+	gater.mut.RLock()
+	expiry, found := gater.peers[pid]
+	gater.mut.RUnlock()
+
+	return found && time.Now().Before(expiry)
+}
+
+// InterceptPeerDial returns false if the dialed peer is incompatible
+func (gater *incompatiblePeersGater) InterceptPeerDial(p peer.ID) bool {
+	return !gater.IsIncompatible(core.PeerID(p))
+}
+
+// InterceptAddrDial returns false if the dialed peer is incompatible
+func (gater *incompatiblePeersGater) InterceptAddrDial(p peer.ID, _ multiaddr.Multiaddr) bool {
+	return !gater.IsIncompatible(core.PeerID(p))
+}
+
+// InterceptAccept returns true as the remote peer is not known yet
+func (gater *incompatiblePeersGater) InterceptAccept(_ network.ConnMultiaddrs) bool {
+	return true
+}
+
+// InterceptSecured returns false if the remote peer is incompatible
+func (gater *incompatiblePeersGater) InterceptSecured(_ network.Direction, p peer.ID, _ network.ConnMultiaddrs) bool {
+	return !gater.IsIncompatible(core.PeerID(p))
+}
+
+// InterceptUpgraded returns true as the remote peer was already checked when the connection was secured
+func (gater *incompatiblePeersGater) InterceptUpgraded(_ network.Conn) (bool, control.DisconnectReason) {
+	return true, 0
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (gater *incompatiblePeersGater) IsInterfaceNil() bool {
+	return gater == nil
+}
@@ -0,0 +1,55 @@
+package libp2p_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+	"github.com/TerraDharitri/drt-go-chain-core/core/check"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncompatiblePeersGater_IsInterfaceNil(t *testing.T) {
+	t.Parallel()
+
+	gater := libp2p.NewIncompatiblePeersGater()
+	assert.False(t, check.IfNil(gater))
+}
+
+func TestIncompatiblePeersGater_ShouldGateIncompatiblePeers(t *testing.T) {
+	t.Parallel()
+
+	gater := libp2p.NewIncompatiblePeersGater()
+	pid := peer.ID(providedPid)
+	assert.False(t, gater.IsIncompatible(providedPid))
+	assert.True(t, gater.InterceptPeerDial(pid))
+	assert.True(t, gater.InterceptAddrDial(pid, nil))
+	assert.True(t, gater.InterceptSecured(network.DirInbound, pid, nil))
+
+	gater.AddIncompatiblePeer(providedPid, time.Hour)
+	assert.True(t, gater.IsIncompatible(providedPid))
+	assert.False(t, gater.InterceptPeerDial(pid))
+	assert.False(t, gater.InterceptAddrDial(pid, nil))
+	assert.False(t, gater.InterceptSecured(network.DirInbound, pid, nil))
+	assert.True(t, gater.InterceptAccept(nil))
+	allow, _ := gater.InterceptUpgraded(nil)
+	assert.True(t, allow)
+
+	assert.False(t, gater.IsIncompatible("other pid"))
+}
+
+func TestIncompatiblePeersGater_ExpiredPeersShouldNotBeGated(t *testing.T) {
+	t.Parallel()
+
+	gater := libp2p.NewIncompatiblePeersGater()
+	gater.AddIncompatiblePeer(providedPid, time.Millisecond)
+	time.Sleep(time.Millisecond * 10)
+	assert.False(t, gater.IsIncompatible(providedPid))
+
+	gater.AddIncompatiblePeer(core.PeerID("other pid"), time.Hour)
+	assert.False(t, gater.IsIncompatible(providedPid))
+	assert.True(t, gater.IsIncompatible("other pid"))
+}
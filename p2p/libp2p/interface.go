@@ -2,6 +2,7 @@ package libp2p
 
 import (
 	"context"
+	"time"
 
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
 	"github.com/TerraDharitri/drt-go-chain-core/core"
@@ -41,6 +42,12 @@ type SendableData struct {
 	Topic string
 	Sk    crypto.PrivKey
 	ID    peer.ID
+	// Ctx, if set, cancels the sending of the data while it is waiting in the outgoing queues
+	Ctx context.Context
+	// Done, if set, receives the outcome of the sending. It should be a buffered channel
+	Done chan error
+	// IsBatch marks that Buff holds a serialized batch of payloads
+	IsBatch bool
 }
 
 // ChannelLoadBalancer defines what a load balancer that uses chans should do
@@ -48,7 +55,9 @@ type ChannelLoadBalancer interface {
 	AddChannel(channel string) error
 	RemoveChannel(channel string) error
 	GetChannelOrDefault(channel string) chan *SendableData
+	GetChannelWithDoneOrDefault(channel string) (chan *SendableData, <-chan struct{})
 	CollectOneElementFromChannels() *SendableData
+	GetChannelsMetrics() map[string]p2p.OutgoingChannelMetrics
 	Close() error
 	IsInterfaceNil() bool
 }
@@ -91,6 +100,24 @@ type PeersOnChannel interface {
 	IsInterfaceNil() bool
 }
 
+// ValidatorMetricsHandler is an extension of the pubsub tracer able to count the overloaded topic validations
+type ValidatorMetricsHandler interface {
+	pubsub.RawTracer
+
+	AddIgnoredOnOverload(topic string)
+	AddTimeout(topic string)
+	GetMetrics() map[string]p2p.ValidatorMetrics
+	IsInterfaceNil() bool
+}
+
+// DuplicateMessagesTracer is an extension of the pubsub tracer providing the messages dropped by pubsub as duplicates
+type DuplicateMessagesTracer interface {
+	pubsub.RawTracer
+
+	DuplicateMessages() <-chan *pubsub.Message
+	IsInterfaceNil() bool
+}
+
 // ConnectionsMetric is an extension of the libp2p network notifiee able to track connections metrics
 type ConnectionsMetric interface {
 	network.Notifiee
@@ -99,3 +126,17 @@ type ConnectionsMetric interface {
 	ResetNumDisconnections() uint32
 	IsInterfaceNil() bool
 }
+
+// PeersInfoHandler defines the component that stores the information of the authenticated peers
+type PeersInfoHandler interface {
+	UpdatePeerInfo(pid core.PeerID, peerInfo core.P2PPeerInfo, nodeVersion string)
+	RemovePeer(pid core.PeerID)
+	IsInterfaceNil() bool
+}
+
+// IncompatiblePeersHandler defines the component that keeps out, for a while, the peers found incompatible
+type IncompatiblePeersHandler interface {
+	AddIncompatiblePeer(pid core.PeerID, duration time.Duration)
+	IsIncompatible(pid core.PeerID) bool
+	IsInterfaceNil() bool
+}
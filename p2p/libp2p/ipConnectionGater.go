@@ -0,0 +1,459 @@
+package libp2p
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/config"
+	"github.com/TerraDharitri/drt-go-chain-core/core/check"
+	"github.com/libp2p/go-libp2p/core/connmgr"
+	"github.com/libp2p/go-libp2p/core/control"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
+)
+
+const (
+	ipv4SubnetMaskSize = 24
+	ipv6SubnetMaskSize = 48
+	// the upgrader gives up on an inbound connection after 15 seconds by default, so an accepted connection not
+	// reported as connected after this timeout failed to be established
+	pendingAcceptTimeout = 30 * time.Second
+)
+
+var _ connmgr.ConnectionGater = (*ipConnectionGater)(nil)
+var _ network.Notifiee = (*ipConnectionGater)(nil)
+
+type inboundAttempts struct {
+	windowStart time.Time
+	numAttempts int
+}
+
+type pendingAccept struct {
+	ipKey     string
+	subnetKey string
+	deadline  time.Time
+}
+
+type ipConnectionGaterLimits struct {
+	allowList               []*net.IPNet
+	denyList                []*net.IPNet
+	maxConnectionsPerIP     int
+	maxConnectionsPerSubnet int
+	maxInboundAttemptsPerIP int
+	inboundAttemptsInterval time.Duration
+}
+
+// ipConnectionGater is the connection gater that refuses the connections by IP address. It is also a network
+// notifiee, so it knows the number of inbound connections opened with each IP address and subnet. The accepted
+// connections count against the limits while they are being established as well, otherwise a burst of connections
+// accepted before the first one is upgraded would bypass them. The outbound connections are not counted, as they are
+// opened by this node
+type ipConnectionGater struct {
+	log                   p2p.Logger
+	mut                   sync.RWMutex
+	limits                ipConnectionGaterLimits
+	ipDenialEvaluator     p2p.IPDenialEvaluator
+	connectionsPerIP      map[string]int
+	connectionsPerSubnet  map[string]int
+	pendingAccepts        map[string][]pendingAccept
+	lastPendingSweep      time.Time
+	inboundAttempts       map[string]*inboundAttempts
+	lastAttemptsSweep     time.Time
+	getTimeHandler        func() time.Time
+	numDenied             uint64
+	numIPLimitReached     uint64
+	numSubnetLimitReached uint64
+	numInboundRateLimited uint64
+}
+
+func newIPConnectionGater(cfg config.ConnectionGaterConfig, log p2p.Logger) (*ipConnectionGater, error) {
+	if check.IfNil(log) {
+		return nil, p2p.ErrNilLogger
+	}
+
+	limits, err := parseConnectionGaterConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ipConnectionGater{
+		log:                  log,
+		limits:               limits,
+		connectionsPerIP:     make(map[string]int),
+		connectionsPerSubnet: make(map[string]int),
+		pendingAccepts:       make(map[string][]pendingAccept),
+		inboundAttempts:      make(map[string]*inboundAttempts),
+		getTimeHandler:       time.Now,
+	}, nil
+}
+
+func parseConnectionGaterConfig(cfg config.ConnectionGaterConfig) (ipConnectionGaterLimits, error) {
+	if cfg.MaxConnectionsPerIP < 0 {
+		return ipConnectionGaterLimits{}, fmt.Errorf("%w for MaxConnectionsPerIP", p2p.ErrInvalidValue)
+	}
+	if cfg.MaxConnectionsPerSubnet < 0 {
+		return ipConnectionGaterLimits{}, fmt.Errorf("%w for MaxConnectionsPerSubnet", p2p.ErrInvalidValue)
+	}
+	if cfg.MaxInboundAttemptsPerIP < 0 {
+		return ipConnectionGaterLimits{}, fmt.Errorf("%w for MaxInboundAttemptsPerIP", p2p.ErrInvalidValue)
+	}
+	if cfg.MaxInboundAttemptsPerIP > 0 && cfg.InboundAttemptsIntervalInSec == 0 {
+		return ipConnectionGaterLimits{}, fmt.Errorf("%w for InboundAttemptsIntervalInSec", p2p.ErrInvalidValue)
+	}
+
+	allowList, err := parseCIDRs(cfg.AllowList)
+	if err != nil {
+		return ipConnectionGaterLimits{}, fmt.Errorf("%w in AllowList", err)
+	}
+	denyList, err := parseCIDRs(cfg.DenyList)
+	if err != nil {
+		return ipConnectionGaterLimits{}, fmt.Errorf("%w in DenyList", err)
+	}
+
+	return ipConnectionGaterLimits{
+		allowList:               allowList,
+		denyList:                denyList,
+		maxConnectionsPerIP:     cfg.MaxConnectionsPerIP,
+		maxConnectionsPerSubnet: cfg.MaxConnectionsPerSubnet,
+		maxInboundAttemptsPerIP: cfg.MaxInboundAttemptsPerIP,
+		inboundAttemptsInterval: time.Duration(cfg.InboundAttemptsIntervalInSec) * time.Second,
+	}, nil
+}
+
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	ipNets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s", p2p.ErrInvalidCIDR, cidr)
+		}
+
+		ipNets = append(ipNets, ipNet)
+	}
+
+	return ipNets, nil
+}
+
+// Reload replaces the allow and deny lists and the limits. The counters of the opened connections are kept
+func (gater *ipConnectionGater) Reload(cfg config.ConnectionGaterConfig) error {
+	limits, err := parseConnectionGaterConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	gater.mut.Lock()
+	gater.limits = limits
+	gater.inboundAttempts = make(map[string]*inboundAttempts)
+	gater.mut.Unlock()
+
+	gater.log.Debug("reloaded the connection gater",
+		"allow list", len(cfg.AllowList),
+		"deny list", len(cfg.DenyList),
+		"max connections per IP", cfg.MaxConnectionsPerIP,
+		"max connections per subnet", cfg.MaxConnectionsPerSubnet,
+		"max inbound attempts per IP", cfg.MaxInboundAttemptsPerIP,
+	)
+
+	return nil
+}
+
+// SetIPDenialEvaluator sets the component that decides which IP addresses are banned. A nil evaluator disables
+// the IP bans
+func (gater *ipConnectionGater) SetIPDenialEvaluator(evaluator p2p.IPDenialEvaluator) {
+	gater.mut.Lock()
+	gater.ipDenialEvaluator = evaluator
+	gater.mut.Unlock()
+}
+
+// GetMetrics returns the counters of the refused connection attempts
+func (gater *ipConnectionGater) GetMetrics() p2p.ConnectionGaterMetrics {
+	return p2p.ConnectionGaterMetrics{
+		NumDenied:             atomic.LoadUint64(&gater.numDenied),
+		NumIPLimitReached:     atomic.LoadUint64(&gater.numIPLimitReached),
+		NumSubnetLimitReached: atomic.LoadUint64(&gater.numSubnetLimitReached),
+		NumInboundRateLimited: atomic.LoadUint64(&gater.numInboundRateLimited),
+	}
+}
+
+// InterceptPeerDial returns true as the address of the peer is not known yet
+func (gater *ipConnectionGater) InterceptPeerDial(_ peer.ID) bool {
+	return true
+}
+
+// InterceptAddrDial returns false if the dialed address is denied
+func (gater *ipConnectionGater) InterceptAddrDial(p peer.ID, address multiaddr.Multiaddr) bool {
+	ip, err := manet.ToIP(address)
+	if err != nil {
+		return true
+	}
+
+	gater.mut.RLock()
+	defer gater.mut.RUnlock()
+
+	if gater.isAllowed(ip) || !gater.isDenied(ip) {
+		return true
+	}
+
+	atomic.AddUint64(&gater.numDenied, 1)
+	gater.log.Trace("connection gater refused dial", "pid", p.String(), "address", address.String(), "reason", "denied")
+
+	return false
+}
+
+// InterceptAccept returns false if the remote address is denied, if the connections limits were reached or if the
+// remote address made too many connection attempts
+func (gater *ipConnectionGater) InterceptAccept(addresses network.ConnMultiaddrs) bool {
+	if addresses == nil {
+		return true
+	}
+	ip, err := manet.ToIP(addresses.RemoteMultiaddr())
+	if err != nil {
+		return true
+	}
+
+	gater.mut.Lock()
+	defer gater.mut.Unlock()
+
+	if gater.isAllowed(ip) {
+		return true
+	}
+
+	counter, reason := gater.checkInbound(ip)
+	if counter == nil {
+		gater.addPendingAccept(addresses.RemoteMultiaddr().String(), ip)
+		return true
+	}
+
+	atomic.AddUint64(counter, 1)
+	gater.log.Trace("connection gater refused inbound connection",
+		"address", addresses.RemoteMultiaddr().String(),
+		"reason", reason)
+
+	return false
+}
+
+// checkInbound returns the counter to be incremented and the reason if the inbound connection should be refused.
+// Must be called under mutex protection
+func (gater *ipConnectionGater) checkInbound(ip net.IP) (*uint64, string) {
+	if gater.isDenied(ip) {
+		return &gater.numDenied, "denied"
+	}
+	if !gater.recordInboundAttempt(ip) {
+		return &gater.numInboundRateLimited, "too many inbound attempts"
+	}
+
+	gater.sweepPendingAccepts(gater.getTimeHandler())
+
+	limits := gater.limits
+	if limits.maxConnectionsPerIP > 0 && gater.connectionsPerIP[ip.String()] >= limits.maxConnectionsPerIP {
+		return &gater.numIPLimitReached, "IP connections limit reached"
+	}
+	if limits.maxConnectionsPerSubnet > 0 && gater.connectionsPerSubnet[subnetKey(ip)] >= limits.maxConnectionsPerSubnet {
+		return &gater.numSubnetLimitReached, "subnet connections limit reached"
+	}
+
+	return nil, ""
+}
+
+// InterceptSecured returns true as the remote address was already checked when the connection was accepted
+func (gater *ipConnectionGater) InterceptSecured(_ network.Direction, _ peer.ID, _ network.ConnMultiaddrs) bool {
+	return true
+}
+
+// InterceptUpgraded returns true as the remote address was already checked when the connection was accepted
+func (gater *ipConnectionGater) InterceptUpgraded(_ network.Conn) (bool, control.DisconnectReason) {
+	return true, 0
+}
+
+// addPendingAccept counts the accepted connection until it is reported as connected or its establishment times out.
+// Must be called under mutex protection
+func (gater *ipConnectionGater) addPendingAccept(address string, ip net.IP) {
+	accept := pendingAccept{
+		ipKey:     ip.String(),
+		subnetKey: subnetKey(ip),
+		deadline:  gater.getTimeHandler().Add(pendingAcceptTimeout),
+	}
+	gater.pendingAccepts[address] = append(gater.pendingAccepts[address], accept)
+	updateCounter(gater.connectionsPerIP, accept.ipKey, 1)
+	updateCounter(gater.connectionsPerSubnet, accept.subnetKey, 1)
+}
+
+// removePendingAccept returns true if the connection was counted when it was accepted. Must be called under mutex
+// protection
+func (gater *ipConnectionGater) removePendingAccept(address string) bool {
+	accepts := gater.pendingAccepts[address]
+	if len(accepts) == 0 {
+		return false
+	}
+
+	if len(accepts) == 1 {
+		delete(gater.pendingAccepts, address)
+	} else {
+		gater.pendingAccepts[address] = accepts[1:]
+	}
+
+	return true
+}
+
+// sweepPendingAccepts stops counting, once in a timeout, the accepted connections that failed to be established.
+// Must be called under mutex protection
+func (gater *ipConnectionGater) sweepPendingAccepts(now time.Time) {
+	if now.Sub(gater.lastPendingSweep) < pendingAcceptTimeout {
+		return
+	}
+
+	gater.lastPendingSweep = now
+	for address, accepts := range gater.pendingAccepts {
+		remaining := accepts[:0]
+		for _, accept := range accepts {
+			if now.Before(accept.deadline) {
+				remaining = append(remaining, accept)
+				continue
+			}
+
+			updateCounter(gater.connectionsPerIP, accept.ipKey, -1)
+			updateCounter(gater.connectionsPerSubnet, accept.subnetKey, -1)
+		}
+
+		if len(remaining) == 0 {
+			delete(gater.pendingAccepts, address)
+			continue
+		}
+		gater.pendingAccepts[address] = remaining
+	}
+}
+
+// isAllowed must be called under mutex protection
+func (gater *ipConnectionGater) isAllowed(ip net.IP) bool {
+	return containsIP(gater.limits.allowList, ip)
+}
+
+// isDenied must be called under mutex protection
+func (gater *ipConnectionGater) isDenied(ip net.IP) bool {
+	if containsIP(gater.limits.denyList, ip) {
+		return true
+	}
+
+	return gater.ipDenialEvaluator != nil && gater.ipDenialEvaluator.IsIPDenied(ip)
+}
+
+// recordInboundAttempt returns false if the IP address exceeded its inbound attempts in the current interval.
+// Must be called under mutex protection
+func (gater *ipConnectionGater) recordInboundAttempt(ip net.IP) bool {
+	limits := gater.limits
+	if limits.maxInboundAttemptsPerIP == 0 {
+		return true
+	}
+
+	now := gater.getTimeHandler()
+	gater.sweepInboundAttempts(now)
+
+	key := ip.String()
+	attempts, found := gater.inboundAttempts[key]
+	if !found || now.Sub(attempts.windowStart) >= limits.inboundAttemptsInterval {
+		attempts = &inboundAttempts{
+			windowStart: now,
+		}
+		gater.inboundAttempts[key] = attempts
+	}
+
+	attempts.numAttempts++
+
+	return attempts.numAttempts <= limits.maxInboundAttemptsPerIP
+}
+
+// sweepInboundAttempts removes, once in an interval, the expired attempts. Must be called under mutex protection
+func (gater *ipConnectionGater) sweepInboundAttempts(now time.Time) {
+	interval := gater.limits.inboundAttemptsInterval
+	if now.Sub(gater.lastAttemptsSweep) < interval {
+		return
+	}
+
+	gater.lastAttemptsSweep = now
+	for key, attempts := range gater.inboundAttempts {
+		if now.Sub(attempts.windowStart) >= interval {
+			delete(gater.inboundAttempts, key)
+		}
+	}
+}
+
+// Listen does nothing
+func (gater *ipConnectionGater) Listen(_ network.Network, _ multiaddr.Multiaddr) {}
+
+// ListenClose does nothing
+func (gater *ipConnectionGater) ListenClose(_ network.Network, _ multiaddr.Multiaddr) {}
+
+// Connected increments the number of inbound connections opened with the remote IP address and its subnet, unless
+// the connection was already counted when it was accepted
+func (gater *ipConnectionGater) Connected(_ network.Network, conn network.Conn) {
+	gater.updateConnections(conn, 1)
+}
+
+// Disconnected decrements the number of inbound connections opened with the remote IP address and its subnet
+func (gater *ipConnectionGater) Disconnected(_ network.Network, conn network.Conn) {
+	gater.updateConnections(conn, -1)
+}
+
+func (gater *ipConnectionGater) updateConnections(conn network.Conn, delta int) {
+	if conn == nil {
+		return
+	}
+	if conn.Stat().Direction == network.DirOutbound {
+		return
+	}
+	address := conn.RemoteMultiaddr()
+	ip, err := manet.ToIP(address)
+	if err != nil {
+		return
+	}
+
+	gater.mut.Lock()
+	defer gater.mut.Unlock()
+
+	if delta > 0 && gater.removePendingAccept(address.String()) {
+		return
+	}
+
+	updateCounter(gater.connectionsPerIP, ip.String(), delta)
+	updateCounter(gater.connectionsPerSubnet, subnetKey(ip), delta)
+}
+
+func updateCounter(counters map[string]int, key string, delta int) {
+	value := counters[key] + delta
+	if value <= 0 {
+		delete(counters, key)
+		return
+	}
+
+	counters[key] = value
+}
+
+func containsIP(ipNets []*net.IPNet, ip net.IP) bool {
+	for _, ipNet := range ipNets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func subnetKey(ip net.IP) string {
+	ipv4 := ip.To4()
+	if ipv4 != nil {
+		return fmt.Sprintf("%s/%d", ipv4.Mask(net.CIDRMask(ipv4SubnetMaskSize, 8*net.IPv4len)), ipv4SubnetMaskSize)
+	}
+
+	return fmt.Sprintf("%s/%d", ip.Mask(net.CIDRMask(ipv6SubnetMaskSize, 8*net.IPv6len)), ipv6SubnetMaskSize)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (gater *ipConnectionGater) IsInterfaceNil() bool {
+	return gater == nil
+}
@@ -0,0 +1,271 @@
+package libp2p_test
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/config"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/mock"
+	"github.com/TerraDharitri/drt-go-chain-communication/testscommon"
+	"github.com/TerraDharitri/drt-go-chain-core/core/check"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ipDenialEvaluatorStub struct {
+	deniedIP string
+}
+
+func (stub *ipDenialEvaluatorStub) IsIPDenied(ip net.IP) bool {
+	return ip.String() == stub.deniedIP
+}
+
+func createRemoteConnStub(address string) *mock.ConnStub {
+	return &mock.ConnStub{
+		RemoteMultiaddrCalled: func() multiaddr.Multiaddr {
+			return multiaddr.StringCast(address)
+		},
+	}
+}
+
+func TestNewIPConnectionGater(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil logger should error", func(t *testing.T) {
+		t.Parallel()
+
+		gater, err := libp2p.NewIPConnectionGater(config.ConnectionGaterConfig{}, nil)
+		assert.Equal(t, p2p.ErrNilLogger, err)
+		assert.True(t, check.IfNil(gater))
+	})
+	t.Run("invalid CIDR should error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.ConnectionGaterConfig{
+			DenyList: []string{"10.0.0.0/8", "not a CIDR"},
+		}
+		gater, err := libp2p.NewIPConnectionGater(cfg, &testscommon.LoggerStub{})
+		assert.True(t, errors.Is(err, p2p.ErrInvalidCIDR))
+		assert.True(t, check.IfNil(gater))
+	})
+	t.Run("negative limit should error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.ConnectionGaterConfig{
+			MaxConnectionsPerSubnet: -1,
+		}
+		gater, err := libp2p.NewIPConnectionGater(cfg, &testscommon.LoggerStub{})
+		assert.True(t, errors.Is(err, p2p.ErrInvalidValue))
+		assert.True(t, check.IfNil(gater))
+	})
+	t.Run("inbound attempts limit without interval should error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.ConnectionGaterConfig{
+			MaxInboundAttemptsPerIP: 5,
+		}
+		gater, err := libp2p.NewIPConnectionGater(cfg, &testscommon.LoggerStub{})
+		assert.True(t, errors.Is(err, p2p.ErrInvalidValue))
+		assert.True(t, check.IfNil(gater))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		gater, err := libp2p.NewIPConnectionGater(config.ConnectionGaterConfig{}, &testscommon.LoggerStub{})
+		assert.Nil(t, err)
+		assert.False(t, check.IfNil(gater))
+	})
+}
+
+func TestIPConnectionGater_DenyAndAllowLists(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.ConnectionGaterConfig{
+		AllowList: []string{"10.1.2.3/32"},
+		DenyList:  []string{"10.0.0.0/8", "2001:db8::/32"},
+	}
+	gater, _ := libp2p.NewIPConnectionGater(cfg, &testscommon.LoggerStub{})
+	pid := peer.ID(providedPid)
+
+	assert.False(t, gater.InterceptAccept(createRemoteConnStub("/ip4/10.0.0.1/tcp/37373")))
+	assert.False(t, gater.InterceptAccept(createRemoteConnStub("/ip6/2001:db8::1/tcp/37373")))
+	assert.False(t, gater.InterceptAddrDial(pid, multiaddr.StringCast("/ip4/10.0.0.1/tcp/37373")))
+	assert.True(t, gater.InterceptAccept(createRemoteConnStub("/ip4/10.1.2.3/tcp/37373")))
+	assert.True(t, gater.InterceptAddrDial(pid, multiaddr.StringCast("/ip4/10.1.2.3/tcp/37373")))
+	assert.True(t, gater.InterceptAccept(createRemoteConnStub("/ip4/192.168.0.1/tcp/37373")))
+	assert.True(t, gater.InterceptAddrDial(pid, multiaddr.StringCast("/dns4/example.com/tcp/37373")))
+	assert.True(t, gater.InterceptPeerDial(pid))
+	assert.Equal(t, uint64(3), gater.GetMetrics().NumDenied)
+}
+
+func TestIPConnectionGater_IPDenialEvaluator(t *testing.T) {
+	t.Parallel()
+
+	gater, _ := libp2p.NewIPConnectionGater(config.ConnectionGaterConfig{}, &testscommon.LoggerStub{})
+	gater.SetIPDenialEvaluator(&ipDenialEvaluatorStub{deniedIP: "192.168.0.1"})
+
+	assert.False(t, gater.InterceptAccept(createRemoteConnStub("/ip4/192.168.0.1/tcp/37373")))
+	assert.True(t, gater.InterceptAccept(createRemoteConnStub("/ip4/192.168.0.2/tcp/37373")))
+
+	gater.SetIPDenialEvaluator(nil)
+	assert.True(t, gater.InterceptAccept(createRemoteConnStub("/ip4/192.168.0.1/tcp/37373")))
+	assert.Equal(t, uint64(1), gater.GetMetrics().NumDenied)
+}
+
+func TestIPConnectionGater_ConnectionsLimits(t *testing.T) {
+	t.Parallel()
+
+	t.Run("per IP limit", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.ConnectionGaterConfig{
+			MaxConnectionsPerIP: 2,
+		}
+		gater, _ := libp2p.NewIPConnectionGater(cfg, &testscommon.LoggerStub{})
+		conn := createRemoteConnStub("/ip4/192.168.0.1/tcp/37373")
+
+		gater.Connected(nil, conn)
+		assert.True(t, gater.InterceptAccept(conn))
+		gater.Connected(nil, conn)
+		assert.False(t, gater.InterceptAccept(conn))
+		assert.True(t, gater.InterceptAccept(createRemoteConnStub("/ip4/192.168.0.2/tcp/37373")))
+
+		gater.Disconnected(nil, conn)
+		assert.True(t, gater.InterceptAccept(conn))
+		assert.Equal(t, uint64(1), gater.GetMetrics().NumIPLimitReached)
+	})
+	t.Run("per subnet limit", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.ConnectionGaterConfig{
+			MaxConnectionsPerSubnet: 2,
+		}
+		gater, _ := libp2p.NewIPConnectionGater(cfg, &testscommon.LoggerStub{})
+
+		gater.Connected(nil, createRemoteConnStub("/ip4/192.168.0.1/tcp/37373"))
+		gater.Connected(nil, createRemoteConnStub("/ip4/192.168.0.2/tcp/37373"))
+		gater.Connected(nil, createRemoteConnStub("/ip6/2001:db8:1:1::1/tcp/37373"))
+		assert.False(t, gater.InterceptAccept(createRemoteConnStub("/ip4/192.168.0.3/tcp/37373")))
+		assert.True(t, gater.InterceptAccept(createRemoteConnStub("/ip4/192.168.1.3/tcp/37373")))
+		assert.True(t, gater.InterceptAccept(createRemoteConnStub("/ip6/2001:db8:1:2::1/tcp/37373")))
+
+		gater.Connected(nil, createRemoteConnStub("/ip6/2001:db8:1:3::1/tcp/37373"))
+		assert.False(t, gater.InterceptAccept(createRemoteConnStub("/ip6/2001:db8:1:4::1/tcp/37373")))
+		assert.True(t, gater.InterceptAccept(createRemoteConnStub("/ip6/2001:db8:2::1/tcp/37373")))
+		assert.Equal(t, uint64(2), gater.GetMetrics().NumSubnetLimitReached)
+	})
+}
+
+func TestIPConnectionGater_PendingAccepts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("accepted connections should count until connected or timed out", func(t *testing.T) {
+		t.Parallel()
+
+		currentTime := time.Unix(1000, 0)
+		cfg := config.ConnectionGaterConfig{
+			MaxConnectionsPerIP: 2,
+		}
+		gater, _ := libp2p.NewIPConnectionGater(cfg, &testscommon.LoggerStub{})
+		gater.SetTimeHandler(func() time.Time {
+			return currentTime
+		})
+		conn1 := createRemoteConnStub("/ip4/192.168.0.1/tcp/37373")
+		conn2 := createRemoteConnStub("/ip4/192.168.0.1/tcp/37374")
+		conn3 := createRemoteConnStub("/ip4/192.168.0.1/tcp/37375")
+
+		assert.True(t, gater.InterceptAccept(conn1))
+		assert.True(t, gater.InterceptAccept(conn2))
+		assert.False(t, gater.InterceptAccept(conn3))
+
+		// the connection was already counted when it was accepted
+		gater.Connected(nil, conn1)
+		assert.False(t, gater.InterceptAccept(conn3))
+
+		// the second connection failed to be established
+		currentTime = currentTime.Add(time.Minute)
+		assert.True(t, gater.InterceptAccept(conn3))
+		assert.False(t, gater.InterceptAccept(conn2))
+
+		gater.Disconnected(nil, conn1)
+		assert.True(t, gater.InterceptAccept(conn2))
+		assert.Equal(t, uint64(3), gater.GetMetrics().NumIPLimitReached)
+	})
+	t.Run("outbound connections should not count", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.ConnectionGaterConfig{
+			MaxConnectionsPerSubnet: 1,
+		}
+		gater, _ := libp2p.NewIPConnectionGater(cfg, &testscommon.LoggerStub{})
+		outboundConn := createRemoteConnStub("/ip4/192.168.0.1/tcp/37373")
+		outboundConn.StatCalled = func() network.ConnStats {
+			return network.ConnStats{
+				Stats: network.Stats{
+					Direction: network.DirOutbound,
+				},
+			}
+		}
+
+		gater.Connected(nil, outboundConn)
+		assert.True(t, gater.InterceptAccept(createRemoteConnStub("/ip4/192.168.0.2/tcp/37373")))
+		assert.False(t, gater.InterceptAccept(createRemoteConnStub("/ip4/192.168.0.3/tcp/37373")))
+		gater.Disconnected(nil, outboundConn)
+		assert.False(t, gater.InterceptAccept(createRemoteConnStub("/ip4/192.168.0.3/tcp/37373")))
+	})
+}
+
+func TestIPConnectionGater_InboundAttemptsRateLimit(t *testing.T) {
+	t.Parallel()
+
+	currentTime := time.Unix(1000, 0)
+	cfg := config.ConnectionGaterConfig{
+		MaxInboundAttemptsPerIP:      2,
+		InboundAttemptsIntervalInSec: 10,
+	}
+	gater, _ := libp2p.NewIPConnectionGater(cfg, &testscommon.LoggerStub{})
+	gater.SetTimeHandler(func() time.Time {
+		return currentTime
+	})
+	conn := createRemoteConnStub("/ip4/192.168.0.1/tcp/37373")
+
+	assert.True(t, gater.InterceptAccept(conn))
+	assert.True(t, gater.InterceptAccept(conn))
+	assert.False(t, gater.InterceptAccept(conn))
+	assert.True(t, gater.InterceptAccept(createRemoteConnStub("/ip4/192.168.0.2/tcp/37373")))
+
+	currentTime = currentTime.Add(time.Second * 10)
+	assert.True(t, gater.InterceptAccept(conn))
+	assert.Equal(t, uint64(1), gater.GetMetrics().NumInboundRateLimited)
+}
+
+func TestIPConnectionGater_Reload(t *testing.T) {
+	t.Parallel()
+
+	gater, _ := libp2p.NewIPConnectionGater(config.ConnectionGaterConfig{}, &testscommon.LoggerStub{})
+	conn := createRemoteConnStub("/ip4/192.168.0.1/tcp/37373")
+	gater.Connected(nil, conn)
+	assert.True(t, gater.InterceptAccept(conn))
+
+	err := gater.Reload(config.ConnectionGaterConfig{DenyList: []string{"invalid"}})
+	assert.True(t, errors.Is(err, p2p.ErrInvalidCIDR))
+	assert.True(t, gater.InterceptAccept(conn))
+
+	err = gater.Reload(config.ConnectionGaterConfig{MaxConnectionsPerIP: 1})
+	require.Nil(t, err)
+	assert.False(t, gater.InterceptAccept(conn))
+
+	err = gater.Reload(config.ConnectionGaterConfig{
+		AllowList:           []string{"192.168.0.0/16"},
+		MaxConnectionsPerIP: 1,
+	})
+	require.Nil(t, err)
+	assert.True(t, gater.InterceptAccept(conn))
+}
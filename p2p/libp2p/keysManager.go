@@ -0,0 +1,86 @@
+package libp2p
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p/crypto"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+	libp2pCrypto "github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+type managedIdentity struct {
+	pid peer.ID
+	sk  libp2pCrypto.PrivKey
+}
+
+// keysManager holds the named identities the messenger can broadcast on behalf of. The private keys are parsed and
+// checked against their peer IDs only once, when the identities are added
+type keysManager struct {
+	mut        sync.RWMutex
+	identities map[string]*managedIdentity
+}
+
+func newKeysManager() *keysManager {
+	return &keysManager{
+		identities: make(map[string]*managedIdentity),
+	}
+}
+
+// addIdentity registers the identity under the provided name, replacing the existing one, if any
+func (manager *keysManager) addIdentity(name string, skBytes []byte, pid core.PeerID) error {
+	if len(name) == 0 {
+		return p2p.ErrEmptyIdentityName
+	}
+
+	sk, err := crypto.UnmarshalRawLibp2pPrivateKey(skBytes)
+	if err != nil {
+		return err
+	}
+
+	derivedPid, err := peer.IDFromPublicKey(sk.GetPublic())
+	if err != nil {
+		return err
+	}
+	if core.PeerID(derivedPid) != pid {
+		return fmt.Errorf("%w for identity %s: provided %s, derived %s",
+			p2p.ErrPeerIDMismatch, name, pid.Pretty(), derivedPid.String())
+	}
+
+	manager.mut.Lock()
+	manager.identities[name] = &managedIdentity{
+		pid: derivedPid,
+		sk:  sk,
+	}
+	manager.mut.Unlock()
+
+	return nil
+}
+
+func (manager *keysManager) removeIdentity(name string) error {
+	manager.mut.Lock()
+	defer manager.mut.Unlock()
+
+	_, found := manager.identities[name]
+	if !found {
+		return fmt.Errorf("%w: %s", p2p.ErrIdentityNotFound, name)
+	}
+
+	delete(manager.identities, name)
+
+	return nil
+}
+
+func (manager *keysManager) getIdentity(name string) (*managedIdentity, error) {
+	manager.mut.RLock()
+	defer manager.mut.RUnlock()
+
+	identity, found := manager.identities[name]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", p2p.ErrIdentityNotFound, name)
+	}
+
+	return identity, nil
+}
@@ -8,22 +8,89 @@ import (
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p/message"
 	"github.com/TerraDharitri/drt-go-chain-core/core"
 	"github.com/TerraDharitri/drt-go-chain-core/core/check"
+	"github.com/TerraDharitri/drt-go-chain-core/data/batch"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core/peer"
 )
 
 const currentTopicMessageVersion = uint32(1)
 
+// batchTopicMessageVersion marks a topic message whose payload is a batch.Batch holding several payloads
+const batchTopicMessageVersion = uint32(2)
+
 // NewMessage returns a new instance of a Message object
 func NewMessage(msg *pubsub.Message, marshaller p2p.Marshaller, broadcastMethod p2p.BroadcastMethod) (*message.Message, error) {
+	newMsg, _, err := newMessage(msg, marshaller, broadcastMethod, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return newMsg, nil
+}
+
+// NewMessages returns the Message objects carried by a pubsub message. A batched message is unpacked in one
+// Message object for each of its payloads, the other fields being the ones of the pubsub message. Unlike NewMessage,
+// it also accepts the attestation fields, which should be verified by the caller before being trusted
+func NewMessages(msg *pubsub.Message, marshaller p2p.Marshaller, broadcastMethod p2p.BroadcastMethod) ([]*message.Message, error) {
+	return newMessages(msg, marshaller, broadcastMethod, true, true)
+}
+
+func newMessages(
+	msg *pubsub.Message,
+	marshaller p2p.Marshaller,
+	broadcastMethod p2p.BroadcastMethod,
+	acceptBatches bool,
+	acceptAttestation bool,
+) ([]*message.Message, error) {
+	newMsg, topicMessage, err := newMessage(msg, marshaller, broadcastMethod, true)
+	if err != nil {
+		return nil, err
+	}
+	if !acceptAttestation && len(topicMessage.Pk) > 0 {
+		return nil, fmt.Errorf("%w for topicMessage.SignatureOnPid and topicMessage.Pk: %s",
+			p2p.ErrUnsupportedFields, p2p.ErrAttestationNotEnabled)
+	}
+	if topicMessage.Version == currentTopicMessageVersion {
+		return []*message.Message{newMsg}, nil
+	}
+	if !acceptBatches {
+		return nil, fmt.Errorf("%w, supported %d, got %d: %s",
+			p2p.ErrUnsupportedMessageVersion, currentTopicMessageVersion, topicMessage.Version, p2p.ErrBatchedMessagesNotEnabled)
+	}
+
+	b := &batch.Batch{}
+	err = marshaller.Unmarshal(b, topicMessage.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w error: %s", p2p.ErrMessageUnmarshalError, err.Error())
+	}
+	if len(b.Data) == 0 {
+		return nil, p2p.ErrEmptyMessagesBatch
+	}
+
+	messages := make([]*message.Message, 0, len(b.Data))
+	for _, payload := range b.Data {
+		batchedMsg := *newMsg
+		batchedMsg.DataField = payload
+		messages = append(messages, &batchedMsg)
+	}
+
+	return messages, nil
+}
+
+func newMessage(
+	msg *pubsub.Message,
+	marshaller p2p.Marshaller,
+	broadcastMethod p2p.BroadcastMethod,
+	isBroadcastPath bool,
+) (*message.Message, *data.TopicMessage, error) {
 	if check.IfNil(marshaller) {
-		return nil, p2p.ErrNilMarshaller
+		return nil, nil, p2p.ErrNilMarshaller
 	}
 	if msg == nil {
-		return nil, p2p.ErrNilMessage
+		return nil, nil, p2p.ErrNilMessage
 	}
 	if msg.Topic == nil {
-		return nil, p2p.ErrNilTopic
+		return nil, nil, p2p.ErrNilTopic
 	}
 
 	newMsg := &message.Message{
@@ -39,28 +106,33 @@ func NewMessage(msg *pubsub.Message, marshaller p2p.Marshaller, broadcastMethod
 	topicMessage := &data.TopicMessage{}
 	err := marshaller.Unmarshal(topicMessage, msg.Data)
 	if err != nil {
-		return nil, fmt.Errorf("%w error: %s", p2p.ErrMessageUnmarshalError, err.Error())
+		return nil, nil, fmt.Errorf("%w error: %s", p2p.ErrMessageUnmarshalError, err.Error())
 	}
 
-	// TODO change this area when new versions of the message will need to be implemented
-	if topicMessage.Version != currentTopicMessageVersion {
-		return nil, fmt.Errorf("%w, supported %d, got %d",
+	isSupportedVersion := topicMessage.Version == currentTopicMessageVersion ||
+		(isBroadcastPath && topicMessage.Version == batchTopicMessageVersion)
+	if !isSupportedVersion {
+		return nil, nil, fmt.Errorf("%w, supported %d, got %d",
 			p2p.ErrUnsupportedMessageVersion, currentTopicMessageVersion, topicMessage.Version)
 	}
 
-	if len(topicMessage.SignatureOnPid)+len(topicMessage.Pk) > 0 {
-		return nil, fmt.Errorf("%w for topicMessage.SignatureOnPid and topicMessage.Pk",
+	hasPk := len(topicMessage.Pk) > 0
+	hasSignatureOnPid := len(topicMessage.SignatureOnPid) > 0
+	if hasPk != hasSignatureOnPid || (hasPk && !isBroadcastPath) {
+		return nil, nil, fmt.Errorf("%w for topicMessage.SignatureOnPid and topicMessage.Pk",
 			p2p.ErrUnsupportedFields)
 	}
 
 	newMsg.DataField = topicMessage.Payload
+	newMsg.AttestedPkField = topicMessage.Pk
+	newMsg.SignatureOnPidField = topicMessage.SignatureOnPid
 	newMsg.TimestampField = topicMessage.Timestamp
 
 	id, err := peer.IDFromBytes(newMsg.From())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	newMsg.PeerField = core.PeerID(id)
-	return newMsg, nil
+	return newMsg, topicMessage, nil
 }
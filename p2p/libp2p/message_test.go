@@ -12,6 +12,7 @@ import (
 	"github.com/TerraDharitri/drt-go-chain-communication/testscommon"
 	"github.com/TerraDharitri/drt-go-chain-core/core"
 	"github.com/TerraDharitri/drt-go-chain-core/core/check"
+	"github.com/TerraDharitri/drt-go-chain-core/data/batch"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	pb "github.com/libp2p/go-libp2p-pubsub/pb"
 	libp2pCrypto "github.com/libp2p/go-libp2p/core/crypto"
@@ -246,3 +247,139 @@ func TestMessage_NilMessage(t *testing.T) {
 	assert.Equal(t, p2p.ErrNilMessage, err)
 	assert.True(t, check.IfNil(m))
 }
+
+func createPubSubMessageWithPayload(marshalizer *testscommon.ProtoMarshallerMock, version uint32, payload []byte) *pubsub.Message {
+	topicMessage := &data.TopicMessage{
+		Version:   version,
+		Timestamp: time.Now().Unix(),
+		Payload:   payload,
+	}
+	buff, _ := marshalizer.Marshal(topicMessage)
+	topic := "topic"
+	mes := &pb.Message{
+		From:  getRandomID(),
+		Data:  buff,
+		Topic: &topic,
+	}
+
+	return &pubsub.Message{Message: mes}
+}
+
+func TestMessage_BatchVersionShouldErr(t *testing.T) {
+	t.Parallel()
+
+	marshalizer := &testscommon.ProtoMarshallerMock{}
+	batchBuff, _ := marshalizer.Marshal(&batch.Batch{Data: [][]byte{[]byte("data")}})
+	pMes := createPubSubMessageWithPayload(marshalizer, libp2p.BatchTopicMessageVersion, batchBuff)
+	m, err := libp2p.NewMessage(pMes, marshalizer, p2p.Broadcast)
+
+	assert.True(t, check.IfNil(m))
+	assert.True(t, errors.Is(err, p2p.ErrUnsupportedMessageVersion))
+}
+
+func TestNewMessages(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid message should error", func(t *testing.T) {
+		t.Parallel()
+
+		messages, err := libp2p.NewMessages(nil, &testscommon.ProtoMarshallerMock{}, p2p.Broadcast)
+		assert.Equal(t, p2p.ErrNilMessage, err)
+		assert.Nil(t, messages)
+	})
+	t.Run("unsupported version should error", func(t *testing.T) {
+		t.Parallel()
+
+		marshalizer := &testscommon.ProtoMarshallerMock{}
+		pMes := createPubSubMessageWithPayload(marshalizer, libp2p.BatchTopicMessageVersion+1, []byte("data"))
+		messages, err := libp2p.NewMessages(pMes, marshalizer, p2p.Broadcast)
+		assert.True(t, errors.Is(err, p2p.ErrUnsupportedMessageVersion))
+		assert.Nil(t, messages)
+	})
+	t.Run("regular message should return one message", func(t *testing.T) {
+		t.Parallel()
+
+		marshalizer := &testscommon.ProtoMarshallerMock{}
+		pMes := createPubSubMessageWithPayload(marshalizer, libp2p.CurrentTopicMessageVersion, []byte("data"))
+		messages, err := libp2p.NewMessages(pMes, marshalizer, p2p.Broadcast)
+		require.Nil(t, err)
+		require.Equal(t, 1, len(messages))
+		assert.Equal(t, []byte("data"), messages[0].Data())
+	})
+	t.Run("invalid batch should error", func(t *testing.T) {
+		t.Parallel()
+
+		marshalizer := &testscommon.ProtoMarshallerMock{}
+		pMes := createPubSubMessageWithPayload(marshalizer, libp2p.BatchTopicMessageVersion, []byte("not a batch"))
+		messages, err := libp2p.NewMessages(pMes, marshalizer, p2p.Broadcast)
+		assert.True(t, errors.Is(err, p2p.ErrMessageUnmarshalError))
+		assert.Nil(t, messages)
+	})
+	t.Run("empty batch should error", func(t *testing.T) {
+		t.Parallel()
+
+		marshalizer := &testscommon.ProtoMarshallerMock{}
+		batchBuff, _ := marshalizer.Marshal(&batch.Batch{})
+		pMes := createPubSubMessageWithPayload(marshalizer, libp2p.BatchTopicMessageVersion, batchBuff)
+		messages, err := libp2p.NewMessages(pMes, marshalizer, p2p.Broadcast)
+		assert.Equal(t, p2p.ErrEmptyMessagesBatch, err)
+		assert.Nil(t, messages)
+	})
+	t.Run("incomplete attestation should error", func(t *testing.T) {
+		t.Parallel()
+
+		marshalizer := &testscommon.ProtoMarshallerMock{}
+		topicMessage := &data.TopicMessage{
+			Version:   libp2p.CurrentTopicMessageVersion,
+			Timestamp: time.Now().Unix(),
+			Payload:   []byte("data"),
+			Pk:        []byte("pk"),
+		}
+		buff, _ := marshalizer.Marshal(topicMessage)
+		pMes := createPubSubMessageWithPayload(marshalizer, libp2p.CurrentTopicMessageVersion, nil)
+		pMes.Data = buff
+		messages, err := libp2p.NewMessages(pMes, marshalizer, p2p.Broadcast)
+		assert.True(t, errors.Is(err, p2p.ErrUnsupportedFields))
+		assert.Nil(t, messages)
+	})
+	t.Run("attestation should be set on all the messages of a batch", func(t *testing.T) {
+		t.Parallel()
+
+		marshalizer := &testscommon.ProtoMarshallerMock{}
+		batchBuff, _ := marshalizer.Marshal(&batch.Batch{Data: [][]byte{[]byte("data 1"), []byte("data 2")}})
+		topicMessage := &data.TopicMessage{
+			Version:        libp2p.BatchTopicMessageVersion,
+			Timestamp:      time.Now().Unix(),
+			Payload:        batchBuff,
+			Pk:             []byte("pk"),
+			SignatureOnPid: []byte("sig"),
+		}
+		buff, _ := marshalizer.Marshal(topicMessage)
+		pMes := createPubSubMessageWithPayload(marshalizer, libp2p.CurrentTopicMessageVersion, nil)
+		pMes.Data = buff
+		messages, err := libp2p.NewMessages(pMes, marshalizer, p2p.Broadcast)
+		require.Nil(t, err)
+		require.Equal(t, 2, len(messages))
+		for _, m := range messages {
+			assert.Equal(t, []byte("pk"), m.AttestedPk())
+			assert.Equal(t, []byte("sig"), m.SignatureOnPid())
+		}
+	})
+	t.Run("batch should return one message for each payload", func(t *testing.T) {
+		t.Parallel()
+
+		marshalizer := &testscommon.ProtoMarshallerMock{}
+		payloads := [][]byte{[]byte("data 1"), []byte("data 2")}
+		batchBuff, _ := marshalizer.Marshal(&batch.Batch{Data: payloads})
+		pMes := createPubSubMessageWithPayload(marshalizer, libp2p.BatchTopicMessageVersion, batchBuff)
+		messages, err := libp2p.NewMessages(pMes, marshalizer, p2p.Broadcast)
+		require.Nil(t, err)
+		require.Equal(t, len(payloads), len(messages))
+		for i, m := range messages {
+			assert.Equal(t, payloads[i], m.Data())
+			assert.Equal(t, "topic", m.Topic())
+			assert.Equal(t, messages[0].Peer(), m.Peer())
+			assert.Equal(t, messages[0].Timestamp(), m.Timestamp())
+		}
+	})
+}
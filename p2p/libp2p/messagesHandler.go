@@ -3,16 +3,21 @@ package libp2p
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/config"
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p/data"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p/crypto"
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p/disabled"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/message"
 	"github.com/TerraDharitri/drt-go-chain-core/core"
 	"github.com/TerraDharitri/drt-go-chain-core/core/check"
+	"github.com/TerraDharitri/drt-go-chain-core/data/batch"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	pubsubPb "github.com/libp2p/go-libp2p-pubsub/pb"
 	libp2pCrypto "github.com/libp2p/go-libp2p/core/crypto"
@@ -24,21 +29,27 @@ import (
 var messageHeader = 64 * 1024 // 64kB
 var maxSendBuffSize = (1 << 21) - messageHeader
 
-const durationBetweenSends = time.Microsecond * 10
-
 // ArgMessagesHandler is the DTO struct used to create a new instance of messages handler
 type ArgMessagesHandler struct {
-	PubSub             PubSub
-	DirectSender       p2p.DirectSender
-	Throttler          core.Throttler
-	OutgoingCLB        ChannelLoadBalancer
-	Marshaller         p2p.Marshaller
-	ConnMonitor        ConnectionMonitor
-	PeersRatingHandler p2p.PeersRatingHandler
-	SyncTimer          p2p.SyncTimer
-	PeerID             core.PeerID
-	NetworkType        p2p.NetworkType
-	Logger             p2p.Logger
+	PubSub                 PubSub
+	DirectSender           p2p.DirectSender
+	Throttler              core.Throttler
+	OutgoingCLB            ChannelLoadBalancer
+	Marshaller             p2p.Marshaller
+	ConnMonitor            ConnectionMonitor
+	PeersRatingHandler     p2p.PeersRatingHandler
+	SyncTimer              p2p.SyncTimer
+	PeerID                 core.PeerID
+	NetworkType            p2p.NetworkType
+	SeenMessagesTTL        time.Duration
+	TopicValidators        []config.TopicValidatorConfig
+	ValidatorMetrics       ValidatorMetricsHandler
+	DuplicateMessages      DuplicateMessagesTracer
+	Subscriptions          config.SubscriptionsConfig
+	Batching               []config.TopicBatchingConfig
+	BatchedMessagesEnabled bool
+	AttestationEnabled     bool
+	Logger                 p2p.Logger
 }
 
 type messagesHandler struct {
@@ -56,12 +67,30 @@ type messagesHandler struct {
 	syncTimer          p2p.SyncTimer
 	peerID             core.PeerID
 	networkType        p2p.NetworkType
+	seenMessagesTTL    time.Duration
+	topicValidators    map[string]topicValidatorSettings
+	validatorMetrics   ValidatorMetricsHandler
+	duplicateMessages  DuplicateMessagesTracer
+	subscribers        *topicSubscribers
+	batchers           map[string]*topicBatcher
+	batchedMsgsEnabled bool
+	attestationEnabled bool
+	keys               *keysManager
 	log                p2p.Logger
 
-	mutTopics     sync.RWMutex
-	processors    map[string]TopicProcessor
-	topics        map[string]PubSubTopic
-	subscriptions map[string]PubSubSubscription
+	mutAttestation       sync.RWMutex
+	attestationPk        []byte
+	attestationSignature []byte
+	attestationVerifier  p2p.AttestationVerifier
+
+	mutMessageObserver sync.RWMutex
+	messageObserver    p2p.MessageObserver
+
+	mutTopics         sync.RWMutex
+	processors        map[string]TopicProcessor
+	topics            map[string]PubSubTopic
+	subscriptions     map[string]PubSubSubscription
+	publishOnlyTopics map[string]struct{}
 }
 
 // NewMessagesHandler creates a new instance of messages handler
@@ -71,6 +100,16 @@ func NewMessagesHandler(args ArgMessagesHandler) (*messagesHandler, error) {
 		return nil, err
 	}
 
+	topicValidators, err := createTopicValidatorSettings(args.TopicValidators)
+	if err != nil {
+		return nil, err
+	}
+
+	subscribers, err := newTopicSubscribers(args.Subscriptions, args.Logger)
+	if err != nil {
+		return nil, err
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	handler := &messagesHandler{
 		ctx:                ctx,
@@ -88,16 +127,31 @@ func NewMessagesHandler(args ArgMessagesHandler) (*messagesHandler, error) {
 		processors:         make(map[string]TopicProcessor),
 		topics:             make(map[string]PubSubTopic),
 		subscriptions:      make(map[string]PubSubSubscription),
+		publishOnlyTopics:  make(map[string]struct{}),
 		networkType:        args.NetworkType,
+		seenMessagesTTL:    args.SeenMessagesTTL,
+		topicValidators:    topicValidators,
+		validatorMetrics:   args.ValidatorMetrics,
+		duplicateMessages:  args.DuplicateMessages,
+		subscribers:        subscribers,
+		batchedMsgsEnabled: args.BatchedMessagesEnabled,
+		attestationEnabled: args.AttestationEnabled,
+		keys:               newKeysManager(),
 		log:                args.Logger,
 	}
 
+	handler.batchers, err = createTopicBatchers(args.Batching, handler.sendBatch)
+	if err != nil {
+		return nil, err
+	}
+
 	err = handler.directSender.RegisterDirectMessageProcessor(handler)
 	if err != nil {
 		return nil, err
 	}
 
 	go handler.processChannelLoadBalancer(handler.outgoingCLB)
+	go handler.processDuplicateMessages()
 
 	return handler, nil
 }
@@ -130,6 +184,18 @@ func checkArgMessagesHandler(args ArgMessagesHandler) error {
 	if check.IfNil(args.Logger) {
 		return p2p.ErrNilLogger
 	}
+	if check.IfNil(args.ValidatorMetrics) {
+		return p2p.ErrNilValidatorMetrics
+	}
+	if check.IfNil(args.DuplicateMessages) {
+		return p2p.ErrNilDuplicateMessagesTracer
+	}
+	if args.SeenMessagesTTL < time.Second {
+		return fmt.Errorf("%w for SeenMessagesTTL, minimum %v", p2p.ErrInvalidDurationProvided, time.Second)
+	}
+	if len(args.Batching) > 0 && !args.BatchedMessagesEnabled {
+		return fmt.Errorf("%w, required by the batching config", p2p.ErrBatchedMessagesNotEnabled)
+	}
 
 	return nil
 }
@@ -137,16 +203,20 @@ func checkArgMessagesHandler(args ArgMessagesHandler) error {
 func (handler *messagesHandler) processChannelLoadBalancer(outgoingCLB ChannelLoadBalancer) {
 	for {
 		select {
-		case <-time.After(durationBetweenSends):
 		case <-handler.ctx.Done():
 			handler.log.Debug("closing messages handler's send from channel load balancer go routine")
 			return
+		default:
 		}
 
 		sendableData := outgoingCLB.CollectOneElementFromChannels()
 		if sendableData == nil {
 			continue
 		}
+		if sendableData.Ctx != nil && sendableData.Ctx.Err() != nil {
+			sendableData.notify(sendableData.Ctx.Err())
+			continue
+		}
 
 		handler.mutTopics.RLock()
 		topic := handler.topics[sendableData.Topic]
@@ -156,12 +226,14 @@ func (handler *messagesHandler) processChannelLoadBalancer(outgoingCLB ChannelLo
 				"network", handler.networkType,
 				"topic", sendableData.Topic,
 			)
+			sendableData.notify(fmt.Errorf("%w: %s", p2p.ErrTopicNotJoined, sendableData.Topic))
 
 			continue
 		}
 
-		packedSendableDataBuff := handler.createMessageBytes(sendableData.Buff)
+		packedSendableDataBuff := handler.createMessageBytesForSendable(sendableData)
 		if len(packedSendableDataBuff) == 0 {
+			sendableData.notify(p2p.ErrCannotCreateMessageBytes)
 			continue
 		}
 
@@ -169,6 +241,7 @@ func (handler *messagesHandler) processChannelLoadBalancer(outgoingCLB ChannelLo
 		if errPublish != nil {
 			handler.log.Trace("error sending data", "network", handler.networkType, "error", errPublish)
 		}
+		sendableData.notify(errPublish)
 	}
 }
 
@@ -211,14 +284,60 @@ func (handler *messagesHandler) broadcastOnChannelBlocking(channel string, topic
 
 	handler.throttler.StartProcessing()
 
+	batcher, found := handler.batchers[topic]
+	if found && batcher.canBatch(buff) {
+		batcher.add(channel, buff)
+		handler.throttler.EndProcessing()
+		return nil
+	}
+
 	sendable := &SendableData{
 		Buff:  buff,
 		Topic: topic,
 		ID:    peer.ID(handler.peerID),
 	}
-	handler.outgoingCLB.GetChannelOrDefault(channel) <- sendable
+	err = handler.pushOnOutgoingChannel(channel, sendable)
 	handler.throttler.EndProcessing()
-	return nil
+	return err
+}
+
+// pushOnOutgoingChannel blocks until the outgoing channel accepts the sendable data. It errors if the channel is
+// removed or the load balancer is closed in the meantime
+func (handler *messagesHandler) pushOnOutgoingChannel(channel string, sendable *SendableData) error {
+	ch, done := handler.outgoingCLB.GetChannelWithDoneOrDefault(channel)
+	select {
+	case ch <- sendable:
+		return nil
+	case <-done:
+		return fmt.Errorf("%w, outgoing channel %s is closed", p2p.ErrMessageDropped, channel)
+	}
+}
+
+// sendBatch sends the payloads collected by a topic batcher as a single message
+func (handler *messagesHandler) sendBatch(channel string, topic string, payloads [][]byte) {
+	sendable := &SendableData{
+		Buff:  payloads[0],
+		Topic: topic,
+		ID:    peer.ID(handler.peerID),
+	}
+	if len(payloads) > 1 {
+		batchBytes, err := handler.marshaller.Marshal(&batch.Batch{Data: payloads})
+		if err != nil {
+			handler.log.Warn("cannot create messages batch", "topic", topic, "error", err)
+			return
+		}
+
+		sendable.Buff = batchBytes
+		sendable.IsBatch = true
+	}
+
+	ch, done := handler.outgoingCLB.GetChannelWithDoneOrDefault(channel)
+	select {
+	case ch <- sendable:
+	case <-done:
+		handler.log.Trace("outgoing channel closed, dropping the messages batch", "channel", channel, "topic", topic)
+	case <-handler.ctx.Done():
+	}
 }
 
 // BroadcastUsingPrivateKey tries to send a byte buffer onto a topic using the topic name as channel
@@ -256,13 +375,22 @@ func (handler *messagesHandler) broadcastOnChannelBlockingUsingPrivateKey(
 	pid core.PeerID,
 	skBytes []byte,
 ) error {
-	id := peer.ID(pid)
-	sk, err := libp2pCrypto.UnmarshalSecp256k1PrivateKey(skBytes)
+	sk, err := crypto.UnmarshalRawLibp2pPrivateKey(skBytes)
 	if err != nil {
 		return err
 	}
 
-	err = handler.checkSendableData(buff)
+	return handler.broadcastOnChannelBlockingUsingLibp2pKey(channel, topic, buff, peer.ID(pid), sk)
+}
+
+func (handler *messagesHandler) broadcastOnChannelBlockingUsingLibp2pKey(
+	channel string,
+	topic string,
+	buff []byte,
+	id peer.ID,
+	sk libp2pCrypto.PrivKey,
+) error {
+	err := handler.checkSendableData(buff)
 	if err != nil {
 		return err
 	}
@@ -279,11 +407,130 @@ func (handler *messagesHandler) broadcastOnChannelBlockingUsingPrivateKey(
 		Sk:    sk,
 		ID:    id,
 	}
-	handler.outgoingCLB.GetChannelOrDefault(channel) <- sendable
+	err = handler.pushOnOutgoingChannel(channel, sendable)
 	handler.throttler.EndProcessing()
+	return err
+}
+
+// AddIdentity registers an identity the messages can be broadcast on behalf of. The private key is parsed and checked
+// against the provided peer ID only once. An existing identity with the same name is replaced
+func (handler *messagesHandler) AddIdentity(name string, skBytes []byte, pid core.PeerID) error {
+	return handler.keys.addIdentity(name, skBytes, pid)
+}
+
+// RemoveIdentity unregisters the identity with the provided name
+func (handler *messagesHandler) RemoveIdentity(name string) error {
+	return handler.keys.removeIdentity(name)
+}
+
+// BroadcastAs tries to send a byte buffer onto a topic on behalf of a registered identity, using the topic name as
+// channel. It errors if the identity is not registered
+func (handler *messagesHandler) BroadcastAs(identityName string, topic string, buff []byte) error {
+	return handler.BroadcastOnChannelAs(topic, identityName, topic, buff)
+}
+
+// BroadcastOnChannelAs tries to send a byte buffer onto a topic on behalf of a registered identity, using the provided
+// channel. It errors if the identity is not registered
+func (handler *messagesHandler) BroadcastOnChannelAs(channel string, identityName string, topic string, buff []byte) error {
+	identity, err := handler.keys.getIdentity(identityName)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		errBroadcast := handler.broadcastOnChannelBlockingUsingLibp2pKey(channel, topic, buff, identity.pid, identity.sk)
+		if errBroadcast != nil {
+			handler.log.Warn("p2p broadcast as identity",
+				"network", handler.networkType,
+				"identity", identityName,
+				"error", errBroadcast.Error())
+		}
+	}()
+
 	return nil
 }
 
+// BroadcastCtx sends a byte buffer onto a topic using the provided channel. The validation and throttling errors are
+// returned right away, otherwise the call waits until the message is published, dropped or the context is done
+func (handler *messagesHandler) BroadcastCtx(ctx context.Context, channel string, topic string, buff []byte) error {
+	sendable := &SendableData{
+		Buff:  buff,
+		Topic: topic,
+		ID:    peer.ID(handler.peerID),
+	}
+
+	return handler.broadcastCtx(ctx, channel, sendable)
+}
+
+// BroadcastUsingPrivateKeyCtx sends a byte buffer onto a topic using the provided channel and private key. The
+// validation and throttling errors are returned right away, otherwise the call waits until the message is published,
+// dropped or the context is done
+func (handler *messagesHandler) BroadcastUsingPrivateKeyCtx(
+	ctx context.Context,
+	channel string,
+	topic string,
+	buff []byte,
+	pid core.PeerID,
+	skBytes []byte,
+) error {
+	sk, err := crypto.UnmarshalRawLibp2pPrivateKey(skBytes)
+	if err != nil {
+		return err
+	}
+
+	sendable := &SendableData{
+		Buff:  buff,
+		Topic: topic,
+		Sk:    sk,
+		ID:    peer.ID(pid),
+	}
+
+	return handler.broadcastCtx(ctx, channel, sendable)
+}
+
+func (handler *messagesHandler) broadcastCtx(ctx context.Context, channel string, sendable *SendableData) error {
+	if ctx == nil {
+		return p2p.ErrNilContext
+	}
+
+	err := handler.checkSendableData(sendable.Buff)
+	if err != nil {
+		return err
+	}
+
+	if !handler.throttler.CanProcess() {
+		return p2p.ErrTooManyGoroutines
+	}
+
+	sendable.Ctx = ctx
+	sendable.Done = make(chan error, 1)
+
+	handler.throttler.StartProcessing()
+	ch, done := handler.outgoingCLB.GetChannelWithDoneOrDefault(channel)
+	select {
+	case ch <- sendable:
+		handler.throttler.EndProcessing()
+	case <-done:
+		handler.throttler.EndProcessing()
+		return fmt.Errorf("%w, outgoing channel %s is closed", p2p.ErrMessageDropped, channel)
+	case <-ctx.Done():
+		handler.throttler.EndProcessing()
+		return ctx.Err()
+	case <-handler.ctx.Done():
+		handler.throttler.EndProcessing()
+		return fmt.Errorf("%w, messages handler closed", p2p.ErrMessageDropped)
+	}
+
+	select {
+	case err = <-sendable.Done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-handler.ctx.Done():
+		return fmt.Errorf("%w, messages handler closed", p2p.ErrMessageDropped)
+	}
+}
+
 func (handler *messagesHandler) checkSendableData(buff []byte) error {
 	if len(buff) > maxSendBuffSize {
 		return fmt.Errorf("%w, to be sent: %d, maximum: %d", p2p.ErrMessageTooLarge, len(buff), maxSendBuffSize)
@@ -312,7 +559,8 @@ func (handler *messagesHandler) RegisterMessageProcessor(topic string, identifie
 		topicProcs = newTopicProcessors()
 		handler.processors[topic] = topicProcs
 
-		err := handler.pubSub.RegisterTopicValidator(topic, handler.pubsubCallback(topicProcs, topic))
+		validator, validatorOptions := handler.createTopicValidator(topicProcs, topic)
+		err := handler.pubSub.RegisterTopicValidator(topic, validator, validatorOptions...)
 		if err != nil {
 			return err
 		}
@@ -329,37 +577,96 @@ func (handler *messagesHandler) RegisterMessageProcessor(topic string, identifie
 func (handler *messagesHandler) pubsubCallback(topicProcs TopicProcessor, topic string) func(ctx context.Context, pid peer.ID, message *pubsub.Message) bool {
 	return func(ctx context.Context, pid peer.ID, message *pubsub.Message) bool {
 		fromConnectedPeer := core.PeerID(pid)
-		msg, err := handler.transformAndCheckMessage(message, fromConnectedPeer, topic)
+		msgs, err := handler.transformAndCheckMessages(message, fromConnectedPeer, topic)
 		if err != nil {
 			handler.log.Trace("p2p validator - new message", "error", err.Error(), "topic", topic)
 			return false
 		}
+		handler.observeMessage(msgs[0])
 
 		identifiers, msgProcessors := topicProcs.GetList()
 		messageOk := true
-		for index, msgProc := range msgProcessors {
-			err = msgProc.ProcessReceivedMessage(msg, fromConnectedPeer, handler)
-			if err != nil {
-				handler.log.Trace("p2p validator",
-					"network", handler.networkType,
-					"error", err.Error(),
-					"topic", topic,
-					"originator", p2p.MessageOriginatorPid(msg),
-					"from connected peer", p2p.PeerIdToShortString(fromConnectedPeer),
-					"seq no", p2p.MessageOriginatorSeq(msg),
-					"topic identifier", identifiers[index],
-				)
-				messageOk = false
+		// a batched message is dispatched as individual messages and it is valid only if all of them are valid
+		for _, msg := range msgs {
+			for index, msgProc := range msgProcessors {
+				err = msgProc.ProcessReceivedMessage(msg, fromConnectedPeer, handler)
+				if err != nil {
+					handler.log.Trace("p2p validator",
+						"network", handler.networkType,
+						"error", err.Error(),
+						"topic", topic,
+						"originator", p2p.MessageOriginatorPid(msg),
+						"from connected peer", p2p.PeerIdToShortString(fromConnectedPeer),
+						"seq no", p2p.MessageOriginatorSeq(msg),
+						"topic identifier", identifiers[index],
+					)
+					messageOk = false
+				}
 			}
 		}
 		handler.processDebugMessage(topic, fromConnectedPeer, uint64(len(message.Data)), !messageOk)
+		if messageOk {
+			// the subscriptions receive the same pubsub message, so they can reuse the already checked messages
+			message.ValidatorData = msgs
+		}
 
 		return messageOk
 	}
 }
 
-func (handler *messagesHandler) transformAndCheckMessage(pbMsg *pubsub.Message, pid core.PeerID, topic string) (p2p.MessageP2P, error) {
-	msg, errUnmarshal := NewMessage(pbMsg, handler.marshaller, p2p.Broadcast)
+// observeMessage provides the message, having its signature already verified by pubsub, to the message observer
+func (handler *messagesHandler) observeMessage(msg p2p.MessageP2P) {
+	observer := handler.getMessageObserver()
+	if check.IfNil(observer) {
+		return
+	}
+
+	err := observer.ObserveMessage(msg)
+	if err != nil {
+		handler.log.Trace("messagesHandler.observeMessage", "error", err.Error())
+	}
+}
+
+// processDuplicateMessages provides the messages dropped by pubsub as duplicates to the message observer, as an
+// equivocating message, signed using an already seen sequence number, never reaches the topic validators
+func (handler *messagesHandler) processDuplicateMessages() {
+	for {
+		select {
+		case <-handler.ctx.Done():
+			return
+		case pbMsg := <-handler.duplicateMessages.DuplicateMessages():
+			handler.observeDuplicateMessage(pbMsg)
+		}
+	}
+}
+
+func (handler *messagesHandler) observeDuplicateMessage(pbMsg *pubsub.Message) {
+	observer := handler.getMessageObserver()
+	if check.IfNil(observer) {
+		return
+	}
+
+	// the duplicates are not validated, so the ones that can not be decoded are only ignored
+	msgs, err := newMessages(pbMsg, handler.marshaller, p2p.Broadcast, handler.batchedMsgsEnabled, handler.attestationEnabled)
+	if err != nil {
+		return
+	}
+
+	err = observer.ObserveDuplicateMessage(msgs[0])
+	if err != nil {
+		handler.log.Trace("messagesHandler.observeDuplicateMessage", "error", err.Error())
+	}
+}
+
+func (handler *messagesHandler) getMessageObserver() p2p.MessageObserver {
+	handler.mutMessageObserver.RLock()
+	defer handler.mutMessageObserver.RUnlock()
+
+	return handler.messageObserver
+}
+
+func (handler *messagesHandler) transformAndCheckMessages(pbMsg *pubsub.Message, pid core.PeerID, topic string) ([]p2p.MessageP2P, error) {
+	newMsgs, errUnmarshal := newMessages(pbMsg, handler.marshaller, p2p.Broadcast, handler.batchedMsgsEnabled, handler.attestationEnabled)
 	if errUnmarshal != nil {
 		// this error is so severe that will need to blacklist both the originator and the connected peer as there is
 		// no way this node can communicate with them
@@ -371,12 +678,56 @@ func (handler *messagesHandler) transformAndCheckMessage(pbMsg *pubsub.Message,
 		return nil, errUnmarshal
 	}
 
-	err := handler.checkMessage(msg, pid, topic)
+	// the messages of a batch share the timestamp, so checking the first one is enough
+	err := handler.checkMessage(newMsgs[0], pid, topic)
 	if err != nil {
 		return nil, err
 	}
 
-	return msg, nil
+	err = handler.checkAttestation(newMsgs)
+	if err != nil {
+		handler.log.Trace("received a message with an invalid attestation",
+			"network", handler.networkType,
+			"originator pid", p2p.MessageOriginatorPid(newMsgs[0]),
+			"from connected pid", p2p.PeerIdToShortString(pid),
+			"error", err,
+		)
+		return nil, err
+	}
+
+	msgs := make([]p2p.MessageP2P, 0, len(newMsgs))
+	for _, msg := range newMsgs {
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, nil
+}
+
+// checkAttestation verifies the attestation carried by the messages of the same pubsub message. Without an
+// attestation verifier the attestation can not be trusted, so it is removed from the messages
+func (handler *messagesHandler) checkAttestation(msgs []*message.Message) error {
+	if len(msgs[0].AttestedPk()) == 0 {
+		return nil
+	}
+
+	handler.mutAttestation.RLock()
+	verifier := handler.attestationVerifier
+	handler.mutAttestation.RUnlock()
+
+	if check.IfNil(verifier) {
+		for _, msg := range msgs {
+			msg.AttestedPkField = nil
+			msg.SignatureOnPidField = nil
+		}
+		return nil
+	}
+
+	err := verifier.VerifyAttestation(msgs[0].AttestedPk(), msgs[0].Peer(), msgs[0].SignatureOnPid())
+	if err != nil {
+		return fmt.Errorf("%w: %s", p2p.ErrInvalidAttestation, err.Error())
+	}
+
+	return nil
 }
 
 func (handler *messagesHandler) checkMessage(msg p2p.MessageP2P, pid core.PeerID, topic string) error {
@@ -412,7 +763,13 @@ func (handler *messagesHandler) blacklistPid(pid core.PeerID, banDuration time.D
 		"time", banDuration,
 	)
 
-	err := handler.connMonitor.PeerDenialEvaluator().UpsertPeerID(pid, banDuration)
+	err := upsertDeniedPeer(
+		handler.connMonitor.PeerDenialEvaluator(),
+		pid,
+		banDuration,
+		incompatibleMessageReason,
+		messagesHandlerDenialSource,
+	)
 	if err != nil {
 		handler.log.Warn("error blacklisting peer ID in network messenger",
 			"pid", pid.Pretty(),
@@ -422,7 +779,7 @@ func (handler *messagesHandler) blacklistPid(pid core.PeerID, banDuration time.D
 }
 
 // validateMessageByTimestamp will check that the message time stamp should be in the interval
-// (now-pubsubTimeCacheDuration+acceptMessagesInAdvanceDuration, now+acceptMessagesInAdvanceDuration)
+// (now-seenMessagesTTL+acceptMessagesInAdvanceDuration, now+acceptMessagesInAdvanceDuration)
 func (handler *messagesHandler) validateMessageByTimestamp(msg p2p.MessageP2P) error {
 	now := handler.syncTimer.CurrentTime()
 	isInFuture := now.Add(acceptMessagesInAdvanceDuration).Unix() < msg.Timestamp()
@@ -431,7 +788,7 @@ func (handler *messagesHandler) validateMessageByTimestamp(msg p2p.MessageP2P) e
 			p2p.ErrMessageTooNew, now.Unix(), msg.Timestamp())
 	}
 
-	past := now.Unix() - int64(pubsubTimeCacheDuration.Seconds())
+	past := now.Unix() - int64(handler.seenMessagesTTL.Seconds())
 	if msg.Timestamp() < past {
 		return fmt.Errorf("%w, self timestamp %d, message timestamp %d",
 			p2p.ErrMessageTooOld, now.Unix(), msg.Timestamp())
@@ -517,13 +874,19 @@ func (handler *messagesHandler) SendToConnectedPeer(topic string, buff []byte, p
 }
 
 func (handler *messagesHandler) sendDirectToSelf(topic string, buff []byte) error {
+	seqNo := handler.directSender.NextSequenceNumber()
+	signature, err := handler.directSender.SignSelfMessage(topic, buff, seqNo)
+	if err != nil {
+		return err
+	}
+
 	pubSubMsg := &pubsub.Message{
 		Message: &pubsubPb.Message{
 			From:      handler.peerID.Bytes(),
 			Data:      buff,
-			Seqno:     handler.directSender.NextSequenceNumber(),
+			Seqno:     seqNo,
 			Topic:     &topic,
-			Signature: handler.peerID.Bytes(),
+			Signature: signature,
 		},
 	}
 
@@ -599,14 +962,39 @@ func (handler *messagesHandler) increaseRatingIfNeeded(msg p2p.MessageP2P, fromC
 	}
 }
 
+func (handler *messagesHandler) createMessageBytesForSendable(sendableData *SendableData) []byte {
+	topicMessage := &data.TopicMessage{
+		Version:   currentTopicMessageVersion,
+		Payload:   sendableData.Buff,
+		Timestamp: handler.syncTimer.CurrentTime().Unix(),
+	}
+	if sendableData.IsBatch {
+		topicMessage.Version = batchTopicMessageVersion
+	}
+	// the attestation binds the key to this node's peer ID, so it can not be attached to messages sent on behalf
+	// of other peer IDs
+	if sendableData.Sk == nil && handler.attestationEnabled {
+		handler.mutAttestation.RLock()
+		topicMessage.Pk = handler.attestationPk
+		topicMessage.SignatureOnPid = handler.attestationSignature
+		handler.mutAttestation.RUnlock()
+	}
+
+	return handler.marshalTopicMessage(topicMessage)
+}
+
 func (handler *messagesHandler) createMessageBytes(buff []byte) []byte {
-	message := &data.TopicMessage{
+	topicMessage := &data.TopicMessage{
 		Version:   currentTopicMessageVersion,
 		Payload:   buff,
 		Timestamp: handler.syncTimer.CurrentTime().Unix(),
 	}
 
-	buffToSend, errMarshal := handler.marshaller.Marshal(message)
+	return handler.marshalTopicMessage(topicMessage)
+}
+
+func (handler *messagesHandler) marshalTopicMessage(topicMessage *data.TopicMessage) []byte {
+	buffToSend, errMarshal := handler.marshaller.Marshal(topicMessage)
 	if errMarshal != nil {
 		handler.log.Warn("error sending data", "error", errMarshal)
 		return nil
@@ -617,12 +1005,28 @@ func (handler *messagesHandler) createMessageBytes(buff []byte) []byte {
 
 // CreateTopic opens a new topic using pubsub infrastructure
 func (handler *messagesHandler) CreateTopic(name string, createChannelForTopic bool) error {
+	return handler.createTopic(name, createChannelForTopic, true)
+}
+
+// CreatePublishOnlyTopic joins a topic without subscribing to it, so the node can publish on the topic without
+// receiving its traffic. Calling CreateTopic afterwards will subscribe to the already joined topic
+func (handler *messagesHandler) CreatePublishOnlyTopic(name string, createChannelForTopic bool) error {
+	return handler.createTopic(name, createChannelForTopic, false)
+}
+
+func (handler *messagesHandler) createTopic(name string, createChannelForTopic bool, subscribe bool) error {
 	handler.mutTopics.Lock()
 	defer handler.mutTopics.Unlock()
 
-	_, found := handler.topics[name]
+	topic, found := handler.topics[name]
 	if found {
-		return nil
+		_, isPublishOnly := handler.publishOnlyTopics[name]
+		if !isPublishOnly || !subscribe {
+			return nil
+		}
+
+		delete(handler.publishOnlyTopics, name)
+		return handler.subscribeToTopic(name, topic)
 	}
 
 	topic, err := handler.pubSub.Join(name)
@@ -631,32 +1035,139 @@ func (handler *messagesHandler) CreateTopic(name string, createChannelForTopic b
 	}
 
 	handler.topics[name] = topic
+	if subscribe {
+		err = handler.subscribeToTopic(name, topic)
+		if err != nil {
+			return err
+		}
+	} else {
+		handler.publishOnlyTopics[name] = struct{}{}
+	}
+
+	if createChannelForTopic {
+		return handler.outgoingCLB.AddChannel(name)
+	}
+
+	return nil
+}
+
+func (handler *messagesHandler) subscribeToTopic(name string, topic PubSubTopic) error {
 	subscrRequest, err := topic.Subscribe()
 	if err != nil {
 		return fmt.Errorf("%w for topic %s", err, name)
 	}
 
 	handler.subscriptions[name] = subscrRequest
-	if createChannelForTopic {
-		err = handler.outgoingCLB.AddChannel(name)
+	go handler.consumeSubscription(subscrRequest)
+
+	return nil
+}
+
+// LeaveTopic cancels the subscription of the topic and the channels returned by Subscribe for it, unregisters its
+// validator and message processors, removes its outgoing channel and closes the pubsub topic. Leaving a topic that
+// was not created does nothing
+func (handler *messagesHandler) LeaveTopic(name string) error {
+	handler.mutTopics.Lock()
+	defer handler.mutTopics.Unlock()
+
+	topic, found := handler.topics[name]
+	if !found {
+		return nil
 	}
 
-	// just a dummy func to consume messages received by the newly created topic
-	go func() {
-		var errSubscrNext error
-		for {
-			_, errSubscrNext = subscrRequest.Next(handler.ctx)
-			if errSubscrNext != nil {
-				handler.log.Debug("closed subscription",
-					"topic", subscrRequest.Topic(),
-					"err", errSubscrNext,
-				)
-				return
-			}
+	subscription := handler.subscriptions[name]
+	if subscription != nil {
+		subscription.Cancel()
+	}
+	delete(handler.subscriptions, name)
+	delete(handler.publishOnlyTopics, name)
+	handler.subscribers.cancelTopic(name)
+
+	if handler.processors[name] != nil {
+		err := handler.pubSub.UnregisterTopicValidator(name)
+		if err != nil {
+			return fmt.Errorf("%w while unregistering the validator of topic %s", err, name)
 		}
-	}()
+	}
+	delete(handler.processors, name)
 
-	return err
+	err := handler.outgoingCLB.RemoveChannel(name)
+	if err != nil && !errors.Is(err, p2p.ErrChannelDoesNotExist) {
+		return fmt.Errorf("%w while removing the outgoing channel of topic %s", err, name)
+	}
+
+	delete(handler.topics, name)
+	err = topic.Close()
+	if err != nil {
+		return fmt.Errorf("%w while closing topic %s", err, name)
+	}
+
+	return nil
+}
+
+// consumeSubscription reads the validated messages of a topic and delivers them to the channels returned by Subscribe
+func (handler *messagesHandler) consumeSubscription(subscrRequest PubSubSubscription) {
+	for {
+		pbMsg, errSubscrNext := subscrRequest.Next(handler.ctx)
+		if errSubscrNext != nil {
+			handler.log.Debug("closed subscription",
+				"topic", subscrRequest.Topic(),
+				"err", errSubscrNext,
+			)
+			return
+		}
+		if pbMsg == nil || !handler.subscribers.hasSubscribers(pbMsg.GetTopic()) {
+			continue
+		}
+
+		msgs, err := handler.messagesForSubscribers(pbMsg)
+		if err != nil {
+			handler.log.Trace("cannot deliver message to subscribers", "topic", pbMsg.GetTopic(), "error", err)
+			continue
+		}
+
+		for _, msg := range msgs {
+			handler.subscribers.deliver(msg)
+		}
+	}
+}
+
+// messagesForSubscribers returns the messages stored by the topic validator. The pubsub messages that did not pass
+// through this node's validator (topics without registered processors) are parsed and checked here
+func (handler *messagesHandler) messagesForSubscribers(pbMsg *pubsub.Message) ([]p2p.MessageP2P, error) {
+	validatedMsgs, ok := pbMsg.ValidatorData.([]p2p.MessageP2P)
+	if ok {
+		return validatedMsgs, nil
+	}
+
+	newMsgs, err := newMessages(pbMsg, handler.marshaller, p2p.Broadcast, handler.batchedMsgsEnabled, handler.attestationEnabled)
+	if err != nil {
+		return nil, err
+	}
+	// the messages of a batch share the timestamp, so checking the first one is enough
+	err = handler.checkMessage(newMsgs[0], core.PeerID(pbMsg.ReceivedFrom), pbMsg.GetTopic())
+	if err != nil {
+		return nil, err
+	}
+	err = handler.checkAttestation(newMsgs)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make([]p2p.MessageP2P, 0, len(newMsgs))
+	for _, msg := range newMsgs {
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, nil
+}
+
+// Subscribe returns a channel on which the validated messages of the provided topic are delivered in arrival order
+// and the function that cancels the subscription. The messages received while the channel is full are dropped
+// according to the configured drop policy. The channel is closed when the subscription is cancelled or when the
+// messages handler is closed
+func (handler *messagesHandler) Subscribe(topic string) (<-chan p2p.MessageP2P, func()) {
+	return handler.subscribers.subscribe(topic)
 }
 
 // HasTopic returns true if the topic has been created
@@ -679,6 +1190,9 @@ func (handler *messagesHandler) UnJoinAllTopics() error {
 		if subscription != nil {
 			subscription.Cancel()
 		}
+		delete(handler.subscriptions, topicName)
+		delete(handler.publishOnlyTopics, topicName)
+		handler.subscribers.cancelTopic(topicName)
 
 		err := t.Close()
 		if err != nil {
@@ -708,9 +1222,77 @@ func (handler *messagesHandler) SetDebugger(debugger p2p.Debugger) error {
 	return nil
 }
 
+// SetAttestation sets the public key and the signature on this node's peer ID attached to the broadcast messages.
+// Providing empty values stops attaching the attestation. The attestation can only be set if it is enabled network-wide
+func (handler *messagesHandler) SetAttestation(pk []byte, signatureOnPid []byte) error {
+	if len(pk) == 0 && len(signatureOnPid) == 0 {
+		handler.mutAttestation.Lock()
+		handler.attestationPk = nil
+		handler.attestationSignature = nil
+		handler.mutAttestation.Unlock()
+
+		return nil
+	}
+	if !handler.attestationEnabled {
+		return p2p.ErrAttestationNotEnabled
+	}
+	if len(pk) == 0 || len(signatureOnPid) == 0 {
+		return fmt.Errorf("%w, both the public key and the signature should be provided", p2p.ErrInvalidAttestation)
+	}
+
+	handler.mutAttestation.RLock()
+	verifier := handler.attestationVerifier
+	handler.mutAttestation.RUnlock()
+
+	if !check.IfNil(verifier) {
+		err := verifier.VerifyAttestation(pk, handler.peerID, signatureOnPid)
+		if err != nil {
+			return fmt.Errorf("%w: %s", p2p.ErrInvalidAttestation, err.Error())
+		}
+	}
+
+	handler.mutAttestation.Lock()
+	handler.attestationPk = pk
+	handler.attestationSignature = signatureOnPid
+	handler.mutAttestation.Unlock()
+
+	return nil
+}
+
+// SetAttestationVerifier sets the component used to verify the attestations of the received messages. Until a
+// verifier is set, the received attestations are discarded
+func (handler *messagesHandler) SetAttestationVerifier(verifier p2p.AttestationVerifier) error {
+	if check.IfNil(verifier) {
+		return p2p.ErrNilAttestationVerifier
+	}
+
+	handler.mutAttestation.Lock()
+	handler.attestationVerifier = verifier
+	handler.mutAttestation.Unlock()
+
+	return nil
+}
+
+// SetMessageObserver sets the component used to detect the equivocations of the received broadcast messages
+func (handler *messagesHandler) SetMessageObserver(observer p2p.MessageObserver) error {
+	if check.IfNil(observer) {
+		return p2p.ErrNilMessageObserver
+	}
+
+	handler.mutMessageObserver.Lock()
+	handler.messageObserver = observer
+	handler.mutMessageObserver.Unlock()
+
+	return nil
+}
+
 // Close closes the messages handler
 func (handler *messagesHandler) Close() error {
 	handler.cancelFunc()
+	handler.subscribers.close()
+	for _, batcher := range handler.batchers {
+		batcher.close()
+	}
 
 	var err error
 	handler.log.Debug("closing messages handler's outgoing load balancer...")
@@ -5,19 +5,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/config"
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p/data"
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p"
 	p2pCrypto "github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p/crypto"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p/metrics"
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p/message"
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p/mock"
 	"github.com/TerraDharitri/drt-go-chain-communication/testscommon"
 	"github.com/TerraDharitri/drt-go-chain-core/core"
 	atomicCore "github.com/TerraDharitri/drt-go-chain-core/core/atomic"
+	"github.com/TerraDharitri/drt-go-chain-core/data/batch"
 	"github.com/TerraDharitri/drt-go-chain-core/marshal"
 	"github.com/TerraDharitri/drt-go-chain-crypto/signing"
 	"github.com/TerraDharitri/drt-go-chain-crypto/signing/secp256k1"
@@ -27,6 +31,7 @@ import (
 	pubsubPb "github.com/libp2p/go-libp2p-pubsub/pb"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -57,6 +62,9 @@ func createMockArgMessagesHandler() libp2p.ArgMessagesHandler {
 		PeersRatingHandler: &mock.PeersRatingHandlerStub{},
 		SyncTimer:          &libp2p.LocalSyncTimer{},
 		PeerID:             providedPid,
+		SeenMessagesTTL:    libp2p.PubsubTimeCacheDuration,
+		ValidatorMetrics:   metrics.NewValidatorMetrics(),
+		DuplicateMessages:  libp2p.NewDuplicateMessagesTracer(10),
 		Logger:             &testscommon.LoggerStub{},
 	}
 }
@@ -136,6 +144,90 @@ func TestNewMessagesHandler(t *testing.T) {
 		assert.Equal(t, p2p.ErrNilSyncTimer, err)
 		assert.Nil(t, mh)
 	})
+	t.Run("invalid SeenMessagesTTL should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgMessagesHandler()
+		args.SeenMessagesTTL = time.Millisecond
+		mh, err := libp2p.NewMessagesHandler(args)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidDurationProvided))
+		assert.Nil(t, mh)
+	})
+	t.Run("nil ValidatorMetrics should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgMessagesHandler()
+		args.ValidatorMetrics = nil
+		mh, err := libp2p.NewMessagesHandler(args)
+		assert.Equal(t, p2p.ErrNilValidatorMetrics, err)
+		assert.Nil(t, mh)
+	})
+	t.Run("nil DuplicateMessages should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgMessagesHandler()
+		args.DuplicateMessages = nil
+		mh, err := libp2p.NewMessagesHandler(args)
+		assert.Equal(t, p2p.ErrNilDuplicateMessagesTracer, err)
+		assert.Nil(t, mh)
+	})
+	t.Run("invalid topic validators config should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgMessagesHandler()
+		args.TopicValidators = []config.TopicValidatorConfig{{Topic: providedTopic, OverloadPolicy: "block"}}
+		mh, err := libp2p.NewMessagesHandler(args)
+		assert.True(t, errors.Is(err, p2p.ErrUnknownOverloadPolicy))
+		assert.Nil(t, mh)
+
+		args.TopicValidators = []config.TopicValidatorConfig{{Topic: providedTopic}, {Topic: providedTopic}}
+		mh, err = libp2p.NewMessagesHandler(args)
+		assert.True(t, errors.Is(err, p2p.ErrDuplicatedTopic))
+		assert.Nil(t, mh)
+
+		args.TopicValidators = []config.TopicValidatorConfig{{Topic: providedTopic, Concurrency: -1}}
+		mh, err = libp2p.NewMessagesHandler(args)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidValue))
+		assert.Nil(t, mh)
+	})
+	t.Run("invalid batching config should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgMessagesHandler()
+		args.Batching = []config.TopicBatchingConfig{{Topic: providedTopic, WindowInMs: 10}}
+		mh, err := libp2p.NewMessagesHandler(args)
+		assert.True(t, errors.Is(err, p2p.ErrBatchedMessagesNotEnabled))
+		assert.Nil(t, mh)
+
+		args.BatchedMessagesEnabled = true
+		args.Batching = []config.TopicBatchingConfig{{WindowInMs: 10}}
+		mh, err = libp2p.NewMessagesHandler(args)
+		assert.True(t, errors.Is(err, p2p.ErrNilTopic))
+		assert.Nil(t, mh)
+
+		args.Batching = []config.TopicBatchingConfig{{Topic: providedTopic}}
+		mh, err = libp2p.NewMessagesHandler(args)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidValue))
+		assert.Nil(t, mh)
+
+		args.Batching = []config.TopicBatchingConfig{{Topic: providedTopic, WindowInMs: 10, MaxSizeInBytes: -1}}
+		mh, err = libp2p.NewMessagesHandler(args)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidValue))
+		assert.Nil(t, mh)
+
+		args.Batching = []config.TopicBatchingConfig{{Topic: providedTopic, WindowInMs: 10, MaxNumMessages: -1}}
+		mh, err = libp2p.NewMessagesHandler(args)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidValue))
+		assert.Nil(t, mh)
+
+		args.Batching = []config.TopicBatchingConfig{
+			{Topic: providedTopic, WindowInMs: 10},
+			{Topic: providedTopic, WindowInMs: 20},
+		}
+		mh, err = libp2p.NewMessagesHandler(args)
+		assert.True(t, errors.Is(err, p2p.ErrDuplicatedTopic))
+		assert.Nil(t, mh)
+	})
 	t.Run("RegisterMessageHandler fails", func(t *testing.T) {
 		t.Parallel()
 
@@ -312,6 +404,147 @@ func TestMessagesHandler_broadcastsUsingPrivateKey(t *testing.T) {
 	t.Run("BroadcastOnChannelUsingPrivateKey fails", testBroadcastOnChannelBlockingThrottlerCanNotProcess(skBytes, true))
 }
 
+func TestMessagesHandler_BroadcastCtx(t *testing.T) {
+	t.Parallel()
+
+	keyGen := signing.NewKeyGenerator(secp256k1.NewSecp256k1())
+	privateKey, _ := keyGen.GeneratePair()
+	skBytes, _ := privateKey.ToByteArray()
+
+	createArgsWithRealLoadBalancer := func(publishErr error) (libp2p.ArgMessagesHandler, map[string]libp2p.PubSubTopic) {
+		args := createMockArgMessagesHandler()
+		args.Throttler = &mock.ThrottlerStub{
+			CanProcessCalled: func() bool {
+				return true
+			},
+		}
+		args.OutgoingCLB, _ = libp2p.NewOutgoingChannelLoadBalancer(createMockArgsOutgoingChannelLoadBalancer())
+		topics := map[string]libp2p.PubSubTopic{
+			providedTopic: &mock.PubSubTopicStub{
+				PublishCalled: func(ctx context.Context, data []byte, opts ...pubsub.PubOpt) error {
+					return publishErr
+				},
+			},
+		}
+
+		return args, topics
+	}
+
+	t.Run("nil context should error", func(t *testing.T) {
+		t.Parallel()
+
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(createMockArgMessagesHandler())
+		err := mh.BroadcastCtx(nil, providedChannel, providedTopic, providedData)
+		assert.Equal(t, p2p.ErrNilContext, err)
+	})
+	t.Run("invalid data should error", func(t *testing.T) {
+		t.Parallel()
+
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(createMockArgMessagesHandler())
+		err := mh.BroadcastCtx(context.Background(), providedChannel, providedTopic, bytes.Repeat([]byte("a"), 1<<21))
+		assert.True(t, errors.Is(err, p2p.ErrMessageTooLarge))
+
+		err = mh.BroadcastUsingPrivateKeyCtx(context.Background(), providedChannel, providedTopic, nil, providedPid, skBytes)
+		assert.True(t, errors.Is(err, p2p.ErrEmptyBufferToSend))
+	})
+	t.Run("invalid private key should error", func(t *testing.T) {
+		t.Parallel()
+
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(createMockArgMessagesHandler())
+		err := mh.BroadcastUsingPrivateKeyCtx(context.Background(), providedChannel, providedTopic, providedData, providedPid, []byte("invalid sk"))
+		assert.NotNil(t, err)
+	})
+	t.Run("throttler can not process should error", func(t *testing.T) {
+		t.Parallel()
+
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(createMockArgMessagesHandler())
+		err := mh.BroadcastCtx(context.Background(), providedChannel, providedTopic, providedData)
+		assert.Equal(t, p2p.ErrTooManyGoroutines, err)
+	})
+	t.Run("context done while waiting for the outgoing channel should error", func(t *testing.T) {
+		t.Parallel()
+
+		numEndProcessing := uint32(0)
+		args := createMockArgMessagesHandler()
+		args.Throttler = &mock.ThrottlerStub{
+			CanProcessCalled: func() bool {
+				return true
+			},
+			EndProcessingCalled: func() {
+				atomic.AddUint32(&numEndProcessing, 1)
+			},
+		}
+		args.OutgoingCLB = &mock.ChannelLoadBalancerStub{
+			GetChannelOrDefaultCalled: func(pipe string) chan *libp2p.SendableData {
+				return make(chan *libp2p.SendableData)
+			},
+		}
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+		defer cancel()
+		err := mh.BroadcastCtx(ctx, providedChannel, providedTopic, providedData)
+		assert.Equal(t, context.DeadlineExceeded, err)
+		assert.Equal(t, uint32(1), atomic.LoadUint32(&numEndProcessing))
+	})
+	t.Run("should return the publish result", func(t *testing.T) {
+		t.Parallel()
+
+		args, topics := createArgsWithRealLoadBalancer(nil)
+		mh := libp2p.NewMessagesHandlerWithTopics(args, topics, true)
+		defer func() {
+			_ = mh.Close()
+		}()
+
+		err := mh.BroadcastCtx(context.Background(), providedChannel, providedTopic, providedData)
+		assert.Nil(t, err)
+
+		err = mh.BroadcastUsingPrivateKeyCtx(context.Background(), providedChannel, providedTopic, providedData, providedPid, skBytes)
+		assert.Nil(t, err)
+
+		args, topics = createArgsWithRealLoadBalancer(expectedError)
+		mhWithPublishError := libp2p.NewMessagesHandlerWithTopics(args, topics, true)
+		defer func() {
+			_ = mhWithPublishError.Close()
+		}()
+
+		err = mhWithPublishError.BroadcastCtx(context.Background(), providedChannel, providedTopic, providedData)
+		assert.Equal(t, expectedError, err)
+	})
+	t.Run("topic not joined should error", func(t *testing.T) {
+		t.Parallel()
+
+		args, topics := createArgsWithRealLoadBalancer(nil)
+		mh := libp2p.NewMessagesHandlerWithTopics(args, topics, true)
+		defer func() {
+			_ = mh.Close()
+		}()
+
+		err := mh.BroadcastCtx(context.Background(), providedChannel, "other topic", providedData)
+		assert.True(t, errors.Is(err, p2p.ErrTopicNotJoined))
+	})
+	t.Run("close should unblock the waiting broadcasts", func(t *testing.T) {
+		t.Parallel()
+
+		args, topics := createArgsWithRealLoadBalancer(nil)
+		mh := libp2p.NewMessagesHandlerWithTopics(args, topics, false)
+
+		chErr := make(chan error)
+		go func() {
+			chErr <- mh.BroadcastCtx(context.Background(), providedChannel, providedTopic, providedData)
+		}()
+		time.Sleep(time.Millisecond * 50)
+		_ = mh.Close()
+
+		select {
+		case err := <-chErr:
+			assert.True(t, errors.Is(err, p2p.ErrMessageDropped))
+		case <-time.After(time.Second):
+			assert.Fail(t, "timeout")
+		}
+	})
+}
+
 func testBroadcastOnChannelBlockingEmptyData(skBytes []byte) func(t *testing.T) {
 	isMultikey := len(skBytes) > 0
 	return func(t *testing.T) {
@@ -580,6 +813,66 @@ func TestMessagesHandler_pubsubCallback(t *testing.T) {
 		cb := mh.PubsubCallback(tp, providedTopic)
 		assert.True(t, cb(context.Background(), peerID, createPubSubMsgWithTimestamp(time.Now().Unix(), realPID, args.Marshaller)))
 	})
+	t.Run("batched message should dispatch each payload", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgMessagesHandler()
+		args.BatchedMessagesEnabled = true
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+		assert.NotNil(t, mh)
+
+		payloads := [][]byte{[]byte("payload 1"), []byte("payload 2"), []byte("payload 3")}
+		received := make([][]byte, 0, len(payloads))
+		tp := &mock.MessageProcessorStub{
+			ProcessMessageCalled: func(message p2p.MessageP2P, fromConnectedPeer core.PeerID, source p2p.MessageHandler) error {
+				received = append(received, message.Data())
+				return nil
+			},
+		}
+		cb := mh.PubsubCallback(tp, providedTopic)
+		assert.True(t, cb(context.Background(), peerID, createBatchedPubSubMsg(time.Now().Unix(), realPID, args.Marshaller, payloads)))
+		assert.Equal(t, payloads, received)
+	})
+	t.Run("batched message with one invalid payload should return false", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgMessagesHandler()
+		args.BatchedMessagesEnabled = true
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+		assert.NotNil(t, mh)
+
+		payloads := [][]byte{[]byte("payload 1"), []byte("invalid")}
+		numProcessed := 0
+		tp := &mock.MessageProcessorStub{
+			ProcessMessageCalled: func(message p2p.MessageP2P, fromConnectedPeer core.PeerID, source p2p.MessageHandler) error {
+				numProcessed++
+				if bytes.Equal(message.Data(), []byte("invalid")) {
+					return expectedError
+				}
+				return nil
+			},
+		}
+		cb := mh.PubsubCallback(tp, providedTopic)
+		assert.False(t, cb(context.Background(), peerID, createBatchedPubSubMsg(time.Now().Unix(), realPID, args.Marshaller, payloads)))
+		assert.Equal(t, 2, numProcessed)
+	})
+	t.Run("batched message without batched messages enabled should return false", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgMessagesHandler()
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+		assert.NotNil(t, mh)
+
+		tp := &mock.MessageProcessorStub{
+			ProcessMessageCalled: func(message p2p.MessageP2P, fromConnectedPeer core.PeerID, source p2p.MessageHandler) error {
+				assert.Fail(t, "should have not processed the message")
+				return nil
+			},
+		}
+		cb := mh.PubsubCallback(tp, providedTopic)
+		payloads := [][]byte{[]byte("payload 1"), []byte("payload 2")}
+		assert.False(t, cb(context.Background(), peerID, createBatchedPubSubMsg(time.Now().Unix(), realPID, args.Marshaller, payloads)))
+	})
 }
 
 func TestMessagesHandler_UnregisterMessageProcessor(t *testing.T) {
@@ -788,6 +1081,22 @@ func TestMessagesHandler_SendToConnectedPeer(t *testing.T) {
 		assert.NotNil(t, err)
 	})
 	realPID, _ := core.NewPeerID("QmY33RXFSbFFpxD2ZfamQvXGULFUsxAYSR2VkTXVewuMNh")
+	t.Run("send to self, SignSelfMessage fails", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgMessagesHandler()
+		args.PeerID = realPID
+		args.DirectSender = &mock.DirectSenderStub{
+			SignSelfMessageCalled: func(topic string, buff []byte, seqNo []byte) ([]byte, error) {
+				return nil, p2p.ErrUnsignedDirectMessage
+			},
+		}
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+		assert.NotNil(t, mh)
+
+		err := mh.SendToConnectedPeer(providedTopic, providedData, realPID)
+		assert.Equal(t, p2p.ErrUnsignedDirectMessage, err)
+	})
 	t.Run("send to self, check message fails", func(t *testing.T) {
 		t.Parallel()
 
@@ -1012,8 +1321,8 @@ func TestMessagesHandler_transformAndCheckMessage(t *testing.T) {
 
 		pubSubMsg := createPubSubMsgWithTimestamp(time.Now().Unix(), realPID, args.Marshaller)
 		pubSubMsg.Topic = nil // fail NewMessage
-		msg, err := mh.TransformAndCheckMessage(pubSubMsg, "pid", providedTopic)
-		assert.Nil(t, msg)
+		msgs, err := mh.TransformAndCheckMessages(pubSubMsg, "pid", providedTopic)
+		assert.Nil(t, msgs)
 		assert.NotNil(t, err)
 		assert.True(t, wasCalled)
 	})
@@ -1027,8 +1336,8 @@ func TestMessagesHandler_transformAndCheckMessage(t *testing.T) {
 		timeStamp := time.Now().Unix() + 1
 		timeStamp += int64(libp2p.AcceptMessagesInAdvanceDuration.Seconds())
 		pubSubMsg := createPubSubMsgWithTimestamp(timeStamp, realPID, args.Marshaller)
-		msg, err := mh.TransformAndCheckMessage(pubSubMsg, realPID, providedTopic)
-		assert.Nil(t, msg)
+		msgs, err := mh.TransformAndCheckMessages(pubSubMsg, realPID, providedTopic)
+		assert.Nil(t, msgs)
 		assert.True(t, errors.Is(err, p2p.ErrMessageTooNew))
 	})
 	t.Run("validate timestamp fails, message too old", func(t *testing.T) {
@@ -1042,8 +1351,8 @@ func TestMessagesHandler_transformAndCheckMessage(t *testing.T) {
 		timeStamp -= int64(libp2p.AcceptMessagesInAdvanceDuration.Seconds())
 		timeStamp -= int64(libp2p.PubsubTimeCacheDuration.Seconds())
 		pubSubMsg := createPubSubMsgWithTimestamp(timeStamp, realPID, args.Marshaller)
-		msg, err := mh.TransformAndCheckMessage(pubSubMsg, realPID, providedTopic)
-		assert.Nil(t, msg)
+		msgs, err := mh.TransformAndCheckMessages(pubSubMsg, realPID, providedTopic)
+		assert.Nil(t, msgs)
 		assert.True(t, errors.Is(err, p2p.ErrMessageTooOld))
 	})
 	t.Run("validate timestamp fails from self, message too old", func(t *testing.T) {
@@ -1058,8 +1367,8 @@ func TestMessagesHandler_transformAndCheckMessage(t *testing.T) {
 		timeStamp -= int64(libp2p.AcceptMessagesInAdvanceDuration.Seconds())
 		timeStamp -= int64(libp2p.PubsubTimeCacheDuration.Seconds())
 		pubSubMsg := createPubSubMsgWithTimestamp(timeStamp, realPID, args.Marshaller)
-		msg, err := mh.TransformAndCheckMessage(pubSubMsg, realPID, providedTopic)
-		assert.Nil(t, msg)
+		msgs, err := mh.TransformAndCheckMessages(pubSubMsg, realPID, providedTopic)
+		assert.Nil(t, msgs)
 		assert.True(t, errors.Is(err, p2p.ErrMessageTooOld))
 	})
 	t.Run("should work", func(t *testing.T) {
@@ -1070,8 +1379,8 @@ func TestMessagesHandler_transformAndCheckMessage(t *testing.T) {
 		assert.NotNil(t, mh)
 
 		pubSubMsg := createPubSubMsgWithTimestamp(time.Now().Unix(), realPID, args.Marshaller)
-		msg, err := mh.TransformAndCheckMessage(pubSubMsg, realPID, providedTopic)
-		assert.NotNil(t, msg)
+		msgs, err := mh.TransformAndCheckMessages(pubSubMsg, realPID, providedTopic)
+		assert.Equal(t, 1, len(msgs))
 		assert.Nil(t, err)
 	})
 }
@@ -1094,6 +1403,25 @@ func createPubSubMsgWithTimestamp(timestamp int64, pid core.PeerID, marshaller m
 	}
 }
 
+func createBatchedPubSubMsg(timestamp int64, pid core.PeerID, marshaller marshal.Marshalizer, payloads [][]byte) *pubsub.Message {
+	batchBuff, _ := marshaller.Marshal(&batch.Batch{Data: payloads})
+	innerMessage := &data.TopicMessage{
+		Payload:   batchBuff,
+		Timestamp: timestamp,
+		Version:   libp2p.BatchTopicMessageVersion,
+	}
+
+	buff, _ := marshaller.Marshal(innerMessage)
+	return &pubsub.Message{
+		Message: &pubsubPb.Message{
+			From:      pid.Bytes(),
+			Data:      buff,
+			Topic:     &providedTopic,
+			Signature: pid.Bytes(),
+		},
+	}
+}
+
 func TestMessagesHandler_CreateTopic(t *testing.T) {
 	t.Parallel()
 
@@ -1127,34 +1455,230 @@ func TestMessagesHandler_CreateTopic(t *testing.T) {
 	})
 }
 
-func TestMessagesHandler_HasTopic(t *testing.T) {
+func TestMessagesHandler_CreatePublishOnlyTopic(t *testing.T) {
 	t.Parallel()
 
-	mh := libp2p.NewMessagesHandlerWithNoRoutine(createMockArgMessagesHandler())
-	assert.NotNil(t, mh)
+	t.Run("pubSub Join returns error", func(t *testing.T) {
+		t.Parallel()
 
-	assert.False(t, mh.HasTopic(providedTopic))
+		args := createMockArgMessagesHandler()
+		args.PubSub = &mock.PubSubStub{
+			JoinCalled: func(topic string, opts ...pubsub.TopicOpt) (*pubsub.Topic, error) {
+				return nil, expectedError
+			},
+		}
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+
+		err := mh.CreatePublishOnlyTopic(providedTopic, false)
+		assert.True(t, errors.Is(err, expectedError))
+		assert.False(t, mh.HasTopic(providedTopic))
+	})
+	t.Run("existing topic should return nil", func(t *testing.T) {
+		t.Parallel()
+
+		topics := map[string]libp2p.PubSubTopic{
+			providedTopic: &mock.PubSubTopicStub{
+				SubscribeCalled: func(opts ...pubsub.SubOpt) (*pubsub.Subscription, error) {
+					assert.Fail(t, "should not have subscribed")
+					return nil, nil
+				},
+			},
+		}
+		args := createMockArgMessagesHandler()
+		mh := libp2p.NewMessagesHandlerWithTopics(args, topics, false)
+
+		err := mh.CreatePublishOnlyTopic(providedTopic, false)
+		assert.Nil(t, err)
+	})
 }
 
-func TestMessagesHandler_UnJoinAllTopics(t *testing.T) {
+func TestMessagesHandler_LeaveTopic(t *testing.T) {
 	t.Parallel()
 
-	counterGetAllTopics := 0
-	counterCancel := 0
-	topics := map[string]libp2p.PubSubTopic{
-		"topic1": &mock.PubSubTopicStub{
-			CloseCalled: func() error {
-				counterGetAllTopics++
+	t.Run("unknown topic should return nil", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgMessagesHandler()
+		args.OutgoingCLB = &mock.ChannelLoadBalancerStub{
+			RemoveChannelCalled: func(pipe string) error {
+				assert.Fail(t, "should not have been called")
 				return nil
 			},
-		},
-		"topic2": &mock.PubSubTopicStub{
-			CloseCalled: func() error {
-				counterGetAllTopics++
-				return expectedError
+		}
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+
+		assert.Nil(t, mh.LeaveTopic(providedTopic))
+	})
+	t.Run("should release all the topic resources", func(t *testing.T) {
+		t.Parallel()
+
+		closeCalled := false
+		cancelCalled := false
+		unregisterCalled := false
+		removedChannel := ""
+		topics := map[string]libp2p.PubSubTopic{
+			providedTopic: &mock.PubSubTopicStub{
+				CloseCalled: func() error {
+					closeCalled = true
+					return nil
+				},
 			},
-		},
-	}
+			"other topic": &mock.PubSubTopicStub{},
+		}
+		subscriptions := map[string]libp2p.PubSubSubscription{
+			providedTopic: &mock.PubSubSubscriptionStub{
+				CancelCalled: func() {
+					cancelCalled = true
+				},
+			},
+		}
+		args := createMockArgMessagesHandler()
+		args.PubSub = &mock.PubSubStub{
+			UnregisterTopicValidatorCalled: func(topic string) error {
+				assert.Equal(t, providedTopic, topic)
+				unregisterCalled = true
+				return nil
+			},
+		}
+		args.OutgoingCLB = &mock.ChannelLoadBalancerStub{
+			RemoveChannelCalled: func(pipe string) error {
+				removedChannel = pipe
+				return p2p.ErrChannelDoesNotExist
+			},
+		}
+		mh := libp2p.NewMessagesHandlerWithNoRoutineTopicsAndSubscriptions(args, topics, subscriptions)
+		err := mh.RegisterMessageProcessor(providedTopic, providedIdentifier, &mock.MessageProcessorStub{})
+		require.Nil(t, err)
+		chTopic, cancelTopic := mh.Subscribe(providedTopic)
+		chOtherTopic, cancelOtherTopic := mh.Subscribe("other topic")
+		defer cancelOtherTopic()
+
+		err = mh.LeaveTopic(providedTopic)
+		assert.Nil(t, err)
+		assert.True(t, closeCalled)
+		assert.True(t, cancelCalled)
+		assert.True(t, unregisterCalled)
+		assert.Equal(t, providedTopic, removedChannel)
+		assert.False(t, mh.HasTopic(providedTopic))
+		assert.True(t, mh.HasTopic("other topic"))
+
+		_, ok := <-chTopic
+		assert.False(t, ok)
+		cancelTopic() // cancelling after leaving the topic should not panic
+		select {
+		case <-chOtherTopic:
+			assert.Fail(t, "the subscriptions of the other topics should be kept")
+		default:
+		}
+	})
+	t.Run("errors should be returned", func(t *testing.T) {
+		t.Parallel()
+
+		topics := map[string]libp2p.PubSubTopic{
+			providedTopic: &mock.PubSubTopicStub{
+				CloseCalled: func() error {
+					return expectedError
+				},
+			},
+		}
+		args := createMockArgMessagesHandler()
+		args.OutgoingCLB = &mock.ChannelLoadBalancerStub{
+			RemoveChannelCalled: func(pipe string) error {
+				return nil
+			},
+		}
+		mh := libp2p.NewMessagesHandlerWithTopics(args, topics, false)
+		err := mh.LeaveTopic(providedTopic)
+		assert.True(t, errors.Is(err, expectedError))
+
+		topics = map[string]libp2p.PubSubTopic{
+			providedTopic: &mock.PubSubTopicStub{},
+		}
+		args.OutgoingCLB = &mock.ChannelLoadBalancerStub{
+			RemoveChannelCalled: func(pipe string) error {
+				return expectedError
+			},
+		}
+		mh = libp2p.NewMessagesHandlerWithTopics(args, topics, false)
+		err = mh.LeaveTopic(providedTopic)
+		assert.True(t, errors.Is(err, expectedError))
+	})
+}
+
+func TestMessagesHandler_LeaveTopicWhileBroadcastingShouldNotPanic(t *testing.T) {
+	t.Parallel()
+
+	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(createMockArgsOutgoingChannelLoadBalancer())
+	defer func() {
+		_ = oclb.Close()
+	}()
+	_ = oclb.AddChannel(providedTopic)
+
+	topics := map[string]libp2p.PubSubTopic{
+		providedTopic: &mock.PubSubTopicStub{},
+	}
+	args := createMockArgMessagesHandler()
+	args.Throttler = &mock.ThrottlerStub{
+		CanProcessCalled: func() bool {
+			return true
+		},
+	}
+	args.OutgoingCLB = oclb
+	mh := libp2p.NewMessagesHandlerWithTopics(args, topics, false)
+
+	numBroadcasters := 10
+	wg := sync.WaitGroup{}
+	wg.Add(numBroadcasters + 1)
+	for i := 0; i < numBroadcasters; i++ {
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 5; j++ {
+				err := mh.BroadcastOnChannelBlocking(providedTopic, providedTopic, providedData)
+				if err != nil {
+					assert.True(t, errors.Is(err, p2p.ErrMessageDropped))
+				}
+			}
+		}()
+	}
+	go func() {
+		defer wg.Done()
+
+		assert.Nil(t, mh.LeaveTopic(providedTopic))
+	}()
+
+	wg.Wait()
+	assert.False(t, mh.HasTopic(providedTopic))
+}
+
+func TestMessagesHandler_HasTopic(t *testing.T) {
+	t.Parallel()
+
+	mh := libp2p.NewMessagesHandlerWithNoRoutine(createMockArgMessagesHandler())
+	assert.NotNil(t, mh)
+
+	assert.False(t, mh.HasTopic(providedTopic))
+}
+
+func TestMessagesHandler_UnJoinAllTopics(t *testing.T) {
+	t.Parallel()
+
+	counterGetAllTopics := 0
+	counterCancel := 0
+	topics := map[string]libp2p.PubSubTopic{
+		"topic1": &mock.PubSubTopicStub{
+			CloseCalled: func() error {
+				counterGetAllTopics++
+				return nil
+			},
+		},
+		"topic2": &mock.PubSubTopicStub{
+			CloseCalled: func() error {
+				counterGetAllTopics++
+				return expectedError
+			},
+		},
+	}
 	subscriptions := map[string]libp2p.PubSubSubscription{
 		"topic1": &mock.PubSubSubscriptionStub{
 			CancelCalled: func() {
@@ -1170,11 +1694,14 @@ func TestMessagesHandler_UnJoinAllTopics(t *testing.T) {
 	args := createMockArgMessagesHandler()
 	mh := libp2p.NewMessagesHandlerWithNoRoutineTopicsAndSubscriptions(args, topics, subscriptions)
 	assert.NotNil(t, mh)
+	ch, _ := mh.Subscribe("topic1")
 
 	err := mh.UnJoinAllTopics()
 	assert.Equal(t, expectedError, err)
 	assert.Equal(t, 2, counterGetAllTopics)
 	assert.Equal(t, 2, counterCancel)
+	_, ok := <-ch
+	assert.False(t, ok)
 }
 
 func TestMessagesHandler_Close(t *testing.T) {
@@ -1306,3 +1833,920 @@ func TestMessagesHandler_IsInterfaceNil(t *testing.T) {
 	mh = libp2p.NewMessagesHandlerWithNoRoutine(createMockArgMessagesHandler())
 	assert.False(t, mh.IsInterfaceNil())
 }
+
+func TestMessagesHandler_TopicValidatorSettings(t *testing.T) {
+	t.Parallel()
+
+	realPID, _ := core.NewPeerID("QmY33RXFSbFFpxD2ZfamQvXGULFUsxAYSR2VkTXVewuMNh")
+	createHandlerAndValidator := func(
+		topicValidator config.TopicValidatorConfig,
+		processor p2p.MessageProcessor,
+	) (p2p.MessageHandler, pubsub.ValidatorEx, []pubsub.ValidatorOpt, libp2p.ArgMessagesHandler) {
+		var validator pubsub.ValidatorEx
+		var validatorOptions []pubsub.ValidatorOpt
+		args := createMockArgMessagesHandler()
+		args.TopicValidators = []config.TopicValidatorConfig{topicValidator}
+		args.PubSub = &mock.PubSubStub{
+			RegisterTopicValidatorCalled: func(topic string, val interface{}, opts ...pubsub.ValidatorOpt) error {
+				validator = val.(pubsub.ValidatorEx)
+				validatorOptions = opts
+				return nil
+			},
+		}
+		mh, err := libp2p.NewMessagesHandler(args)
+		require.Nil(t, err)
+
+		err = mh.RegisterMessageProcessor(providedTopic, providedIdentifier, processor)
+		require.Nil(t, err)
+
+		return mh, validator, validatorOptions, args
+	}
+
+	t.Run("default settings should not add validator options", func(t *testing.T) {
+		t.Parallel()
+
+		mh, validator, opts, args := createHandlerAndValidator(config.TopicValidatorConfig{Topic: "other topic"}, &mock.MessageProcessorStub{})
+		defer func() {
+			_ = mh.Close()
+		}()
+
+		assert.Empty(t, opts)
+		msg := createPubSubMsgWithTimestamp(time.Now().Unix(), realPID, args.Marshaller)
+		assert.Equal(t, pubsub.ValidationAccept, validator(context.Background(), peer.ID(realPID), msg))
+		validatedMsgs, ok := msg.ValidatorData.([]p2p.MessageP2P)
+		require.True(t, ok)
+		require.Equal(t, 1, len(validatedMsgs))
+		assert.Equal(t, providedData, validatedMsgs[0].Data())
+	})
+	t.Run("drop policy should use the pubsub throttle", func(t *testing.T) {
+		t.Parallel()
+
+		mh, _, opts, _ := createHandlerAndValidator(config.TopicValidatorConfig{
+			Topic:       providedTopic,
+			Concurrency: 2,
+			TimeoutInMs: 100,
+			Inline:      true,
+		}, &mock.MessageProcessorStub{})
+		defer func() {
+			_ = mh.Close()
+		}()
+
+		assert.Equal(t, 3, len(opts))
+	})
+	t.Run("rejected message should return reject", func(t *testing.T) {
+		t.Parallel()
+
+		mh, validator, _, args := createHandlerAndValidator(config.TopicValidatorConfig{Topic: providedTopic}, &mock.MessageProcessorStub{
+			ProcessMessageCalled: func(message p2p.MessageP2P, fromConnectedPeer core.PeerID, source p2p.MessageHandler) error {
+				return expectedError
+			},
+		})
+		defer func() {
+			_ = mh.Close()
+		}()
+
+		msg := createPubSubMsgWithTimestamp(time.Now().Unix(), realPID, args.Marshaller)
+		assert.Equal(t, pubsub.ValidationReject, validator(context.Background(), peer.ID(realPID), msg))
+		assert.Nil(t, msg.ValidatorData)
+	})
+	t.Run("ignore policy should ignore the messages over the concurrency", func(t *testing.T) {
+		t.Parallel()
+
+		chStarted := make(chan struct{})
+		chRelease := make(chan struct{})
+		mh, validator, opts, args := createHandlerAndValidator(config.TopicValidatorConfig{
+			Topic:          providedTopic,
+			Concurrency:    1,
+			OverloadPolicy: p2p.IgnoreOnOverload,
+		}, &mock.MessageProcessorStub{
+			ProcessMessageCalled: func(message p2p.MessageP2P, fromConnectedPeer core.PeerID, source p2p.MessageHandler) error {
+				close(chStarted)
+				<-chRelease
+				return nil
+			},
+		})
+		defer func() {
+			_ = mh.Close()
+		}()
+		assert.Empty(t, opts)
+
+		msg := createPubSubMsgWithTimestamp(time.Now().Unix(), realPID, args.Marshaller)
+		chResult := make(chan pubsub.ValidationResult)
+		go func() {
+			chResult <- validator(context.Background(), peer.ID(realPID), msg)
+		}()
+		<-chStarted
+
+		assert.Equal(t, pubsub.ValidationIgnore, validator(context.Background(), peer.ID(realPID), msg))
+		close(chRelease)
+		assert.Equal(t, pubsub.ValidationAccept, <-chResult)
+		assert.Equal(t, uint64(1), args.ValidatorMetrics.GetMetrics()[providedTopic].NumIgnoredOnOverload)
+	})
+	t.Run("timeout should ignore the message", func(t *testing.T) {
+		t.Parallel()
+
+		chRelease := make(chan struct{})
+		mh, validator, _, args := createHandlerAndValidator(config.TopicValidatorConfig{
+			Topic:       providedTopic,
+			TimeoutInMs: 10,
+		}, &mock.MessageProcessorStub{
+			ProcessMessageCalled: func(message p2p.MessageP2P, fromConnectedPeer core.PeerID, source p2p.MessageHandler) error {
+				<-chRelease
+				return nil
+			},
+		})
+		defer func() {
+			close(chRelease)
+			_ = mh.Close()
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		msg := createPubSubMsgWithTimestamp(time.Now().Unix(), realPID, args.Marshaller)
+		assert.Equal(t, pubsub.ValidationIgnore, validator(ctx, peer.ID(realPID), msg))
+		assert.Equal(t, uint64(1), args.ValidatorMetrics.GetMetrics()[providedTopic].NumTimeouts)
+	})
+	t.Run("timed out validation should hold the concurrency slot until the callback returns", func(t *testing.T) {
+		t.Parallel()
+
+		chRelease := make(chan struct{})
+		chDone := make(chan struct{}, 1)
+		mh, validator, _, args := createHandlerAndValidator(config.TopicValidatorConfig{
+			Topic:          providedTopic,
+			Concurrency:    1,
+			TimeoutInMs:    10,
+			OverloadPolicy: p2p.IgnoreOnOverload,
+		}, &mock.MessageProcessorStub{
+			ProcessMessageCalled: func(message p2p.MessageP2P, fromConnectedPeer core.PeerID, source p2p.MessageHandler) error {
+				<-chRelease
+				chDone <- struct{}{}
+				return nil
+			},
+		})
+		defer func() {
+			_ = mh.Close()
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		msg := createPubSubMsgWithTimestamp(time.Now().Unix(), realPID, args.Marshaller)
+		assert.Equal(t, pubsub.ValidationIgnore, validator(ctx, peer.ID(realPID), msg))
+
+		assert.Equal(t, pubsub.ValidationIgnore, validator(context.Background(), peer.ID(realPID), msg))
+		assert.Equal(t, uint64(1), args.ValidatorMetrics.GetMetrics()[providedTopic].NumIgnoredOnOverload)
+
+		close(chRelease)
+		<-chDone
+		assert.Eventually(t, func() bool {
+			return validator(context.Background(), peer.ID(realPID), msg) == pubsub.ValidationAccept
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestMessagesHandler_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	createMessage := func(topic string, payload string) p2p.MessageP2P {
+		return &message.Message{
+			TopicField: topic,
+			DataField:  []byte(payload),
+		}
+	}
+
+	t.Run("invalid subscriptions config should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgMessagesHandler()
+		args.Subscriptions = config.SubscriptionsConfig{DropPolicy: "drop-all"}
+		mh, err := libp2p.NewMessagesHandler(args)
+		assert.True(t, errors.Is(err, p2p.ErrUnknownDropPolicy))
+		assert.Nil(t, mh)
+
+		args.Subscriptions = config.SubscriptionsConfig{BufferSize: -1}
+		mh, err = libp2p.NewMessagesHandler(args)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidValue))
+		assert.Nil(t, mh)
+	})
+	t.Run("should deliver the messages of the topic in arrival order", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgMessagesHandler()
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+		ch, cancel := mh.Subscribe(providedTopic)
+		defer cancel()
+
+		mh.DeliverToSubscribers(createMessage(providedTopic, "1"))
+		mh.DeliverToSubscribers(createMessage("other topic", "2"))
+		mh.DeliverToSubscribers(createMessage(providedTopic, "3"))
+
+		assert.Equal(t, []byte("1"), (<-ch).Data())
+		assert.Equal(t, []byte("3"), (<-ch).Data())
+		assert.Equal(t, 0, len(ch))
+	})
+	t.Run("full channel with drop newest policy should drop the received messages", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgMessagesHandler()
+		args.Subscriptions = config.SubscriptionsConfig{BufferSize: 2, DropPolicy: p2p.DropNewestPolicy}
+		mh, err := libp2p.NewMessagesHandler(args)
+		require.Nil(t, err)
+		defer func() {
+			_ = mh.Close()
+		}()
+
+		ch, _ := mh.Subscribe(providedTopic)
+		for _, payload := range []string{"1", "2", "3"} {
+			mh.DeliverToSubscribers(createMessage(providedTopic, payload))
+		}
+
+		assert.Equal(t, []byte("1"), (<-ch).Data())
+		assert.Equal(t, []byte("2"), (<-ch).Data())
+		assert.Equal(t, 0, len(ch))
+	})
+	t.Run("full channel with drop oldest policy should drop the oldest messages", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgMessagesHandler()
+		args.Subscriptions = config.SubscriptionsConfig{BufferSize: 2, DropPolicy: p2p.DropOldestPolicy}
+		mh, err := libp2p.NewMessagesHandler(args)
+		require.Nil(t, err)
+		defer func() {
+			_ = mh.Close()
+		}()
+
+		ch, _ := mh.Subscribe(providedTopic)
+		for _, payload := range []string{"1", "2", "3"} {
+			mh.DeliverToSubscribers(createMessage(providedTopic, payload))
+		}
+
+		assert.Equal(t, []byte("2"), (<-ch).Data())
+		assert.Equal(t, []byte("3"), (<-ch).Data())
+		assert.Equal(t, 0, len(ch))
+	})
+	t.Run("cancel should close the channel and stop the delivery", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgMessagesHandler()
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+		ch1, cancel1 := mh.Subscribe(providedTopic)
+		ch2, cancel2 := mh.Subscribe(providedTopic)
+		defer cancel2()
+
+		cancel1()
+		cancel1() // second call should not panic
+		mh.DeliverToSubscribers(createMessage(providedTopic, "1"))
+
+		_, ok := <-ch1
+		assert.False(t, ok)
+		assert.Equal(t, []byte("1"), (<-ch2).Data())
+	})
+	t.Run("messages of topics without processors should be checked by timestamp", func(t *testing.T) {
+		t.Parallel()
+
+		realPID, _ := core.NewPeerID("QmY33RXFSbFFpxD2ZfamQvXGULFUsxAYSR2VkTXVewuMNh")
+		args := createMockArgMessagesHandler()
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+
+		msgs, err := mh.MessagesForSubscribers(createPubSubMsgWithTimestamp(time.Now().Unix(), realPID, args.Marshaller))
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(msgs))
+
+		timeStamp := time.Now().Unix() - int64(libp2p.PubsubTimeCacheDuration.Seconds()) - 1
+		msgs, err = mh.MessagesForSubscribers(createPubSubMsgWithTimestamp(timeStamp, realPID, args.Marshaller))
+		assert.Nil(t, msgs)
+		assert.True(t, errors.Is(err, p2p.ErrMessageTooOld))
+	})
+	t.Run("close should close all the channels", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgMessagesHandler()
+		mh, err := libp2p.NewMessagesHandler(args)
+		require.Nil(t, err)
+
+		ch, cancel := mh.Subscribe(providedTopic)
+		_ = mh.Close()
+		cancel()
+
+		_, ok := <-ch
+		assert.False(t, ok)
+
+		ch, _ = mh.Subscribe(providedTopic)
+		_, ok = <-ch
+		assert.False(t, ok)
+	})
+}
+
+func createMockArgMessagesHandlerWithBatching(sendables chan *libp2p.SendableData, batching config.TopicBatchingConfig) libp2p.ArgMessagesHandler {
+	args := createMockArgMessagesHandler()
+	args.Throttler = &mock.ThrottlerStub{
+		CanProcessCalled: func() bool {
+			return true
+		},
+	}
+	args.OutgoingCLB = &mock.ChannelLoadBalancerStub{
+		GetChannelOrDefaultCalled: func(pipe string) chan *libp2p.SendableData {
+			return sendables
+		},
+	}
+	args.Batching = []config.TopicBatchingConfig{batching}
+	args.BatchedMessagesEnabled = true
+
+	return args
+}
+
+func getBatchedPayloads(t *testing.T, marshaller marshal.Marshalizer, sendable *libp2p.SendableData) [][]byte {
+	if !sendable.IsBatch {
+		return [][]byte{sendable.Buff}
+	}
+
+	b := &batch.Batch{}
+	err := marshaller.Unmarshal(b, sendable.Buff)
+	require.Nil(t, err)
+
+	return b.Data
+}
+
+func TestMessagesHandler_Batching(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should send the collected payloads when the window expires", func(t *testing.T) {
+		t.Parallel()
+
+		sendables := make(chan *libp2p.SendableData, 10)
+		args := createMockArgMessagesHandlerWithBatching(sendables, config.TopicBatchingConfig{
+			Topic:      providedTopic,
+			WindowInMs: 50,
+		})
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+
+		for _, payload := range []string{"1", "2", "3"} {
+			err := mh.BroadcastOnChannelBlocking(providedChannel, providedTopic, []byte(payload))
+			assert.Nil(t, err)
+		}
+		assert.Equal(t, 0, len(sendables))
+
+		select {
+		case sendable := <-sendables:
+			assert.True(t, sendable.IsBatch)
+			assert.Equal(t, providedTopic, sendable.Topic)
+			assert.Equal(t, [][]byte{[]byte("1"), []byte("2"), []byte("3")}, getBatchedPayloads(t, args.Marshaller, sendable))
+		case <-time.After(time.Second):
+			assert.Fail(t, "timeout while waiting for the batch")
+		}
+	})
+	t.Run("a single collected payload should be sent as a regular message", func(t *testing.T) {
+		t.Parallel()
+
+		sendables := make(chan *libp2p.SendableData, 10)
+		args := createMockArgMessagesHandlerWithBatching(sendables, config.TopicBatchingConfig{
+			Topic:      providedTopic,
+			WindowInMs: 10,
+		})
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+
+		err := mh.BroadcastOnChannelBlocking(providedChannel, providedTopic, providedData)
+		assert.Nil(t, err)
+
+		select {
+		case sendable := <-sendables:
+			assert.False(t, sendable.IsBatch)
+			assert.Equal(t, providedData, sendable.Buff)
+		case <-time.After(time.Second):
+			assert.Fail(t, "timeout while waiting for the message")
+		}
+	})
+	t.Run("should send the batch when the maximum number of messages is reached", func(t *testing.T) {
+		t.Parallel()
+
+		sendables := make(chan *libp2p.SendableData, 10)
+		args := createMockArgMessagesHandlerWithBatching(sendables, config.TopicBatchingConfig{
+			Topic:          providedTopic,
+			WindowInMs:     100000,
+			MaxNumMessages: 2,
+		})
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+
+		for _, payload := range []string{"1", "2", "3"} {
+			err := mh.BroadcastOnChannelBlocking(providedChannel, providedTopic, []byte(payload))
+			assert.Nil(t, err)
+		}
+
+		require.Equal(t, 1, len(sendables))
+		assert.Equal(t, [][]byte{[]byte("1"), []byte("2")}, getBatchedPayloads(t, args.Marshaller, <-sendables))
+	})
+	t.Run("should send the batch when the maximum size is reached", func(t *testing.T) {
+		t.Parallel()
+
+		sendables := make(chan *libp2p.SendableData, 10)
+		args := createMockArgMessagesHandlerWithBatching(sendables, config.TopicBatchingConfig{
+			Topic:          providedTopic,
+			WindowInMs:     100000,
+			MaxSizeInBytes: 50,
+		})
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+
+		payload := bytes.Repeat([]byte("a"), 15)
+		for i := 0; i < 3; i++ {
+			err := mh.BroadcastOnChannelBlocking(providedChannel, providedTopic, payload)
+			assert.Nil(t, err)
+		}
+
+		require.Equal(t, 1, len(sendables))
+		assert.Equal(t, [][]byte{payload, payload}, getBatchedPayloads(t, args.Marshaller, <-sendables))
+	})
+	t.Run("should send the pending batch when the channel changes", func(t *testing.T) {
+		t.Parallel()
+
+		sendables := make(chan *libp2p.SendableData, 10)
+		args := createMockArgMessagesHandlerWithBatching(sendables, config.TopicBatchingConfig{
+			Topic:      providedTopic,
+			WindowInMs: 100000,
+		})
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+
+		_ = mh.BroadcastOnChannelBlocking(providedChannel, providedTopic, []byte("1"))
+		_ = mh.BroadcastOnChannelBlocking(providedChannel, providedTopic, []byte("2"))
+		_ = mh.BroadcastOnChannelBlocking("other channel", providedTopic, []byte("3"))
+
+		require.Equal(t, 1, len(sendables))
+		assert.Equal(t, [][]byte{[]byte("1"), []byte("2")}, getBatchedPayloads(t, args.Marshaller, <-sendables))
+	})
+	t.Run("large payloads and other topics should not be batched", func(t *testing.T) {
+		t.Parallel()
+
+		sendables := make(chan *libp2p.SendableData, 10)
+		args := createMockArgMessagesHandlerWithBatching(sendables, config.TopicBatchingConfig{
+			Topic:          providedTopic,
+			WindowInMs:     100000,
+			MaxSizeInBytes: 50,
+		})
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+
+		largePayload := bytes.Repeat([]byte("a"), 50)
+		err := mh.BroadcastOnChannelBlocking(providedChannel, providedTopic, largePayload)
+		assert.Nil(t, err)
+		err = mh.BroadcastOnChannelBlocking(providedChannel, "other topic", providedData)
+		assert.Nil(t, err)
+
+		require.Equal(t, 2, len(sendables))
+		sendable := <-sendables
+		assert.False(t, sendable.IsBatch)
+		assert.Equal(t, largePayload, sendable.Buff)
+		sendable = <-sendables
+		assert.False(t, sendable.IsBatch)
+		assert.Equal(t, providedData, sendable.Buff)
+	})
+	t.Run("the batches should be sent in the order they were collected", func(t *testing.T) {
+		t.Parallel()
+
+		// unbuffered, so the batches sent by the window timer and by the broadcasts contend on the channel
+		sendables := make(chan *libp2p.SendableData)
+		args := createMockArgMessagesHandlerWithBatching(sendables, config.TopicBatchingConfig{
+			Topic:          providedTopic,
+			WindowInMs:     1,
+			MaxNumMessages: 3,
+		})
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+
+		numPayloads := 200
+		go func() {
+			for i := 0; i < numPayloads; i++ {
+				_ = mh.BroadcastOnChannelBlocking(providedChannel, providedTopic, []byte(fmt.Sprintf("%d", i)))
+			}
+		}()
+
+		expected := 0
+		for expected < numPayloads {
+			select {
+			case sendable := <-sendables:
+				for _, payload := range getBatchedPayloads(t, args.Marshaller, sendable) {
+					require.Equal(t, fmt.Sprintf("%d", expected), string(payload))
+					expected++
+				}
+			case <-time.After(time.Second):
+				require.Fail(t, "timeout while waiting for the batches")
+			}
+		}
+	})
+	t.Run("close should drop the pending payloads", func(t *testing.T) {
+		t.Parallel()
+
+		sendables := make(chan *libp2p.SendableData, 10)
+		args := createMockArgMessagesHandlerWithBatching(sendables, config.TopicBatchingConfig{
+			Topic:      providedTopic,
+			WindowInMs: 50,
+		})
+		args.OutgoingCLB.(*mock.ChannelLoadBalancerStub).CloseCalled = func() error {
+			return nil
+		}
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+
+		_ = mh.BroadcastOnChannelBlocking(providedChannel, providedTopic, providedData)
+		_ = mh.Close()
+
+		time.Sleep(time.Millisecond * 200)
+		assert.Equal(t, 0, len(sendables))
+	})
+}
+
+func createPubSubMsgWithAttestation(pid core.PeerID, marshaller marshal.Marshalizer, pk []byte, signatureOnPid []byte) *pubsub.Message {
+	innerMessage := &data.TopicMessage{
+		Payload:        providedData,
+		Timestamp:      time.Now().Unix(),
+		Version:        libp2p.CurrentTopicMessageVersion,
+		Pk:             pk,
+		SignatureOnPid: signatureOnPid,
+	}
+
+	buff, _ := marshaller.Marshal(innerMessage)
+	return &pubsub.Message{
+		Message: &pubsubPb.Message{
+			From:      pid.Bytes(),
+			Data:      buff,
+			Topic:     &providedTopic,
+			Signature: pid.Bytes(),
+		},
+	}
+}
+
+func TestMessagesHandler_Attestation(t *testing.T) {
+	t.Parallel()
+
+	realPID, _ := core.NewPeerID("QmY33RXFSbFFpxD2ZfamQvXGULFUsxAYSR2VkTXVewuMNh")
+	providedPk := []byte("validator pk")
+	providedSignature := []byte("signature on pid")
+	createArgs := func() libp2p.ArgMessagesHandler {
+		args := createMockArgMessagesHandler()
+		args.AttestationEnabled = true
+
+		return args
+	}
+
+	t.Run("nil verifier should error", func(t *testing.T) {
+		t.Parallel()
+
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(createArgs())
+		err := mh.SetAttestationVerifier(nil)
+		assert.Equal(t, p2p.ErrNilAttestationVerifier, err)
+	})
+	t.Run("attestation not enabled should error", func(t *testing.T) {
+		t.Parallel()
+
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(createMockArgMessagesHandler())
+		err := mh.SetAttestation(providedPk, providedSignature)
+		assert.Equal(t, p2p.ErrAttestationNotEnabled, err)
+
+		err = mh.SetAttestation(nil, nil)
+		assert.Nil(t, err)
+	})
+	t.Run("incomplete attestation should error", func(t *testing.T) {
+		t.Parallel()
+
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(createArgs())
+		err := mh.SetAttestation(providedPk, nil)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidAttestation))
+
+		err = mh.SetAttestation(nil, providedSignature)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidAttestation))
+	})
+	t.Run("attestation rejected by the verifier should error", func(t *testing.T) {
+		t.Parallel()
+
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(createArgs())
+		_ = mh.SetAttestationVerifier(&mock.AttestationVerifierStub{
+			VerifyAttestationCalled: func(pk []byte, pid core.PeerID, signatureOnPid []byte) error {
+				assert.Equal(t, providedPid, pid)
+				return expectedError
+			},
+		})
+		err := mh.SetAttestation(providedPk, providedSignature)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidAttestation))
+	})
+	t.Run("should attach the attestation only to the messages sent with the own peer ID", func(t *testing.T) {
+		t.Parallel()
+
+		keyGen := signing.NewKeyGenerator(secp256k1.NewSecp256k1())
+		privateKey, _ := keyGen.GeneratePair()
+		skBytes, _ := privateKey.ToByteArray()
+
+		published := make(chan []byte, 3)
+		args := createArgs()
+		args.Throttler = &mock.ThrottlerStub{
+			CanProcessCalled: func() bool {
+				return true
+			},
+		}
+		args.OutgoingCLB, _ = libp2p.NewOutgoingChannelLoadBalancer(createMockArgsOutgoingChannelLoadBalancer())
+		topics := map[string]libp2p.PubSubTopic{
+			providedTopic: &mock.PubSubTopicStub{
+				PublishCalled: func(ctx context.Context, data []byte, opts ...pubsub.PubOpt) error {
+					published <- data
+					return nil
+				},
+			},
+		}
+		mh := libp2p.NewMessagesHandlerWithTopics(args, topics, true)
+		defer func() {
+			_ = mh.Close()
+		}()
+
+		getPublishedTopicMessage := func() *data.TopicMessage {
+			topicMessage := &data.TopicMessage{}
+			err := args.Marshaller.Unmarshal(topicMessage, <-published)
+			require.Nil(t, err)
+
+			return topicMessage
+		}
+
+		err := mh.SetAttestation(providedPk, providedSignature)
+		require.Nil(t, err)
+
+		err = mh.BroadcastCtx(context.Background(), providedChannel, providedTopic, providedData)
+		require.Nil(t, err)
+		topicMessage := getPublishedTopicMessage()
+		assert.Equal(t, providedPk, topicMessage.Pk)
+		assert.Equal(t, providedSignature, topicMessage.SignatureOnPid)
+
+		err = mh.BroadcastUsingPrivateKeyCtx(context.Background(), providedChannel, providedTopic, providedData, providedPid, skBytes)
+		require.Nil(t, err)
+		topicMessage = getPublishedTopicMessage()
+		assert.Empty(t, topicMessage.Pk)
+		assert.Empty(t, topicMessage.SignatureOnPid)
+
+		err = mh.SetAttestation(nil, nil)
+		require.Nil(t, err)
+		err = mh.BroadcastCtx(context.Background(), providedChannel, providedTopic, providedData)
+		require.Nil(t, err)
+		topicMessage = getPublishedTopicMessage()
+		assert.Empty(t, topicMessage.Pk)
+		assert.Empty(t, topicMessage.SignatureOnPid)
+	})
+	t.Run("received attestation should be rejected if not enabled", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgMessagesHandler()
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+
+		pubSubMsg := createPubSubMsgWithAttestation(realPID, args.Marshaller, providedPk, providedSignature)
+		msgs, err := mh.TransformAndCheckMessages(pubSubMsg, realPID, providedTopic)
+		assert.True(t, errors.Is(err, p2p.ErrUnsupportedFields))
+		assert.Nil(t, msgs)
+	})
+	t.Run("received attestation without a verifier should be discarded", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+
+		pubSubMsg := createPubSubMsgWithAttestation(realPID, args.Marshaller, providedPk, providedSignature)
+		msgs, err := mh.TransformAndCheckMessages(pubSubMsg, realPID, providedTopic)
+		require.Nil(t, err)
+		require.Equal(t, 1, len(msgs))
+		assert.Empty(t, msgs[0].AttestedPk())
+		assert.Empty(t, msgs[0].SignatureOnPid())
+	})
+	t.Run("received attestation should be verified against the originator", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+		_ = mh.SetAttestationVerifier(&mock.AttestationVerifierStub{
+			VerifyAttestationCalled: func(pk []byte, pid core.PeerID, signatureOnPid []byte) error {
+				assert.Equal(t, providedPk, pk)
+				assert.Equal(t, realPID, pid)
+				assert.Equal(t, providedSignature, signatureOnPid)
+				return nil
+			},
+		})
+
+		pubSubMsg := createPubSubMsgWithAttestation(realPID, args.Marshaller, providedPk, providedSignature)
+		msgs, err := mh.TransformAndCheckMessages(pubSubMsg, realPID, providedTopic)
+		require.Nil(t, err)
+		require.Equal(t, 1, len(msgs))
+		assert.Equal(t, providedPk, msgs[0].AttestedPk())
+		assert.Equal(t, providedSignature, msgs[0].SignatureOnPid())
+	})
+	t.Run("received invalid attestation should reject the message", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+		_ = mh.SetAttestationVerifier(&mock.AttestationVerifierStub{
+			VerifyAttestationCalled: func(pk []byte, pid core.PeerID, signatureOnPid []byte) error {
+				return expectedError
+			},
+		})
+
+		pubSubMsg := createPubSubMsgWithAttestation(realPID, args.Marshaller, providedPk, providedSignature)
+		msgs, err := mh.TransformAndCheckMessages(pubSubMsg, realPID, providedTopic)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidAttestation))
+		assert.Nil(t, msgs)
+	})
+	t.Run("received message without attestation should not call the verifier", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+		_ = mh.SetAttestationVerifier(&mock.AttestationVerifierStub{
+			VerifyAttestationCalled: func(pk []byte, pid core.PeerID, signatureOnPid []byte) error {
+				assert.Fail(t, "should have not been called")
+				return nil
+			},
+		})
+
+		pubSubMsg := createPubSubMsgWithTimestamp(time.Now().Unix(), realPID, args.Marshaller)
+		msgs, err := mh.TransformAndCheckMessages(pubSubMsg, realPID, providedTopic)
+		require.Nil(t, err)
+		require.Equal(t, 1, len(msgs))
+		assert.Empty(t, msgs[0].AttestedPk())
+	})
+}
+
+func TestMessagesHandler_MessageObserver(t *testing.T) {
+	t.Parallel()
+
+	realPID, _ := core.NewPeerID("QmY33RXFSbFFpxD2ZfamQvXGULFUsxAYSR2VkTXVewuMNh")
+
+	t.Run("nil observer should error", func(t *testing.T) {
+		t.Parallel()
+
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(createMockArgMessagesHandler())
+		err := mh.SetMessageObserver(nil)
+		assert.Equal(t, p2p.ErrNilMessageObserver, err)
+	})
+	t.Run("validated message should be observed", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgMessagesHandler()
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(args)
+		var observed []p2p.MessageP2P
+		err := mh.SetMessageObserver(&mock.MessageObserverStub{
+			ObserveMessageCalled: func(msg p2p.MessageP2P) error {
+				observed = append(observed, msg)
+				return nil
+			},
+			ObserveDuplicateMessageCalled: func(msg p2p.MessageP2P) error {
+				assert.Fail(t, "should have not been called")
+				return nil
+			},
+		})
+		require.Nil(t, err)
+
+		cb := mh.PubsubCallback(&mock.MessageProcessorStub{}, providedTopic)
+		assert.True(t, cb(context.Background(), peer.ID(realPID), createPubSubMsgWithTimestamp(time.Now().Unix(), realPID, args.Marshaller)))
+		assert.False(t, cb(context.Background(), peer.ID(realPID), nil))
+		require.Equal(t, 1, len(observed))
+		assert.Equal(t, realPID, observed[0].Peer())
+	})
+	t.Run("duplicate message should be observed", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgMessagesHandler()
+		args.AttestationEnabled = true
+		tracer := libp2p.NewDuplicateMessagesTracer(10)
+		args.DuplicateMessages = tracer
+		mh, err := libp2p.NewMessagesHandler(args)
+		require.Nil(t, err)
+		defer func() {
+			_ = mh.Close()
+		}()
+
+		observed := make(chan p2p.MessageP2P, 1)
+		err = mh.SetMessageObserver(&mock.MessageObserverStub{
+			ObserveDuplicateMessageCalled: func(msg p2p.MessageP2P) error {
+				observed <- msg
+				return nil
+			},
+		})
+		require.Nil(t, err)
+
+		pubSubMsg := createPubSubMsgWithAttestation(realPID, args.Marshaller, []byte("pk"), []byte("signature on pid"))
+		tracer.DuplicateMessage(&pubsub.Message{Message: &pubsubPb.Message{Topic: pubSubMsg.Topic}})
+		tracer.DuplicateMessage(pubSubMsg)
+
+		select {
+		case msg := <-observed:
+			assert.Equal(t, realPID, msg.Peer())
+			assert.Equal(t, pubSubMsg.Data, msg.Payload())
+		case <-time.After(time.Second):
+			assert.Fail(t, "duplicate message not observed")
+		}
+	})
+}
+
+func createMockArgMessagesHandlerWithSendables(sendables chan *libp2p.SendableData, channels chan string) libp2p.ArgMessagesHandler {
+	args := createMockArgMessagesHandler()
+	args.Throttler = &mock.ThrottlerStub{
+		CanProcessCalled: func() bool {
+			return true
+		},
+	}
+	args.OutgoingCLB = &mock.ChannelLoadBalancerStub{
+		GetChannelOrDefaultCalled: func(pipe string) chan *libp2p.SendableData {
+			channels <- pipe
+			return sendables
+		},
+	}
+
+	return args
+}
+
+func TestMessagesHandler_Identities(t *testing.T) {
+	t.Parallel()
+
+	secpGenerator, _ := p2pCrypto.NewIdentityGenerator(&testscommon.LoggerStub{})
+	edGenerator, _ := p2pCrypto.NewIdentityGeneratorWithKeyType(&testscommon.LoggerStub{}, p2p.Ed25519KeyType)
+
+	t.Run("invalid identities should error", func(t *testing.T) {
+		t.Parallel()
+
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(createMockArgMessagesHandler())
+		skBytes, pid, _ := secpGenerator.CreateRandomP2PIdentity()
+		_, otherPid, _ := secpGenerator.CreateRandomP2PIdentity()
+
+		err := mh.AddIdentity("", skBytes, pid)
+		assert.Equal(t, p2p.ErrEmptyIdentityName, err)
+
+		err = mh.AddIdentity("validator", []byte("invalid sk"), pid)
+		assert.NotNil(t, err)
+
+		err = mh.AddIdentity("validator", skBytes, otherPid)
+		assert.True(t, errors.Is(err, p2p.ErrPeerIDMismatch))
+
+		err = mh.BroadcastAs("validator", providedTopic, providedData)
+		assert.True(t, errors.Is(err, p2p.ErrIdentityNotFound))
+
+		err = mh.RemoveIdentity("validator")
+		assert.True(t, errors.Is(err, p2p.ErrIdentityNotFound))
+	})
+	t.Run("should broadcast as the registered identities", func(t *testing.T) {
+		t.Parallel()
+
+		sendables := make(chan *libp2p.SendableData, 10)
+		channels := make(chan string, 10)
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(createMockArgMessagesHandlerWithSendables(sendables, channels))
+
+		secpSkBytes, secpPid, _ := secpGenerator.CreateRandomP2PIdentity()
+		edSkBytes, edPid, _ := edGenerator.CreateRandomP2PIdentity()
+		require.Nil(t, mh.AddIdentity("secp", secpSkBytes, secpPid))
+		require.Nil(t, mh.AddIdentity("ed", edSkBytes, edPid))
+
+		err := mh.BroadcastAs("secp", providedTopic, providedData)
+		require.Nil(t, err)
+		sendable := <-sendables
+		assert.Equal(t, providedTopic, <-channels)
+		assert.Equal(t, peer.ID(secpPid), sendable.ID)
+		assert.Equal(t, providedData, sendable.Buff)
+		derivedPid, _ := peer.IDFromPublicKey(sendable.Sk.GetPublic())
+		assert.Equal(t, peer.ID(secpPid), derivedPid)
+
+		err = mh.BroadcastOnChannelAs(providedChannel, "ed", providedTopic, providedData)
+		require.Nil(t, err)
+		sendable = <-sendables
+		assert.Equal(t, providedChannel, <-channels)
+		assert.Equal(t, peer.ID(edPid), sendable.ID)
+		derivedPid, _ = peer.IDFromPublicKey(sendable.Sk.GetPublic())
+		assert.Equal(t, peer.ID(edPid), derivedPid)
+
+		require.Nil(t, mh.RemoveIdentity("secp"))
+		err = mh.BroadcastAs("secp", providedTopic, providedData)
+		assert.True(t, errors.Is(err, p2p.ErrIdentityNotFound))
+
+		// the identity name can be reused with another key
+		require.Nil(t, mh.AddIdentity("ed", secpSkBytes, secpPid))
+		err = mh.BroadcastAs("ed", providedTopic, providedData)
+		require.Nil(t, err)
+		sendable = <-sendables
+		assert.Equal(t, peer.ID(secpPid), sendable.ID)
+	})
+	t.Run("concurrent operations should not panic", func(t *testing.T) {
+		t.Parallel()
+
+		defer checkForPanic(t)
+
+		sendables := make(chan *libp2p.SendableData, 1000)
+		channels := make(chan string, 1000)
+		mh := libp2p.NewMessagesHandlerWithNoRoutine(createMockArgMessagesHandlerWithSendables(sendables, channels))
+		skBytes, pid, _ := secpGenerator.CreateRandomP2PIdentity()
+
+		numCalls := 300
+		wg := sync.WaitGroup{}
+		wg.Add(numCalls)
+		for i := 0; i < numCalls; i++ {
+			go func(idx int) {
+				defer wg.Done()
+
+				name := fmt.Sprintf("identity%d", idx%5)
+				switch idx % 3 {
+				case 0:
+					_ = mh.AddIdentity(name, skBytes, pid)
+				case 1:
+					_ = mh.RemoveIdentity(name)
+				case 2:
+					_ = mh.BroadcastAs(name, providedTopic, providedData)
+				}
+			}(i)
+		}
+		wg.Wait()
+	})
+}
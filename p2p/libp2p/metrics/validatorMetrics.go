@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+)
+
+// validatorMetrics counts, for each topic, the validations that could not be done because the topic was overloaded.
+// It is also a pubsub tracer, so the validations throttled by pubsub itself are counted as well
+type validatorMetrics struct {
+	mut     sync.RWMutex
+	metrics map[string]*p2p.ValidatorMetrics
+}
+
+// NewValidatorMetrics returns a new validatorMetrics instance
+func NewValidatorMetrics() *validatorMetrics {
+	return &validatorMetrics{
+		metrics: make(map[string]*p2p.ValidatorMetrics),
+	}
+}
+
+func (vm *validatorMetrics) update(topic string, handler func(metrics *p2p.ValidatorMetrics)) {
+	vm.mut.Lock()
+	defer vm.mut.Unlock()
+
+	topicMetrics, found := vm.metrics[topic]
+	if !found {
+		topicMetrics = &p2p.ValidatorMetrics{}
+		vm.metrics[topic] = topicMetrics
+	}
+
+	handler(topicMetrics)
+}
+
+// AddIgnoredOnOverload increments the number of messages ignored because the topic was at full concurrency
+func (vm *validatorMetrics) AddIgnoredOnOverload(topic string) {
+	vm.update(topic, func(metrics *p2p.ValidatorMetrics) {
+		metrics.NumIgnoredOnOverload++
+	})
+}
+
+// AddTimeout increments the number of abandoned validations
+func (vm *validatorMetrics) AddTimeout(topic string) {
+	vm.update(topic, func(metrics *p2p.ValidatorMetrics) {
+		metrics.NumTimeouts++
+	})
+}
+
+// GetMetrics returns a copy of the counters of each topic
+func (vm *validatorMetrics) GetMetrics() map[string]p2p.ValidatorMetrics {
+	vm.mut.RLock()
+	defer vm.mut.RUnlock()
+
+	metrics := make(map[string]p2p.ValidatorMetrics, len(vm.metrics))
+	for topic, topicMetrics := range vm.metrics {
+		metrics[topic] = *topicMetrics
+	}
+
+	return metrics
+}
+
+// RejectMessage is called when a message is rejected. It increments the number of throttled messages of the topic
+func (vm *validatorMetrics) RejectMessage(msg *pubsub.Message, reason string) {
+	if reason != pubsub.RejectValidationThrottled || msg == nil {
+		return
+	}
+
+	vm.update(msg.GetTopic(), func(metrics *p2p.ValidatorMetrics) {
+		metrics.NumThrottled++
+	})
+}
+
+// AddPeer does nothing
+func (vm *validatorMetrics) AddPeer(_ peer.ID, _ protocol.ID) {}
+
+// RemovePeer does nothing
+func (vm *validatorMetrics) RemovePeer(_ peer.ID) {}
+
+// Join does nothing
+func (vm *validatorMetrics) Join(_ string) {}
+
+// Leave does nothing
+func (vm *validatorMetrics) Leave(_ string) {}
+
+// Graft does nothing
+func (vm *validatorMetrics) Graft(_ peer.ID, _ string) {}
+
+// Prune does nothing
+func (vm *validatorMetrics) Prune(_ peer.ID, _ string) {}
+
+// ValidateMessage does nothing
+func (vm *validatorMetrics) ValidateMessage(_ *pubsub.Message) {}
+
+// DeliverMessage does nothing
+func (vm *validatorMetrics) DeliverMessage(_ *pubsub.Message) {}
+
+// DuplicateMessage does nothing
+func (vm *validatorMetrics) DuplicateMessage(_ *pubsub.Message) {}
+
+// ThrottlePeer does nothing
+func (vm *validatorMetrics) ThrottlePeer(_ peer.ID) {}
+
+// RecvRPC does nothing
+func (vm *validatorMetrics) RecvRPC(_ *pubsub.RPC) {}
+
+// SendRPC does nothing
+func (vm *validatorMetrics) SendRPC(_ *pubsub.RPC, _ peer.ID) {}
+
+// DropRPC does nothing
+func (vm *validatorMetrics) DropRPC(_ *pubsub.RPC, _ peer.ID) {}
+
+// UndeliverableMessage does nothing
+func (vm *validatorMetrics) UndeliverableMessage(_ *pubsub.Message) {}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (vm *validatorMetrics) IsInterfaceNil() bool {
+	return vm == nil
+}
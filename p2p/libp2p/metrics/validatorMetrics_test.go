@@ -0,0 +1,57 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p/metrics"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewValidatorMetrics(t *testing.T) {
+	t.Parallel()
+
+	vm := metrics.NewValidatorMetrics()
+	assert.False(t, vm.IsInterfaceNil())
+	assert.Empty(t, vm.GetMetrics())
+}
+
+func TestValidatorMetrics_CountersShouldWork(t *testing.T) {
+	t.Parallel()
+
+	topic := "topic"
+	vm := metrics.NewValidatorMetrics()
+	vm.AddIgnoredOnOverload(topic)
+	vm.AddIgnoredOnOverload(topic)
+	vm.AddTimeout(topic)
+
+	expected := map[string]p2p.ValidatorMetrics{
+		topic: {
+			NumIgnoredOnOverload: 2,
+			NumTimeouts:          1,
+		},
+	}
+	assert.Equal(t, expected, vm.GetMetrics())
+}
+
+func TestValidatorMetrics_RejectMessageShouldCountOnlyThrottled(t *testing.T) {
+	t.Parallel()
+
+	topic := "topic"
+	msg := &pubsub.Message{
+		Message: &pb.Message{
+			Topic: &topic,
+		},
+	}
+
+	vm := metrics.NewValidatorMetrics()
+	vm.RejectMessage(nil, pubsub.RejectValidationThrottled)
+	vm.RejectMessage(msg, pubsub.RejectValidationFailed)
+	vm.RejectMessage(msg, pubsub.RejectValidationIgnored)
+	assert.Empty(t, vm.GetMetrics())
+
+	vm.RejectMessage(msg, pubsub.RejectValidationThrottled)
+	assert.Equal(t, uint64(1), vm.GetMetrics()[topic].NumThrottled)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p/crypto"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p/metrics"
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p/metrics/factory"
 	"github.com/TerraDharitri/drt-go-chain-communication/testscommon"
 	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
@@ -40,11 +41,15 @@ func NewMockMessenger(
 
 	ctx, cancelFunc := context.WithCancel(context.Background())
 	p2pNode := &networkMessenger{
-		p2pSigner:  signer,
-		p2pHost:    NewConnectableHost(h),
-		ctx:        ctx,
-		cancelFunc: cancelFunc,
-		log:        args.Logger,
+		p2pSigner:         signer,
+		p2pHost:           NewConnectableHost(h),
+		ctx:               ctx,
+		cancelFunc:        cancelFunc,
+		peerScores:        newPeerScoresHolder(),
+		validatorMetrics:  metrics.NewValidatorMetrics(),
+		duplicateMessages: NewDuplicateMessagesTracer(duplicateMessagesBufferSize),
+		incompatiblePeers: newIncompatiblePeersGater(),
+		log:               args.Logger,
 	}
 	p2pNode.printConnectionsWatcher, err = factory.NewConnectionsWatcher(args.ConnectionWatcherType, ttlConnectionsWatcher, &testscommon.LoggerStub{})
 	if err != nil {
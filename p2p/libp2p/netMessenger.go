@@ -33,6 +33,10 @@ import (
 const (
 	// DirectSendID represents the protocol ID for sending and receiving direct P2P messages
 	DirectSendID = protocol.ID("/drt/directsend/1.0.0")
+	// PeerAuthenticationID represents the protocol ID used by the peers to authenticate to each other
+	PeerAuthenticationID = protocol.ID("/drt/peerauth/1.0.0")
+	// NetworkIdentityID represents the protocol ID used by the peers to check they are compatible right after connecting
+	NetworkIdentityID = protocol.ID("/drt/netidentity/1.0.0")
 
 	refreshPeersOnTopic             = time.Second * 3
 	ttlPeersOnTopic                 = time.Second * 10
@@ -47,8 +51,9 @@ const (
 	msgBindError                    = "address already in use"
 	maxRetriesIfBindError           = 10
 
-	baseErrorSuffix      = "when creating a new network messenger"
-	pubSubMaxMessageSize = 1 << 21 // 2 MB
+	baseErrorSuffix             = "when creating a new network messenger"
+	pubSubMaxMessageSize        = 1 << 21 // 2 MB
+	duplicateMessagesBufferSize = 1000
 )
 
 type messageSigningConfig bool
@@ -62,8 +67,6 @@ var _ p2p.Messenger = (*networkMessenger)(nil)
 var externalPackages = []string{"dht", "nat", "basichost", "pubsub"}
 
 func init() {
-	pubsub.TimeCacheDuration = pubsubTimeCacheDuration
-
 	for _, external := range externalPackages {
 		_ = logger.GetOrCreate(fmt.Sprintf("external/%s", external))
 	}
@@ -80,6 +83,16 @@ type networkMessenger struct {
 	port                    int
 	printConnectionsWatcher p2p.ConnectionsWatcher
 	networkType             p2p.NetworkType
+	peerScores              *peerScoresHolder
+	validatorMetrics        ValidatorMetricsHandler
+	duplicateMessages       DuplicateMessagesTracer
+	outgoingCLB             ChannelLoadBalancer
+	peersInfo               *peersInfoCollector
+	peerAuthenticator       *peerAuthenticator
+	incompatiblePeers       *incompatiblePeersGater
+	ipGater                 *ipConnectionGater
+	identityHandshaker      *networkIdentityHandshaker
+	directSender            *directSender
 	log                     p2p.Logger
 }
 
@@ -128,6 +141,16 @@ func newNetworkMessenger(args ArgsNetworkMessenger, messageSigning messageSignin
 	if check.IfNil(args.Logger) {
 		return nil, fmt.Errorf("%w %s", p2p.ErrNilLogger, baseErrorSuffix)
 	}
+	// the peers not supporting the batched messages can only be kept out through the network identity handshake
+	if args.P2pConfig.PubSub.BatchedMessagesEnabled && !args.P2pConfig.NetworkIdentity.Enabled {
+		return nil, fmt.Errorf("%w, the batched messages require the network identity handshake %s",
+			p2p.ErrInvalidConfig, baseErrorSuffix)
+	}
+	// the same goes for the peers not supporting the attestation
+	if args.P2pConfig.PubSub.AttestationEnabled && !args.P2pConfig.NetworkIdentity.Enabled {
+		return nil, fmt.Errorf("%w, the messages attestation requires the network identity handshake %s",
+			p2p.ErrInvalidConfig, baseErrorSuffix)
+	}
 
 	setupExternalP2PLoggers()
 
@@ -180,6 +203,12 @@ func constructNode(
 		return nil, err
 	}
 
+	incompatiblePeers := newIncompatiblePeersGater()
+	ipGater, err := newIPConnectionGater(args.P2pConfig.Node.ConnectionGater, args.Logger)
+	if err != nil {
+		return nil, err
+	}
+
 	options := []libp2p.Option{
 		libp2p.ListenAddrStrings(addresses...),
 		libp2p.Identity(p2pPrivateKey),
@@ -189,6 +218,7 @@ func constructNode(
 		libp2p.DisableRelay(),
 		libp2p.NATPortMap(),
 		resourceLimiterOption,
+		libp2p.ConnectionGater(connectionGaters{ipGater, incompatiblePeers}),
 	}
 	options = append(options, transportOptions...)
 
@@ -196,6 +226,7 @@ func constructNode(
 	if err != nil {
 		return nil, err
 	}
+	h.Network().Notify(ipGater)
 
 	p2pSignerArgs := crypto.ArgsP2pSignerWrapper{
 		PrivateKey:      args.P2pPrivateKey,
@@ -218,6 +249,11 @@ func constructNode(
 		port:                    port,
 		printConnectionsWatcher: connWatcher,
 		networkType:             args.NetworkType,
+		peerScores:              newPeerScoresHolder(),
+		validatorMetrics:        metrics.NewValidatorMetrics(),
+		duplicateMessages:       NewDuplicateMessagesTracer(duplicateMessagesBufferSize),
+		incompatiblePeers:       incompatiblePeers,
+		ipGater:                 ipGater,
 		log:                     args.Logger,
 	}
 
@@ -331,7 +367,8 @@ func addComponentsToNode(
 	peersRatingHandler := args.PeersRatingHandler
 	marshaller := args.Marshaller
 
-	pubSub, err := p2pNode.createPubSub(messageSigning)
+	seenMessagesTTL := getSeenMessagesTTL(args.P2pConfig.PubSub.Router)
+	pubSub, err := p2pNode.createPubSub(messageSigning, args.P2pConfig.PubSub, seenMessagesTTL)
 	if err != nil {
 		return err
 	}
@@ -345,7 +382,13 @@ func addComponentsToNode(
 		return err
 	}
 
-	sharder, err := p2pNode.createSharder(args)
+	var peerShardResolver p2p.PeerShardResolver = &unknownPeerShardResolver{}
+	if args.P2pConfig.PeerAuthentication.Enabled {
+		p2pNode.peersInfo = newPeersInfoCollector()
+		peerShardResolver = p2pNode.peersInfo
+	}
+
+	sharder, err := p2pNode.createSharder(args, peerShardResolver)
 	if err != nil {
 		return err
 	}
@@ -364,29 +407,46 @@ func addComponentsToNode(
 	if err != nil {
 		return err
 	}
+	err = ds.SetSignaturePolicy(args.P2pConfig.DirectMessages, connMonitor)
+	if err != nil {
+		return err
+	}
+	p2pNode.directSender = ds
 
 	goRoutinesThrottler, err := throttler.NewNumGoRoutinesThrottler(broadcastGoRoutines)
 	if err != nil {
 		return err
 	}
 
-	oclb, err := NewOutgoingChannelLoadBalancer(p2pNode.log)
+	oclb, err := NewOutgoingChannelLoadBalancer(ArgsOutgoingChannelLoadBalancer{
+		Config: args.P2pConfig.PubSub.OutgoingChannels,
+		Logger: p2pNode.log,
+	})
 	if err != nil {
 		return err
 	}
+	p2pNode.outgoingCLB = oclb
 
 	argsMessageHandler := ArgMessagesHandler{
-		PubSub:             pubSub,
-		DirectSender:       ds,
-		Throttler:          goRoutinesThrottler,
-		OutgoingCLB:        oclb,
-		Marshaller:         marshaller,
-		ConnMonitor:        connMonitor,
-		PeersRatingHandler: peersRatingHandler,
-		SyncTimer:          args.SyncTimer,
-		PeerID:             p2pNode.ID(),
-		Logger:             p2pNode.log,
-		NetworkType:        p2pNode.networkType,
+		PubSub:                 pubSub,
+		DirectSender:           ds,
+		Throttler:              goRoutinesThrottler,
+		OutgoingCLB:            oclb,
+		Marshaller:             marshaller,
+		ConnMonitor:            connMonitor,
+		PeersRatingHandler:     peersRatingHandler,
+		SyncTimer:              args.SyncTimer,
+		PeerID:                 p2pNode.ID(),
+		Logger:                 p2pNode.log,
+		NetworkType:            p2pNode.networkType,
+		SeenMessagesTTL:        seenMessagesTTL,
+		TopicValidators:        args.P2pConfig.PubSub.TopicValidators,
+		Subscriptions:          args.P2pConfig.PubSub.Subscriptions,
+		Batching:               args.P2pConfig.PubSub.Batching,
+		BatchedMessagesEnabled: args.P2pConfig.PubSub.BatchedMessagesEnabled,
+		AttestationEnabled:     args.P2pConfig.PubSub.AttestationEnabled,
+		ValidatorMetrics:       p2pNode.validatorMetrics,
+		DuplicateMessages:      p2pNode.duplicateMessages,
 	}
 	p2pNode.MessageHandler, err = NewMessagesHandler(argsMessageHandler)
 	if err != nil {
@@ -399,7 +459,7 @@ func addComponentsToNode(
 	argsConnectionsHandler := ArgConnectionsHandler{
 		P2pHost:              p2pNode.p2pHost,
 		PeersOnChannel:       peersOnChannelInstance,
-		PeerShardResolver:    &unknownPeerShardResolver{},
+		PeerShardResolver:    peerShardResolver,
 		Sharder:              sharder,
 		PreferredPeersHolder: preferredPeersHolder,
 		ConnMonitor:          connMonitor,
@@ -415,6 +475,36 @@ func addComponentsToNode(
 		return err
 	}
 
+	if args.P2pConfig.NetworkIdentity.Enabled {
+		p2pNode.identityHandshaker, err = NewNetworkIdentityHandshaker(ArgsNetworkIdentityHandshaker{
+			Host:                   p2pNode.p2pHost,
+			IncompatiblePeers:      p2pNode.incompatiblePeers,
+			NetworkType:            p2pNode.networkType,
+			Config:                 args.P2pConfig.NetworkIdentity,
+			BatchedMessagesEnabled: args.P2pConfig.PubSub.BatchedMessagesEnabled,
+			AttestationEnabled:     args.P2pConfig.PubSub.AttestationEnabled,
+			Logger:                 p2pNode.log,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if args.P2pConfig.PeerAuthentication.Enabled {
+		p2pNode.peerAuthenticator, err = NewPeerAuthenticator(ArgsPeerAuthenticator{
+			Host:       p2pNode.p2pHost,
+			Signer:     p2pNode,
+			Marshaller: marshaller,
+			SyncTimer:  args.SyncTimer,
+			PeersInfo:  p2pNode.peersInfo,
+			Config:     args.P2pConfig.PeerAuthentication,
+			Logger:     p2pNode.log,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	p2pNode.printLogs()
 
 	return nil
@@ -431,7 +521,7 @@ func (netMes *networkMessenger) validateSeeders(seeders []string) error {
 	return nil
 }
 
-func (netMes *networkMessenger) createPubSub(messageSigning messageSigningConfig) (PubSub, error) {
+func (netMes *networkMessenger) createPubSub(messageSigning messageSigningConfig, pubSubConfig config.PubSubConfig, seenMessagesTTL time.Duration) (PubSub, error) {
 	optsPS := make([]pubsub.Option, 0)
 	if messageSigning == withoutMessageSigning {
 		netMes.log.Warn("signature verification is turned off in network messenger instance. NOT recommended in production environment")
@@ -439,13 +529,22 @@ func (netMes *networkMessenger) createPubSub(messageSigning messageSigningConfig
 	}
 
 	optsPS = append(optsPS, pubsub.WithMaxMessageSize(pubSubMaxMessageSize))
+	optsPS = append(optsPS, pubsub.WithSeenMessagesTTL(seenMessagesTTL))
+	optsPS = append(optsPS, pubsub.WithRawTracer(netMes.validatorMetrics))
+	optsPS = append(optsPS, pubsub.WithRawTracer(netMes.duplicateMessages))
+
+	peerScoreOptions, err := createPeerScoreOptions(pubSubConfig.PeerScoring, netMes.peerScores)
+	if err != nil {
+		return nil, err
+	}
+	optsPS = append(optsPS, peerScoreOptions...)
 
-	return pubsub.NewGossipSub(netMes.ctx, netMes.p2pHost, optsPS...)
+	return newPubSubWithRouter(netMes.ctx, netMes.p2pHost, pubSubConfig.Router, optsPS)
 }
 
-func (netMes *networkMessenger) createSharder(argsNetMes ArgsNetworkMessenger) (p2p.Sharder, error) {
+func (netMes *networkMessenger) createSharder(argsNetMes ArgsNetworkMessenger, peerShardResolver p2p.PeerShardResolver) (p2p.Sharder, error) {
 	args := factory.ArgsSharderFactory{
-		PeerShardResolver:    &unknownPeerShardResolver{},
+		PeerShardResolver:    peerShardResolver,
 		Pid:                  netMes.p2pHost.ID(),
 		P2pConfig:            argsNetMes.P2pConfig,
 		PreferredPeersHolder: argsNetMes.PreferredPeersHolder,
@@ -549,6 +648,28 @@ func (netMes *networkMessenger) Close() error {
 			"error", err)
 	}
 
+	if netMes.peerAuthenticator != nil {
+		netMes.log.Debug("closing network messenger's peer authenticator...")
+		errPA := netMes.peerAuthenticator.Close()
+		if errPA != nil {
+			err = errPA
+			netMes.log.Warn("networkMessenger.Close",
+				"component", "peerAuthenticator",
+				"error", err)
+		}
+	}
+
+	if netMes.identityHandshaker != nil {
+		netMes.log.Debug("closing network messenger's network identity handshaker...")
+		errIH := netMes.identityHandshaker.Close()
+		if errIH != nil {
+			err = errIH
+			netMes.log.Warn("networkMessenger.Close",
+				"component", "networkIdentityHandshaker",
+				"error", err)
+		}
+	}
+
 	errHost := netMes.p2pHost.Close()
 	if errHost != nil {
 		err = errHost
@@ -597,6 +718,97 @@ func (netMes *networkMessenger) Port() int {
 	return netMes.port
 }
 
+// GetPeerScores returns the last inspected gossipsub scores of the connected peers. The map is empty if the
+// peer scoring is disabled
+func (netMes *networkMessenger) GetPeerScores() map[core.PeerID]p2p.PeerScore {
+	return netMes.peerScores.getPeerScores()
+}
+
+// GetValidatorMetrics returns, for each topic, how many validations could not be done because the topic was overloaded
+func (netMes *networkMessenger) GetValidatorMetrics() map[string]p2p.ValidatorMetrics {
+	return netMes.validatorMetrics.GetMetrics()
+}
+
+// GetOutgoingChannelsMetrics returns the queue depth and the wait time of each channel used when broadcasting
+func (netMes *networkMessenger) GetOutgoingChannelsMetrics() map[string]p2p.OutgoingChannelMetrics {
+	return netMes.outgoingCLB.GetChannelsMetrics()
+}
+
+// GetDirectMessageSignatureMetrics returns how many unsigned direct messages were accepted or rejected and how many
+// direct messages had an invalid signature
+func (netMes *networkMessenger) GetDirectMessageSignatureMetrics() p2p.DirectMessageSignatureMetrics {
+	return netMes.directSender.GetSignatureMetrics()
+}
+
+// GetConnectionGaterMetrics returns how many connection attempts were refused by the IP connection gater
+func (netMes *networkMessenger) GetConnectionGaterMetrics() p2p.ConnectionGaterMetrics {
+	return netMes.ipGater.GetMetrics()
+}
+
+// ReloadConnectionGater replaces the allow and deny lists and the limits of the IP connection gater. The already
+// opened connections are not closed
+func (netMes *networkMessenger) ReloadConnectionGater(cfg config.ConnectionGaterConfig) error {
+	return netMes.ipGater.Reload(cfg)
+}
+
+// SetPeerDenialEvaluator sets the peer black list handler. If the handler is also able to ban IP addresses, the
+// connections with the banned addresses are refused
+func (netMes *networkMessenger) SetPeerDenialEvaluator(handler p2p.PeerDenialEvaluator) error {
+	err := netMes.ConnectionsHandler.SetPeerDenialEvaluator(handler)
+	if err != nil {
+		return err
+	}
+
+	ipDenialEvaluator, _ := handler.(p2p.IPDenialEvaluator)
+	netMes.ipGater.SetIPDenialEvaluator(ipDenialEvaluator)
+
+	return nil
+}
+
+// SetPeerShardResolver sets the peer shard resolver component that is able to resolve the link
+// between peerID and shardId. If the peer authentication is enabled, the peers unknown to the provided
+// resolver are resolved using the authenticated information
+func (netMes *networkMessenger) SetPeerShardResolver(peerShardResolver p2p.PeerShardResolver) error {
+	if netMes.peersInfo == nil {
+		return netMes.ConnectionsHandler.SetPeerShardResolver(peerShardResolver)
+	}
+
+	return netMes.peersInfo.SetPeerShardResolver(peerShardResolver)
+}
+
+// SetPeerAuthenticationInfo sets the data this node states about itself when authenticating to its peers
+func (netMes *networkMessenger) SetPeerAuthenticationInfo(info p2p.PeerAuthenticationInfo) error {
+	if netMes.peerAuthenticator == nil {
+		return p2p.ErrPeerAuthenticationDisabled
+	}
+
+	return netMes.peerAuthenticator.SetPeerAuthenticationInfo(info)
+}
+
+// SetAttestationVerifier sets the component used to verify the attestations of the received messages and of the
+// authenticated validators
+func (netMes *networkMessenger) SetAttestationVerifier(verifier p2p.AttestationVerifier) error {
+	err := netMes.MessageHandler.SetAttestationVerifier(verifier)
+	if err != nil {
+		return err
+	}
+	if netMes.peerAuthenticator == nil {
+		return nil
+	}
+
+	return netMes.peerAuthenticator.SetAttestationVerifier(verifier)
+}
+
+// GetPeerNodeVersion returns the node version stated by an authenticated peer. The result is empty if the peer
+// authentication is disabled or the peer did not authenticate
+func (netMes *networkMessenger) GetPeerNodeVersion(pid core.PeerID) string {
+	if netMes.peersInfo == nil {
+		return ""
+	}
+
+	return netMes.peersInfo.GetPeerNodeVersion(pid)
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (netMes *networkMessenger) IsInterfaceNil() bool {
 	return netMes == nil
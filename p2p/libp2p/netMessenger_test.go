@@ -25,7 +25,10 @@ import (
 	"github.com/TerraDharitri/drt-go-chain-core/marshal"
 	commonCrypto "github.com/TerraDharitri/drt-go-chain-crypto"
 	"github.com/TerraDharitri/drt-go-chain-crypto/signing"
+	"github.com/TerraDharitri/drt-go-chain-crypto/signing/ed25519"
+	ed25519SingleSig "github.com/TerraDharitri/drt-go-chain-crypto/signing/ed25519/singlesig"
 	"github.com/TerraDharitri/drt-go-chain-crypto/signing/secp256k1"
+	secp256k1SingleSig "github.com/TerraDharitri/drt-go-chain-crypto/signing/secp256k1/singlesig"
 	logger "github.com/TerraDharitri/drt-go-chain-logger"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	pb "github.com/libp2p/go-libp2p-pubsub/pb"
@@ -311,6 +314,28 @@ func TestNewNetworkMessenger_NilChecksShouldErr(t *testing.T) {
 		assert.Nil(t, messenger)
 		assert.True(t, errors.Is(err, p2p.ErrNoTransportsDefined))
 	})
+	t.Run("batched messages without network identity should error", func(t *testing.T) {
+		t.Parallel()
+
+		arg := createMockNetworkArgs()
+		arg.P2pConfig.PubSub.BatchedMessagesEnabled = true
+		arg.P2pConfig.NetworkIdentity.Enabled = false
+		messenger, err := libp2p.NewNetworkMessenger(arg)
+
+		assert.True(t, check.IfNil(messenger))
+		assert.True(t, errors.Is(err, p2p.ErrInvalidConfig))
+	})
+	t.Run("attestation without network identity should error", func(t *testing.T) {
+		t.Parallel()
+
+		arg := createMockNetworkArgs()
+		arg.P2pConfig.PubSub.AttestationEnabled = true
+		arg.P2pConfig.NetworkIdentity.Enabled = false
+		messenger, err := libp2p.NewNetworkMessenger(arg)
+
+		assert.True(t, check.IfNil(messenger))
+		assert.True(t, errors.Is(err, p2p.ErrInvalidConfig))
+	})
 }
 
 func TestNewNetworkMessenger_WithDeactivatedKadDiscovererShouldWork(t *testing.T) {
@@ -1265,6 +1290,57 @@ func TestLibp2pMessenger_SendDirectWithRealMessengersWithoutSignatureShouldWork(
 	waitDoneWithTimeout(t, chanDone, timeoutWaitResponses)
 }
 
+func TestNetworkMessenger_SendDirectWithoutSignatureAndEnforcePolicyShouldBeRejected(t *testing.T) {
+	netw := mocknet.New()
+
+	messenger1, err := libp2p.NewMockMessenger(createMockNetworkArgs(), netw)
+	require.Nil(t, err)
+	// force messenger1 not to sign a direct message
+	messenger1.SetSignerInDirectSender(&noSigner{messenger1})
+
+	args := createMockNetworkArgs()
+	args.P2pConfig.DirectMessages = config.DirectMessagesConfig{
+		SignaturePolicy: p2p.EnforceSignaturePolicy,
+	}
+	messenger2, err := libp2p.NewMockMessenger(args, netw)
+	require.Nil(t, err)
+	defer closeMessengers(messenger1, messenger2)
+
+	_ = netw.LinkAll()
+	_ = messenger1.ConnectToPeer(getConnectableAddress(messenger2))
+
+	_ = messenger2.CreateTopic(testTopic, false)
+	_ = messenger2.RegisterMessageProcessor(testTopic, "identifier", &mock.MessageProcessorStub{
+		ProcessMessageCalled: func(message p2p.MessageP2P, _ core.PeerID, _ p2p.MessageHandler) error {
+			assert.Fail(t, "should have not processed the unsigned message")
+			return nil
+		},
+	})
+
+	err = messenger1.SendToConnectedPeer(testTopic, []byte("test message"), messenger2.ID())
+	assert.Nil(t, err)
+
+	require.Eventually(t, func() bool {
+		return messenger2.GetDirectMessageSignatureMetrics().NumUnsignedRejected == 1
+	}, timeoutWaitResponses, time.Millisecond*10)
+	assert.Equal(t, uint64(0), messenger2.GetDirectMessageSignatureMetrics().NumUnsignedAccepted)
+
+	_ = messenger1.CreateTopic(testTopic, false)
+	_ = messenger1.RegisterMessageProcessor(testTopic, "identifier", &mock.MessageProcessorStub{})
+	err = messenger1.SendToConnectedPeer(testTopic, []byte("test message"), messenger1.ID())
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), messenger1.GetDirectMessageSignatureMetrics().NumUnsignedAccepted)
+}
+
+func TestNetworkMessenger_InvalidSignaturePolicyShouldErr(t *testing.T) {
+	args := createMockNetworkArgs()
+	args.P2pConfig.DirectMessages.SignaturePolicy = "unknown"
+
+	messenger, err := libp2p.NewMockMessenger(args, mocknet.New())
+	assert.True(t, errors.Is(err, p2p.ErrUnknownSignaturePolicy))
+	assert.True(t, check.IfNil(messenger))
+}
+
 func TestLibp2pMessenger_SendDirectWithRealNetToConnectedPeerShouldWork(t *testing.T) {
 	msg := []byte("test message")
 
@@ -1987,6 +2063,133 @@ func TestLibp2pMessenger_SignVerifyPayloadShouldWork(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func createNetworkArgsWithKeyType(keyType p2p.KeyType) libp2p.ArgsNetworkMessenger {
+	args := createMockNetworkArgs()
+	switch keyType {
+	case p2p.Ed25519KeyType:
+		args.P2pKeyGenerator = signing.NewKeyGenerator(ed25519.NewEd25519())
+		args.P2pSingleSigner = &ed25519SingleSig.Ed25519Signer{}
+	default:
+		args.P2pKeyGenerator = signing.NewKeyGenerator(secp256k1.NewSecp256k1())
+		args.P2pSingleSigner = &secp256k1SingleSig.Secp256k1Signer{}
+	}
+	args.P2pPrivateKey, _ = args.P2pKeyGenerator.GeneratePair()
+
+	return args
+}
+
+func TestLibp2pMessenger_SignVerifyPayloadWithMixedKeyTypesShouldWork(t *testing.T) {
+	messenger1, err := libp2p.NewNetworkMessenger(createNetworkArgsWithKeyType(p2p.Ed25519KeyType))
+	require.Nil(t, err)
+
+	messenger2, err := libp2p.NewNetworkMessenger(createNetworkArgsWithKeyType(p2p.Secp256k1KeyType))
+	require.Nil(t, err)
+
+	defer closeMessengers(messenger1, messenger2)
+
+	keyType, _ := p2pCrypto.KeyTypeFromPeerID(messenger1.ID())
+	assert.Equal(t, p2p.Ed25519KeyType, keyType)
+	keyType, _ = p2pCrypto.KeyTypeFromPeerID(messenger2.ID())
+	assert.Equal(t, p2p.Secp256k1KeyType, keyType)
+
+	err = messenger1.ConnectToPeer(getConnectableAddress(messenger2))
+	assert.Nil(t, err)
+
+	payload := []byte("payload")
+	sig1, err := messenger1.Sign(payload)
+	assert.Nil(t, err)
+	sig2, err := messenger2.Sign(payload)
+	assert.Nil(t, err)
+
+	assert.Nil(t, messenger2.Verify(payload, messenger1.ID(), sig1))
+	assert.Nil(t, messenger1.Verify(payload, messenger2.ID(), sig2))
+	assert.NotNil(t, messenger1.Verify(payload, messenger2.ID(), sig1))
+
+	generator, _ := p2pCrypto.NewIdentityGeneratorWithKeyType(&testscommon.LoggerStub{}, p2p.Ed25519KeyType)
+	skBytes, pid, err := generator.CreateRandomP2PIdentity()
+	require.Nil(t, err)
+
+	sig, err := messenger2.SignUsingPrivateKey(skBytes, payload)
+	assert.Nil(t, err)
+	assert.Nil(t, messenger2.Verify(payload, pid, sig))
+}
+
+func TestNetworkMessenger_BroadcastUsingEd25519PrivateKey(t *testing.T) {
+	if testing.Short() {
+		t.Skip("this is not a short test")
+	}
+
+	msg := []byte("test message")
+	topic := "topic"
+
+	messenger1, _ := libp2p.NewNetworkMessenger(createNetworkArgsWithKeyType(p2p.Secp256k1KeyType))
+	_ = messenger1.CreateTopic(topic, true)
+
+	messenger2, _ := libp2p.NewNetworkMessenger(createNetworkArgsWithKeyType(p2p.Ed25519KeyType))
+	_ = messenger2.CreateTopic(topic, true)
+	interceptor := mock.NewMessageProcessorMock()
+	_ = messenger2.RegisterMessageProcessor(topic, "", interceptor)
+
+	defer closeMessengers(messenger1, messenger2)
+
+	err := messenger1.ConnectToPeer(getConnectableAddress(messenger2))
+	assert.Nil(t, err)
+
+	time.Sleep(time.Second * 2)
+
+	generator, _ := p2pCrypto.NewIdentityGeneratorWithKeyType(&testscommon.LoggerStub{}, p2p.Ed25519KeyType)
+	skBytes, pid, err := generator.CreateRandomP2PIdentity()
+	require.Nil(t, err)
+
+	messenger1.BroadcastUsingPrivateKey(topic, msg, pid, skBytes)
+
+	time.Sleep(time.Second * 2)
+
+	messages := interceptor.GetMessages()
+	assert.Equal(t, 1, len(messages))
+	assert.Equal(t, 1, messages[pid])
+}
+
+func TestNetworkMessenger_BroadcastAs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("this is not a short test")
+	}
+
+	msg := []byte("test message")
+	topic := "topic"
+
+	messenger1, _ := libp2p.NewNetworkMessenger(createMockNetworkArgs())
+	_ = messenger1.CreateTopic(topic, true)
+
+	messenger2, _ := libp2p.NewNetworkMessenger(createMockNetworkArgs())
+	_ = messenger2.CreateTopic(topic, true)
+	interceptor := mock.NewMessageProcessorMock()
+	_ = messenger2.RegisterMessageProcessor(topic, "", interceptor)
+
+	defer closeMessengers(messenger1, messenger2)
+
+	err := messenger1.ConnectToPeer(getConnectableAddress(messenger2))
+	assert.Nil(t, err)
+
+	time.Sleep(time.Second * 2)
+
+	skBytes, peerID := createP2PPrivKeyAndPid()
+	pid := core.PeerID(peerID)
+	err = messenger1.AddIdentity("validator", skBytes, pid)
+	require.Nil(t, err)
+
+	err = messenger1.BroadcastAs("validator", topic, msg)
+	assert.Nil(t, err)
+	err = messenger1.BroadcastAs("missing", topic, msg)
+	assert.True(t, errors.Is(err, p2p.ErrIdentityNotFound))
+
+	time.Sleep(time.Second * 2)
+
+	messages := interceptor.GetMessages()
+	assert.Equal(t, 1, len(messages))
+	assert.Equal(t, 1, messages[pid])
+}
+
 func TestNetworkMessenger_BroadcastUsingPrivateKey(t *testing.T) {
 	if testing.Short() {
 		t.Skip("this is not a short test")
@@ -2322,3 +2525,554 @@ func TestNetworkMessenger_HasCompatibleProtocolID(t *testing.T) {
 		assert.True(t, messenger3.HasCompatibleProtocolID(messenger2.Addresses()[0]))
 	})
 }
+
+func TestNetworkMessenger_PeerScoringConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid IP colocation whitelist should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockNetworkArgs()
+		args.P2pConfig.PubSub.PeerScoring = config.PeerScoringConfig{
+			Enabled:                     true,
+			IPColocationFactorWhitelist: []string{"not a CIDR"},
+		}
+		messenger, err := libp2p.NewMockMessenger(args, mocknet.New())
+		assert.Nil(t, messenger)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidConfig))
+	})
+	t.Run("duplicated topic should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockNetworkArgs()
+		args.P2pConfig.PubSub.PeerScoring = config.PeerScoringConfig{
+			Enabled: true,
+			Topics:  []config.TopicScoringConfig{{Topic: "topic"}, {Topic: "topic"}},
+		}
+		messenger, err := libp2p.NewMockMessenger(args, mocknet.New())
+		assert.Nil(t, messenger)
+		assert.True(t, errors.Is(err, p2p.ErrDuplicatedTopic))
+	})
+	t.Run("invalid gossipsub parameter should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockNetworkArgs()
+		args.P2pConfig.PubSub.PeerScoring = config.PeerScoringConfig{
+			Enabled:                  true,
+			IPColocationFactorWeight: 10,
+		}
+		messenger, err := libp2p.NewMockMessenger(args, mocknet.New())
+		assert.Nil(t, messenger)
+		assert.NotNil(t, err)
+	})
+	t.Run("disabled peer scoring should not return scores", func(t *testing.T) {
+		t.Parallel()
+
+		messenger, err := libp2p.NewMockMessenger(createMockNetworkArgs(), mocknet.New())
+		assert.Nil(t, err)
+		defer closeMessengers(messenger)
+
+		assert.Empty(t, messenger.GetPeerScores())
+	})
+}
+
+func TestNetworkMessenger_RejectedMessagesShouldLowerThePeerScore(t *testing.T) {
+	topic := "test"
+	args := createMockNetworkArgs()
+	args.P2pConfig.PubSub.PeerScoring = config.PeerScoringConfig{
+		Enabled:              true,
+		GossipThreshold:      -100,
+		PublishThreshold:     -200,
+		GraylistThreshold:    -300,
+		InspectIntervalInSec: 1,
+		Topics: []config.TopicScoringConfig{
+			{
+				Topic:                          topic,
+				TopicWeight:                    1,
+				InvalidMessageDeliveriesWeight: -10,
+				InvalidMessageDeliveriesDecay:  0.9,
+			},
+		},
+	}
+
+	netw := mocknet.New()
+	messenger1, _ := libp2p.NewMockMessenger(args, netw)
+	messenger2, _ := libp2p.NewMockMessenger(args, netw)
+	_ = netw.LinkAll()
+	defer closeMessengers(messenger1, messenger2)
+
+	_ = messenger1.ConnectToPeer(messenger2.Addresses()[0])
+
+	_ = messenger1.CreateTopic(topic, true)
+	_ = messenger2.CreateTopic(topic, true)
+	_ = messenger2.RegisterMessageProcessor(topic, "identifier", &mock.MessageProcessorStub{
+		ProcessMessageCalled: func(message p2p.MessageP2P, fromConnectedPeer core.PeerID, source p2p.MessageHandler) error {
+			return expectedErr
+		},
+	})
+
+	time.Sleep(time.Second)
+	messenger1.Broadcast(topic, []byte("invalid message"))
+
+	assert.Eventually(t, func() bool {
+		score, found := messenger2.GetPeerScores()[messenger1.ID()]
+		if !found {
+			return false
+		}
+
+		return score.Topics[topic].InvalidMessageDeliveries > 0 && score.Score < 0
+	}, 5*time.Second, 100*time.Millisecond)
+}
+
+func TestNetworkMessenger_PubSubRouterConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown router type should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockNetworkArgs()
+		args.P2pConfig.PubSub.Router.Type = "unknown"
+		messenger, err := libp2p.NewMockMessenger(args, mocknet.New())
+		assert.Nil(t, messenger)
+		assert.True(t, errors.Is(err, p2p.ErrUnknownPubSubRouterType))
+	})
+	t.Run("randomsub with invalid size should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockNetworkArgs()
+		args.P2pConfig.PubSub.Router.Type = p2p.RandomSubRouter
+		messenger, err := libp2p.NewMockMessenger(args, mocknet.New())
+		assert.Nil(t, messenger)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidConfig))
+	})
+	t.Run("invalid mesh degrees should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockNetworkArgs()
+		args.P2pConfig.PubSub.Router.GossipSub = config.GossipSubConfig{
+			D:   4,
+			Dlo: 5,
+		}
+		messenger, err := libp2p.NewMockMessenger(args, mocknet.New())
+		assert.Nil(t, messenger)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidConfig))
+	})
+	t.Run("invalid history windows should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockNetworkArgs()
+		args.P2pConfig.PubSub.Router.GossipSub = config.GossipSubConfig{
+			HistoryLength: 2,
+			HistoryGossip: 3,
+		}
+		messenger, err := libp2p.NewMockMessenger(args, mocknet.New())
+		assert.Nil(t, messenger)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidConfig))
+	})
+	t.Run("custom gossipsub parameters should work", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockNetworkArgs()
+		args.P2pConfig.PubSub.Router.SeenMessagesTTLInSec = 60
+		args.P2pConfig.PubSub.Router.GossipSub = config.GossipSubConfig{
+			D:                     4,
+			Dlo:                   3,
+			Dhi:                   6,
+			Dscore:                2,
+			Dout:                  1,
+			HeartbeatIntervalInMs: 500,
+			FanoutTTLInSec:        30,
+		}
+		messenger, err := libp2p.NewMockMessenger(args, mocknet.New())
+		assert.Nil(t, err)
+		closeMessengers(messenger)
+	})
+}
+
+func TestNetworkMessenger_SubscribeShouldDeliverReceivedMessages(t *testing.T) {
+	msg := []byte("test message")
+
+	_, messenger1, messenger2 := createMockNetworkOf2()
+	defer closeMessengers(messenger1, messenger2)
+
+	_ = messenger1.ConnectToPeer(messenger2.Addresses()[0])
+
+	_ = messenger1.CreateTopic(testTopic, true)
+	_ = messenger2.CreateTopic(testTopic, true)
+	ch, cancel := messenger2.Subscribe(testTopic)
+	defer cancel()
+
+	time.Sleep(time.Second)
+	messenger1.Broadcast(testTopic, msg)
+
+	select {
+	case receivedMsg := <-ch:
+		assert.Equal(t, msg, receivedMsg.Data())
+		assert.Equal(t, testTopic, receivedMsg.Topic())
+		assert.Equal(t, messenger1.ID(), receivedMsg.Peer())
+	case <-time.After(timeoutWaitResponses):
+		assert.Fail(t, "timeout while waiting for the subscription message")
+	}
+}
+
+func TestNetworkMessenger_BatchedBroadcastShouldDeliverEachMessage(t *testing.T) {
+	netw := mocknet.New()
+	createArgs := func() libp2p.ArgsNetworkMessenger {
+		args := createMockNetworkArgs()
+		args.P2pConfig.PubSub.BatchedMessagesEnabled = true
+		args.P2pConfig.NetworkIdentity.Enabled = true
+		args.P2pConfig.NetworkIdentity.ChainID = "chain"
+
+		return args
+	}
+	args := createArgs()
+	args.P2pConfig.PubSub.Batching = []config.TopicBatchingConfig{
+		{
+			Topic:      testTopic,
+			WindowInMs: 200,
+		},
+	}
+	messenger1, _ := libp2p.NewMockMessenger(args, netw)
+	messenger2, _ := libp2p.NewMockMessenger(createArgs(), netw)
+	_ = netw.LinkAll()
+	defer closeMessengers(messenger1, messenger2)
+
+	_ = messenger1.ConnectToPeer(messenger2.Addresses()[0])
+
+	_ = messenger1.CreateTopic(testTopic, true)
+	_ = messenger2.CreateTopic(testTopic, true)
+	ch, cancel := messenger2.Subscribe(testTopic)
+	defer cancel()
+
+	time.Sleep(time.Second)
+	msgs := [][]byte{[]byte("message 1"), []byte("message 2"), []byte("message 3")}
+	for _, msg := range msgs {
+		messenger1.Broadcast(testTopic, msg)
+	}
+
+	received := make(map[string]struct{})
+	for range msgs {
+		select {
+		case receivedMsg := <-ch:
+			assert.Equal(t, messenger1.ID(), receivedMsg.Peer())
+			received[string(receivedMsg.Data())] = struct{}{}
+		case <-time.After(timeoutWaitResponses):
+			require.Fail(t, "timeout while waiting for the batched messages")
+		}
+	}
+	for _, msg := range msgs {
+		assert.Contains(t, received, string(msg))
+	}
+}
+
+func TestNetworkMessenger_AttestationShouldBeVerifiedByReceiver(t *testing.T) {
+	providedPk := []byte("validator pk")
+	providedSignature := []byte("signature on pid")
+
+	netw := mocknet.New()
+	createArgs := func() libp2p.ArgsNetworkMessenger {
+		args := createMockNetworkArgs()
+		args.P2pConfig.PubSub.AttestationEnabled = true
+		args.P2pConfig.NetworkIdentity.Enabled = true
+		args.P2pConfig.NetworkIdentity.ChainID = "chain"
+
+		return args
+	}
+	messenger1, _ := libp2p.NewMockMessenger(createArgs(), netw)
+	messenger2, _ := libp2p.NewMockMessenger(createArgs(), netw)
+	_ = netw.LinkAll()
+	defer closeMessengers(messenger1, messenger2)
+
+	_ = messenger1.ConnectToPeer(messenger2.Addresses()[0])
+
+	err := messenger1.SetAttestation(providedPk, providedSignature)
+	require.Nil(t, err)
+	err = messenger2.SetAttestationVerifier(&mock.AttestationVerifierStub{
+		VerifyAttestationCalled: func(pk []byte, pid core.PeerID, signatureOnPid []byte) error {
+			if pid != messenger1.ID() || !bytes.Equal(pk, providedPk) || !bytes.Equal(signatureOnPid, providedSignature) {
+				return expectedError
+			}
+			return nil
+		},
+	})
+	require.Nil(t, err)
+
+	_ = messenger1.CreateTopic(testTopic, true)
+	_ = messenger2.CreateTopic(testTopic, true)
+	ch, cancel := messenger2.Subscribe(testTopic)
+	defer cancel()
+
+	time.Sleep(time.Second)
+	messenger1.Broadcast(testTopic, []byte("test message"))
+
+	select {
+	case receivedMsg := <-ch:
+		assert.Equal(t, providedPk, receivedMsg.AttestedPk())
+	case <-time.After(timeoutWaitResponses):
+		assert.Fail(t, "timeout while waiting for the attested message")
+	}
+}
+
+func TestNetworkMessenger_PublishOnlyTopicAndLeaveTopic(t *testing.T) {
+	msg := []byte("test message")
+
+	_, messenger1, messenger2 := createMockNetworkOf2()
+	defer closeMessengers(messenger1, messenger2)
+
+	_ = messenger1.ConnectToPeer(messenger2.Addresses()[0])
+
+	err := messenger1.CreatePublishOnlyTopic(testTopic, true)
+	require.Nil(t, err)
+	assert.True(t, messenger1.HasTopic(testTopic))
+	ch1, cancel1 := messenger1.Subscribe(testTopic)
+	defer cancel1()
+
+	err = messenger2.CreateTopic(testTopic, true)
+	require.Nil(t, err)
+	ch2, cancel2 := messenger2.Subscribe(testTopic)
+	defer cancel2()
+
+	time.Sleep(time.Second)
+	messenger1.Broadcast(testTopic, msg)
+
+	select {
+	case receivedMsg := <-ch2:
+		assert.Equal(t, messenger1.ID(), receivedMsg.Peer())
+	case <-time.After(timeoutWaitResponses):
+		assert.Fail(t, "timeout while waiting for the subscription message")
+	}
+
+	messenger2.Broadcast(testTopic, msg)
+	select {
+	case <-ch1:
+		assert.Fail(t, "publish only topic should not receive messages")
+	case <-time.After(time.Second):
+	}
+
+	err = messenger2.RegisterMessageProcessor(testTopic, "identifier", &mock.MessageProcessorStub{})
+	require.Nil(t, err)
+	err = messenger2.LeaveTopic(testTopic)
+	assert.Nil(t, err)
+	assert.False(t, messenger2.HasTopic(testTopic))
+
+	err = messenger2.CreateTopic(testTopic, true)
+	assert.Nil(t, err)
+	err = messenger2.RegisterMessageProcessor(testTopic, "identifier", &mock.MessageProcessorStub{})
+	assert.Nil(t, err)
+}
+
+func TestNetworkMessenger_GetOutgoingChannelsMetrics(t *testing.T) {
+	args := createMockNetworkArgs()
+	args.P2pConfig.PubSub.OutgoingChannels.Channels = []config.OutgoingChannelConfig{
+		{Name: testTopic, Priority: 1, QueueSize: 10},
+	}
+	messenger, err := libp2p.NewMockMessenger(args, mocknet.New())
+	require.Nil(t, err)
+	defer closeMessengers(messenger)
+
+	err = messenger.CreateTopic(testTopic, true)
+	require.Nil(t, err)
+	messenger.Broadcast(testTopic, []byte("test message"))
+
+	require.Eventually(t, func() bool {
+		return messenger.GetOutgoingChannelsMetrics()[testTopic].NumSent == 1
+	}, timeoutWaitResponses, time.Millisecond*10)
+	assert.Equal(t, 0, messenger.GetOutgoingChannelsMetrics()[testTopic].QueueDepth)
+	assert.Equal(t, 2, len(messenger.GetOutgoingChannelsMetrics()))
+}
+
+func TestNetworkMessenger_BroadcastWithAlternativeRouters(t *testing.T) {
+	routers := map[string]config.RouterConfig{
+		p2p.FloodSubRouter:  {Type: p2p.FloodSubRouter},
+		p2p.RandomSubRouter: {Type: p2p.RandomSubRouter, RandomSubSize: 5},
+	}
+
+	for name, routerConfig := range routers {
+		t.Run(name, func(t *testing.T) {
+			msg := []byte("test message")
+			args := createMockNetworkArgs()
+			args.P2pConfig.PubSub.Router = routerConfig
+
+			netw := mocknet.New()
+			messenger1, _ := libp2p.NewMockMessenger(args, netw)
+			messenger2, _ := libp2p.NewMockMessenger(args, netw)
+			_ = netw.LinkAll()
+			defer closeMessengers(messenger1, messenger2)
+
+			_ = messenger1.ConnectToPeer(messenger2.Addresses()[0])
+
+			wg := &sync.WaitGroup{}
+			chanDone := make(chan bool)
+			wg.Add(2)
+			go func() {
+				wg.Wait()
+				chanDone <- true
+			}()
+
+			prepareMessengerForMatchDataReceive(messenger1, msg, wg, noSigCheckHandler)
+			prepareMessengerForMatchDataReceive(messenger2, msg, wg, noSigCheckHandler)
+			time.Sleep(time.Second)
+
+			messenger1.Broadcast(testTopic, msg)
+
+			waitDoneWithTimeout(t, chanDone, timeoutWaitResponses)
+		})
+	}
+}
+
+func TestNetworkMessenger_PeerAuthenticationShouldFeedThePeerShardResolver(t *testing.T) {
+	netw := mocknet.New()
+	args := createMockNetworkArgs()
+	args.P2pConfig.PeerAuthentication.Enabled = true
+	messenger1, _ := libp2p.NewMockMessenger(args, netw)
+	messenger2, _ := libp2p.NewMockMessenger(args, netw)
+	_ = netw.LinkAll()
+	defer closeMessengers(messenger1, messenger2)
+
+	// the mocknet peer IDs do not embed the public keys, so the statements' signatures can not be verified
+	messenger1.SetPeerAuthenticatorSigner(&mock.P2PSignerStub{})
+	messenger2.SetPeerAuthenticatorSigner(&mock.P2PSignerStub{})
+
+	err := messenger1.SetPeerAuthenticationInfo(p2p.PeerAuthenticationInfo{
+		PeerType:       core.ValidatorPeer,
+		ShardID:        1,
+		NodeVersion:    "v1.0.0",
+		Pk:             []byte("validator pk"),
+		SignatureOnPid: []byte("signature on pid"),
+	})
+	require.Nil(t, err)
+	err = messenger2.SetPeerAuthenticationInfo(p2p.PeerAuthenticationInfo{
+		PeerType:    core.ObserverPeer,
+		ShardID:     core.MetachainShardId,
+		NodeVersion: "v2.0.0",
+	})
+	require.Nil(t, err)
+	err = messenger2.SetAttestationVerifier(&mock.AttestationVerifierStub{})
+	require.Nil(t, err)
+
+	err = messenger1.ConnectToPeer(messenger2.Addresses()[0])
+	require.Nil(t, err)
+	time.Sleep(time.Second)
+
+	assert.Equal(t, "v1.0.0", messenger2.GetPeerNodeVersion(messenger1.ID()))
+	assert.Equal(t, "v2.0.0", messenger1.GetPeerNodeVersion(messenger2.ID()))
+	assert.Equal(t, 1, messenger2.GetConnectedPeersInfo().NumValidatorsOnShard[1])
+	// messenger1 did not set an attestation verifier, so messenger2 can only be an observer
+	assert.Equal(t, 1, messenger1.GetConnectedPeersInfo().NumObserversOnShard[core.MetachainShardId])
+}
+
+func TestNetworkMessenger_PeerAuthenticationInfoSetAfterConnectingShouldAuthenticate(t *testing.T) {
+	netw := mocknet.New()
+	args := createMockNetworkArgs()
+	args.P2pConfig.PeerAuthentication.Enabled = true
+	messenger1, _ := libp2p.NewMockMessenger(args, netw)
+	messenger2, _ := libp2p.NewMockMessenger(args, netw)
+	_ = netw.LinkAll()
+	defer closeMessengers(messenger1, messenger2)
+
+	messenger1.SetPeerAuthenticatorSigner(&mock.P2PSignerStub{})
+	messenger2.SetPeerAuthenticatorSigner(&mock.P2PSignerStub{})
+
+	err := messenger1.ConnectToPeer(messenger2.Addresses()[0])
+	require.Nil(t, err)
+	time.Sleep(time.Millisecond * 200)
+
+	// the outbound side sets its info only after the connection was established
+	err = messenger2.SetPeerAuthenticationInfo(p2p.PeerAuthenticationInfo{
+		PeerType:    core.ObserverPeer,
+		NodeVersion: "v2.0.0",
+	})
+	require.Nil(t, err)
+	err = messenger1.SetPeerAuthenticationInfo(p2p.PeerAuthenticationInfo{
+		PeerType:    core.ObserverPeer,
+		NodeVersion: "v1.0.0",
+	})
+	require.Nil(t, err)
+
+	assert.Eventually(t, func() bool {
+		return messenger2.GetPeerNodeVersion(messenger1.ID()) == "v1.0.0" &&
+			messenger1.GetPeerNodeVersion(messenger2.ID()) == "v2.0.0"
+	}, timeoutWaitResponses, time.Millisecond*10)
+}
+
+func TestNetworkMessenger_PeerAuthenticationDisabledShouldErr(t *testing.T) {
+	messenger := createMockMessenger()
+	defer closeMessengers(messenger)
+
+	err := messenger.SetPeerAuthenticationInfo(p2p.PeerAuthenticationInfo{PeerType: core.ObserverPeer})
+	assert.Equal(t, p2p.ErrPeerAuthenticationDisabled, err)
+}
+
+func TestNetworkMessenger_NetworkIdentityHandshake(t *testing.T) {
+	createMessengers := func(chainID1 string, chainID2 string) (p2p.Messenger, p2p.Messenger) {
+		netw := mocknet.New()
+		args := createMockNetworkArgs()
+		args.P2pConfig.NetworkIdentity.Enabled = true
+		args.P2pConfig.NetworkIdentity.ChainID = chainID1
+		messenger1, _ := libp2p.NewMockMessenger(args, netw)
+		args.P2pConfig.NetworkIdentity.ChainID = chainID2
+		messenger2, _ := libp2p.NewMockMessenger(args, netw)
+		_ = netw.LinkAll()
+
+		return messenger1, messenger2
+	}
+
+	t.Run("compatible peers should stay connected", func(t *testing.T) {
+		messenger1, messenger2 := createMessengers("chain", "chain")
+		defer closeMessengers(messenger1, messenger2)
+
+		err := messenger1.ConnectToPeer(messenger2.Addresses()[0])
+		assert.Nil(t, err)
+		time.Sleep(time.Second)
+
+		assert.True(t, messenger1.IsConnected(messenger2.ID()))
+		assert.True(t, messenger2.IsConnected(messenger1.ID()))
+	})
+	t.Run("incompatible peers should be disconnected and kept out", func(t *testing.T) {
+		messenger1, messenger2 := createMessengers("chain", "other chain")
+		defer closeMessengers(messenger1, messenger2)
+
+		err := messenger1.ConnectToPeer(messenger2.Addresses()[0])
+		assert.Nil(t, err)
+		time.Sleep(time.Second)
+
+		assert.False(t, messenger1.IsConnected(messenger2.ID()))
+		assert.False(t, messenger2.IsConnected(messenger1.ID()))
+		// the rejected peers are removed from the peerstore
+		assert.False(t, containsPeerID(messenger1.Peers(), messenger2.ID()))
+		assert.False(t, containsPeerID(messenger2.Peers(), messenger1.ID()))
+
+		// the connection is dropped right away on reconnection
+		_ = messenger2.ConnectToPeer(messenger1.Addresses()[0])
+		time.Sleep(time.Millisecond * 500)
+		assert.False(t, messenger1.IsConnected(messenger2.ID()))
+		assert.False(t, messenger2.IsConnected(messenger1.ID()))
+	})
+}
+
+func TestNetworkMessenger_IncompatiblePeerShouldBeGated(t *testing.T) {
+	args := createMockNetworkArgs()
+	args.P2pConfig.NetworkIdentity.Enabled = true
+	args.P2pConfig.NetworkIdentity.ChainID = "chain"
+	args.P2pPrivateKey = mock.NewPrivateKeyMock()
+	messenger1, err := libp2p.NewNetworkMessenger(args)
+	require.Nil(t, err)
+	args.P2pConfig.NetworkIdentity.ChainID = "other chain"
+	args.P2pPrivateKey = mock.NewPrivateKeyMock()
+	messenger2, err := libp2p.NewNetworkMessenger(args)
+	require.Nil(t, err)
+	defer closeMessengers(messenger1, messenger2)
+
+	err = messenger1.ConnectToPeer(messenger2.Addresses()[0])
+	require.Nil(t, err)
+	time.Sleep(time.Second)
+
+	assert.False(t, messenger1.IsConnected(messenger2.ID()))
+	assert.False(t, messenger2.IsConnected(messenger1.ID()))
+
+	err = messenger1.ConnectToPeer(messenger2.Addresses()[0])
+	assert.NotNil(t, err)
+	err = messenger2.ConnectToPeer(messenger1.Addresses()[0])
+	assert.NotNil(t, err)
+	assert.False(t, messenger1.IsConnected(messenger2.ID()))
+}
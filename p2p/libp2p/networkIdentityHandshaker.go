@@ -0,0 +1,423 @@
+package libp2p
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/config"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/message"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+	"github.com/TerraDharitri/drt-go-chain-core/core/check"
+	ggio "github.com/gogo/protobuf/io"
+	"github.com/libp2p/go-libp2p/core/event"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+)
+
+// currentNetworkIdentityVersion is the version of the protocol this node speaks, as stated in the handshake
+const currentNetworkIdentityVersion = uint32(1)
+const defaultNetworkIdentityTimeout = time.Second * 10
+const maxNetworkIdentitySize = 1 << 10
+
+// the remote peer has up to the timeout to open the stream and as much to exchange the identities
+const inboundHandshakeTimeoutFactor = 2
+
+// ArgsNetworkIdentityHandshaker is the DTO struct used to create a new instance of network identity handshaker
+type ArgsNetworkIdentityHandshaker struct {
+	Host                   ConnectableHost
+	IncompatiblePeers      IncompatiblePeersHandler
+	NetworkType            p2p.NetworkType
+	Config                 config.NetworkIdentityConfig
+	BatchedMessagesEnabled bool
+	AttestationEnabled     bool
+	Logger                 p2p.Logger
+}
+
+// networkIdentityHandshaker runs the network identity handshake on each new outbound connection and answers the
+// handshakes started by the remote peers. The incompatible peers are disconnected, removed from the peerstore and
+// kept out for a while. When the batched messages or the attestation are enabled, the peers that do not complete the
+// handshake are considered incompatible as well
+type networkIdentityHandshaker struct {
+	ctx               context.Context
+	cancelFunc        context.CancelFunc
+	host              ConnectableHost
+	incompatiblePeers IncompatiblePeersHandler
+	emitter           event.Emitter
+	subscription      event.Subscription
+	selfIdentity      *message.NetworkIdentity
+	requireBatches    bool
+	requireAttest     bool
+	timeout           time.Duration
+	log               p2p.Logger
+	mutHandshaken     sync.RWMutex
+	handshakenPeers   map[peer.ID]struct{}
+}
+
+// NewNetworkIdentityHandshaker creates a new network identity handshaker and registers it on the host
+func NewNetworkIdentityHandshaker(args ArgsNetworkIdentityHandshaker) (*networkIdentityHandshaker, error) {
+	err := checkArgsNetworkIdentityHandshaker(args)
+	if err != nil {
+		return nil, err
+	}
+
+	emitter, err := args.Host.EventBus().Emitter(new(event.EvtPeerProtocolsUpdated))
+	if err != nil {
+		return nil, err
+	}
+	subscription, err := args.Host.EventBus().Subscribe(new(event.EvtPeerIdentificationCompleted))
+	if err != nil {
+		_ = emitter.Close()
+		return nil, err
+	}
+
+	minProtocolVersion := args.Config.MinProtocolVersion
+	if minProtocolVersion == 0 {
+		minProtocolVersion = currentNetworkIdentityVersion
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	nih := &networkIdentityHandshaker{
+		ctx:               ctx,
+		cancelFunc:        cancel,
+		host:              args.Host,
+		incompatiblePeers: args.IncompatiblePeers,
+		emitter:           emitter,
+		subscription:      subscription,
+		selfIdentity: &message.NetworkIdentity{
+			ChainId:              args.Config.ChainID,
+			NetworkType:          string(args.NetworkType),
+			TopicMessageVersions: []uint32{currentTopicMessageVersion},
+			ProtocolVersion:      currentNetworkIdentityVersion,
+			MinProtocolVersion:   minProtocolVersion,
+			AttestationEnabled:   args.AttestationEnabled,
+		},
+		requireBatches:  args.BatchedMessagesEnabled,
+		requireAttest:   args.AttestationEnabled,
+		timeout:         time.Duration(args.Config.TimeoutInSec) * time.Second,
+		log:             args.Logger,
+		handshakenPeers: make(map[peer.ID]struct{}),
+	}
+	if nih.requireBatches {
+		nih.selfIdentity.TopicMessageVersions = append(nih.selfIdentity.TopicMessageVersions, batchTopicMessageVersion)
+	}
+	if nih.timeout == 0 {
+		nih.timeout = defaultNetworkIdentityTimeout
+	}
+
+	nih.host.SetStreamHandler(NetworkIdentityID, nih.handleStream)
+	nih.host.Network().Notify(nih)
+
+	go nih.processIdentificationEvents()
+
+	return nih, nil
+}
+
+func checkArgsNetworkIdentityHandshaker(args ArgsNetworkIdentityHandshaker) error {
+	if check.IfNil(args.Host) {
+		return p2p.ErrNilHost
+	}
+	if check.IfNil(args.IncompatiblePeers) {
+		return p2p.ErrNilIncompatiblePeersHandler
+	}
+	if check.IfNil(args.Logger) {
+		return p2p.ErrNilLogger
+	}
+
+	return nil
+}
+
+// Listen is called when network starts listening on an addr
+func (nih *networkIdentityHandshaker) Listen(network.Network, multiaddr.Multiaddr) {}
+
+// ListenClose is called when network stops listening on an addr
+func (nih *networkIdentityHandshaker) ListenClose(network.Network, multiaddr.Multiaddr) {}
+
+// Connected drops the connections of the peers already found incompatible and starts the handshake on the outbound
+// connections, the inbound ones being handshaken by the remote peers. If the handshake is required, the inbound
+// connections are watched so the remote peers not starting the handshake in time are rejected
+func (nih *networkIdentityHandshaker) Connected(netw network.Network, conn network.Conn) {
+	pid := conn.RemotePeer()
+	if nih.incompatiblePeers.IsIncompatible(core.PeerID(pid)) {
+		nih.log.Trace("dropping connection to incompatible peer", "pid", pid.String())
+		_ = conn.Close()
+
+		return
+	}
+
+	if conn.Stat().Direction != network.DirOutbound {
+		if nih.isHandshakeRequired() {
+			go nih.awaitInboundHandshake(conn)
+		}
+
+		return
+	}
+
+	go nih.handshake(pid)
+}
+
+// Disconnected forgets the handshake of the peers no longer connected and removes the incompatible peers from the
+// peerstore once again, as the data about them might have been added while their connection was open
+func (nih *networkIdentityHandshaker) Disconnected(netw network.Network, conn network.Conn) {
+	pid := conn.RemotePeer()
+	if netw.Connectedness(pid) == network.Connected {
+		return
+	}
+
+	nih.mutHandshaken.Lock()
+	delete(nih.handshakenPeers, pid)
+	nih.mutHandshaken.Unlock()
+
+	if !nih.incompatiblePeers.IsIncompatible(core.PeerID(pid)) {
+		return
+	}
+
+	nih.removeFromPeerstore(pid)
+}
+
+// processIdentificationEvents removes from the peerstore the incompatible peers whose identification completed after
+// they were rejected, as the identify service stores their addresses and keys
+func (nih *networkIdentityHandshaker) processIdentificationEvents() {
+	for {
+		select {
+		case <-nih.ctx.Done():
+			return
+		case evt, ok := <-nih.subscription.Out():
+			if !ok {
+				return
+			}
+
+			identificationCompleted, isIdentification := evt.(event.EvtPeerIdentificationCompleted)
+			if !isIdentification {
+				continue
+			}
+
+			pid := identificationCompleted.Peer
+			if !nih.incompatiblePeers.IsIncompatible(core.PeerID(pid)) {
+				continue
+			}
+			if nih.host.Network().Connectedness(pid) == network.Connected {
+				continue
+			}
+
+			nih.removeFromPeerstore(pid)
+		}
+	}
+}
+
+func (nih *networkIdentityHandshaker) handshake(pid peer.ID) {
+	ctx, cancel := context.WithTimeout(nih.ctx, nih.timeout)
+	defer cancel()
+
+	stream, err := nih.host.NewStream(ctx, pid, NetworkIdentityID)
+	if err != nil {
+		nih.processFailedHandshake(pid, fmt.Errorf("cannot open the network identity stream: %w", err))
+		return
+	}
+	_ = stream.SetDeadline(time.Now().Add(nih.timeout))
+
+	err = ggio.NewDelimitedWriter(stream).WriteMsg(nih.selfIdentity)
+	if err != nil {
+		_ = stream.Reset()
+		nih.processFailedHandshake(pid, fmt.Errorf("cannot send the network identity: %w", err))
+		return
+	}
+
+	remoteIdentity := &message.NetworkIdentity{}
+	err = ggio.NewDelimitedReader(stream, maxNetworkIdentitySize).ReadMsg(remoteIdentity)
+	if err != nil {
+		_ = stream.Reset()
+		nih.processFailedHandshake(pid, fmt.Errorf("cannot read the network identity: %w", err))
+		return
+	}
+	_ = stream.Close()
+
+	nih.processIdentity(remoteIdentity, pid)
+}
+
+// isHandshakeRequired returns true if the peers not completing the handshake should be rejected, as they could not
+// process the batched or the attested messages
+func (nih *networkIdentityHandshaker) isHandshakeRequired() bool {
+	return nih.requireBatches || nih.requireAttest
+}
+
+// processFailedHandshake keeps the peers not speaking the protocol yet, so the nodes can be upgraded one by one,
+// unless the handshake is required
+func (nih *networkIdentityHandshaker) processFailedHandshake(pid peer.ID, reason error) {
+	nih.log.Trace("network identity handshake failed", "pid", pid.Pretty(), "error", reason)
+	if !nih.isHandshakeRequired() {
+		return
+	}
+	if nih.ctx.Err() != nil {
+		return
+	}
+	if nih.host.Network().Connectedness(pid) != network.Connected {
+		return
+	}
+
+	nih.rejectPeer(pid, fmt.Errorf("%w, %s", p2p.ErrIncompatiblePeer, reason.Error()))
+}
+
+// awaitInboundHandshake rejects the remote peer of an inbound connection if it was not handshaken within the timeout
+func (nih *networkIdentityHandshaker) awaitInboundHandshake(conn network.Conn) {
+	timer := time.NewTimer(nih.timeout * inboundHandshakeTimeoutFactor)
+	defer timer.Stop()
+
+	select {
+	case <-nih.ctx.Done():
+		return
+	case <-timer.C:
+	}
+
+	pid := conn.RemotePeer()
+	if conn.IsClosed() || nih.isHandshaken(pid) {
+		return
+	}
+
+	nih.rejectPeer(pid, fmt.Errorf("%w, the network identity handshake was not completed in time",
+		p2p.ErrIncompatiblePeer))
+}
+
+func (nih *networkIdentityHandshaker) isHandshaken(pid peer.ID) bool {
+	nih.mutHandshaken.RLock()
+	defer nih.mutHandshaken.RUnlock()
+
+	_, found := nih.handshakenPeers[pid]
+
+	return found
+}
+
+func (nih *networkIdentityHandshaker) handleStream(stream network.Stream) {
+	_ = stream.SetDeadline(time.Now().Add(nih.timeout))
+	pid := stream.Conn().RemotePeer()
+
+	remoteIdentity := &message.NetworkIdentity{}
+	err := ggio.NewDelimitedReader(stream, maxNetworkIdentitySize).ReadMsg(remoteIdentity)
+	if err != nil {
+		_ = stream.Reset()
+		nih.log.Trace("cannot read the network identity", "pid", pid.Pretty(), "error", err)
+		return
+	}
+
+	// the own identity is sent even to an incompatible peer so it can log the reason on its side as well
+	err = ggio.NewDelimitedWriter(stream).WriteMsg(nih.selfIdentity)
+	if err != nil {
+		_ = stream.Reset()
+		nih.log.Trace("cannot send the network identity", "pid", pid.Pretty(), "error", err)
+	} else {
+		// the connection is not closed before the initiator reads the identity, so wait for it to close the stream
+		_ = stream.CloseWrite()
+		_, _ = io.Copy(io.Discard, io.LimitReader(stream, maxNetworkIdentitySize))
+		_ = stream.Close()
+	}
+
+	nih.processIdentity(remoteIdentity, pid)
+}
+
+func (nih *networkIdentityHandshaker) processIdentity(remoteIdentity *message.NetworkIdentity, pid peer.ID) {
+	err := nih.checkCompatibility(remoteIdentity)
+	if err != nil {
+		nih.rejectPeer(pid, err)
+		return
+	}
+
+	nih.mutHandshaken.Lock()
+	nih.handshakenPeers[pid] = struct{}{}
+	nih.mutHandshaken.Unlock()
+
+	nih.log.Trace("compatible peer", "pid", pid.Pretty())
+}
+
+func (nih *networkIdentityHandshaker) checkCompatibility(remoteIdentity *message.NetworkIdentity) error {
+	if remoteIdentity.ChainId != nih.selfIdentity.ChainId {
+		return fmt.Errorf("%w, chain ID mismatch: local %s, remote %s",
+			p2p.ErrIncompatiblePeer, nih.selfIdentity.ChainId, remoteIdentity.ChainId)
+	}
+	if remoteIdentity.NetworkType != nih.selfIdentity.NetworkType {
+		return fmt.Errorf("%w, network type mismatch: local %s, remote %s",
+			p2p.ErrIncompatiblePeer, nih.selfIdentity.NetworkType, remoteIdentity.NetworkType)
+	}
+	if remoteIdentity.ProtocolVersion < nih.selfIdentity.MinProtocolVersion {
+		return fmt.Errorf("%w, remote protocol version %d is lower than the minimum supported version %d",
+			p2p.ErrIncompatiblePeer, remoteIdentity.ProtocolVersion, nih.selfIdentity.MinProtocolVersion)
+	}
+	if nih.selfIdentity.ProtocolVersion < remoteIdentity.MinProtocolVersion {
+		return fmt.Errorf("%w, local protocol version %d is lower than the remote minimum supported version %d",
+			p2p.ErrIncompatiblePeer, nih.selfIdentity.ProtocolVersion, remoteIdentity.MinProtocolVersion)
+	}
+	if !hasCommonVersion(nih.selfIdentity.TopicMessageVersions, remoteIdentity.TopicMessageVersions) {
+		return fmt.Errorf("%w, no common topic message version: local %v, remote %v",
+			p2p.ErrIncompatiblePeer, nih.selfIdentity.TopicMessageVersions, remoteIdentity.TopicMessageVersions)
+	}
+	// the batches are relayed to all the peers, so a peer not supporting them can not be part of the network
+	if nih.requireBatches && !hasCommonVersion([]uint32{batchTopicMessageVersion}, remoteIdentity.TopicMessageVersions) {
+		return fmt.Errorf("%w, remote peer does not support the batched messages, remote versions %v",
+			p2p.ErrIncompatiblePeer, remoteIdentity.TopicMessageVersions)
+	}
+	// the attested messages are relayed to all the peers as well
+	if nih.requireAttest && !remoteIdentity.AttestationEnabled {
+		return fmt.Errorf("%w, remote peer does not support the messages attestation", p2p.ErrIncompatiblePeer)
+	}
+
+	return nil
+}
+
+func hasCommonVersion(localVersions []uint32, remoteVersions []uint32) bool {
+	for _, localVersion := range localVersions {
+		for _, remoteVersion := range remoteVersions {
+			if localVersion == remoteVersion {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (nih *networkIdentityHandshaker) rejectPeer(pid peer.ID, reason error) {
+	nih.log.Debug("rejecting incompatible peer",
+		"pid", pid.Pretty(),
+		"reason", reason.Error(),
+		"time", p2p.WrongP2PMessageBlacklistDuration,
+	)
+
+	nih.incompatiblePeers.AddIncompatiblePeer(core.PeerID(pid), p2p.WrongP2PMessageBlacklistDuration)
+	_ = nih.host.Network().ClosePeer(pid)
+	nih.removeFromPeerstore(pid)
+
+	// the kad dht drops from its routing table the peers that no longer speak its protocols
+	err := nih.emitter.Emit(event.EvtPeerProtocolsUpdated{Peer: pid})
+	if err != nil {
+		nih.log.Trace("cannot emit the peer protocols update", "pid", pid.Pretty(), "error", err)
+	}
+}
+
+func (nih *networkIdentityHandshaker) removeFromPeerstore(pid peer.ID) {
+	peerstore := nih.host.Peerstore()
+	peerstore.RemovePeer(pid)
+	peerstore.ClearAddrs(pid)
+}
+
+// Close stops the network identity handshaker
+func (nih *networkIdentityHandshaker) Close() error {
+	nih.cancelFunc()
+	nih.host.RemoveStreamHandler(NetworkIdentityID)
+	nih.host.Network().StopNotify(nih)
+
+	errSubscription := nih.subscription.Close()
+	errEmitter := nih.emitter.Close()
+	if errSubscription != nil {
+		return errSubscription
+	}
+
+	return errEmitter
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (nih *networkIdentityHandshaker) IsInterfaceNil() bool {
+	return nih == nil
+}
@@ -0,0 +1,521 @@
+package libp2p_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/config"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/message"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/mock"
+	"github.com/TerraDharitri/drt-go-chain-communication/testscommon"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+	"github.com/TerraDharitri/drt-go-chain-core/core/check"
+	"github.com/libp2p/go-libp2p/core/event"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func createMockArgsNetworkIdentityHandshaker() libp2p.ArgsNetworkIdentityHandshaker {
+	return libp2p.ArgsNetworkIdentityHandshaker{
+		Host: &mock.ConnectableHostStub{
+			EventBusCalled: func() event.Bus {
+				return &mock.EventBusStub{
+					EmitterCalled: func(eventType interface{}, opts ...event.EmitterOpt) (event.Emitter, error) {
+						return &mock.EventEmitterStub{}, nil
+					},
+				}
+			},
+		},
+		IncompatiblePeers: &mock.IncompatiblePeersHandlerStub{},
+		NetworkType:       "main",
+		Config: config.NetworkIdentityConfig{
+			Enabled: true,
+			ChainID: "chain",
+		},
+		Logger: &testscommon.LoggerStub{},
+	}
+}
+
+func TestNewNetworkIdentityHandshaker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil host should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsNetworkIdentityHandshaker()
+		args.Host = nil
+		nih, err := libp2p.NewNetworkIdentityHandshaker(args)
+		assert.Equal(t, p2p.ErrNilHost, err)
+		assert.True(t, check.IfNil(nih))
+	})
+	t.Run("nil incompatible peers handler should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsNetworkIdentityHandshaker()
+		args.IncompatiblePeers = nil
+		nih, err := libp2p.NewNetworkIdentityHandshaker(args)
+		assert.Equal(t, p2p.ErrNilIncompatiblePeersHandler, err)
+		assert.True(t, check.IfNil(nih))
+	})
+	t.Run("nil logger should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsNetworkIdentityHandshaker()
+		args.Logger = nil
+		nih, err := libp2p.NewNetworkIdentityHandshaker(args)
+		assert.Equal(t, p2p.ErrNilLogger, err)
+		assert.True(t, check.IfNil(nih))
+	})
+	t.Run("emitter creation fails should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsNetworkIdentityHandshaker()
+		args.Host = &mock.ConnectableHostStub{
+			EventBusCalled: func() event.Bus {
+				return &mock.EventBusStub{
+					EmitterCalled: func(eventType interface{}, opts ...event.EmitterOpt) (event.Emitter, error) {
+						return nil, expectedError
+					},
+				}
+			},
+		}
+		nih, err := libp2p.NewNetworkIdentityHandshaker(args)
+		assert.Equal(t, expectedError, err)
+		assert.True(t, check.IfNil(nih))
+	})
+	t.Run("should work and register on the host", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsNetworkIdentityHandshaker()
+		host := args.Host.(*mock.ConnectableHostStub)
+		var registeredProtocol protocol.ID
+		var removedProtocol protocol.ID
+		host.SetStreamHandlerCalled = func(pid protocol.ID, handler network.StreamHandler) {
+			registeredProtocol = pid
+		}
+		host.RemoveStreamHandlerCalled = func(pid protocol.ID) {
+			removedProtocol = pid
+		}
+		nih, err := libp2p.NewNetworkIdentityHandshaker(args)
+		assert.Nil(t, err)
+		assert.False(t, check.IfNil(nih))
+		assert.Equal(t, libp2p.NetworkIdentityID, registeredProtocol)
+
+		selfIdentity := nih.SelfIdentity()
+		assert.Equal(t, "chain", selfIdentity.ChainId)
+		assert.Equal(t, "main", selfIdentity.NetworkType)
+		assert.Equal(t, selfIdentity.ProtocolVersion, selfIdentity.MinProtocolVersion)
+
+		assert.Nil(t, nih.Close())
+		assert.Equal(t, libp2p.NetworkIdentityID, removedProtocol)
+	})
+}
+
+func TestNetworkIdentityHandshaker_ProcessIdentity(t *testing.T) {
+	t.Parallel()
+
+	createCompatibleIdentity := func() *message.NetworkIdentity {
+		args := createMockArgsNetworkIdentityHandshaker()
+		nih, _ := libp2p.NewNetworkIdentityHandshaker(args)
+		selfIdentity := *nih.SelfIdentity()
+
+		return &selfIdentity
+	}
+	testRejected := func(t *testing.T, remoteIdentity *message.NetworkIdentity) {
+		args := createMockArgsNetworkIdentityHandshaker()
+		var addedPid core.PeerID
+		args.IncompatiblePeers = &mock.IncompatiblePeersHandlerStub{
+			AddIncompatiblePeerCalled: func(pid core.PeerID, duration time.Duration) {
+				addedPid = pid
+				assert.Equal(t, p2p.WrongP2PMessageBlacklistDuration, duration)
+			},
+		}
+		closedPeer := peer.ID("")
+		removedPeer := peer.ID("")
+		clearedPeer := peer.ID("")
+		var emittedEvent interface{}
+		args.Host = &mock.ConnectableHostStub{
+			EventBusCalled: func() event.Bus {
+				return &mock.EventBusStub{
+					EmitterCalled: func(eventType interface{}, opts ...event.EmitterOpt) (event.Emitter, error) {
+						return &mock.EventEmitterStub{
+							EmitCalled: func(evt interface{}) error {
+								emittedEvent = evt
+								return nil
+							},
+						}, nil
+					},
+				}
+			},
+			NetworkCalled: func() network.Network {
+				return &mock.NetworkStub{
+					ClosePeerCall: func(pid peer.ID) error {
+						closedPeer = pid
+						return nil
+					},
+				}
+			},
+			PeerstoreCalled: func() peerstore.Peerstore {
+				return &mock.PeerstoreStub{
+					RemovePeerCalled: func(id peer.ID) {
+						removedPeer = id
+					},
+					ClearAddrsCalled: func(p peer.ID) {
+						clearedPeer = p
+					},
+				}
+			},
+		}
+		nih, _ := libp2p.NewNetworkIdentityHandshaker(args)
+
+		pid := peer.ID(providedPid)
+		nih.ProcessIdentity(remoteIdentity, pid)
+		assert.Equal(t, providedPid, addedPid)
+		assert.Equal(t, pid, closedPeer)
+		assert.Equal(t, pid, removedPeer)
+		assert.Equal(t, pid, clearedPeer)
+		assert.Equal(t, event.EvtPeerProtocolsUpdated{Peer: pid}, emittedEvent)
+	}
+
+	t.Run("compatible peer should be kept", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsNetworkIdentityHandshaker()
+		args.IncompatiblePeers = &mock.IncompatiblePeersHandlerStub{
+			AddIncompatiblePeerCalled: func(pid core.PeerID, duration time.Duration) {
+				assert.Fail(t, "should have not rejected the peer")
+			},
+		}
+		args.Host.(*mock.ConnectableHostStub).NetworkCalled = func() network.Network {
+			return &mock.NetworkStub{
+				ClosePeerCall: func(pid peer.ID) error {
+					assert.Fail(t, "should have not closed the peer")
+					return nil
+				},
+			}
+		}
+		nih, _ := libp2p.NewNetworkIdentityHandshaker(args)
+
+		remoteIdentity := createCompatibleIdentity()
+		remoteIdentity.TopicMessageVersions = []uint32{remoteIdentity.TopicMessageVersions[0], 100}
+		nih.ProcessIdentity(remoteIdentity, peer.ID(providedPid))
+	})
+	t.Run("different chain ID should reject the peer", func(t *testing.T) {
+		t.Parallel()
+
+		remoteIdentity := createCompatibleIdentity()
+		remoteIdentity.ChainId = "other chain"
+		testRejected(t, remoteIdentity)
+	})
+	t.Run("different network type should reject the peer", func(t *testing.T) {
+		t.Parallel()
+
+		remoteIdentity := createCompatibleIdentity()
+		remoteIdentity.NetworkType = "full archive"
+		testRejected(t, remoteIdentity)
+	})
+	t.Run("too old remote protocol version should reject the peer", func(t *testing.T) {
+		t.Parallel()
+
+		remoteIdentity := createCompatibleIdentity()
+		remoteIdentity.ProtocolVersion = 0
+		testRejected(t, remoteIdentity)
+	})
+	t.Run("too old local protocol version should reject the peer", func(t *testing.T) {
+		t.Parallel()
+
+		remoteIdentity := createCompatibleIdentity()
+		remoteIdentity.ProtocolVersion++
+		remoteIdentity.MinProtocolVersion = remoteIdentity.ProtocolVersion
+		testRejected(t, remoteIdentity)
+	})
+	t.Run("no common topic message version should reject the peer", func(t *testing.T) {
+		t.Parallel()
+
+		remoteIdentity := createCompatibleIdentity()
+		remoteIdentity.TopicMessageVersions = []uint32{100}
+		testRejected(t, remoteIdentity)
+	})
+	t.Run("batched messages enabled should require the peers to support them", func(t *testing.T) {
+		t.Parallel()
+
+		var rejectedPids []core.PeerID
+		args := createMockArgsNetworkIdentityHandshaker()
+		args.BatchedMessagesEnabled = true
+		args.IncompatiblePeers = &mock.IncompatiblePeersHandlerStub{
+			AddIncompatiblePeerCalled: func(pid core.PeerID, duration time.Duration) {
+				rejectedPids = append(rejectedPids, pid)
+			},
+		}
+		args.Host.(*mock.ConnectableHostStub).NetworkCalled = func() network.Network {
+			return &mock.NetworkStub{
+				ClosePeerCall: func(pid peer.ID) error {
+					return nil
+				},
+			}
+		}
+		args.Host.(*mock.ConnectableHostStub).PeerstoreCalled = func() peerstore.Peerstore {
+			return &mock.PeerstoreStub{}
+		}
+		nih, _ := libp2p.NewNetworkIdentityHandshaker(args)
+		assert.Equal(t, []uint32{libp2p.CurrentTopicMessageVersion, libp2p.BatchTopicMessageVersion}, nih.SelfIdentity().TopicMessageVersions)
+
+		remoteIdentity := *nih.SelfIdentity()
+		nih.ProcessIdentity(&remoteIdentity, peer.ID("supporting peer"))
+		assert.Empty(t, rejectedPids)
+
+		remoteIdentity.TopicMessageVersions = []uint32{libp2p.CurrentTopicMessageVersion}
+		nih.ProcessIdentity(&remoteIdentity, peer.ID("old peer"))
+		assert.Equal(t, []core.PeerID{"old peer"}, rejectedPids)
+	})
+	t.Run("attestation enabled should require the peers to support it", func(t *testing.T) {
+		t.Parallel()
+
+		var rejectedPids []core.PeerID
+		args := createMockArgsNetworkIdentityHandshaker()
+		args.AttestationEnabled = true
+		args.IncompatiblePeers = &mock.IncompatiblePeersHandlerStub{
+			AddIncompatiblePeerCalled: func(pid core.PeerID, duration time.Duration) {
+				rejectedPids = append(rejectedPids, pid)
+			},
+		}
+		args.Host.(*mock.ConnectableHostStub).NetworkCalled = func() network.Network {
+			return &mock.NetworkStub{
+				ClosePeerCall: func(pid peer.ID) error {
+					return nil
+				},
+			}
+		}
+		args.Host.(*mock.ConnectableHostStub).PeerstoreCalled = func() peerstore.Peerstore {
+			return &mock.PeerstoreStub{}
+		}
+		nih, _ := libp2p.NewNetworkIdentityHandshaker(args)
+		assert.True(t, nih.SelfIdentity().AttestationEnabled)
+
+		remoteIdentity := *nih.SelfIdentity()
+		nih.ProcessIdentity(&remoteIdentity, peer.ID("supporting peer"))
+		assert.Empty(t, rejectedPids)
+
+		remoteIdentity.AttestationEnabled = false
+		nih.ProcessIdentity(&remoteIdentity, peer.ID("old peer"))
+		assert.Equal(t, []core.PeerID{"old peer"}, rejectedPids)
+	})
+}
+
+func TestNetworkIdentityHandshaker_ConnectedShouldDropIncompatiblePeers(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsNetworkIdentityHandshaker()
+	args.IncompatiblePeers = &mock.IncompatiblePeersHandlerStub{
+		IsIncompatibleCalled: func(pid core.PeerID) bool {
+			return true
+		},
+	}
+	nih, _ := libp2p.NewNetworkIdentityHandshaker(args)
+
+	closed := false
+	nih.Connected(nil, &mock.ConnStub{
+		RemotePeerCalled: func() peer.ID {
+			return peer.ID(providedPid)
+		},
+		CloseCalled: func() error {
+			closed = true
+			return errors.New("already closed")
+		},
+	})
+	assert.True(t, closed)
+}
+
+func TestNetworkIdentityHandshaker_FailedHandshake(t *testing.T) {
+	t.Parallel()
+
+	createArgs := func(chRejected chan core.PeerID) libp2p.ArgsNetworkIdentityHandshaker {
+		args := createMockArgsNetworkIdentityHandshaker()
+		args.IncompatiblePeers = &mock.IncompatiblePeersHandlerStub{
+			AddIncompatiblePeerCalled: func(pid core.PeerID, duration time.Duration) {
+				chRejected <- pid
+			},
+		}
+		host := args.Host.(*mock.ConnectableHostStub)
+		host.NetworkCalled = func() network.Network {
+			return &mock.NetworkStub{
+				ConnectednessCalled: func(id peer.ID) network.Connectedness {
+					return network.Connected
+				},
+				ClosePeerCall: func(pid peer.ID) error {
+					return nil
+				},
+			}
+		}
+		host.PeerstoreCalled = func() peerstore.Peerstore {
+			return &mock.PeerstoreStub{}
+		}
+		host.NewStreamCalled = func(ctx context.Context, p peer.ID, pids ...protocol.ID) (network.Stream, error) {
+			return nil, errors.New("protocols not supported")
+		}
+
+		return args
+	}
+	createConn := func(direction network.Direction, isClosed bool) *mock.ConnStub {
+		return &mock.ConnStub{
+			RemotePeerCalled: func() peer.ID {
+				return peer.ID(providedPid)
+			},
+			StatCalled: func() network.ConnStats {
+				return network.ConnStats{
+					Stats: network.Stats{
+						Direction: direction,
+					},
+				}
+			},
+			IsClosedCalled: func() bool {
+				return isClosed
+			},
+		}
+	}
+	testRejected := func(t *testing.T, chRejected chan core.PeerID) {
+		select {
+		case pid := <-chRejected:
+			assert.Equal(t, providedPid, pid)
+		case <-time.After(time.Second):
+			assert.Fail(t, "timeout while waiting for the peer to be rejected")
+		}
+	}
+	testKept := func(t *testing.T, chRejected chan core.PeerID) {
+		select {
+		case <-chRejected:
+			assert.Fail(t, "should have not rejected the peer")
+		case <-time.After(time.Millisecond * 200):
+		}
+	}
+
+	t.Run("outbound handshake failure should keep the peer if batched messages are disabled", func(t *testing.T) {
+		t.Parallel()
+
+		chRejected := make(chan core.PeerID, 1)
+		nih, _ := libp2p.NewNetworkIdentityHandshaker(createArgs(chRejected))
+		defer func() {
+			_ = nih.Close()
+		}()
+
+		nih.Connected(nil, createConn(network.DirOutbound, false))
+		testKept(t, chRejected)
+	})
+	t.Run("outbound handshake failure should reject the peer if batched messages are enabled", func(t *testing.T) {
+		t.Parallel()
+
+		chRejected := make(chan core.PeerID, 1)
+		args := createArgs(chRejected)
+		args.BatchedMessagesEnabled = true
+		nih, _ := libp2p.NewNetworkIdentityHandshaker(args)
+		defer func() {
+			_ = nih.Close()
+		}()
+
+		nih.Connected(nil, createConn(network.DirOutbound, false))
+		testRejected(t, chRejected)
+	})
+	t.Run("missing inbound handshake should reject the peer if batched messages are enabled", func(t *testing.T) {
+		t.Parallel()
+
+		chRejected := make(chan core.PeerID, 1)
+		args := createArgs(chRejected)
+		args.BatchedMessagesEnabled = true
+		nih, _ := libp2p.NewNetworkIdentityHandshaker(args)
+		nih.SetTimeout(time.Millisecond * 10)
+		defer func() {
+			_ = nih.Close()
+		}()
+
+		nih.Connected(nil, createConn(network.DirInbound, false))
+		testRejected(t, chRejected)
+	})
+	t.Run("completed inbound handshake should keep the peer", func(t *testing.T) {
+		t.Parallel()
+
+		chRejected := make(chan core.PeerID, 1)
+		args := createArgs(chRejected)
+		args.BatchedMessagesEnabled = true
+		nih, _ := libp2p.NewNetworkIdentityHandshaker(args)
+		nih.SetTimeout(time.Millisecond * 10)
+		defer func() {
+			_ = nih.Close()
+		}()
+
+		remoteIdentity := *nih.SelfIdentity()
+		nih.ProcessIdentity(&remoteIdentity, peer.ID(providedPid))
+		nih.Connected(nil, createConn(network.DirInbound, false))
+		testKept(t, chRejected)
+	})
+	t.Run("closed inbound connection should not reject the peer", func(t *testing.T) {
+		t.Parallel()
+
+		chRejected := make(chan core.PeerID, 1)
+		args := createArgs(chRejected)
+		args.BatchedMessagesEnabled = true
+		nih, _ := libp2p.NewNetworkIdentityHandshaker(args)
+		nih.SetTimeout(time.Millisecond * 10)
+		defer func() {
+			_ = nih.Close()
+		}()
+
+		nih.Connected(nil, createConn(network.DirInbound, true))
+		testKept(t, chRejected)
+	})
+}
+
+func TestNetworkIdentityHandshaker_IdentifiedIncompatiblePeersShouldBeRemovedFromPeerstore(t *testing.T) {
+	t.Parallel()
+
+	chEvents := make(chan interface{})
+	chRemoved := make(chan peer.ID, 1)
+	args := createMockArgsNetworkIdentityHandshaker()
+	args.IncompatiblePeers = &mock.IncompatiblePeersHandlerStub{
+		IsIncompatibleCalled: func(pid core.PeerID) bool {
+			return pid == providedPid
+		},
+	}
+	host := args.Host.(*mock.ConnectableHostStub)
+	host.EventBusCalled = func() event.Bus {
+		return &mock.EventBusStub{
+			EmitterCalled: func(eventType interface{}, opts ...event.EmitterOpt) (event.Emitter, error) {
+				return &mock.EventEmitterStub{}, nil
+			},
+			SubscribeCalled: func(eventType interface{}, opts ...event.SubscriptionOpt) (event.Subscription, error) {
+				return &mock.EventSubscriptionStub{
+					OutCalled: func() <-chan interface{} {
+						return chEvents
+					},
+				}, nil
+			},
+		}
+	}
+	host.PeerstoreCalled = func() peerstore.Peerstore {
+		return &mock.PeerstoreStub{
+			RemovePeerCalled: func(id peer.ID) {
+				chRemoved <- id
+			},
+		}
+	}
+	nih, _ := libp2p.NewNetworkIdentityHandshaker(args)
+	defer func() {
+		_ = nih.Close()
+	}()
+
+	chEvents <- event.EvtPeerIdentificationCompleted{Peer: "compatible pid"}
+	chEvents <- event.EvtPeerIdentificationCompleted{Peer: peer.ID(providedPid)}
+
+	select {
+	case removedPid := <-chRemoved:
+		assert.Equal(t, peer.ID(providedPid), removedPid)
+	case <-time.After(time.Second):
+		assert.Fail(t, "timeout while waiting for the peerstore removal")
+	}
+}
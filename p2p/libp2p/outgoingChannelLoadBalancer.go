@@ -2,76 +2,256 @@ package libp2p
 
 import (
 	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/config"
 	"github.com/TerraDharitri/drt-go-chain-core/core/check"
 )
 
 var _ ChannelLoadBalancer = (*outgoingChannelLoadBalancer)(nil)
 
 const defaultSendChannel = "default send channel"
+const defaultOutgoingQueueSize = 100
 
-// outgoingChannelLoadBalancer is a component that evenly balances requests to be sent
+// ArgsOutgoingChannelLoadBalancer is the DTO struct used to create a new instance of outgoing channel load balancer
+type ArgsOutgoingChannelLoadBalancer struct {
+	Config config.OutgoingChannelsConfig
+	Logger p2p.Logger
+}
+
+type channelSettings struct {
+	priority   uint32
+	weight     int64
+	queueSize  int
+	dropPolicy string
+}
+
+type queuedSendableData struct {
+	data       *SendableData
+	enqueuedAt time.Time
+}
+
+// channelQueue holds the messages collected from a channel until they are scheduled for sending
+type channelQueue struct {
+	name          string
+	settings      channelSettings
+	items         []*queuedSendableData
+	currentWeight int64
+	removed       bool
+	numSent       uint64
+	numDropped    uint64
+	lastWaitTime  time.Duration
+	totalWaitTime time.Duration
+}
+
+// outgoingChannelLoadBalancer is a component that schedules the requests to be sent. The channels with a higher
+// priority are always served first, while the channels with the same priority share the sending slots according
+// to their weights
 type outgoingChannelLoadBalancer struct {
-	mut      sync.RWMutex
-	chans    []chan *SendableData
-	mainChan chan *SendableData
-	names    []string
+	mut   sync.RWMutex
+	chans []chan *SendableData
+	names []string
 	//namesChans is defined only for performance purposes as to fast search by name
 	//iteration is done directly on slices as that is used very often and is about 50x
 	//faster than an iteration over a map
 	namesChans map[string]chan *SendableData
-	cancelFunc context.CancelFunc
-	ctx        context.Context //we need the context saved here in order to call appendChannel from exported func AddChannel
-	log        p2p.Logger
+	//namesDone holds, for each channel, the signal closed when the channel is removed or the load balancer is closed.
+	//The channels are never closed, so the senders must select on this signal to avoid blocking forever
+	namesDone    map[string]<-chan struct{}
+	namesCancels map[string]context.CancelFunc
+	cancelFunc   context.CancelFunc
+	ctx          context.Context //we need the context saved here in order to call appendChannel from exported func AddChannel
+	log          p2p.Logger
+
+	defaultSettings  channelSettings
+	channelsSettings map[string]channelSettings
+
+	mutQueues     sync.Mutex
+	queuesChanged *sync.Cond
+	queues        []*channelQueue
+	closed        bool
 }
 
 // NewOutgoingChannelLoadBalancer creates a new instance of a ChannelLoadBalancer instance
-func NewOutgoingChannelLoadBalancer(logger p2p.Logger) (*outgoingChannelLoadBalancer, error) {
-	if check.IfNil(logger) {
+func NewOutgoingChannelLoadBalancer(args ArgsOutgoingChannelLoadBalancer) (*outgoingChannelLoadBalancer, error) {
+	if check.IfNil(args.Logger) {
 		return nil, p2p.ErrNilLogger
 	}
 
+	defaultSettings, channelsSettings, err := createChannelsSettings(args.Config)
+	if err != nil {
+		return nil, err
+	}
+
 	ctx, cancelFunc := context.WithCancel(context.Background())
 
 	oclb := &outgoingChannelLoadBalancer{
-		chans:      make([]chan *SendableData, 0),
-		names:      make([]string, 0),
-		namesChans: make(map[string]chan *SendableData),
-		mainChan:   make(chan *SendableData),
-		cancelFunc: cancelFunc,
-		ctx:        ctx,
-		log:        logger,
+		chans:            make([]chan *SendableData, 0),
+		names:            make([]string, 0),
+		namesChans:       make(map[string]chan *SendableData),
+		namesDone:        make(map[string]<-chan struct{}),
+		namesCancels:     make(map[string]context.CancelFunc),
+		cancelFunc:       cancelFunc,
+		ctx:              ctx,
+		log:              args.Logger,
+		defaultSettings:  defaultSettings,
+		channelsSettings: channelsSettings,
+		queues:           make([]*channelQueue, 0),
 	}
+	oclb.queuesChanged = sync.NewCond(&oclb.mutQueues)
 
 	oclb.appendChannel(defaultSendChannel)
 
 	return oclb, nil
 }
 
+func createChannelsSettings(channelsConfig config.OutgoingChannelsConfig) (channelSettings, map[string]channelSettings, error) {
+	defaultSettings := channelSettings{
+		weight:     1,
+		queueSize:  defaultOutgoingQueueSize,
+		dropPolicy: p2p.BlockOnFullQueuePolicy,
+	}
+	if channelsConfig.DefaultQueueSize < 0 {
+		return channelSettings{}, nil, fmt.Errorf("%w for outgoing channels DefaultQueueSize", p2p.ErrInvalidValue)
+	}
+	if channelsConfig.DefaultQueueSize > 0 {
+		defaultSettings.queueSize = channelsConfig.DefaultQueueSize
+	}
+	if len(channelsConfig.DefaultDropPolicy) > 0 {
+		err := checkOutgoingDropPolicy(channelsConfig.DefaultDropPolicy)
+		if err != nil {
+			return channelSettings{}, nil, err
+		}
+		defaultSettings.dropPolicy = channelsConfig.DefaultDropPolicy
+	}
+
+	channelsSettings := make(map[string]channelSettings, len(channelsConfig.Channels))
+	for _, channelConfig := range channelsConfig.Channels {
+		settings, err := createChannelSettings(channelConfig, defaultSettings)
+		if err != nil {
+			return channelSettings{}, nil, fmt.Errorf("%w for outgoing channel %s", err, channelConfig.Name)
+		}
+
+		_, found := channelsSettings[channelConfig.Name]
+		if found {
+			return channelSettings{}, nil, fmt.Errorf("%w: %s", p2p.ErrDuplicatedChannel, channelConfig.Name)
+		}
+
+		channelsSettings[channelConfig.Name] = settings
+	}
+
+	return defaultSettings, channelsSettings, nil
+}
+
+func createChannelSettings(channelConfig config.OutgoingChannelConfig, defaultSettings channelSettings) (channelSettings, error) {
+	if len(channelConfig.Name) == 0 {
+		return channelSettings{}, p2p.ErrEmptyChannelName
+	}
+	if channelConfig.QueueSize < 0 {
+		return channelSettings{}, fmt.Errorf("%w for QueueSize", p2p.ErrInvalidValue)
+	}
+
+	settings := defaultSettings
+	settings.priority = channelConfig.Priority
+	if channelConfig.Weight > 0 {
+		settings.weight = int64(channelConfig.Weight)
+	}
+	if channelConfig.QueueSize > 0 {
+		settings.queueSize = channelConfig.QueueSize
+	}
+	if len(channelConfig.DropPolicy) > 0 {
+		err := checkOutgoingDropPolicy(channelConfig.DropPolicy)
+		if err != nil {
+			return channelSettings{}, err
+		}
+		settings.dropPolicy = channelConfig.DropPolicy
+	}
+
+	return settings, nil
+}
+
+func checkOutgoingDropPolicy(dropPolicy string) error {
+	switch dropPolicy {
+	case p2p.BlockOnFullQueuePolicy, p2p.DropNewestPolicy, p2p.DropOldestPolicy:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", p2p.ErrUnknownDropPolicy, dropPolicy)
+	}
+}
+
 func (oplb *outgoingChannelLoadBalancer) appendChannel(channel string) {
 	oplb.names = append(oplb.names, channel)
 	ch := make(chan *SendableData)
 	oplb.chans = append(oplb.chans, ch)
 	oplb.namesChans[channel] = ch
+	channelCtx, channelCancel := context.WithCancel(oplb.ctx)
+	oplb.namesDone[channel] = channelCtx.Done()
+	oplb.namesCancels[channel] = channelCancel
+
+	settings, found := oplb.channelsSettings[channel]
+	if !found {
+		settings = oplb.defaultSettings
+	}
+	queue := &channelQueue{
+		name:     channel,
+		settings: settings,
+		items:    make([]*queuedSendableData, 0),
+	}
+
+	oplb.mutQueues.Lock()
+	oplb.queues = append(oplb.queues, queue)
+	oplb.mutQueues.Unlock()
 
 	go func() {
 		for {
-			var obj *SendableData
-
 			select {
-			case obj = <-ch:
-			case <-oplb.ctx.Done():
-				oplb.log.Debug("closing OutgoingChannelLoadBalancer's append channel go routine")
+			case obj := <-ch:
+				oplb.enqueue(queue, obj)
+			case <-channelCtx.Done():
+				oplb.log.Debug("closing OutgoingChannelLoadBalancer's append channel go routine", "channel", channel)
 				return
 			}
-
-			oplb.mainChan <- obj
 		}
 	}()
 }
 
+func (oplb *outgoingChannelLoadBalancer) enqueue(queue *channelQueue, obj *SendableData) {
+	oplb.mutQueues.Lock()
+	defer oplb.mutQueues.Unlock()
+
+	isBlockingPolicy := queue.settings.dropPolicy == p2p.BlockOnFullQueuePolicy
+	for isBlockingPolicy && len(queue.items) >= queue.settings.queueSize && !oplb.closed && !queue.removed {
+		oplb.queuesChanged.Wait()
+	}
+	if oplb.closed || queue.removed {
+		obj.notify(p2p.ErrMessageDropped)
+		return
+	}
+
+	if len(queue.items) >= queue.settings.queueSize {
+		queue.numDropped++
+		if queue.settings.dropPolicy == p2p.DropNewestPolicy {
+			oplb.log.Trace("outgoing queue full, dropping the newest message", "channel", queue.name)
+			obj.notify(fmt.Errorf("%w, outgoing queue %s is full", p2p.ErrMessageDropped, queue.name))
+			return
+		}
+
+		oplb.log.Trace("outgoing queue full, dropping the oldest message", "channel", queue.name)
+		queue.items[0].data.notify(fmt.Errorf("%w, outgoing queue %s is full", p2p.ErrMessageDropped, queue.name))
+		queue.items[0] = nil
+		queue.items = queue.items[1:]
+	}
+
+	queue.items = append(queue.items, &queuedSendableData{
+		data:       obj,
+		enqueuedAt: time.Now(),
+	})
+	oplb.queuesChanged.Broadcast()
+}
+
 // AddChannel adds a new channel to the throttler, if it does not exists
 func (oplb *outgoingChannelLoadBalancer) AddChannel(channel string) error {
 	if channel == defaultSendChannel {
@@ -91,7 +271,8 @@ func (oplb *outgoingChannelLoadBalancer) AddChannel(channel string) error {
 	return nil
 }
 
-// RemoveChannel removes an existing channel from the throttler
+// RemoveChannel removes an existing channel from the throttler. The messages still queued on the channel are dropped.
+// The channel is not closed as senders might still hold it, instead its done signal is closed
 func (oplb *outgoingChannelLoadBalancer) RemoveChannel(channel string) error {
 	if channel == defaultSendChannel {
 		return p2p.ErrChannelCanNotBeDeleted
@@ -113,8 +294,6 @@ func (oplb *outgoingChannelLoadBalancer) RemoveChannel(channel string) error {
 		return p2p.ErrChannelDoesNotExist
 	}
 
-	sendableChan := oplb.chans[index]
-
 	//remove the index-th element in the chan slice
 	copy(oplb.chans[index:], oplb.chans[index+1:])
 	oplb.chans[len(oplb.chans)-1] = nil
@@ -124,42 +303,186 @@ func (oplb *outgoingChannelLoadBalancer) RemoveChannel(channel string) error {
 	copy(oplb.names[index:], oplb.names[index+1:])
 	oplb.names = oplb.names[:len(oplb.names)-1]
 
-	close(sendableChan)
+	oplb.namesCancels[channel]()
 
 	delete(oplb.namesChans, channel)
+	delete(oplb.namesDone, channel)
+	delete(oplb.namesCancels, channel)
+
+	oplb.removeQueue(channel)
 
 	return nil
 }
 
+func (oplb *outgoingChannelLoadBalancer) removeQueue(channel string) {
+	oplb.mutQueues.Lock()
+	defer oplb.mutQueues.Unlock()
+
+	for idx, queue := range oplb.queues {
+		if queue.name != channel {
+			continue
+		}
+
+		queue.removed = true
+		queue.dropAll()
+		oplb.queues = append(oplb.queues[:idx], oplb.queues[idx+1:]...)
+		oplb.queuesChanged.Broadcast()
+		return
+	}
+}
+
 // GetChannelOrDefault fetches the required channel or the default if the channel is not present
 func (oplb *outgoingChannelLoadBalancer) GetChannelOrDefault(channel string) chan *SendableData {
+	ch, _ := oplb.GetChannelWithDoneOrDefault(channel)
+	return ch
+}
+
+// GetChannelWithDoneOrDefault fetches the required channel or the default if the channel is not present, together
+// with the signal closed when that channel is removed or the load balancer is closed
+func (oplb *outgoingChannelLoadBalancer) GetChannelWithDoneOrDefault(channel string) (chan *SendableData, <-chan struct{}) {
 	oplb.mut.RLock()
 	defer oplb.mut.RUnlock()
 
 	ch := oplb.namesChans[channel]
 	if ch != nil {
-		return ch
+		return ch, oplb.namesDone[channel]
 	}
 
-	return oplb.chans[0]
+	return oplb.chans[0], oplb.namesDone[defaultSendChannel]
 }
 
-// CollectOneElementFromChannels gets the waiting object from mainChan. It is a blocking call.
+// CollectOneElementFromChannels gets the next object to be sent, according to the channels priorities and weights.
+// It is a blocking call that returns nil after the load balancer is closed.
 func (oplb *outgoingChannelLoadBalancer) CollectOneElementFromChannels() *SendableData {
-	select {
-	case obj := <-oplb.mainChan:
-		return obj
-	case <-oplb.ctx.Done():
+	oplb.mutQueues.Lock()
+	defer oplb.mutQueues.Unlock()
+
+	for {
+		if oplb.closed {
+			return nil
+		}
+
+		queue := oplb.selectQueue()
+		if queue != nil {
+			return oplb.dequeue(queue)
+		}
+
+		oplb.queuesChanged.Wait()
+	}
+}
+
+// selectQueue returns the non-empty queue with the highest priority. Between the queues with the same priority,
+// a smooth weighted round-robin is used so each queue gets a share of the sending slots proportional to its weight
+func (oplb *outgoingChannelLoadBalancer) selectQueue() *channelQueue {
+	highestPriority := uint32(0)
+	found := false
+	for _, queue := range oplb.queues {
+		if len(queue.items) == 0 {
+			continue
+		}
+		if !found || queue.settings.priority > highestPriority {
+			highestPriority = queue.settings.priority
+			found = true
+		}
+	}
+	if !found {
 		return nil
 	}
+
+	var selected *channelQueue
+	totalWeight := int64(0)
+	for _, queue := range oplb.queues {
+		if len(queue.items) == 0 || queue.settings.priority != highestPriority {
+			continue
+		}
+
+		queue.currentWeight += queue.settings.weight
+		totalWeight += queue.settings.weight
+		if selected == nil || queue.currentWeight > selected.currentWeight {
+			selected = queue
+		}
+	}
+	selected.currentWeight -= totalWeight
+
+	return selected
+}
+
+func (oplb *outgoingChannelLoadBalancer) dequeue(queue *channelQueue) *SendableData {
+	item := queue.items[0]
+	queue.items[0] = nil
+	queue.items = queue.items[1:]
+	if len(queue.items) == 0 {
+		queue.currentWeight = 0
+	}
+
+	waitTime := time.Since(item.enqueuedAt)
+	queue.numSent++
+	queue.lastWaitTime = waitTime
+	queue.totalWaitTime += waitTime
+
+	// wake up the channels blocked on a full queue
+	oplb.queuesChanged.Broadcast()
+
+	return item.data
+}
+
+// GetChannelsMetrics returns the queue depth and the wait time of each channel
+func (oplb *outgoingChannelLoadBalancer) GetChannelsMetrics() map[string]p2p.OutgoingChannelMetrics {
+	oplb.mutQueues.Lock()
+	defer oplb.mutQueues.Unlock()
+
+	channelsMetrics := make(map[string]p2p.OutgoingChannelMetrics, len(oplb.queues))
+	for _, queue := range oplb.queues {
+		channelMetrics := p2p.OutgoingChannelMetrics{
+			QueueDepth:   len(queue.items),
+			NumSent:      queue.numSent,
+			NumDropped:   queue.numDropped,
+			LastWaitTime: queue.lastWaitTime,
+		}
+		if queue.numSent > 0 {
+			channelMetrics.AverageWaitTime = queue.totalWaitTime / time.Duration(queue.numSent)
+		}
+
+		channelsMetrics[queue.name] = channelMetrics
+	}
+
+	return channelsMetrics
 }
 
 // Close finishes all started go routines in this instance
 func (oplb *outgoingChannelLoadBalancer) Close() error {
 	oplb.cancelFunc()
+
+	oplb.mutQueues.Lock()
+	oplb.closed = true
+	for _, queue := range oplb.queues {
+		queue.dropAll()
+	}
+	oplb.queuesChanged.Broadcast()
+	oplb.mutQueues.Unlock()
+
 	return nil
 }
 
+func (queue *channelQueue) dropAll() {
+	for _, item := range queue.items {
+		item.data.notify(p2p.ErrMessageDropped)
+	}
+	queue.items = nil
+}
+
+// notify sends the outcome of the sending, if the sender waits for it
+func (data *SendableData) notify(err error) {
+	if data.Done == nil {
+		return
+	}
+
+	select {
+	case data.Done <- err:
+	default:
+	}
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (oplb *outgoingChannelLoadBalancer) IsInterfaceNil() bool {
 	return oplb == nil
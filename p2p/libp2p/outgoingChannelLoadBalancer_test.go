@@ -7,9 +7,11 @@ import (
 	"time"
 
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/config"
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p"
 	"github.com/TerraDharitri/drt-go-chain-communication/testscommon"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var errInvalidType = errors.New("invalid type")
@@ -19,6 +21,12 @@ var errMissingChannel = errors.New("missing channel")
 var errChannelsMismatch = errors.New("channels mismatch")
 var durationWait = time.Second * 2
 
+func createMockArgsOutgoingChannelLoadBalancer() libp2p.ArgsOutgoingChannelLoadBalancer {
+	return libp2p.ArgsOutgoingChannelLoadBalancer{
+		Logger: &testscommon.LoggerStub{},
+	}
+}
+
 func checkIntegrity(oclbInstance libp2p.ChannelLoadBalancer, name string) error {
 	type x interface {
 		Chans() []chan *libp2p.SendableData
@@ -64,21 +72,59 @@ func TestNewOutgoingChannelLoadBalancer(t *testing.T) {
 	t.Run("nil logger should error", func(t *testing.T) {
 		t.Parallel()
 
-		oclb, err := libp2p.NewOutgoingChannelLoadBalancer(nil)
+		args := createMockArgsOutgoingChannelLoadBalancer()
+		args.Logger = nil
+		oclb, err := libp2p.NewOutgoingChannelLoadBalancer(args)
 		assert.Equal(t, p2p.ErrNilLogger, err)
 		assert.Nil(t, oclb)
 	})
+	t.Run("invalid config should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsOutgoingChannelLoadBalancer()
+		args.Config.DefaultQueueSize = -1
+		oclb, err := libp2p.NewOutgoingChannelLoadBalancer(args)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidValue))
+		assert.Nil(t, oclb)
+
+		args = createMockArgsOutgoingChannelLoadBalancer()
+		args.Config.DefaultDropPolicy = "drop-all"
+		oclb, err = libp2p.NewOutgoingChannelLoadBalancer(args)
+		assert.True(t, errors.Is(err, p2p.ErrUnknownDropPolicy))
+		assert.Nil(t, oclb)
+
+		args = createMockArgsOutgoingChannelLoadBalancer()
+		args.Config.Channels = []config.OutgoingChannelConfig{{Name: ""}}
+		oclb, err = libp2p.NewOutgoingChannelLoadBalancer(args)
+		assert.True(t, errors.Is(err, p2p.ErrEmptyChannelName))
+		assert.Nil(t, oclb)
+
+		args.Config.Channels = []config.OutgoingChannelConfig{{Name: "test", QueueSize: -1}}
+		oclb, err = libp2p.NewOutgoingChannelLoadBalancer(args)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidValue))
+		assert.Nil(t, oclb)
+
+		args.Config.Channels = []config.OutgoingChannelConfig{{Name: "test", DropPolicy: "drop-all"}}
+		oclb, err = libp2p.NewOutgoingChannelLoadBalancer(args)
+		assert.True(t, errors.Is(err, p2p.ErrUnknownDropPolicy))
+		assert.Nil(t, oclb)
+
+		args.Config.Channels = []config.OutgoingChannelConfig{{Name: "test"}, {Name: "test"}}
+		oclb, err = libp2p.NewOutgoingChannelLoadBalancer(args)
+		assert.True(t, errors.Is(err, p2p.ErrDuplicatedChannel))
+		assert.Nil(t, oclb)
+	})
 	t.Run("should work", func(t *testing.T) {
 		t.Parallel()
 
-		oclb, err := libp2p.NewOutgoingChannelLoadBalancer(&testscommon.LoggerStub{})
+		oclb, err := libp2p.NewOutgoingChannelLoadBalancer(createMockArgsOutgoingChannelLoadBalancer())
 		assert.Nil(t, err)
 		assert.NotNil(t, oclb)
 	})
 	t.Run("should work and add default channel", func(t *testing.T) {
 		t.Parallel()
 
-		oclb, err := libp2p.NewOutgoingChannelLoadBalancer(&testscommon.LoggerStub{})
+		oclb, err := libp2p.NewOutgoingChannelLoadBalancer(createMockArgsOutgoingChannelLoadBalancer())
 		assert.Nil(t, err)
 		assert.NotNil(t, oclb)
 
@@ -92,7 +138,7 @@ func TestNewOutgoingChannelLoadBalancer(t *testing.T) {
 func TestOutgoingChannelLoadBalancer_AddChannelNewChannelShouldNotErrAndAddNewChannel(t *testing.T) {
 	t.Parallel()
 
-	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(&testscommon.LoggerStub{})
+	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(createMockArgsOutgoingChannelLoadBalancer())
 
 	err := oclb.AddChannel("test")
 
@@ -105,7 +151,7 @@ func TestOutgoingChannelLoadBalancer_AddChannelNewChannelShouldNotErrAndAddNewCh
 func TestOutgoingChannelLoadBalancer_AddChannelDefaultChannelShouldErr(t *testing.T) {
 	t.Parallel()
 
-	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(&testscommon.LoggerStub{})
+	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(createMockArgsOutgoingChannelLoadBalancer())
 
 	err := oclb.AddChannel(libp2p.DefaultSendChannel())
 
@@ -115,7 +161,7 @@ func TestOutgoingChannelLoadBalancer_AddChannelDefaultChannelShouldErr(t *testin
 func TestOutgoingChannelLoadBalancer_AddChannelReAddChannelShouldDoNothing(t *testing.T) {
 	t.Parallel()
 
-	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(&testscommon.LoggerStub{})
+	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(createMockArgsOutgoingChannelLoadBalancer())
 
 	_ = oclb.AddChannel("test")
 	err := oclb.AddChannel("test")
@@ -129,7 +175,7 @@ func TestOutgoingChannelLoadBalancer_AddChannelReAddChannelShouldDoNothing(t *te
 func TestOutgoingChannelLoadBalancer_RemoveChannelRemoveDefaultShouldErr(t *testing.T) {
 	t.Parallel()
 
-	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(&testscommon.LoggerStub{})
+	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(createMockArgsOutgoingChannelLoadBalancer())
 
 	err := oclb.RemoveChannel(libp2p.DefaultSendChannel())
 
@@ -139,7 +185,7 @@ func TestOutgoingChannelLoadBalancer_RemoveChannelRemoveDefaultShouldErr(t *test
 func TestOutgoingChannelLoadBalancer_RemoveChannelRemoveNotFoundChannelShouldErr(t *testing.T) {
 	t.Parallel()
 
-	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(&testscommon.LoggerStub{})
+	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(createMockArgsOutgoingChannelLoadBalancer())
 
 	err := oclb.RemoveChannel("test")
 
@@ -149,7 +195,7 @@ func TestOutgoingChannelLoadBalancer_RemoveChannelRemoveNotFoundChannelShouldErr
 func TestOutgoingChannelLoadBalancer_RemoveChannelRemoveLastChannelAddedShouldWork(t *testing.T) {
 	t.Parallel()
 
-	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(&testscommon.LoggerStub{})
+	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(createMockArgsOutgoingChannelLoadBalancer())
 
 	_ = oclb.AddChannel("test1")
 	_ = oclb.AddChannel("test2")
@@ -169,7 +215,7 @@ func TestOutgoingChannelLoadBalancer_RemoveChannelRemoveLastChannelAddedShouldWo
 func TestOutgoingChannelLoadBalancer_RemoveChannelRemoveFirstChannelAddedShouldWork(t *testing.T) {
 	t.Parallel()
 
-	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(&testscommon.LoggerStub{})
+	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(createMockArgsOutgoingChannelLoadBalancer())
 
 	_ = oclb.AddChannel("test1")
 	_ = oclb.AddChannel("test2")
@@ -189,7 +235,7 @@ func TestOutgoingChannelLoadBalancer_RemoveChannelRemoveFirstChannelAddedShouldW
 func TestOutgoingChannelLoadBalancer_RemoveChannelRemoveMiddleChannelAddedShouldWork(t *testing.T) {
 	t.Parallel()
 
-	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(&testscommon.LoggerStub{})
+	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(createMockArgsOutgoingChannelLoadBalancer())
 
 	_ = oclb.AddChannel("test1")
 	_ = oclb.AddChannel("test2")
@@ -211,7 +257,7 @@ func TestOutgoingChannelLoadBalancer_RemoveChannelRemoveMiddleChannelAddedShould
 func TestOutgoingChannelLoadBalancer_GetChannelOrDefaultNotFoundShouldReturnDefault(t *testing.T) {
 	t.Parallel()
 
-	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(&testscommon.LoggerStub{})
+	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(createMockArgsOutgoingChannelLoadBalancer())
 
 	_ = oclb.AddChannel("test1")
 
@@ -223,7 +269,7 @@ func TestOutgoingChannelLoadBalancer_GetChannelOrDefaultNotFoundShouldReturnDefa
 func TestOutgoingChannelLoadBalancer_GetChannelOrDefaultFoundShouldReturnChannel(t *testing.T) {
 	t.Parallel()
 
-	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(&testscommon.LoggerStub{})
+	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(createMockArgsOutgoingChannelLoadBalancer())
 
 	_ = oclb.AddChannel("test1")
 
@@ -237,7 +283,7 @@ func TestOutgoingChannelLoadBalancer_GetChannelOrDefaultFoundShouldReturnChannel
 func TestOutgoingChannelLoadBalancer_CollectFromChannelsNoObjectsShouldWaitBlocking(t *testing.T) {
 	t.Parallel()
 
-	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(&testscommon.LoggerStub{})
+	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(createMockArgsOutgoingChannelLoadBalancer())
 
 	chanDone := make(chan struct{})
 
@@ -257,7 +303,7 @@ func TestOutgoingChannelLoadBalancer_CollectFromChannelsNoObjectsShouldWaitBlock
 func TestOutgoingChannelLoadBalancer_CollectOneElementFromChannelsShouldWork(t *testing.T) {
 	t.Parallel()
 
-	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(&testscommon.LoggerStub{})
+	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(createMockArgsOutgoingChannelLoadBalancer())
 
 	_ = oclb.AddChannel("test")
 
@@ -322,3 +368,261 @@ func TestOutgoingChannelLoadBalancer_CollectOneElementFromChannelsShouldWork(t *
 		return
 	}
 }
+
+func sendOnChannelAndWaitQueued(t *testing.T, oclb libp2p.ChannelLoadBalancer, channel string, objs ...*libp2p.SendableData) {
+	initialDepth := oclb.GetChannelsMetrics()[channel].QueueDepth
+	for _, obj := range objs {
+		oclb.GetChannelOrDefault(channel) <- obj
+	}
+
+	require.Eventually(t, func() bool {
+		return oclb.GetChannelsMetrics()[channel].QueueDepth == initialDepth+len(objs)
+	}, durationWait, time.Millisecond)
+}
+
+func TestOutgoingChannelLoadBalancer_CollectOneElementFromChannelsShouldRespectPriorities(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsOutgoingChannelLoadBalancer()
+	args.Config.Channels = []config.OutgoingChannelConfig{
+		{Name: "consensus", Priority: 10},
+		{Name: "transactions", Priority: 1},
+	}
+	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(args)
+	defer func() {
+		_ = oclb.Close()
+	}()
+	_ = oclb.AddChannel("consensus")
+	_ = oclb.AddChannel("transactions")
+
+	txObj1 := &libp2p.SendableData{Topic: "transactions"}
+	txObj2 := &libp2p.SendableData{Topic: "transactions"}
+	defaultObj := &libp2p.SendableData{Topic: "default"}
+	consensusObj := &libp2p.SendableData{Topic: "consensus"}
+	sendOnChannelAndWaitQueued(t, oclb, libp2p.DefaultSendChannel(), defaultObj)
+	sendOnChannelAndWaitQueued(t, oclb, "transactions", txObj1, txObj2)
+	sendOnChannelAndWaitQueued(t, oclb, "consensus", consensusObj)
+
+	assert.True(t, consensusObj == oclb.CollectOneElementFromChannels())
+	assert.True(t, txObj1 == oclb.CollectOneElementFromChannels())
+	assert.True(t, txObj2 == oclb.CollectOneElementFromChannels())
+	assert.True(t, defaultObj == oclb.CollectOneElementFromChannels())
+}
+
+func TestOutgoingChannelLoadBalancer_CollectOneElementFromChannelsShouldRespectWeights(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsOutgoingChannelLoadBalancer()
+	args.Config.Channels = []config.OutgoingChannelConfig{
+		{Name: "heavy", Weight: 3},
+		{Name: "light", Weight: 1},
+	}
+	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(args)
+	defer func() {
+		_ = oclb.Close()
+	}()
+	_ = oclb.AddChannel("heavy")
+	_ = oclb.AddChannel("light")
+
+	numMessages := 8
+	for i := 0; i < numMessages; i++ {
+		sendOnChannelAndWaitQueued(t, oclb, "heavy", &libp2p.SendableData{Topic: "heavy"})
+		sendOnChannelAndWaitQueued(t, oclb, "light", &libp2p.SendableData{Topic: "light"})
+	}
+
+	numCollected := make(map[string]int)
+	for i := 0; i < numMessages; i++ {
+		numCollected[oclb.CollectOneElementFromChannels().Topic]++
+	}
+
+	assert.Equal(t, 6, numCollected["heavy"])
+	assert.Equal(t, 2, numCollected["light"])
+}
+
+func TestOutgoingChannelLoadBalancer_DropPolicies(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsOutgoingChannelLoadBalancer()
+	args.Config.Channels = []config.OutgoingChannelConfig{
+		{Name: "newest", QueueSize: 2, DropPolicy: p2p.DropNewestPolicy},
+		{Name: "oldest", QueueSize: 2, DropPolicy: p2p.DropOldestPolicy, Priority: 1},
+		{Name: "block", QueueSize: 1, Priority: 2},
+	}
+	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(args)
+	defer func() {
+		_ = oclb.Close()
+	}()
+	_ = oclb.AddChannel("newest")
+	_ = oclb.AddChannel("oldest")
+	_ = oclb.AddChannel("block")
+
+	objs := make([]*libp2p.SendableData, 0)
+	for i := 0; i < 3; i++ {
+		objs = append(objs, &libp2p.SendableData{Buff: []byte{byte(i)}})
+	}
+	sendOnChannelAndWaitQueued(t, oclb, "newest", objs[0], objs[1])
+	oclb.GetChannelOrDefault("newest") <- objs[2]
+	sendOnChannelAndWaitQueued(t, oclb, "oldest", objs[0], objs[1])
+	oclb.GetChannelOrDefault("oldest") <- objs[2]
+	sendOnChannelAndWaitQueued(t, oclb, "block", objs[0])
+
+	chBlockedSend := make(chan struct{})
+	go func() {
+		oclb.GetChannelOrDefault("block") <- objs[1]
+		oclb.GetChannelOrDefault("block") <- objs[2]
+		close(chBlockedSend)
+	}()
+	select {
+	case <-chBlockedSend:
+		assert.Fail(t, "send on a full blocking queue should have blocked")
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	waitBlockQueueRefilled := func() {
+		require.Eventually(t, func() bool {
+			return oclb.GetChannelsMetrics()["block"].QueueDepth == 1
+		}, durationWait, time.Millisecond)
+	}
+	assert.True(t, objs[0] == oclb.CollectOneElementFromChannels())
+	waitBlockQueueRefilled()
+	assert.True(t, objs[1] == oclb.CollectOneElementFromChannels())
+	<-chBlockedSend
+	waitBlockQueueRefilled()
+	assert.True(t, objs[2] == oclb.CollectOneElementFromChannels())
+
+	require.Eventually(t, func() bool {
+		return oclb.GetChannelsMetrics()["oldest"].NumDropped == 1
+	}, durationWait, time.Millisecond)
+	assert.True(t, objs[1] == oclb.CollectOneElementFromChannels())
+	assert.True(t, objs[2] == oclb.CollectOneElementFromChannels())
+
+	require.Eventually(t, func() bool {
+		return oclb.GetChannelsMetrics()["newest"].NumDropped == 1
+	}, durationWait, time.Millisecond)
+	assert.True(t, objs[0] == oclb.CollectOneElementFromChannels())
+	assert.True(t, objs[1] == oclb.CollectOneElementFromChannels())
+
+	channelsMetrics := oclb.GetChannelsMetrics()
+	assert.Equal(t, 4, len(channelsMetrics))
+	for _, name := range []string{"newest", "oldest", "block"} {
+		assert.Equal(t, 0, channelsMetrics[name].QueueDepth)
+		assert.True(t, channelsMetrics[name].AverageWaitTime > 0)
+	}
+	assert.Equal(t, uint64(3), channelsMetrics["block"].NumSent)
+	assert.Equal(t, uint64(0), channelsMetrics["block"].NumDropped)
+}
+
+func TestOutgoingChannelLoadBalancer_CloseShouldUnblockCollect(t *testing.T) {
+	t.Parallel()
+
+	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(createMockArgsOutgoingChannelLoadBalancer())
+
+	chDone := make(chan *libp2p.SendableData)
+	go func() {
+		chDone <- oclb.CollectOneElementFromChannels()
+	}()
+
+	time.Sleep(time.Millisecond * 10)
+	_ = oclb.Close()
+
+	select {
+	case obj := <-chDone:
+		assert.Nil(t, obj)
+	case <-time.After(durationWait):
+		assert.Fail(t, "timeout")
+	}
+}
+
+func TestOutgoingChannelLoadBalancer_RemoveChannelShouldDropQueuedMessages(t *testing.T) {
+	t.Parallel()
+
+	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(createMockArgsOutgoingChannelLoadBalancer())
+	defer func() {
+		_ = oclb.Close()
+	}()
+
+	_ = oclb.AddChannel("test")
+	sendOnChannelAndWaitQueued(t, oclb, "test", &libp2p.SendableData{Topic: "test"})
+
+	err := oclb.RemoveChannel("test")
+	assert.Nil(t, err)
+
+	_, found := oclb.GetChannelsMetrics()["test"]
+	assert.False(t, found)
+
+	defaultObj := &libp2p.SendableData{Topic: "default"}
+	sendOnChannelAndWaitQueued(t, oclb, libp2p.DefaultSendChannel(), defaultObj)
+	assert.True(t, defaultObj == oclb.CollectOneElementFromChannels())
+}
+
+func TestOutgoingChannelLoadBalancer_RemoveChannelShouldCloseTheDoneSignal(t *testing.T) {
+	t.Parallel()
+
+	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(createMockArgsOutgoingChannelLoadBalancer())
+	_ = oclb.AddChannel("test")
+	ch, done := oclb.GetChannelWithDoneOrDefault("test")
+	_, defaultDone := oclb.GetChannelWithDoneOrDefault(libp2p.DefaultSendChannel())
+
+	err := oclb.RemoveChannel("test")
+	assert.Nil(t, err)
+
+	// the removed channel is not closed, the senders are released by the done signal
+	select {
+	case ch <- &libp2p.SendableData{Topic: "test"}:
+		assert.Fail(t, "should not have been accepted")
+	case <-done:
+	case <-time.After(durationWait):
+		assert.Fail(t, "timeout")
+	}
+	select {
+	case <-defaultDone:
+		assert.Fail(t, "default channel should not be done")
+	default:
+	}
+
+	_ = oclb.Close()
+	select {
+	case <-defaultDone:
+	case <-time.After(durationWait):
+		assert.Fail(t, "timeout")
+	}
+}
+
+func TestOutgoingChannelLoadBalancer_DroppedMessagesShouldBeNotified(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsOutgoingChannelLoadBalancer()
+	args.Config.Channels = []config.OutgoingChannelConfig{
+		{Name: "newest", QueueSize: 1, DropPolicy: p2p.DropNewestPolicy},
+		{Name: "oldest", QueueSize: 1, DropPolicy: p2p.DropOldestPolicy},
+	}
+	oclb, _ := libp2p.NewOutgoingChannelLoadBalancer(args)
+	_ = oclb.AddChannel("newest")
+	_ = oclb.AddChannel("oldest")
+
+	createObj := func() *libp2p.SendableData {
+		return &libp2p.SendableData{Done: make(chan error, 1)}
+	}
+	waitNotification := func(obj *libp2p.SendableData) error {
+		select {
+		case err := <-obj.Done:
+			return err
+		case <-time.After(durationWait):
+			return errors.New("timeout")
+		}
+	}
+
+	newestKept, newestDropped := createObj(), createObj()
+	sendOnChannelAndWaitQueued(t, oclb, "newest", newestKept)
+	oclb.GetChannelOrDefault("newest") <- newestDropped
+	assert.True(t, errors.Is(waitNotification(newestDropped), p2p.ErrMessageDropped))
+
+	oldestDropped, oldestKept := createObj(), createObj()
+	sendOnChannelAndWaitQueued(t, oclb, "oldest", oldestDropped)
+	oclb.GetChannelOrDefault("oldest") <- oldestKept
+	assert.True(t, errors.Is(waitNotification(oldestDropped), p2p.ErrMessageDropped))
+
+	_ = oclb.Close()
+	assert.True(t, errors.Is(waitNotification(newestKept), p2p.ErrMessageDropped))
+	assert.True(t, errors.Is(waitNotification(oldestKept), p2p.ErrMessageDropped))
+}
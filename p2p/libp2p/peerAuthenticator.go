@@ -0,0 +1,414 @@
+package libp2p
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/config"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/message"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+	"github.com/TerraDharitri/drt-go-chain-core/core/check"
+	ggio "github.com/gogo/protobuf/io"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+)
+
+const defaultPeerAuthenticationTimeout = time.Second * 10
+const defaultPeerAuthenticationMaxTimeDifference = time.Minute
+const maxPeerAuthenticationSize = 1 << 12
+
+// ArgsPeerAuthenticator is the DTO struct used to create a new instance of peer authenticator
+type ArgsPeerAuthenticator struct {
+	Host       ConnectableHost
+	Signer     p2p.SignerVerifier
+	Marshaller p2p.Marshaller
+	SyncTimer  p2p.SyncTimer
+	PeersInfo  PeersInfoHandler
+	Config     config.PeerAuthenticationConfig
+	Logger     p2p.Logger
+}
+
+// peerAuthenticator starts the authentication protocol on each new outbound connection and answers the
+// authentications started by the remote peers. The inbound connections are authenticated by this node only if the
+// remote peers did not start the authentication in time. The verified statements are stored in the peers info handler
+type peerAuthenticator struct {
+	ctx               context.Context
+	cancelFunc        context.CancelFunc
+	host              ConnectableHost
+	signer            p2p.SignerVerifier
+	marshaller        p2p.Marshaller
+	syncTimer         p2p.SyncTimer
+	peersInfo         PeersInfoHandler
+	timeout           time.Duration
+	maxTimeDifference time.Duration
+	log               p2p.Logger
+
+	mutSettings         sync.RWMutex
+	selfInfo            *p2p.PeerAuthenticationInfo
+	attestationVerifier p2p.AttestationVerifier
+
+	mutPeers           sync.RWMutex
+	peersWithStatement map[core.PeerID]struct{}
+}
+
+// NewPeerAuthenticator creates a new peer authenticator and registers it on the host
+func NewPeerAuthenticator(args ArgsPeerAuthenticator) (*peerAuthenticator, error) {
+	err := checkArgsPeerAuthenticator(args)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pa := &peerAuthenticator{
+		ctx:                ctx,
+		cancelFunc:         cancel,
+		host:               args.Host,
+		signer:             args.Signer,
+		marshaller:         args.Marshaller,
+		syncTimer:          args.SyncTimer,
+		peersInfo:          args.PeersInfo,
+		timeout:            time.Duration(args.Config.TimeoutInSec) * time.Second,
+		maxTimeDifference:  time.Duration(args.Config.MaxTimeDifferenceInSec) * time.Second,
+		log:                args.Logger,
+		peersWithStatement: make(map[core.PeerID]struct{}),
+	}
+	if pa.timeout == 0 {
+		pa.timeout = defaultPeerAuthenticationTimeout
+	}
+	if pa.maxTimeDifference == 0 {
+		pa.maxTimeDifference = defaultPeerAuthenticationMaxTimeDifference
+	}
+
+	pa.host.SetStreamHandler(PeerAuthenticationID, pa.handleStream)
+	pa.host.Network().Notify(pa)
+
+	return pa, nil
+}
+
+func checkArgsPeerAuthenticator(args ArgsPeerAuthenticator) error {
+	if check.IfNil(args.Host) {
+		return p2p.ErrNilHost
+	}
+	if check.IfNil(args.Signer) {
+		return p2p.ErrNilP2PSigner
+	}
+	if check.IfNil(args.Marshaller) {
+		return p2p.ErrNilMarshaller
+	}
+	if check.IfNil(args.SyncTimer) {
+		return p2p.ErrNilSyncTimer
+	}
+	if check.IfNil(args.PeersInfo) {
+		return p2p.ErrNilPeersInfoHandler
+	}
+	if check.IfNil(args.Logger) {
+		return p2p.ErrNilLogger
+	}
+
+	return nil
+}
+
+// SetPeerAuthenticationInfo sets the data this node states about itself. Until it is set, the node does not
+// authenticate to its peers, so the authentication is run again with the already connected peers when the info is
+// first set
+func (pa *peerAuthenticator) SetPeerAuthenticationInfo(info p2p.PeerAuthenticationInfo) error {
+	switch info.PeerType {
+	case core.ValidatorPeer:
+		if len(info.Pk) == 0 || len(info.SignatureOnPid) == 0 {
+			return fmt.Errorf("%w, a validator should provide the attested public key", p2p.ErrInvalidPeerAuthentication)
+		}
+	case core.ObserverPeer:
+	default:
+		return fmt.Errorf("%w, unsupported peer type %s", p2p.ErrInvalidPeerAuthentication, info.PeerType)
+	}
+
+	pa.mutSettings.Lock()
+	isFirstInfo := pa.selfInfo == nil
+	pa.selfInfo = &info
+	pa.mutSettings.Unlock()
+
+	if isFirstInfo {
+		for _, pid := range pa.host.Network().Peers() {
+			go pa.authenticate(pid)
+		}
+	}
+
+	return nil
+}
+
+// SetAttestationVerifier sets the component used to verify the public keys stated by the validators. Until it is
+// set, the validators are considered observers
+func (pa *peerAuthenticator) SetAttestationVerifier(verifier p2p.AttestationVerifier) error {
+	if check.IfNil(verifier) {
+		return p2p.ErrNilAttestationVerifier
+	}
+
+	pa.mutSettings.Lock()
+	pa.attestationVerifier = verifier
+	pa.mutSettings.Unlock()
+
+	return nil
+}
+
+// Listen is called when network starts listening on an addr
+func (pa *peerAuthenticator) Listen(network.Network, multiaddr.Multiaddr) {}
+
+// ListenClose is called when network stops listening on an addr
+func (pa *peerAuthenticator) ListenClose(network.Network, multiaddr.Multiaddr) {}
+
+// Connected starts the authentication on the outbound connections. The inbound ones are authenticated by the remote
+// peers, this node starting the authentication only if no statement was received from the remote peer in time
+func (pa *peerAuthenticator) Connected(_ network.Network, conn network.Conn) {
+	if conn.Stat().Direction == network.DirOutbound {
+		go pa.authenticate(conn.RemotePeer())
+		return
+	}
+
+	go pa.authenticateIfNotStarted(conn.RemotePeer())
+}
+
+// Disconnected removes the information of the peers this node is no longer connected to
+func (pa *peerAuthenticator) Disconnected(netw network.Network, conn network.Conn) {
+	pid := conn.RemotePeer()
+	if netw.Connectedness(pid) == network.Connected {
+		return
+	}
+
+	pa.mutPeers.Lock()
+	delete(pa.peersWithStatement, core.PeerID(pid))
+	pa.mutPeers.Unlock()
+
+	pa.peersInfo.RemovePeer(core.PeerID(pid))
+}
+
+// authenticateIfNotStarted waits for the remote peer to start the authentication, which it might not do, for
+// example when its own authentication info is not set yet
+func (pa *peerAuthenticator) authenticateIfNotStarted(pid peer.ID) {
+	timer := time.NewTimer(pa.timeout)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-pa.ctx.Done():
+		return
+	}
+
+	pa.mutPeers.RLock()
+	_, hasStatement := pa.peersWithStatement[core.PeerID(pid)]
+	pa.mutPeers.RUnlock()
+	if hasStatement || pa.host.Network().Connectedness(pid) != network.Connected {
+		return
+	}
+
+	pa.authenticate(pid)
+}
+
+func (pa *peerAuthenticator) authenticate(pid peer.ID) {
+	statement, err := pa.createStatement()
+	if err != nil {
+		pa.log.Trace("peer authentication not started", "pid", pid.Pretty(), "error", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(pa.ctx, pa.timeout)
+	defer cancel()
+
+	stream, err := pa.host.NewStream(ctx, pid, PeerAuthenticationID)
+	if err != nil {
+		return
+	}
+	_ = stream.SetDeadline(time.Now().Add(pa.timeout))
+
+	err = ggio.NewDelimitedWriter(stream).WriteMsg(statement)
+	if err != nil {
+		_ = stream.Reset()
+		pa.log.Trace("cannot send the peer authentication", "pid", pid.Pretty(), "error", err)
+		return
+	}
+
+	remoteStatement := &message.PeerAuthentication{}
+	err = ggio.NewDelimitedReader(stream, maxPeerAuthenticationSize).ReadMsg(remoteStatement)
+	if err != nil {
+		_ = stream.Reset()
+		pa.log.Trace("cannot read the peer authentication", "pid", pid.Pretty(), "error", err)
+		return
+	}
+	_ = stream.Close()
+
+	pa.processStatement(remoteStatement, core.PeerID(pid))
+}
+
+func (pa *peerAuthenticator) handleStream(stream network.Stream) {
+	_ = stream.SetDeadline(time.Now().Add(pa.timeout))
+	pid := core.PeerID(stream.Conn().RemotePeer())
+
+	remoteStatement := &message.PeerAuthentication{}
+	err := ggio.NewDelimitedReader(stream, maxPeerAuthenticationSize).ReadMsg(remoteStatement)
+	if err != nil {
+		_ = stream.Reset()
+		pa.log.Trace("cannot read the peer authentication", "pid", pid.Pretty(), "error", err)
+		return
+	}
+
+	pa.processStatement(remoteStatement, pid)
+
+	statement, err := pa.createStatement()
+	if err != nil {
+		_ = stream.Reset()
+		pa.log.Trace("cannot answer the peer authentication", "pid", pid.Pretty(), "error", err)
+		return
+	}
+
+	err = ggio.NewDelimitedWriter(stream).WriteMsg(statement)
+	if err != nil {
+		_ = stream.Reset()
+		pa.log.Trace("cannot send the peer authentication", "pid", pid.Pretty(), "error", err)
+		return
+	}
+	_ = stream.Close()
+}
+
+func (pa *peerAuthenticator) createStatement() (*message.PeerAuthentication, error) {
+	pa.mutSettings.RLock()
+	selfInfo := pa.selfInfo
+	pa.mutSettings.RUnlock()
+
+	if selfInfo == nil {
+		return nil, p2p.ErrPeerAuthenticationInfoNotSet
+	}
+
+	payload := &message.PeerAuthenticationPayload{
+		Pid: []byte(pa.host.ID()),
+		PeerShard: &message.PeerShard{
+			ShardId: strconv.FormatUint(uint64(selfInfo.ShardID), 10),
+		},
+		PeerType:       uint32(selfInfo.PeerType),
+		PeerSubType:    uint32(selfInfo.PeerSubType),
+		NodeVersion:    selfInfo.NodeVersion,
+		Timestamp:      pa.syncTimer.CurrentTime().Unix(),
+		Pk:             selfInfo.Pk,
+		SignatureOnPid: selfInfo.SignatureOnPid,
+	}
+	payloadBytes, err := pa.marshaller.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := pa.signer.Sign(payloadBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &message.PeerAuthentication{
+		Payload:   payloadBytes,
+		Signature: signature,
+	}, nil
+}
+
+func (pa *peerAuthenticator) processStatement(statement *message.PeerAuthentication, pid core.PeerID) {
+	pa.mutPeers.Lock()
+	pa.peersWithStatement[pid] = struct{}{}
+	pa.mutPeers.Unlock()
+
+	peerInfo, nodeVersion, err := pa.verifyStatement(statement, pid)
+	if err != nil {
+		return
+	}
+
+	pa.peersInfo.UpdatePeerInfo(pid, peerInfo, nodeVersion)
+	pa.log.Trace("peer authenticated",
+		"pid", pid.Pretty(),
+		"peer type", peerInfo.PeerType.String(),
+		"shard", peerInfo.ShardID,
+		"node version", nodeVersion,
+	)
+}
+
+func (pa *peerAuthenticator) verifyStatement(statement *message.PeerAuthentication, pid core.PeerID) (core.P2PPeerInfo, string, error) {
+	err := pa.signer.Verify(statement.Payload, pid, statement.Signature)
+	if err != nil {
+		return core.P2PPeerInfo{}, "", fmt.Errorf("%w, signature: %s", p2p.ErrInvalidPeerAuthentication, err.Error())
+	}
+
+	payload := &message.PeerAuthenticationPayload{}
+	err = pa.marshaller.Unmarshal(payload, statement.Payload)
+	if err != nil {
+		return core.P2PPeerInfo{}, "", fmt.Errorf("%w, payload: %s", p2p.ErrInvalidPeerAuthentication, err.Error())
+	}
+	if core.PeerID(payload.Pid) != pid {
+		return core.P2PPeerInfo{}, "", fmt.Errorf("%w, the statement was made by another peer", p2p.ErrInvalidPeerAuthentication)
+	}
+
+	timeDifference := pa.syncTimer.CurrentTime().Sub(time.Unix(payload.Timestamp, 0))
+	if timeDifference > pa.maxTimeDifference || timeDifference < -pa.maxTimeDifference {
+		return core.P2PPeerInfo{}, "", fmt.Errorf("%w, timestamp differs by %v", p2p.ErrInvalidPeerAuthentication, timeDifference)
+	}
+
+	if payload.PeerShard == nil {
+		return core.P2PPeerInfo{}, "", fmt.Errorf("%w, missing shard", p2p.ErrInvalidPeerAuthentication)
+	}
+	shardID, err := strconv.ParseUint(payload.PeerShard.ShardId, 10, 32)
+	if err != nil {
+		return core.P2PPeerInfo{}, "", fmt.Errorf("%w, shard: %s", p2p.ErrInvalidPeerAuthentication, err.Error())
+	}
+
+	peerInfo := core.P2PPeerInfo{
+		PeerType:    core.ObserverPeer,
+		PeerSubType: core.P2PPeerSubType(payload.PeerSubType),
+		ShardID:     uint32(shardID),
+	}
+
+	switch core.P2PPeerType(payload.PeerType) {
+	case core.ObserverPeer:
+		return peerInfo, payload.NodeVersion, nil
+	case core.ValidatorPeer:
+	default:
+		return core.P2PPeerInfo{}, "", fmt.Errorf("%w, unsupported peer type %d", p2p.ErrInvalidPeerAuthentication, payload.PeerType)
+	}
+
+	err = pa.verifyAttestation(payload.Pk, pid, payload.SignatureOnPid)
+	if err != nil {
+		// the statement is valid, but without a verified key the peer can not be trusted as a validator
+		pa.log.Debug("unverified validator statement, the peer is considered an observer", "pid", pid.Pretty(), "error", err)
+		return peerInfo, payload.NodeVersion, nil
+	}
+
+	peerInfo.PeerType = core.ValidatorPeer
+	peerInfo.PkBytes = payload.Pk
+
+	return peerInfo, payload.NodeVersion, nil
+}
+
+func (pa *peerAuthenticator) verifyAttestation(pk []byte, pid core.PeerID, signatureOnPid []byte) error {
+	pa.mutSettings.RLock()
+	verifier := pa.attestationVerifier
+	pa.mutSettings.RUnlock()
+
+	if check.IfNil(verifier) {
+		return p2p.ErrNilAttestationVerifier
+	}
+	if len(pk) == 0 || len(signatureOnPid) == 0 {
+		return p2p.ErrInvalidAttestation
+	}
+
+	return verifier.VerifyAttestation(pk, pid, signatureOnPid)
+}
+
+// Close stops the peer authenticator
+func (pa *peerAuthenticator) Close() error {
+	pa.cancelFunc()
+	pa.host.RemoveStreamHandler(PeerAuthenticationID)
+	pa.host.Network().StopNotify(pa)
+
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (pa *peerAuthenticator) IsInterfaceNil() bool {
+	return pa == nil
+}
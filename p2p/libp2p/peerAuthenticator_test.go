@@ -0,0 +1,428 @@
+package libp2p_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/config"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/message"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/mock"
+	"github.com/TerraDharitri/drt-go-chain-communication/testscommon"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+	"github.com/TerraDharitri/drt-go-chain-core/core/check"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const authenticatorPid = core.PeerID("mock pid")
+
+func createMockArgsPeerAuthenticator() libp2p.ArgsPeerAuthenticator {
+	currentTime := time.Now()
+
+	return libp2p.ArgsPeerAuthenticator{
+		Host:       &mock.ConnectableHostStub{},
+		Signer:     &mock.P2PSignerStub{},
+		Marshaller: &testscommon.ProtoMarshallerMock{},
+		SyncTimer: &mock.SyncTimerStub{
+			CurrentTimeCalled: func() time.Time {
+				return currentTime
+			},
+		},
+		PeersInfo: &mock.PeersInfoHandlerStub{},
+		Config:    config.PeerAuthenticationConfig{Enabled: true},
+		Logger:    &testscommon.LoggerStub{},
+	}
+}
+
+func createValidatorAuthenticationInfo() p2p.PeerAuthenticationInfo {
+	return p2p.PeerAuthenticationInfo{
+		PeerType:       core.ValidatorPeer,
+		ShardID:        1,
+		NodeVersion:    "v1.0.0",
+		Pk:             []byte("pk"),
+		SignatureOnPid: []byte("signature on pid"),
+	}
+}
+
+func TestNewPeerAuthenticator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil host should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsPeerAuthenticator()
+		args.Host = nil
+		pa, err := libp2p.NewPeerAuthenticator(args)
+		assert.Equal(t, p2p.ErrNilHost, err)
+		assert.True(t, check.IfNil(pa))
+	})
+	t.Run("nil signer should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsPeerAuthenticator()
+		args.Signer = nil
+		pa, err := libp2p.NewPeerAuthenticator(args)
+		assert.Equal(t, p2p.ErrNilP2PSigner, err)
+		assert.True(t, check.IfNil(pa))
+	})
+	t.Run("nil marshaller should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsPeerAuthenticator()
+		args.Marshaller = nil
+		pa, err := libp2p.NewPeerAuthenticator(args)
+		assert.Equal(t, p2p.ErrNilMarshaller, err)
+		assert.True(t, check.IfNil(pa))
+	})
+	t.Run("nil sync timer should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsPeerAuthenticator()
+		args.SyncTimer = nil
+		pa, err := libp2p.NewPeerAuthenticator(args)
+		assert.Equal(t, p2p.ErrNilSyncTimer, err)
+		assert.True(t, check.IfNil(pa))
+	})
+	t.Run("nil peers info handler should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsPeerAuthenticator()
+		args.PeersInfo = nil
+		pa, err := libp2p.NewPeerAuthenticator(args)
+		assert.Equal(t, p2p.ErrNilPeersInfoHandler, err)
+		assert.True(t, check.IfNil(pa))
+	})
+	t.Run("nil logger should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsPeerAuthenticator()
+		args.Logger = nil
+		pa, err := libp2p.NewPeerAuthenticator(args)
+		assert.Equal(t, p2p.ErrNilLogger, err)
+		assert.True(t, check.IfNil(pa))
+	})
+	t.Run("should work and register on the host", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsPeerAuthenticator()
+		var registeredProtocol protocol.ID
+		var removedProtocol protocol.ID
+		notified := false
+		stopNotified := false
+		args.Host = &mock.ConnectableHostStub{
+			SetStreamHandlerCalled: func(pid protocol.ID, handler network.StreamHandler) {
+				registeredProtocol = pid
+			},
+			RemoveStreamHandlerCalled: func(pid protocol.ID) {
+				removedProtocol = pid
+			},
+			NetworkCalled: func() network.Network {
+				return &mock.NetworkStub{
+					NotifyCalled: func(notifiee network.Notifiee) {
+						notified = true
+					},
+					StopNotifyCalled: func(notifiee network.Notifiee) {
+						stopNotified = true
+					},
+				}
+			},
+		}
+		pa, err := libp2p.NewPeerAuthenticator(args)
+		assert.Nil(t, err)
+		assert.False(t, check.IfNil(pa))
+		assert.Equal(t, libp2p.PeerAuthenticationID, registeredProtocol)
+		assert.True(t, notified)
+
+		assert.Nil(t, pa.Close())
+		assert.Equal(t, libp2p.PeerAuthenticationID, removedProtocol)
+		assert.True(t, stopNotified)
+	})
+}
+
+func TestPeerAuthenticator_SetPeerAuthenticationInfo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("validator without attested key should error", func(t *testing.T) {
+		t.Parallel()
+
+		pa, _ := libp2p.NewPeerAuthenticator(createMockArgsPeerAuthenticator())
+		info := createValidatorAuthenticationInfo()
+		info.SignatureOnPid = nil
+		err := pa.SetPeerAuthenticationInfo(info)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidPeerAuthentication))
+	})
+	t.Run("unknown peer type should error", func(t *testing.T) {
+		t.Parallel()
+
+		pa, _ := libp2p.NewPeerAuthenticator(createMockArgsPeerAuthenticator())
+		err := pa.SetPeerAuthenticationInfo(p2p.PeerAuthenticationInfo{PeerType: core.UnknownPeer})
+		assert.True(t, errors.Is(err, p2p.ErrInvalidPeerAuthentication))
+	})
+	t.Run("not set should not create statements", func(t *testing.T) {
+		t.Parallel()
+
+		pa, _ := libp2p.NewPeerAuthenticator(createMockArgsPeerAuthenticator())
+		statement, err := pa.CreateStatement()
+		assert.Nil(t, statement)
+		assert.Equal(t, p2p.ErrPeerAuthenticationInfoNotSet, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		pa, _ := libp2p.NewPeerAuthenticator(createMockArgsPeerAuthenticator())
+		err := pa.SetPeerAuthenticationInfo(p2p.PeerAuthenticationInfo{PeerType: core.ObserverPeer})
+		assert.Nil(t, err)
+
+		statement, err := pa.CreateStatement()
+		assert.Nil(t, err)
+		assert.NotNil(t, statement)
+	})
+}
+
+func TestPeerAuthenticator_SetAttestationVerifierNilShouldErr(t *testing.T) {
+	t.Parallel()
+
+	pa, _ := libp2p.NewPeerAuthenticator(createMockArgsPeerAuthenticator())
+	err := pa.SetAttestationVerifier(nil)
+	assert.Equal(t, p2p.ErrNilAttestationVerifier, err)
+}
+
+func TestPeerAuthenticator_ProcessStatement(t *testing.T) {
+	t.Parallel()
+
+	createStatement := func(t *testing.T, args libp2p.ArgsPeerAuthenticator, info p2p.PeerAuthenticationInfo) *message.PeerAuthentication {
+		pa, _ := libp2p.NewPeerAuthenticator(args)
+		err := pa.SetPeerAuthenticationInfo(info)
+		require.Nil(t, err)
+
+		statement, err := pa.CreateStatement()
+		require.Nil(t, err)
+
+		return statement
+	}
+	createReceiver := func(args libp2p.ArgsPeerAuthenticator, updated map[core.PeerID]core.P2PPeerInfo) libp2p.ArgsPeerAuthenticator {
+		args.PeersInfo = &mock.PeersInfoHandlerStub{
+			UpdatePeerInfoCalled: func(pid core.PeerID, peerInfo core.P2PPeerInfo, nodeVersion string) {
+				assert.Equal(t, "v1.0.0", nodeVersion)
+				updated[pid] = peerInfo
+			},
+		}
+
+		return args
+	}
+
+	t.Run("verified validator should be stored as validator", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsPeerAuthenticator()
+		statement := createStatement(t, args, createValidatorAuthenticationInfo())
+
+		updated := make(map[core.PeerID]core.P2PPeerInfo)
+		receiver, _ := libp2p.NewPeerAuthenticator(createReceiver(args, updated))
+		_ = receiver.SetAttestationVerifier(&mock.AttestationVerifierStub{})
+		receiver.ProcessStatement(statement, authenticatorPid)
+
+		expectedPeerInfo := core.P2PPeerInfo{
+			PeerType: core.ValidatorPeer,
+			ShardID:  1,
+			PkBytes:  []byte("pk"),
+		}
+		assert.Equal(t, expectedPeerInfo, updated[authenticatorPid])
+	})
+	t.Run("unverified validator should be stored as observer", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsPeerAuthenticator()
+		statement := createStatement(t, args, createValidatorAuthenticationInfo())
+
+		updated := make(map[core.PeerID]core.P2PPeerInfo)
+		receiver, _ := libp2p.NewPeerAuthenticator(createReceiver(args, updated))
+		_ = receiver.SetAttestationVerifier(&mock.AttestationVerifierStub{
+			VerifyAttestationCalled: func(pk []byte, pid core.PeerID, signatureOnPid []byte) error {
+				return expectedError
+			},
+		})
+		receiver.ProcessStatement(statement, authenticatorPid)
+
+		expectedPeerInfo := core.P2PPeerInfo{
+			PeerType: core.ObserverPeer,
+			ShardID:  1,
+		}
+		assert.Equal(t, expectedPeerInfo, updated[authenticatorPid])
+	})
+	t.Run("validator without attestation verifier should be stored as observer", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsPeerAuthenticator()
+		statement := createStatement(t, args, createValidatorAuthenticationInfo())
+
+		updated := make(map[core.PeerID]core.P2PPeerInfo)
+		receiver, _ := libp2p.NewPeerAuthenticator(createReceiver(args, updated))
+		receiver.ProcessStatement(statement, authenticatorPid)
+
+		assert.Equal(t, core.ObserverPeer, updated[authenticatorPid].PeerType)
+	})
+	t.Run("statement of another peer should be ignored", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsPeerAuthenticator()
+		statement := createStatement(t, args, createValidatorAuthenticationInfo())
+
+		updated := make(map[core.PeerID]core.P2PPeerInfo)
+		receiver, _ := libp2p.NewPeerAuthenticator(createReceiver(args, updated))
+		receiver.ProcessStatement(statement, "other pid")
+
+		assert.Empty(t, updated)
+	})
+	t.Run("invalid signature should be ignored", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsPeerAuthenticator()
+		statement := createStatement(t, args, createValidatorAuthenticationInfo())
+
+		updated := make(map[core.PeerID]core.P2PPeerInfo)
+		receiverArgs := createReceiver(args, updated)
+		receiverArgs.Signer = &mock.P2PSignerStub{
+			VerifyCalled: func(payload []byte, pid core.PeerID, signature []byte) error {
+				return expectedError
+			},
+		}
+		receiver, _ := libp2p.NewPeerAuthenticator(receiverArgs)
+		receiver.ProcessStatement(statement, authenticatorPid)
+
+		assert.Empty(t, updated)
+	})
+	t.Run("old statement should be ignored", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsPeerAuthenticator()
+		statement := createStatement(t, args, createValidatorAuthenticationInfo())
+
+		updated := make(map[core.PeerID]core.P2PPeerInfo)
+		receiverArgs := createReceiver(args, updated)
+		receiverArgs.SyncTimer = &mock.SyncTimerStub{
+			CurrentTimeCalled: func() time.Time {
+				return time.Now().Add(time.Hour)
+			},
+		}
+		receiver, _ := libp2p.NewPeerAuthenticator(receiverArgs)
+		receiver.ProcessStatement(statement, authenticatorPid)
+
+		assert.Empty(t, updated)
+	})
+}
+
+func TestPeerAuthenticator_SetPeerAuthenticationInfoShouldAuthenticateConnectedPeers(t *testing.T) {
+	t.Parallel()
+
+	connectedPeers := []peer.ID{"peer 1", "peer 2"}
+	chAuthenticated := make(chan peer.ID, 10)
+	args := createMockArgsPeerAuthenticator()
+	args.Host = &mock.ConnectableHostStub{
+		NetworkCalled: func() network.Network {
+			return &mock.NetworkStub{
+				PeersCall: func() []peer.ID {
+					return connectedPeers
+				},
+			}
+		},
+		NewStreamCalled: func(ctx context.Context, p peer.ID, pids ...protocol.ID) (network.Stream, error) {
+			chAuthenticated <- p
+			return nil, expectedError
+		},
+	}
+	pa, _ := libp2p.NewPeerAuthenticator(args)
+
+	err := pa.SetPeerAuthenticationInfo(p2p.PeerAuthenticationInfo{PeerType: core.ObserverPeer})
+	require.Nil(t, err)
+
+	authenticated := make([]peer.ID, 0, len(connectedPeers))
+	for range connectedPeers {
+		select {
+		case pid := <-chAuthenticated:
+			authenticated = append(authenticated, pid)
+		case <-time.After(time.Second):
+			require.Fail(t, "timeout while waiting for the authentications")
+		}
+	}
+	assert.ElementsMatch(t, connectedPeers, authenticated)
+
+	// only the first info triggers the authentication of the connected peers
+	err = pa.SetPeerAuthenticationInfo(createValidatorAuthenticationInfo())
+	require.Nil(t, err)
+	time.Sleep(time.Millisecond * 100)
+	assert.Equal(t, 0, len(chAuthenticated))
+}
+
+func TestPeerAuthenticator_ConnectedInbound(t *testing.T) {
+	t.Parallel()
+
+	remotePid := peer.ID(authenticatorPid)
+	createInboundConn := func() *mock.ConnStub {
+		return &mock.ConnStub{
+			RemotePeerCalled: func() peer.ID {
+				return remotePid
+			},
+			StatCalled: func() network.ConnStats {
+				return network.ConnStats{Stats: network.Stats{Direction: network.DirInbound}}
+			},
+		}
+	}
+	createArgs := func(chAuthenticated chan peer.ID) libp2p.ArgsPeerAuthenticator {
+		args := createMockArgsPeerAuthenticator()
+		args.Config.TimeoutInSec = 1
+		args.Host = &mock.ConnectableHostStub{
+			NetworkCalled: func() network.Network {
+				return &mock.NetworkStub{
+					ConnectednessCalled: func(id peer.ID) network.Connectedness {
+						return network.Connected
+					},
+				}
+			},
+			NewStreamCalled: func(ctx context.Context, p peer.ID, pids ...protocol.ID) (network.Stream, error) {
+				chAuthenticated <- p
+				return nil, expectedError
+			},
+		}
+
+		return args
+	}
+
+	t.Run("no statement from the remote peer should start the authentication", func(t *testing.T) {
+		t.Parallel()
+
+		chAuthenticated := make(chan peer.ID, 1)
+		pa, _ := libp2p.NewPeerAuthenticator(createArgs(chAuthenticated))
+		_ = pa.SetPeerAuthenticationInfo(p2p.PeerAuthenticationInfo{PeerType: core.ObserverPeer})
+
+		pa.Connected(nil, createInboundConn())
+		select {
+		case pid := <-chAuthenticated:
+			assert.Equal(t, remotePid, pid)
+		case <-time.After(time.Second * 3):
+			assert.Fail(t, "timeout while waiting for the authentication")
+		}
+	})
+	t.Run("statement from the remote peer should not start the authentication", func(t *testing.T) {
+		t.Parallel()
+
+		chAuthenticated := make(chan peer.ID, 1)
+		args := createArgs(chAuthenticated)
+		pa, _ := libp2p.NewPeerAuthenticator(args)
+		_ = pa.SetPeerAuthenticationInfo(p2p.PeerAuthenticationInfo{PeerType: core.ObserverPeer})
+		statement, _ := pa.CreateStatement()
+
+		pa.Connected(nil, createInboundConn())
+		pa.ProcessStatement(statement, authenticatorPid)
+
+		time.Sleep(time.Second * 2)
+		assert.Equal(t, 0, len(chAuthenticated))
+	})
+}
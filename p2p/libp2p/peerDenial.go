@@ -0,0 +1,31 @@
+package libp2p
+
+import (
+	"time"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+)
+
+const (
+	messagesHandlerDenialSource = "messages handler"
+	directSenderDenialSource    = "direct sender"
+	incompatibleMessageReason   = "incompatible p2p message"
+	unsignedDirectMessageReason = "unsigned direct message"
+)
+
+// upsertDeniedPeer records the reason and the source of the ban if the evaluator is able to store them
+func upsertDeniedPeer(
+	evaluator p2p.PeerDenialEvaluator,
+	pid core.PeerID,
+	duration time.Duration,
+	reason string,
+	source string,
+) error {
+	reasonHandler, ok := evaluator.(p2p.PeerDenialReasonHandler)
+	if ok {
+		return reasonHandler.UpsertPeerIDWithReason(pid, duration, reason, source)
+	}
+
+	return evaluator.UpsertPeerID(pid, duration)
+}
@@ -0,0 +1,170 @@
+package libp2p
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/config"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+const (
+	defaultPeerScoreInspectInterval = 10 * time.Second
+	// gossipsub divides the time spent in mesh by the quantum even if the time in mesh component is disabled
+	defaultTimeInMeshQuantum = time.Second
+)
+
+// peerScoresHolder keeps the last snapshot of the gossipsub peer scores
+type peerScoresHolder struct {
+	mut    sync.RWMutex
+	scores map[core.PeerID]p2p.PeerScore
+}
+
+func newPeerScoresHolder() *peerScoresHolder {
+	return &peerScoresHolder{
+		scores: make(map[core.PeerID]p2p.PeerScore),
+	}
+}
+
+func (holder *peerScoresHolder) inspect(snapshots map[peer.ID]*pubsub.PeerScoreSnapshot) {
+	scores := make(map[core.PeerID]p2p.PeerScore, len(snapshots))
+	for pid, snapshot := range snapshots {
+		topics := make(map[string]p2p.TopicScore, len(snapshot.Topics))
+		for topic, topicSnapshot := range snapshot.Topics {
+			topics[topic] = p2p.TopicScore{
+				TimeInMesh:               topicSnapshot.TimeInMesh,
+				FirstMessageDeliveries:   topicSnapshot.FirstMessageDeliveries,
+				MeshMessageDeliveries:    topicSnapshot.MeshMessageDeliveries,
+				InvalidMessageDeliveries: topicSnapshot.InvalidMessageDeliveries,
+			}
+		}
+
+		scores[core.PeerID(pid)] = p2p.PeerScore{
+			Score:              snapshot.Score,
+			AppSpecificScore:   snapshot.AppSpecificScore,
+			IPColocationFactor: snapshot.IPColocationFactor,
+			BehaviourPenalty:   snapshot.BehaviourPenalty,
+			Topics:             topics,
+		}
+	}
+
+	holder.mut.Lock()
+	holder.scores = scores
+	holder.mut.Unlock()
+}
+
+func (holder *peerScoresHolder) getPeerScores() map[core.PeerID]p2p.PeerScore {
+	holder.mut.RLock()
+	defer holder.mut.RUnlock()
+
+	scores := make(map[core.PeerID]p2p.PeerScore, len(holder.scores))
+	for pid, score := range holder.scores {
+		scores[pid] = score
+	}
+
+	return scores
+}
+
+// createPeerScoreOptions converts the peer scoring config in gossipsub options. The values are validated by gossipsub
+// when the router is created, the unset parameter groups being disabled
+func createPeerScoreOptions(scoringConfig config.PeerScoringConfig, holder *peerScoresHolder) ([]pubsub.Option, error) {
+	if !scoringConfig.Enabled {
+		return make([]pubsub.Option, 0), nil
+	}
+
+	params, err := createPeerScoreParams(scoringConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	thresholds := &pubsub.PeerScoreThresholds{
+		SkipAtomicValidation:        true,
+		GossipThreshold:             scoringConfig.GossipThreshold,
+		PublishThreshold:            scoringConfig.PublishThreshold,
+		GraylistThreshold:           scoringConfig.GraylistThreshold,
+		AcceptPXThreshold:           scoringConfig.AcceptPXThreshold,
+		OpportunisticGraftThreshold: scoringConfig.OpportunisticGraftThreshold,
+	}
+
+	inspectInterval := time.Duration(scoringConfig.InspectIntervalInSec) * time.Second
+	if inspectInterval == 0 {
+		inspectInterval = defaultPeerScoreInspectInterval
+	}
+
+	return []pubsub.Option{
+		pubsub.WithPeerScore(params, thresholds),
+		pubsub.WithPeerScoreInspect(pubsub.ExtendedPeerScoreInspectFn(holder.inspect), inspectInterval),
+	}, nil
+}
+
+func createPeerScoreParams(scoringConfig config.PeerScoringConfig) (*pubsub.PeerScoreParams, error) {
+	whitelist := make([]*net.IPNet, 0, len(scoringConfig.IPColocationFactorWhitelist))
+	for _, cidr := range scoringConfig.IPColocationFactorWhitelist {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("%w, IP colocation factor whitelist entry %s: %s", p2p.ErrInvalidConfig, cidr, err.Error())
+		}
+		whitelist = append(whitelist, ipNet)
+	}
+
+	decayInterval := time.Duration(scoringConfig.DecayIntervalInSec) * time.Second
+	if decayInterval == 0 {
+		decayInterval = pubsub.DefaultDecayInterval
+	}
+	decayToZero := scoringConfig.DecayToZero
+	if decayToZero == 0 {
+		decayToZero = pubsub.DefaultDecayToZero
+	}
+
+	topics := make(map[string]*pubsub.TopicScoreParams, len(scoringConfig.Topics))
+	for _, topicConfig := range scoringConfig.Topics {
+		if len(topicConfig.Topic) == 0 {
+			return nil, fmt.Errorf("%w in peer scoring config", p2p.ErrNilTopic)
+		}
+		_, found := topics[topicConfig.Topic]
+		if found {
+			return nil, fmt.Errorf("%w in peer scoring config: %s", p2p.ErrDuplicatedTopic, topicConfig.Topic)
+		}
+
+		timeInMeshQuantum := time.Duration(topicConfig.TimeInMeshQuantumInSec) * time.Second
+		if timeInMeshQuantum == 0 {
+			timeInMeshQuantum = defaultTimeInMeshQuantum
+		}
+
+		topics[topicConfig.Topic] = &pubsub.TopicScoreParams{
+			SkipAtomicValidation:           true,
+			TopicWeight:                    topicConfig.TopicWeight,
+			TimeInMeshWeight:               topicConfig.TimeInMeshWeight,
+			TimeInMeshQuantum:              timeInMeshQuantum,
+			TimeInMeshCap:                  topicConfig.TimeInMeshCap,
+			FirstMessageDeliveriesWeight:   topicConfig.FirstMessageDeliveriesWeight,
+			FirstMessageDeliveriesDecay:    topicConfig.FirstMessageDeliveriesDecay,
+			FirstMessageDeliveriesCap:      topicConfig.FirstMessageDeliveriesCap,
+			InvalidMessageDeliveriesWeight: topicConfig.InvalidMessageDeliveriesWeight,
+			InvalidMessageDeliveriesDecay:  topicConfig.InvalidMessageDeliveriesDecay,
+		}
+	}
+
+	return &pubsub.PeerScoreParams{
+		SkipAtomicValidation: true,
+		Topics:               topics,
+		TopicScoreCap:        scoringConfig.TopicScoreCap,
+		AppSpecificScore: func(p peer.ID) float64 {
+			return 0
+		},
+		IPColocationFactorWeight:    scoringConfig.IPColocationFactorWeight,
+		IPColocationFactorThreshold: scoringConfig.IPColocationFactorThreshold,
+		IPColocationFactorWhitelist: whitelist,
+		BehaviourPenaltyWeight:      scoringConfig.BehaviourPenaltyWeight,
+		BehaviourPenaltyThreshold:   scoringConfig.BehaviourPenaltyThreshold,
+		BehaviourPenaltyDecay:       scoringConfig.BehaviourPenaltyDecay,
+		DecayInterval:               decayInterval,
+		DecayToZero:                 decayToZero,
+		RetainScore:                 time.Duration(scoringConfig.RetainScoreInSec) * time.Second,
+	}, nil
+}
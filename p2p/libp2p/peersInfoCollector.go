@@ -0,0 +1,106 @@
+package libp2p
+
+import (
+	"sync"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+	"github.com/TerraDharitri/drt-go-chain-core/core/check"
+)
+
+var _ p2p.PeerShardResolver = (*peersInfoCollector)(nil)
+var _ p2p.NetworkShardingCollector = (*peersInfoCollector)(nil)
+
+type collectedPeerInfo struct {
+	peerInfo    core.P2PPeerInfo
+	nodeVersion string
+}
+
+// peersInfoCollector is the built-in PeerShardResolver and NetworkShardingCollector implementation. It holds the
+// information of the authenticated peers and it is used when the peer shard resolver set by the higher layers does
+// not know a peer
+type peersInfoCollector struct {
+	mut               sync.RWMutex
+	peersInfo         map[core.PeerID]collectedPeerInfo
+	peerShardResolver p2p.PeerShardResolver
+}
+
+func newPeersInfoCollector() *peersInfoCollector {
+	return &peersInfoCollector{
+		peersInfo:         make(map[core.PeerID]collectedPeerInfo),
+		peerShardResolver: &unknownPeerShardResolver{},
+	}
+}
+
+// GetPeerInfo returns the peer info known by the higher layers' peer shard resolver, falling back to the
+// information collected for the peer
+func (pic *peersInfoCollector) GetPeerInfo(pid core.PeerID) core.P2PPeerInfo {
+	pic.mut.RLock()
+	defer pic.mut.RUnlock()
+
+	peerInfo := pic.peerShardResolver.GetPeerInfo(pid)
+	if peerInfo.PeerType != core.UnknownPeer {
+		return peerInfo
+	}
+
+	collected, found := pic.peersInfo[pid]
+	if !found {
+		return peerInfo
+	}
+
+	return collected.peerInfo
+}
+
+// GetPeerNodeVersion returns the node version stated by an authenticated peer
+func (pic *peersInfoCollector) GetPeerNodeVersion(pid core.PeerID) string {
+	pic.mut.RLock()
+	defer pic.mut.RUnlock()
+
+	return pic.peersInfo[pid].nodeVersion
+}
+
+// UpdatePeerIDInfo updates the public key and the shard of a peer, keeping its peer type
+func (pic *peersInfoCollector) UpdatePeerIDInfo(pid core.PeerID, pk []byte, shardID uint32) {
+	pic.mut.Lock()
+	defer pic.mut.Unlock()
+
+	collected := pic.peersInfo[pid]
+	collected.peerInfo.PkBytes = pk
+	collected.peerInfo.ShardID = shardID
+	pic.peersInfo[pid] = collected
+}
+
+// UpdatePeerInfo stores the information stated by an authenticated peer
+func (pic *peersInfoCollector) UpdatePeerInfo(pid core.PeerID, peerInfo core.P2PPeerInfo, nodeVersion string) {
+	pic.mut.Lock()
+	pic.peersInfo[pid] = collectedPeerInfo{
+		peerInfo:    peerInfo,
+		nodeVersion: nodeVersion,
+	}
+	pic.mut.Unlock()
+}
+
+// RemovePeer removes the information of a peer
+func (pic *peersInfoCollector) RemovePeer(pid core.PeerID) {
+	pic.mut.Lock()
+	delete(pic.peersInfo, pid)
+	pic.mut.Unlock()
+}
+
+// SetPeerShardResolver sets the peer shard resolver of the higher layers
+func (pic *peersInfoCollector) SetPeerShardResolver(peerShardResolver p2p.PeerShardResolver) error {
+	if check.IfNil(peerShardResolver) {
+		return p2p.ErrNilPeerShardResolver
+	}
+
+	pic.mut.Lock()
+	pic.peerShardResolver = peerShardResolver
+	pic.mut.Unlock()
+
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (pic *peersInfoCollector) IsInterfaceNil() bool {
+	return pic == nil
+}
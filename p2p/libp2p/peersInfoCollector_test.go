@@ -0,0 +1,92 @@
+package libp2p_test
+
+import (
+	"testing"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/mock"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+	"github.com/TerraDharitri/drt-go-chain-core/core/check"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeersInfoCollector_IsInterfaceNil(t *testing.T) {
+	t.Parallel()
+
+	pic := libp2p.NewPeersInfoCollector()
+	assert.False(t, check.IfNil(pic))
+}
+
+func TestPeersInfoCollector_SetPeerShardResolverNilShouldErr(t *testing.T) {
+	t.Parallel()
+
+	pic := libp2p.NewPeersInfoCollector()
+	err := pic.SetPeerShardResolver(nil)
+	assert.Equal(t, p2p.ErrNilPeerShardResolver, err)
+}
+
+func TestPeersInfoCollector_GetPeerInfo(t *testing.T) {
+	t.Parallel()
+
+	providedPeerInfo := core.P2PPeerInfo{
+		PeerType: core.ValidatorPeer,
+		ShardID:  1,
+		PkBytes:  []byte("pk"),
+	}
+
+	t.Run("unknown peer should return unknown", func(t *testing.T) {
+		t.Parallel()
+
+		pic := libp2p.NewPeersInfoCollector()
+		assert.Equal(t, core.UnknownPeer, pic.GetPeerInfo(providedPid).PeerType)
+		assert.Empty(t, pic.GetPeerNodeVersion(providedPid))
+	})
+	t.Run("authenticated peer should return the collected info", func(t *testing.T) {
+		t.Parallel()
+
+		pic := libp2p.NewPeersInfoCollector()
+		pic.UpdatePeerInfo(providedPid, providedPeerInfo, "v1.0.0")
+		assert.Equal(t, providedPeerInfo, pic.GetPeerInfo(providedPid))
+		assert.Equal(t, "v1.0.0", pic.GetPeerNodeVersion(providedPid))
+
+		pic.RemovePeer(providedPid)
+		assert.Equal(t, core.UnknownPeer, pic.GetPeerInfo(providedPid).PeerType)
+		assert.Empty(t, pic.GetPeerNodeVersion(providedPid))
+	})
+	t.Run("peer known by the resolver should return the resolver's info", func(t *testing.T) {
+		t.Parallel()
+
+		resolverPeerInfo := core.P2PPeerInfo{
+			PeerType: core.ObserverPeer,
+			ShardID:  core.MetachainShardId,
+		}
+		pic := libp2p.NewPeersInfoCollector()
+		err := pic.SetPeerShardResolver(&mock.PeerShardResolverStub{
+			GetPeerInfoCalled: func(pid core.PeerID) core.P2PPeerInfo {
+				if pid == providedPid {
+					return resolverPeerInfo
+				}
+				return core.P2PPeerInfo{PeerType: core.UnknownPeer}
+			},
+		})
+		assert.Nil(t, err)
+
+		pic.UpdatePeerInfo(providedPid, providedPeerInfo, "v1.0.0")
+		pic.UpdatePeerInfo("other pid", providedPeerInfo, "v1.0.0")
+		assert.Equal(t, resolverPeerInfo, pic.GetPeerInfo(providedPid))
+		assert.Equal(t, providedPeerInfo, pic.GetPeerInfo("other pid"))
+	})
+	t.Run("UpdatePeerIDInfo should keep the peer type", func(t *testing.T) {
+		t.Parallel()
+
+		pic := libp2p.NewPeersInfoCollector()
+		pic.UpdatePeerInfo(providedPid, providedPeerInfo, "v1.0.0")
+		pic.UpdatePeerIDInfo(providedPid, []byte("new pk"), 2)
+
+		peerInfo := pic.GetPeerInfo(providedPid)
+		assert.Equal(t, core.ValidatorPeer, peerInfo.PeerType)
+		assert.Equal(t, uint32(2), peerInfo.ShardID)
+		assert.Equal(t, []byte("new pk"), peerInfo.PkBytes)
+	})
+}
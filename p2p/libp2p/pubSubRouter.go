@@ -0,0 +1,115 @@
+package libp2p
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/config"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/host"
+)
+
+func getSeenMessagesTTL(routerConfig config.RouterConfig) time.Duration {
+	if routerConfig.SeenMessagesTTLInSec == 0 {
+		return pubsubTimeCacheDuration
+	}
+
+	return time.Duration(routerConfig.SeenMessagesTTLInSec) * time.Second
+}
+
+func newPubSubWithRouter(ctx context.Context, h host.Host, routerConfig config.RouterConfig, opts []pubsub.Option) (*pubsub.PubSub, error) {
+	switch routerConfig.Type {
+	case "", p2p.GossipSubRouter:
+		params, err := createGossipSubParams(routerConfig.GossipSub)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, pubsub.WithGossipSubParams(params))
+		return pubsub.NewGossipSub(ctx, h, opts...)
+	case p2p.FloodSubRouter:
+		return pubsub.NewFloodSub(ctx, h, opts...)
+	case p2p.RandomSubRouter:
+		if routerConfig.RandomSubSize < 1 {
+			return nil, fmt.Errorf("%w, RandomSubSize should be at least 1", p2p.ErrInvalidConfig)
+		}
+		return pubsub.NewRandomSub(ctx, h, routerConfig.RandomSubSize, opts...)
+	default:
+		return nil, fmt.Errorf("%w: %s", p2p.ErrUnknownPubSubRouterType, routerConfig.Type)
+	}
+}
+
+// createGossipSubParams overrides the gossipsub defaults with the provided non-zero values and validates the result
+func createGossipSubParams(gossipSubConfig config.GossipSubConfig) (pubsub.GossipSubParams, error) {
+	params := pubsub.DefaultGossipSubParams()
+	if gossipSubConfig.D != 0 {
+		params.D = gossipSubConfig.D
+	}
+	if gossipSubConfig.Dlo != 0 {
+		params.Dlo = gossipSubConfig.Dlo
+	}
+	if gossipSubConfig.Dhi != 0 {
+		params.Dhi = gossipSubConfig.Dhi
+	}
+	if gossipSubConfig.Dscore != 0 {
+		params.Dscore = gossipSubConfig.Dscore
+	}
+	if gossipSubConfig.Dout != 0 {
+		params.Dout = gossipSubConfig.Dout
+	}
+	if gossipSubConfig.Dlazy != 0 {
+		params.Dlazy = gossipSubConfig.Dlazy
+	}
+	if gossipSubConfig.HistoryLength != 0 {
+		params.HistoryLength = gossipSubConfig.HistoryLength
+	}
+	if gossipSubConfig.HistoryGossip != 0 {
+		params.HistoryGossip = gossipSubConfig.HistoryGossip
+	}
+	if gossipSubConfig.GossipFactor != 0 {
+		params.GossipFactor = gossipSubConfig.GossipFactor
+	}
+	if gossipSubConfig.HeartbeatIntervalInMs != 0 {
+		params.HeartbeatInterval = time.Duration(gossipSubConfig.HeartbeatIntervalInMs) * time.Millisecond
+	}
+	if gossipSubConfig.FanoutTTLInSec != 0 {
+		params.FanoutTTL = time.Duration(gossipSubConfig.FanoutTTLInSec) * time.Second
+	}
+
+	err := checkGossipSubParams(params)
+	if err != nil {
+		return pubsub.GossipSubParams{}, fmt.Errorf("%w, gossipsub parameters: %s", p2p.ErrInvalidConfig, err.Error())
+	}
+
+	return params, nil
+}
+
+func checkGossipSubParams(params pubsub.GossipSubParams) error {
+	if params.D < 1 || params.Dlo < 1 || params.Dhi < 1 || params.Dscore < 0 || params.Dout < 0 || params.Dlazy < 0 {
+		return fmt.Errorf("mesh degrees can not be negative and D, Dlo and Dhi should be at least 1")
+	}
+	if params.Dlo > params.D || params.D > params.Dhi {
+		return fmt.Errorf("the mesh degrees should respect Dlo <= D <= Dhi, provided Dlo=%d, D=%d, Dhi=%d",
+			params.Dlo, params.D, params.Dhi)
+	}
+	if params.Dscore > params.Dhi {
+		return fmt.Errorf("Dscore=%d should not be greater than Dhi=%d", params.Dscore, params.Dhi)
+	}
+	if params.Dout >= params.Dlo || params.Dout > params.D/2 {
+		return fmt.Errorf("Dout=%d should be lower than Dlo=%d and at most D/2=%d", params.Dout, params.Dlo, params.D/2)
+	}
+	if params.HistoryLength < 1 || params.HistoryGossip < 1 || params.HistoryGossip > params.HistoryLength {
+		return fmt.Errorf("the history windows should respect 1 <= HistoryGossip <= HistoryLength, provided HistoryGossip=%d, HistoryLength=%d",
+			params.HistoryGossip, params.HistoryLength)
+	}
+	if params.GossipFactor < 0 || params.GossipFactor > 1 {
+		return fmt.Errorf("GossipFactor=%v should be in the [0, 1] interval", params.GossipFactor)
+	}
+	if params.HeartbeatInterval < time.Millisecond*100 {
+		return fmt.Errorf("HeartbeatInterval=%v should be at least 100ms", params.HeartbeatInterval)
+	}
+
+	return nil
+}
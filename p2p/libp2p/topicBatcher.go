@@ -0,0 +1,187 @@
+package libp2p
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/config"
+)
+
+const defaultBatchMaxSizeInBytes = 64 * 1024
+
+// batchElementOverhead approximates the bytes added by the batch serialization for each payload
+const batchElementOverhead = 8
+
+type batchSettings struct {
+	window         time.Duration
+	maxSizeInBytes int
+	maxNumMessages int
+}
+
+type pendingBatch struct {
+	channel  string
+	payloads [][]byte
+}
+
+// topicBatcher collects the payloads broadcast on a topic for a short window, or until a size limit is reached,
+// so they can be published as a single message
+type topicBatcher struct {
+	// mutSend is held from taking a batch until it is sent, so the batches leave in the order they were taken
+	mutSend    sync.Mutex
+	mut        sync.Mutex
+	topic      string
+	settings   batchSettings
+	channel    string
+	payloads   [][]byte
+	size       int
+	timer      *time.Timer
+	generation uint64
+	closed     bool
+	sendBatch  func(channel string, topic string, payloads [][]byte)
+}
+
+func createTopicBatchers(
+	batchingConfigs []config.TopicBatchingConfig,
+	sendBatch func(channel string, topic string, payloads [][]byte),
+) (map[string]*topicBatcher, error) {
+	batchers := make(map[string]*topicBatcher, len(batchingConfigs))
+	for _, batchingConfig := range batchingConfigs {
+		settings, err := createBatchSettings(batchingConfig)
+		if err != nil {
+			return nil, fmt.Errorf("%w in batching config for topic %s", err, batchingConfig.Topic)
+		}
+
+		_, found := batchers[batchingConfig.Topic]
+		if found {
+			return nil, fmt.Errorf("%w in batching config: %s", p2p.ErrDuplicatedTopic, batchingConfig.Topic)
+		}
+
+		batchers[batchingConfig.Topic] = &topicBatcher{
+			topic:     batchingConfig.Topic,
+			settings:  settings,
+			sendBatch: sendBatch,
+		}
+	}
+
+	return batchers, nil
+}
+
+func createBatchSettings(batchingConfig config.TopicBatchingConfig) (batchSettings, error) {
+	if len(batchingConfig.Topic) == 0 {
+		return batchSettings{}, p2p.ErrNilTopic
+	}
+	if batchingConfig.WindowInMs == 0 {
+		return batchSettings{}, fmt.Errorf("%w for WindowInMs", p2p.ErrInvalidValue)
+	}
+	if batchingConfig.MaxSizeInBytes < 0 || batchingConfig.MaxSizeInBytes > maxSendBuffSize {
+		return batchSettings{}, fmt.Errorf("%w for MaxSizeInBytes, maximum %d", p2p.ErrInvalidValue, maxSendBuffSize)
+	}
+	if batchingConfig.MaxNumMessages < 0 {
+		return batchSettings{}, fmt.Errorf("%w for MaxNumMessages", p2p.ErrInvalidValue)
+	}
+
+	settings := batchSettings{
+		window:         time.Duration(batchingConfig.WindowInMs) * time.Millisecond,
+		maxSizeInBytes: batchingConfig.MaxSizeInBytes,
+		maxNumMessages: batchingConfig.MaxNumMessages,
+	}
+	if settings.maxSizeInBytes == 0 {
+		settings.maxSizeInBytes = defaultBatchMaxSizeInBytes
+	}
+
+	return settings, nil
+}
+
+// canBatch returns false for the payloads that would not fit in a batch, these should be sent individually
+func (tb *topicBatcher) canBatch(buff []byte) bool {
+	return len(buff)+batchElementOverhead <= tb.settings.maxSizeInBytes
+}
+
+// add appends the payload to the pending batch. The full batches are sent on the calling go routine
+func (tb *topicBatcher) add(channel string, buff []byte) {
+	batchesToSend := make([]pendingBatch, 0, 2)
+	elementSize := len(buff) + batchElementOverhead
+
+	tb.mutSend.Lock()
+	defer tb.mutSend.Unlock()
+
+	tb.mut.Lock()
+	if tb.closed {
+		tb.mut.Unlock()
+		return
+	}
+
+	hasPending := len(tb.payloads) > 0
+	if hasPending && (channel != tb.channel || tb.size+elementSize > tb.settings.maxSizeInBytes) {
+		batchesToSend = append(batchesToSend, tb.takePendingBatch())
+	}
+	if len(tb.payloads) == 0 {
+		tb.startWindow(channel)
+	}
+
+	tb.payloads = append(tb.payloads, buff)
+	tb.size += elementSize
+
+	isFull := tb.size >= tb.settings.maxSizeInBytes ||
+		(tb.settings.maxNumMessages > 0 && len(tb.payloads) >= tb.settings.maxNumMessages)
+	if isFull {
+		batchesToSend = append(batchesToSend, tb.takePendingBatch())
+	}
+	tb.mut.Unlock()
+
+	for _, b := range batchesToSend {
+		tb.sendBatch(b.channel, tb.topic, b.payloads)
+	}
+}
+
+func (tb *topicBatcher) startWindow(channel string) {
+	tb.channel = channel
+	tb.generation++
+	generation := tb.generation
+	tb.timer = time.AfterFunc(tb.settings.window, func() {
+		tb.flush(generation)
+	})
+}
+
+func (tb *topicBatcher) takePendingBatch() pendingBatch {
+	if tb.timer != nil {
+		tb.timer.Stop()
+		tb.timer = nil
+	}
+
+	b := pendingBatch{
+		channel:  tb.channel,
+		payloads: tb.payloads,
+	}
+	tb.payloads = nil
+	tb.size = 0
+
+	return b
+}
+
+func (tb *topicBatcher) flush(generation uint64) {
+	tb.mutSend.Lock()
+	defer tb.mutSend.Unlock()
+
+	tb.mut.Lock()
+	if tb.closed || generation != tb.generation || len(tb.payloads) == 0 {
+		tb.mut.Unlock()
+		return
+	}
+
+	b := tb.takePendingBatch()
+	tb.mut.Unlock()
+
+	tb.sendBatch(b.channel, tb.topic, b.payloads)
+}
+
+// close drops the pending payloads
+func (tb *topicBatcher) close() {
+	tb.mut.Lock()
+	defer tb.mut.Unlock()
+
+	tb.closed = true
+	_ = tb.takePendingBatch()
+}
@@ -0,0 +1,158 @@
+package libp2p
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/config"
+)
+
+const defaultSubscriptionBufferSize = 1000
+
+// topicSubscribers holds the channels returned by the Subscribe calls and delivers the validated messages
+// of a topic, in arrival order, on each of them
+type topicSubscribers struct {
+	mut         sync.RWMutex
+	bufferSize  int
+	dropOldest  bool
+	nextID      uint64
+	subscribers map[string]map[uint64]chan p2p.MessageP2P
+	closed      bool
+	log         p2p.Logger
+}
+
+func newTopicSubscribers(subscriptionsConfig config.SubscriptionsConfig, log p2p.Logger) (*topicSubscribers, error) {
+	if subscriptionsConfig.BufferSize < 0 {
+		return nil, fmt.Errorf("%w for subscriptions BufferSize", p2p.ErrInvalidValue)
+	}
+
+	bufferSize := subscriptionsConfig.BufferSize
+	if bufferSize == 0 {
+		bufferSize = defaultSubscriptionBufferSize
+	}
+
+	dropOldest := false
+	switch subscriptionsConfig.DropPolicy {
+	case "", p2p.DropNewestPolicy:
+	case p2p.DropOldestPolicy:
+		dropOldest = true
+	default:
+		return nil, fmt.Errorf("%w: %s", p2p.ErrUnknownDropPolicy, subscriptionsConfig.DropPolicy)
+	}
+
+	return &topicSubscribers{
+		bufferSize:  bufferSize,
+		dropOldest:  dropOldest,
+		subscribers: make(map[string]map[uint64]chan p2p.MessageP2P),
+		log:         log,
+	}, nil
+}
+
+func (ts *topicSubscribers) subscribe(topic string) (<-chan p2p.MessageP2P, func()) {
+	ts.mut.Lock()
+	defer ts.mut.Unlock()
+
+	ch := make(chan p2p.MessageP2P, ts.bufferSize)
+	if ts.closed {
+		close(ch)
+		return ch, func() {}
+	}
+
+	ts.nextID++
+	id := ts.nextID
+	topicSubscribers, found := ts.subscribers[topic]
+	if !found {
+		topicSubscribers = make(map[uint64]chan p2p.MessageP2P)
+		ts.subscribers[topic] = topicSubscribers
+	}
+	topicSubscribers[id] = ch
+
+	return ch, func() {
+		ts.unsubscribe(topic, id)
+	}
+}
+
+func (ts *topicSubscribers) unsubscribe(topic string, id uint64) {
+	ts.mut.Lock()
+	defer ts.mut.Unlock()
+
+	ch, found := ts.subscribers[topic][id]
+	if !found {
+		return
+	}
+
+	delete(ts.subscribers[topic], id)
+	if len(ts.subscribers[topic]) == 0 {
+		delete(ts.subscribers, topic)
+	}
+	close(ch)
+}
+
+func (ts *topicSubscribers) hasSubscribers(topic string) bool {
+	ts.mut.RLock()
+	defer ts.mut.RUnlock()
+
+	return len(ts.subscribers[topic]) > 0
+}
+
+// deliver should be called from a single go routine for each topic so the messages keep their arrival order
+func (ts *topicSubscribers) deliver(msg p2p.MessageP2P) {
+	ts.mut.RLock()
+	defer ts.mut.RUnlock()
+
+	for _, ch := range ts.subscribers[msg.Topic()] {
+		ts.deliverOnChannel(ch, msg)
+	}
+}
+
+func (ts *topicSubscribers) deliverOnChannel(ch chan p2p.MessageP2P, msg p2p.MessageP2P) {
+	select {
+	case ch <- msg:
+		return
+	default:
+	}
+
+	if !ts.dropOldest {
+		ts.log.Trace("subscription channel full, dropping the newest message", "topic", msg.Topic())
+		return
+	}
+
+	select {
+	case <-ch:
+	default:
+	}
+	select {
+	case ch <- msg:
+	default:
+	}
+	ts.log.Trace("subscription channel full, dropped the oldest message", "topic", msg.Topic())
+}
+
+// cancelTopic closes the channels of the topic's subscribers, so the consumers ranging over them are released
+func (ts *topicSubscribers) cancelTopic(topic string) {
+	ts.mut.Lock()
+	defer ts.mut.Unlock()
+
+	for _, ch := range ts.subscribers[topic] {
+		close(ch)
+	}
+	delete(ts.subscribers, topic)
+}
+
+func (ts *topicSubscribers) close() {
+	ts.mut.Lock()
+	defer ts.mut.Unlock()
+
+	if ts.closed {
+		return
+	}
+	ts.closed = true
+
+	for _, topicSubscribers := range ts.subscribers {
+		for _, ch := range topicSubscribers {
+			close(ch)
+		}
+	}
+	ts.subscribers = make(map[string]map[uint64]chan p2p.MessageP2P)
+}
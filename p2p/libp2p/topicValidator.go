@@ -0,0 +1,135 @@
+package libp2p
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/config"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// pubsub throttles each topic validator at this number of concurrent validations if not configured otherwise
+const pubsubDefaultValidatorConcurrency = 1024
+
+type topicValidatorSettings struct {
+	concurrency      int
+	timeout          time.Duration
+	inline           bool
+	ignoreOnOverload bool
+}
+
+func createTopicValidatorSettings(topicValidators []config.TopicValidatorConfig) (map[string]topicValidatorSettings, error) {
+	settings := make(map[string]topicValidatorSettings, len(topicValidators))
+	for _, topicValidator := range topicValidators {
+		if len(topicValidator.Topic) == 0 {
+			return nil, fmt.Errorf("%w in topic validators config", p2p.ErrNilTopic)
+		}
+		_, found := settings[topicValidator.Topic]
+		if found {
+			return nil, fmt.Errorf("%w in topic validators config: %s", p2p.ErrDuplicatedTopic, topicValidator.Topic)
+		}
+		if topicValidator.Concurrency < 0 {
+			return nil, fmt.Errorf("%w, negative validator concurrency for topic %s", p2p.ErrInvalidValue, topicValidator.Topic)
+		}
+
+		ignoreOnOverload := false
+		switch topicValidator.OverloadPolicy {
+		case "", p2p.DropOnOverload:
+		case p2p.IgnoreOnOverload:
+			ignoreOnOverload = true
+		default:
+			return nil, fmt.Errorf("%w: %s for topic %s", p2p.ErrUnknownOverloadPolicy, topicValidator.OverloadPolicy, topicValidator.Topic)
+		}
+
+		settings[topicValidator.Topic] = topicValidatorSettings{
+			concurrency:      topicValidator.Concurrency,
+			timeout:          time.Duration(topicValidator.TimeoutInMs) * time.Millisecond,
+			inline:           topicValidator.Inline,
+			ignoreOnOverload: ignoreOnOverload,
+		}
+	}
+
+	return settings, nil
+}
+
+func (settings topicValidatorSettings) validatorOptions() []pubsub.ValidatorOpt {
+	opts := make([]pubsub.ValidatorOpt, 0)
+	if settings.inline {
+		opts = append(opts, pubsub.WithValidatorInline(true))
+	}
+	if settings.timeout > 0 {
+		opts = append(opts, pubsub.WithValidatorTimeout(settings.timeout))
+	}
+	if settings.concurrency == 0 {
+		return opts
+	}
+	if !settings.ignoreOnOverload {
+		return append(opts, pubsub.WithValidatorConcurrency(settings.concurrency))
+	}
+	// the concurrency is enforced by the validator itself, pubsub should not throttle before reaching it
+	if settings.concurrency > pubsubDefaultValidatorConcurrency {
+		opts = append(opts, pubsub.WithValidatorConcurrency(settings.concurrency))
+	}
+
+	return opts
+}
+
+// createTopicValidator wraps the pubsub callback of a topic so that the overload and timeout settings are applied
+func (handler *messagesHandler) createTopicValidator(topicProcs TopicProcessor, topic string) (pubsub.ValidatorEx, []pubsub.ValidatorOpt) {
+	settings := handler.topicValidators[topic]
+	callback := handler.pubsubCallback(topicProcs, topic)
+
+	var semaphore chan struct{}
+	if settings.ignoreOnOverload && settings.concurrency > 0 {
+		semaphore = make(chan struct{}, settings.concurrency)
+	}
+
+	validator := func(ctx context.Context, pid peer.ID, message *pubsub.Message) pubsub.ValidationResult {
+		release := func() {}
+		if semaphore != nil {
+			select {
+			case semaphore <- struct{}{}:
+				release = func() {
+					<-semaphore
+				}
+			default:
+				handler.validatorMetrics.AddIgnoredOnOverload(topic)
+				return pubsub.ValidationIgnore
+			}
+		}
+
+		if settings.timeout == 0 {
+			defer release()
+			return toValidationResult(callback(ctx, pid, message))
+		}
+
+		chResult := make(chan bool, 1)
+		go func() {
+			// the slot is held until the callback returns, even if the validation timed out before
+			defer release()
+			chResult <- callback(ctx, pid, message)
+		}()
+
+		select {
+		case messageOk := <-chResult:
+			return toValidationResult(messageOk)
+		case <-ctx.Done():
+			handler.validatorMetrics.AddTimeout(topic)
+			handler.log.Trace("p2p validator timeout", "network", handler.networkType, "topic", topic)
+			return pubsub.ValidationIgnore
+		}
+	}
+
+	return validator, settings.validatorOptions()
+}
+
+func toValidationResult(messageOk bool) pubsub.ValidationResult {
+	if messageOk {
+		return pubsub.ValidationAccept
+	}
+
+	return pubsub.ValidationReject
+}
@@ -19,6 +19,8 @@ type Message struct {
 	PeerField            core.PeerID
 	TimestampField       int64
 	BroadcastMethodField p2p.BroadcastMethod
+	AttestedPkField      []byte
+	SignatureOnPidField  []byte
 }
 
 // From returns the message originator's peer ID
@@ -71,6 +73,16 @@ func (m *Message) BroadcastMethod() p2p.BroadcastMethod {
 	return m.BroadcastMethodField
 }
 
+// AttestedPk returns the public key the originator peer attested to own
+func (m *Message) AttestedPk() []byte {
+	return m.AttestedPkField
+}
+
+// SignatureOnPid returns the signature binding the attested public key to the originator peer ID
+func (m *Message) SignatureOnPid() []byte {
+	return m.SignatureOnPidField
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (m *Message) IsInterfaceNil() bool {
 	return m == nil
@@ -21,6 +21,8 @@ func TestMessage_AllFieldsShouldWork(t *testing.T) {
 	key := []byte("key")
 	peer := core.PeerID("peer")
 	msgType := p2p.Direct
+	attestedPk := []byte("attested pk")
+	signatureOnPid := []byte("signature on pid")
 
 	msg := &message.Message{
 		FromField:            from,
@@ -31,6 +33,8 @@ func TestMessage_AllFieldsShouldWork(t *testing.T) {
 		KeyField:             key,
 		PeerField:            peer,
 		BroadcastMethodField: msgType,
+		AttestedPkField:      attestedPk,
+		SignatureOnPidField:  signatureOnPid,
 	}
 
 	assert.False(t, check.IfNil(msg))
@@ -42,4 +46,6 @@ func TestMessage_AllFieldsShouldWork(t *testing.T) {
 	assert.Equal(t, key, msg.Key())
 	assert.Equal(t, peer, msg.Peer())
 	assert.Equal(t, msgType, msg.BroadcastMethod())
+	assert.Equal(t, attestedPk, msg.AttestedPk())
+	assert.Equal(t, signatureOnPid, msg.SignatureOnPid())
 }
@@ -0,0 +1,538 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: misbehaviorEvidence.proto
+
+package message
+
+import (
+	bytes "bytes"
+	fmt "fmt"
+	_ "github.com/gogo/protobuf/gogoproto"
+	proto "github.com/gogo/protobuf/proto"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+	reflect "reflect"
+	strings "strings"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+// MisbehaviorEvidence holds the signed messages proving that a peer misbehaved. The messages are serialized as a
+// batch of pubsub messages, so any node can check their signatures
+type MisbehaviorEvidence struct {
+	Type     uint32 `protobuf:"varint,1,opt,name=Type,proto3" json:"type"`
+	Offender []byte `protobuf:"bytes,2,opt,name=Offender,proto3" json:"offender"`
+	Messages []byte `protobuf:"bytes,3,opt,name=Messages,proto3" json:"messages"`
+	Reason   string `protobuf:"bytes,4,opt,name=Reason,proto3" json:"reason"`
+}
+
+func (m *MisbehaviorEvidence) Reset()      { *m = MisbehaviorEvidence{} }
+func (*MisbehaviorEvidence) ProtoMessage() {}
+func (*MisbehaviorEvidence) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0f360bc841757822, []int{0}
+}
+func (m *MisbehaviorEvidence) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MisbehaviorEvidence) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *MisbehaviorEvidence) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MisbehaviorEvidence.Merge(m, src)
+}
+func (m *MisbehaviorEvidence) XXX_Size() int {
+	return m.Size()
+}
+func (m *MisbehaviorEvidence) XXX_DiscardUnknown() {
+	xxx_messageInfo_MisbehaviorEvidence.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MisbehaviorEvidence proto.InternalMessageInfo
+
+func (m *MisbehaviorEvidence) GetType() uint32 {
+	if m != nil {
+		return m.Type
+	}
+	return 0
+}
+
+func (m *MisbehaviorEvidence) GetOffender() []byte {
+	if m != nil {
+		return m.Offender
+	}
+	return nil
+}
+
+func (m *MisbehaviorEvidence) GetMessages() []byte {
+	if m != nil {
+		return m.Messages
+	}
+	return nil
+}
+
+func (m *MisbehaviorEvidence) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*MisbehaviorEvidence)(nil), "proto.MisbehaviorEvidence")
+}
+
+func init() { proto.RegisterFile("misbehaviorEvidence.proto", fileDescriptor_0f360bc841757822) }
+
+var fileDescriptor_0f360bc841757822 = []byte{
+	// 266 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x92, 0xcc, 0xcd, 0x2c, 0x4e,
+	0x4a, 0xcd, 0x48, 0x2c, 0xcb, 0xcc, 0x2f, 0x72, 0x2d, 0xcb, 0x4c, 0x49, 0xcd, 0x4b, 0x4e, 0xd5,
+	0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x05, 0x53, 0x52, 0xba, 0xe9, 0x99, 0x25, 0x19, 0xa5,
+	0x49, 0x7a, 0xc9, 0xf9, 0xb9, 0xfa, 0xe9, 0xf9, 0xe9, 0xf9, 0xfa, 0x60, 0xe1, 0xa4, 0xd2, 0x34,
+	0x30, 0x0f, 0xcc, 0x01, 0xb3, 0x20, 0xba, 0x94, 0x56, 0x33, 0x72, 0x09, 0xfb, 0x62, 0x9a, 0x29,
+	0x24, 0xc3, 0xc5, 0x12, 0x52, 0x59, 0x90, 0x2a, 0xc1, 0xa8, 0xc0, 0xa8, 0xc1, 0xeb, 0xc4, 0xf1,
+	0xea, 0x9e, 0x3c, 0x4b, 0x49, 0x65, 0x41, 0x6a, 0x10, 0x58, 0x54, 0x48, 0x83, 0x8b, 0xc3, 0x3f,
+	0x2d, 0x2d, 0x35, 0x2f, 0x25, 0xb5, 0x48, 0x82, 0x49, 0x81, 0x51, 0x83, 0xc7, 0x89, 0xe7, 0xd5,
+	0x3d, 0x79, 0x8e, 0x7c, 0xa8, 0x58, 0x10, 0x5c, 0x16, 0xa4, 0xd2, 0x37, 0xb5, 0xb8, 0x38, 0x31,
+	0x3d, 0xb5, 0x58, 0x82, 0x19, 0xa1, 0x32, 0x17, 0x2a, 0x16, 0x04, 0x97, 0x15, 0x52, 0xe2, 0x62,
+	0x0b, 0x4a, 0x4d, 0x2c, 0xce, 0xcf, 0x93, 0x60, 0x51, 0x60, 0xd4, 0xe0, 0x74, 0xe2, 0x7a, 0x75,
+	0x4f, 0x9e, 0xad, 0x08, 0x2c, 0x12, 0x04, 0x95, 0x71, 0x72, 0xbc, 0xf0, 0x50, 0x8e, 0xe1, 0xc6,
+	0x43, 0x39, 0x86, 0x0f, 0x0f, 0xe5, 0x18, 0x1b, 0x1e, 0xc9, 0x31, 0xae, 0x78, 0x24, 0xc7, 0x78,
+	0xe2, 0x91, 0x1c, 0xe3, 0x85, 0x47, 0x72, 0x8c, 0x37, 0x1e, 0xc9, 0x31, 0x3e, 0x78, 0x24, 0xc7,
+	0xf8, 0xe2, 0x91, 0x1c, 0xc3, 0x87, 0x47, 0x72, 0x8c, 0x13, 0x1e, 0xcb, 0x31, 0x5c, 0x78, 0x2c,
+	0xc7, 0x70, 0xe3, 0xb1, 0x1c, 0x43, 0x14, 0x3b, 0xd4, 0xc6, 0x24, 0x36, 0xb0, 0xbf, 0x8d, 0x01,
+	0x03, 0x00, 0x92, 0x0a, 0x14, 0x3c, 0x4a, 0x01, 0x00, 0x00,
+}
+
+func (this *MisbehaviorEvidence) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*MisbehaviorEvidence)
+	if !ok {
+		that2, ok := that.(MisbehaviorEvidence)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Type != that1.Type {
+		return false
+	}
+	if !bytes.Equal(this.Offender, that1.Offender) {
+		return false
+	}
+	if !bytes.Equal(this.Messages, that1.Messages) {
+		return false
+	}
+	if this.Reason != that1.Reason {
+		return false
+	}
+	return true
+}
+func (this *MisbehaviorEvidence) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&message.MisbehaviorEvidence{")
+	s = append(s, "Type: "+fmt.Sprintf("%#v", this.Type)+",\n")
+	s = append(s, "Offender: "+fmt.Sprintf("%#v", this.Offender)+",\n")
+	s = append(s, "Messages: "+fmt.Sprintf("%#v", this.Messages)+",\n")
+	s = append(s, "Reason: "+fmt.Sprintf("%#v", this.Reason)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func valueToGoStringMisbehaviorEvidence(v interface{}, typ string) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+}
+func (m *MisbehaviorEvidence) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MisbehaviorEvidence) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MisbehaviorEvidence) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Reason) > 0 {
+		i -= len(m.Reason)
+		copy(dAtA[i:], m.Reason)
+		i = encodeVarintMisbehaviorEvidence(dAtA, i, uint64(len(m.Reason)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Messages) > 0 {
+		i -= len(m.Messages)
+		copy(dAtA[i:], m.Messages)
+		i = encodeVarintMisbehaviorEvidence(dAtA, i, uint64(len(m.Messages)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Offender) > 0 {
+		i -= len(m.Offender)
+		copy(dAtA[i:], m.Offender)
+		i = encodeVarintMisbehaviorEvidence(dAtA, i, uint64(len(m.Offender)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Type != 0 {
+		i = encodeVarintMisbehaviorEvidence(dAtA, i, uint64(m.Type))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintMisbehaviorEvidence(dAtA []byte, offset int, v uint64) int {
+	offset -= sovMisbehaviorEvidence(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *MisbehaviorEvidence) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Type != 0 {
+		n += 1 + sovMisbehaviorEvidence(uint64(m.Type))
+	}
+	l = len(m.Offender)
+	if l > 0 {
+		n += 1 + l + sovMisbehaviorEvidence(uint64(l))
+	}
+	l = len(m.Messages)
+	if l > 0 {
+		n += 1 + l + sovMisbehaviorEvidence(uint64(l))
+	}
+	l = len(m.Reason)
+	if l > 0 {
+		n += 1 + l + sovMisbehaviorEvidence(uint64(l))
+	}
+	return n
+}
+
+func sovMisbehaviorEvidence(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozMisbehaviorEvidence(x uint64) (n int) {
+	return sovMisbehaviorEvidence(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *MisbehaviorEvidence) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&MisbehaviorEvidence{`,
+		`Type:` + fmt.Sprintf("%v", this.Type) + `,`,
+		`Offender:` + fmt.Sprintf("%v", this.Offender) + `,`,
+		`Messages:` + fmt.Sprintf("%v", this.Messages) + `,`,
+		`Reason:` + fmt.Sprintf("%v", this.Reason) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringMisbehaviorEvidence(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *MisbehaviorEvidence) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMisbehaviorEvidence
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MisbehaviorEvidence: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MisbehaviorEvidence: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMisbehaviorEvidence
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offender", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMisbehaviorEvidence
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMisbehaviorEvidence
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMisbehaviorEvidence
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Offender = append(m.Offender[:0], dAtA[iNdEx:postIndex]...)
+			if m.Offender == nil {
+				m.Offender = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Messages", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMisbehaviorEvidence
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMisbehaviorEvidence
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMisbehaviorEvidence
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Messages = append(m.Messages[:0], dAtA[iNdEx:postIndex]...)
+			if m.Messages == nil {
+				m.Messages = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Reason", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMisbehaviorEvidence
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMisbehaviorEvidence
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMisbehaviorEvidence
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Reason = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMisbehaviorEvidence(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMisbehaviorEvidence
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipMisbehaviorEvidence(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowMisbehaviorEvidence
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowMisbehaviorEvidence
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowMisbehaviorEvidence
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthMisbehaviorEvidence
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupMisbehaviorEvidence
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthMisbehaviorEvidence
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthMisbehaviorEvidence        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowMisbehaviorEvidence          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupMisbehaviorEvidence = fmt.Errorf("proto: unexpected end of group")
+)
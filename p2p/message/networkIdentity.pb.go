@@ -0,0 +1,689 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: networkIdentity.proto
+
+package message
+
+import (
+	fmt "fmt"
+	_ "github.com/gogo/protobuf/gogoproto"
+	proto "github.com/gogo/protobuf/proto"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+	reflect "reflect"
+	strings "strings"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+// NetworkIdentity represents the data 2 peers exchange right after connecting in order to check they are compatible
+type NetworkIdentity struct {
+	ChainId              string   `protobuf:"bytes,1,opt,name=ChainId,proto3" json:"chainId"`
+	NetworkType          string   `protobuf:"bytes,2,opt,name=NetworkType,proto3" json:"networkType"`
+	TopicMessageVersions []uint32 `protobuf:"varint,3,rep,packed,name=TopicMessageVersions,proto3" json:"topicMessageVersions"`
+	ProtocolVersion      uint32   `protobuf:"varint,4,opt,name=ProtocolVersion,proto3" json:"protocolVersion"`
+	MinProtocolVersion   uint32   `protobuf:"varint,5,opt,name=MinProtocolVersion,proto3" json:"minProtocolVersion"`
+	AttestationEnabled   bool     `protobuf:"varint,6,opt,name=AttestationEnabled,proto3" json:"attestationEnabled"`
+}
+
+func (m *NetworkIdentity) Reset()      { *m = NetworkIdentity{} }
+func (*NetworkIdentity) ProtoMessage() {}
+func (*NetworkIdentity) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4a60b4d13bea2798, []int{0}
+}
+func (m *NetworkIdentity) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NetworkIdentity) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *NetworkIdentity) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NetworkIdentity.Merge(m, src)
+}
+func (m *NetworkIdentity) XXX_Size() int {
+	return m.Size()
+}
+func (m *NetworkIdentity) XXX_DiscardUnknown() {
+	xxx_messageInfo_NetworkIdentity.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NetworkIdentity proto.InternalMessageInfo
+
+func (m *NetworkIdentity) GetChainId() string {
+	if m != nil {
+		return m.ChainId
+	}
+	return ""
+}
+
+func (m *NetworkIdentity) GetNetworkType() string {
+	if m != nil {
+		return m.NetworkType
+	}
+	return ""
+}
+
+func (m *NetworkIdentity) GetTopicMessageVersions() []uint32 {
+	if m != nil {
+		return m.TopicMessageVersions
+	}
+	return nil
+}
+
+func (m *NetworkIdentity) GetProtocolVersion() uint32 {
+	if m != nil {
+		return m.ProtocolVersion
+	}
+	return 0
+}
+
+func (m *NetworkIdentity) GetMinProtocolVersion() uint32 {
+	if m != nil {
+		return m.MinProtocolVersion
+	}
+	return 0
+}
+
+func (m *NetworkIdentity) GetAttestationEnabled() bool {
+	if m != nil {
+		return m.AttestationEnabled
+	}
+	return false
+}
+
+func init() {
+	proto.RegisterType((*NetworkIdentity)(nil), "proto.NetworkIdentity")
+}
+
+func init() { proto.RegisterFile("networkIdentity.proto", fileDescriptor_4a60b4d13bea2798) }
+
+var fileDescriptor_4a60b4d13bea2798 = []byte{
+	// 343 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x90, 0xcf, 0x4a, 0xeb, 0x40,
+	0x14, 0xc6, 0x33, 0xed, 0x6d, 0x7b, 0xef, 0x94, 0x12, 0x98, 0xdb, 0x7b, 0x09, 0x2e, 0x4e, 0x82,
+	0x20, 0x64, 0x63, 0x8b, 0xba, 0x76, 0xd1, 0x88, 0x42, 0xc1, 0x8a, 0x84, 0xe2, 0xc2, 0x5d, 0x92,
+	0x8e, 0x69, 0xb0, 0x9d, 0x09, 0xcd, 0x14, 0xe9, 0xce, 0x47, 0xf0, 0x31, 0x7c, 0x14, 0x97, 0x5d,
+	0x76, 0x15, 0xec, 0x74, 0x23, 0x59, 0x75, 0xe1, 0x03, 0x88, 0x93, 0x8a, 0xf6, 0xcf, 0x6a, 0xe6,
+	0x7c, 0xbf, 0xef, 0xfb, 0xe0, 0x1c, 0xfc, 0x8f, 0x51, 0xf1, 0xc0, 0x47, 0xf7, 0xed, 0x1e, 0x65,
+	0x22, 0x12, 0x93, 0x46, 0x3c, 0xe2, 0x82, 0x93, 0x92, 0x7a, 0xf6, 0x0e, 0xc3, 0x48, 0xf4, 0xc7,
+	0x7e, 0x23, 0xe0, 0xc3, 0x66, 0xc8, 0x43, 0xde, 0x54, 0xb2, 0x3f, 0xbe, 0x53, 0x93, 0x1a, 0xd4,
+	0x2f, 0x4f, 0xed, 0xbf, 0x17, 0xb0, 0x7e, 0xb5, 0xde, 0x47, 0x0e, 0x70, 0xe5, 0xac, 0xef, 0x45,
+	0xac, 0xdd, 0x33, 0x90, 0x85, 0xec, 0x3f, 0x4e, 0x35, 0x4b, 0xcd, 0x4a, 0x90, 0x4b, 0xee, 0x17,
+	0x23, 0x47, 0xb8, 0xba, 0x4a, 0x76, 0x27, 0x31, 0x35, 0x0a, 0xca, 0xaa, 0x67, 0xa9, 0x59, 0x65,
+	0xdf, 0xb2, 0xfb, 0xd3, 0x43, 0x2e, 0x71, 0xbd, 0xcb, 0xe3, 0x28, 0xe8, 0xd0, 0x24, 0xf1, 0x42,
+	0x7a, 0x43, 0x47, 0x49, 0xc4, 0x59, 0x62, 0x14, 0xad, 0xa2, 0x5d, 0x73, 0x8c, 0x2c, 0x35, 0xeb,
+	0x62, 0x07, 0x77, 0x77, 0xa6, 0xc8, 0x29, 0xd6, 0xaf, 0x3f, 0x97, 0x08, 0xf8, 0x60, 0xa5, 0x19,
+	0xbf, 0x2c, 0x64, 0xd7, 0x9c, 0xbf, 0x59, 0x6a, 0xea, 0xf1, 0x3a, 0x72, 0x37, 0xbd, 0xe4, 0x02,
+	0x93, 0x4e, 0xc4, 0x36, 0x1b, 0x4a, 0xaa, 0xe1, 0x7f, 0x96, 0x9a, 0x64, 0xb8, 0x45, 0xdd, 0x1d,
+	0x09, 0x72, 0x8c, 0x49, 0x4b, 0x08, 0x9a, 0x08, 0x4f, 0x44, 0x9c, 0x9d, 0x33, 0xcf, 0x1f, 0xd0,
+	0x9e, 0x51, 0xb6, 0x90, 0xfd, 0x3b, 0xef, 0xf1, 0xb6, 0xa8, 0xd3, 0x9a, 0xce, 0x41, 0x9b, 0xcd,
+	0x41, 0x5b, 0xce, 0x01, 0x3d, 0x4a, 0x40, 0xcf, 0x12, 0xd0, 0x8b, 0x04, 0x34, 0x95, 0x80, 0x66,
+	0x12, 0xd0, 0xab, 0x04, 0xf4, 0x26, 0x41, 0x5b, 0x4a, 0x40, 0x4f, 0x0b, 0xd0, 0xa6, 0x0b, 0xd0,
+	0x66, 0x0b, 0xd0, 0x6e, 0x2b, 0xc3, 0xfc, 0x0a, 0x7e, 0x59, 0x2d, 0x78, 0xf2, 0x31, 0x00, 0x9b,
+	0xe3, 0x0b, 0x60, 0x0f, 0x02, 0x00, 0x00,
+}
+
+func (this *NetworkIdentity) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*NetworkIdentity)
+	if !ok {
+		that2, ok := that.(NetworkIdentity)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.ChainId != that1.ChainId {
+		return false
+	}
+	if this.NetworkType != that1.NetworkType {
+		return false
+	}
+	if len(this.TopicMessageVersions) != len(that1.TopicMessageVersions) {
+		return false
+	}
+	for i := range this.TopicMessageVersions {
+		if this.TopicMessageVersions[i] != that1.TopicMessageVersions[i] {
+			return false
+		}
+	}
+	if this.ProtocolVersion != that1.ProtocolVersion {
+		return false
+	}
+	if this.MinProtocolVersion != that1.MinProtocolVersion {
+		return false
+	}
+	if this.AttestationEnabled != that1.AttestationEnabled {
+		return false
+	}
+	return true
+}
+func (this *NetworkIdentity) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 10)
+	s = append(s, "&message.NetworkIdentity{")
+	s = append(s, "ChainId: "+fmt.Sprintf("%#v", this.ChainId)+",\n")
+	s = append(s, "NetworkType: "+fmt.Sprintf("%#v", this.NetworkType)+",\n")
+	s = append(s, "TopicMessageVersions: "+fmt.Sprintf("%#v", this.TopicMessageVersions)+",\n")
+	s = append(s, "ProtocolVersion: "+fmt.Sprintf("%#v", this.ProtocolVersion)+",\n")
+	s = append(s, "MinProtocolVersion: "+fmt.Sprintf("%#v", this.MinProtocolVersion)+",\n")
+	s = append(s, "AttestationEnabled: "+fmt.Sprintf("%#v", this.AttestationEnabled)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func valueToGoStringNetworkIdentity(v interface{}, typ string) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+}
+func (m *NetworkIdentity) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NetworkIdentity) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *NetworkIdentity) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.AttestationEnabled {
+		i--
+		if m.AttestationEnabled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.MinProtocolVersion != 0 {
+		i = encodeVarintNetworkIdentity(dAtA, i, uint64(m.MinProtocolVersion))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.ProtocolVersion != 0 {
+		i = encodeVarintNetworkIdentity(dAtA, i, uint64(m.ProtocolVersion))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.TopicMessageVersions) > 0 {
+		dAtA2 := make([]byte, len(m.TopicMessageVersions)*10)
+		var j1 int
+		for _, num := range m.TopicMessageVersions {
+			for num >= 1<<7 {
+				dAtA2[j1] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j1++
+			}
+			dAtA2[j1] = uint8(num)
+			j1++
+		}
+		i -= j1
+		copy(dAtA[i:], dAtA2[:j1])
+		i = encodeVarintNetworkIdentity(dAtA, i, uint64(j1))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.NetworkType) > 0 {
+		i -= len(m.NetworkType)
+		copy(dAtA[i:], m.NetworkType)
+		i = encodeVarintNetworkIdentity(dAtA, i, uint64(len(m.NetworkType)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.ChainId) > 0 {
+		i -= len(m.ChainId)
+		copy(dAtA[i:], m.ChainId)
+		i = encodeVarintNetworkIdentity(dAtA, i, uint64(len(m.ChainId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintNetworkIdentity(dAtA []byte, offset int, v uint64) int {
+	offset -= sovNetworkIdentity(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *NetworkIdentity) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ChainId)
+	if l > 0 {
+		n += 1 + l + sovNetworkIdentity(uint64(l))
+	}
+	l = len(m.NetworkType)
+	if l > 0 {
+		n += 1 + l + sovNetworkIdentity(uint64(l))
+	}
+	if len(m.TopicMessageVersions) > 0 {
+		l = 0
+		for _, e := range m.TopicMessageVersions {
+			l += sovNetworkIdentity(uint64(e))
+		}
+		n += 1 + sovNetworkIdentity(uint64(l)) + l
+	}
+	if m.ProtocolVersion != 0 {
+		n += 1 + sovNetworkIdentity(uint64(m.ProtocolVersion))
+	}
+	if m.MinProtocolVersion != 0 {
+		n += 1 + sovNetworkIdentity(uint64(m.MinProtocolVersion))
+	}
+	if m.AttestationEnabled {
+		n += 2
+	}
+	return n
+}
+
+func sovNetworkIdentity(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozNetworkIdentity(x uint64) (n int) {
+	return sovNetworkIdentity(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *NetworkIdentity) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&NetworkIdentity{`,
+		`ChainId:` + fmt.Sprintf("%v", this.ChainId) + `,`,
+		`NetworkType:` + fmt.Sprintf("%v", this.NetworkType) + `,`,
+		`TopicMessageVersions:` + fmt.Sprintf("%v", this.TopicMessageVersions) + `,`,
+		`ProtocolVersion:` + fmt.Sprintf("%v", this.ProtocolVersion) + `,`,
+		`MinProtocolVersion:` + fmt.Sprintf("%v", this.MinProtocolVersion) + `,`,
+		`AttestationEnabled:` + fmt.Sprintf("%v", this.AttestationEnabled) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringNetworkIdentity(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *NetworkIdentity) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNetworkIdentity
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NetworkIdentity: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NetworkIdentity: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ChainId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNetworkIdentity
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNetworkIdentity
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthNetworkIdentity
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ChainId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NetworkType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNetworkIdentity
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNetworkIdentity
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthNetworkIdentity
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NetworkType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType == 0 {
+				var v uint32
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowNetworkIdentity
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= uint32(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.TopicMessageVersions = append(m.TopicMessageVersions, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowNetworkIdentity
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthNetworkIdentity
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthNetworkIdentity
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.TopicMessageVersions) == 0 {
+					m.TopicMessageVersions = make([]uint32, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint32
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowNetworkIdentity
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= uint32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.TopicMessageVersions = append(m.TopicMessageVersions, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field TopicMessageVersions", wireType)
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProtocolVersion", wireType)
+			}
+			m.ProtocolVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNetworkIdentity
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ProtocolVersion |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinProtocolVersion", wireType)
+			}
+			m.MinProtocolVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNetworkIdentity
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MinProtocolVersion |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AttestationEnabled", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNetworkIdentity
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.AttestationEnabled = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNetworkIdentity(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNetworkIdentity
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthNetworkIdentity
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipNetworkIdentity(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowNetworkIdentity
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowNetworkIdentity
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowNetworkIdentity
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthNetworkIdentity
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupNetworkIdentity
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthNetworkIdentity
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthNetworkIdentity        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowNetworkIdentity          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupNetworkIdentity = fmt.Errorf("proto: unexpected end of group")
+)
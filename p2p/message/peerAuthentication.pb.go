@@ -0,0 +1,1028 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: peerAuthentication.proto
+
+package message
+
+import (
+	bytes "bytes"
+	fmt "fmt"
+	_ "github.com/gogo/protobuf/gogoproto"
+	proto "github.com/gogo/protobuf/proto"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+	reflect "reflect"
+	strings "strings"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+// PeerAuthenticationPayload represents the statement a peer makes about itself after a connection has been made
+type PeerAuthenticationPayload struct {
+	Pid            []byte     `protobuf:"bytes,1,opt,name=Pid,proto3" json:"pid"`
+	PeerShard      *PeerShard `protobuf:"bytes,2,opt,name=PeerShard,proto3" json:"peerShard"`
+	PeerType       uint32     `protobuf:"varint,3,opt,name=PeerType,proto3" json:"peerType"`
+	PeerSubType    uint32     `protobuf:"varint,4,opt,name=PeerSubType,proto3" json:"peerSubType"`
+	NodeVersion    string     `protobuf:"bytes,5,opt,name=NodeVersion,proto3" json:"nodeVersion"`
+	Timestamp      int64      `protobuf:"varint,6,opt,name=Timestamp,proto3" json:"timestamp"`
+	Pk             []byte     `protobuf:"bytes,7,opt,name=Pk,proto3" json:"pk"`
+	SignatureOnPid []byte     `protobuf:"bytes,8,opt,name=SignatureOnPid,proto3" json:"signatureOnPid"`
+}
+
+func (m *PeerAuthenticationPayload) Reset()      { *m = PeerAuthenticationPayload{} }
+func (*PeerAuthenticationPayload) ProtoMessage() {}
+func (*PeerAuthenticationPayload) Descriptor() ([]byte, []int) {
+	return fileDescriptor_999a4db3e7e23700, []int{0}
+}
+func (m *PeerAuthenticationPayload) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PeerAuthenticationPayload) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *PeerAuthenticationPayload) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerAuthenticationPayload.Merge(m, src)
+}
+func (m *PeerAuthenticationPayload) XXX_Size() int {
+	return m.Size()
+}
+func (m *PeerAuthenticationPayload) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerAuthenticationPayload.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerAuthenticationPayload proto.InternalMessageInfo
+
+func (m *PeerAuthenticationPayload) GetPid() []byte {
+	if m != nil {
+		return m.Pid
+	}
+	return nil
+}
+
+func (m *PeerAuthenticationPayload) GetPeerShard() *PeerShard {
+	if m != nil {
+		return m.PeerShard
+	}
+	return nil
+}
+
+func (m *PeerAuthenticationPayload) GetPeerType() uint32 {
+	if m != nil {
+		return m.PeerType
+	}
+	return 0
+}
+
+func (m *PeerAuthenticationPayload) GetPeerSubType() uint32 {
+	if m != nil {
+		return m.PeerSubType
+	}
+	return 0
+}
+
+func (m *PeerAuthenticationPayload) GetNodeVersion() string {
+	if m != nil {
+		return m.NodeVersion
+	}
+	return ""
+}
+
+func (m *PeerAuthenticationPayload) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *PeerAuthenticationPayload) GetPk() []byte {
+	if m != nil {
+		return m.Pk
+	}
+	return nil
+}
+
+func (m *PeerAuthenticationPayload) GetSignatureOnPid() []byte {
+	if m != nil {
+		return m.SignatureOnPid
+	}
+	return nil
+}
+
+// PeerAuthentication represents the signed statement exchanged by 2 peers during the authentication
+type PeerAuthentication struct {
+	Payload   []byte `protobuf:"bytes,1,opt,name=Payload,proto3" json:"payload"`
+	Signature []byte `protobuf:"bytes,2,opt,name=Signature,proto3" json:"signature"`
+}
+
+func (m *PeerAuthentication) Reset()      { *m = PeerAuthentication{} }
+func (*PeerAuthentication) ProtoMessage() {}
+func (*PeerAuthentication) Descriptor() ([]byte, []int) {
+	return fileDescriptor_999a4db3e7e23700, []int{1}
+}
+func (m *PeerAuthentication) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PeerAuthentication) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *PeerAuthentication) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerAuthentication.Merge(m, src)
+}
+func (m *PeerAuthentication) XXX_Size() int {
+	return m.Size()
+}
+func (m *PeerAuthentication) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerAuthentication.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerAuthentication proto.InternalMessageInfo
+
+func (m *PeerAuthentication) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *PeerAuthentication) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*PeerAuthenticationPayload)(nil), "proto.PeerAuthenticationPayload")
+	proto.RegisterType((*PeerAuthentication)(nil), "proto.PeerAuthentication")
+}
+
+func init() { proto.RegisterFile("peerAuthentication.proto", fileDescriptor_999a4db3e7e23700) }
+
+var fileDescriptor_999a4db3e7e23700 = []byte{
+	// 425 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x52, 0x3f, 0x6f, 0xd3, 0x40,
+	0x14, 0xf7, 0xc5, 0x34, 0x4e, 0x2e, 0x69, 0x41, 0x37, 0x54, 0x6e, 0x87, 0xb3, 0x55, 0x09, 0xc9,
+	0x12, 0x22, 0x15, 0xb0, 0x21, 0x31, 0x34, 0x3b, 0x60, 0x5d, 0x2b, 0x06, 0x36, 0xbb, 0x3e, 0xec,
+	0x53, 0xb0, 0xef, 0x64, 0x9f, 0x87, 0x6e, 0x4c, 0xcc, 0x7c, 0x0c, 0x3e, 0x0a, 0x63, 0xc6, 0x4c,
+	0x16, 0xb9, 0x2c, 0xc8, 0x53, 0x3f, 0x02, 0xf2, 0x5d, 0x62, 0x17, 0x3a, 0xd9, 0xef, 0xf7, 0xe7,
+	0xbd, 0xa7, 0xf7, 0x3b, 0xe8, 0x0a, 0x4a, 0xcb, 0xab, 0x5a, 0x66, 0xb4, 0x90, 0xec, 0x36, 0x92,
+	0x8c, 0x17, 0x0b, 0x51, 0x72, 0xc9, 0xd1, 0x91, 0xfe, 0x9c, 0xbf, 0x4c, 0x99, 0xcc, 0xea, 0x78,
+	0x71, 0xcb, 0xf3, 0xcb, 0x94, 0xa7, 0xfc, 0x52, 0xc3, 0x71, 0xfd, 0x45, 0x57, 0xba, 0xd0, 0x7f,
+	0xc6, 0x75, 0x7e, 0xda, 0xf5, 0xbb, 0xce, 0xa2, 0x32, 0x79, 0x4f, 0xab, 0x2a, 0x4a, 0xa9, 0xc1,
+	0x2f, 0xbe, 0xdb, 0xf0, 0x2c, 0x7c, 0x34, 0x2a, 0x8c, 0xee, 0xbe, 0xf2, 0x28, 0x41, 0x67, 0xd0,
+	0x0e, 0x59, 0xe2, 0x02, 0x1f, 0x04, 0xf3, 0xa5, 0xd3, 0x36, 0x9e, 0x2d, 0x58, 0x42, 0x3a, 0x0c,
+	0xbd, 0x83, 0xd3, 0xf0, 0xd0, 0xd2, 0x1d, 0xf9, 0x20, 0x98, 0xbd, 0x7e, 0x66, 0x7a, 0x2e, 0x7a,
+	0x7c, 0x79, 0xdc, 0x36, 0xde, 0xb4, 0x9f, 0x4c, 0x06, 0x07, 0x0a, 0xe0, 0xa4, 0x2b, 0x6e, 0xee,
+	0x04, 0x75, 0x6d, 0x1f, 0x04, 0xc7, 0xcb, 0x79, 0xdb, 0x78, 0x13, 0xb1, 0xc7, 0x48, 0xcf, 0xa2,
+	0x57, 0x70, 0xa6, 0x6d, 0x75, 0xac, 0xc5, 0x4f, 0xb4, 0xf8, 0x69, 0xdb, 0x78, 0x33, 0x31, 0xc0,
+	0xe4, 0xa1, 0xa6, 0xb3, 0x7c, 0xe0, 0x09, 0xfd, 0x44, 0xcb, 0x8a, 0xf1, 0xc2, 0x3d, 0xf2, 0x41,
+	0x30, 0x35, 0x96, 0x62, 0x80, 0xc9, 0x43, 0x0d, 0x7a, 0x01, 0xa7, 0x37, 0x2c, 0xa7, 0x95, 0x8c,
+	0x72, 0xe1, 0x8e, 0x7d, 0x10, 0xd8, 0x66, 0x79, 0x79, 0x00, 0xc9, 0xc0, 0xa3, 0x53, 0x38, 0x0a,
+	0x57, 0xae, 0xa3, 0xaf, 0x32, 0x6e, 0x1b, 0x6f, 0x24, 0x56, 0x64, 0x14, 0xae, 0xd0, 0x5b, 0x78,
+	0x72, 0xcd, 0xd2, 0x22, 0x92, 0x75, 0x49, 0x3f, 0x16, 0xdd, 0xe5, 0x26, 0x5a, 0x83, 0xda, 0xc6,
+	0x3b, 0xa9, 0xfe, 0x61, 0xc8, 0x7f, 0xca, 0x8b, 0x0c, 0xa2, 0xc7, 0x39, 0xa0, 0xe7, 0xd0, 0xd9,
+	0x67, 0xb1, 0x0f, 0x61, 0xd6, 0x36, 0x9e, 0x23, 0x0c, 0x44, 0x0e, 0x5c, 0xb7, 0x7d, 0xdf, 0x4e,
+	0x87, 0x31, 0x37, 0xdb, 0xf7, 0x33, 0xc9, 0xc0, 0x2f, 0xaf, 0xd6, 0x5b, 0x6c, 0x6d, 0xb6, 0xd8,
+	0xba, 0xdf, 0x62, 0xf0, 0x4d, 0x61, 0xf0, 0x53, 0x61, 0xf0, 0x4b, 0x61, 0xb0, 0x56, 0x18, 0x6c,
+	0x14, 0x06, 0xbf, 0x15, 0x06, 0x7f, 0x14, 0xb6, 0xee, 0x15, 0x06, 0x3f, 0x76, 0xd8, 0x5a, 0xef,
+	0xb0, 0xb5, 0xd9, 0x61, 0xeb, 0xb3, 0x93, 0x9b, 0xb7, 0x13, 0x8f, 0x75, 0xd0, 0x6f, 0xfe, 0x0e,
+	0x00, 0xf8, 0xb8, 0x84, 0x3f, 0xa6, 0x02, 0x00, 0x00,
+}
+
+func (this *PeerAuthenticationPayload) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*PeerAuthenticationPayload)
+	if !ok {
+		that2, ok := that.(PeerAuthenticationPayload)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.Pid, that1.Pid) {
+		return false
+	}
+	if !this.PeerShard.Equal(that1.PeerShard) {
+		return false
+	}
+	if this.PeerType != that1.PeerType {
+		return false
+	}
+	if this.PeerSubType != that1.PeerSubType {
+		return false
+	}
+	if this.NodeVersion != that1.NodeVersion {
+		return false
+	}
+	if this.Timestamp != that1.Timestamp {
+		return false
+	}
+	if !bytes.Equal(this.Pk, that1.Pk) {
+		return false
+	}
+	if !bytes.Equal(this.SignatureOnPid, that1.SignatureOnPid) {
+		return false
+	}
+	return true
+}
+func (this *PeerAuthentication) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*PeerAuthentication)
+	if !ok {
+		that2, ok := that.(PeerAuthentication)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.Payload, that1.Payload) {
+		return false
+	}
+	if !bytes.Equal(this.Signature, that1.Signature) {
+		return false
+	}
+	return true
+}
+func (this *PeerAuthenticationPayload) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 12)
+	s = append(s, "&message.PeerAuthenticationPayload{")
+	s = append(s, "Pid: "+fmt.Sprintf("%#v", this.Pid)+",\n")
+	if this.PeerShard != nil {
+		s = append(s, "PeerShard: "+fmt.Sprintf("%#v", this.PeerShard)+",\n")
+	}
+	s = append(s, "PeerType: "+fmt.Sprintf("%#v", this.PeerType)+",\n")
+	s = append(s, "PeerSubType: "+fmt.Sprintf("%#v", this.PeerSubType)+",\n")
+	s = append(s, "NodeVersion: "+fmt.Sprintf("%#v", this.NodeVersion)+",\n")
+	s = append(s, "Timestamp: "+fmt.Sprintf("%#v", this.Timestamp)+",\n")
+	s = append(s, "Pk: "+fmt.Sprintf("%#v", this.Pk)+",\n")
+	s = append(s, "SignatureOnPid: "+fmt.Sprintf("%#v", this.SignatureOnPid)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PeerAuthentication) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&message.PeerAuthentication{")
+	s = append(s, "Payload: "+fmt.Sprintf("%#v", this.Payload)+",\n")
+	s = append(s, "Signature: "+fmt.Sprintf("%#v", this.Signature)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func valueToGoStringPeerAuthentication(v interface{}, typ string) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+}
+func (m *PeerAuthenticationPayload) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PeerAuthenticationPayload) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PeerAuthenticationPayload) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.SignatureOnPid) > 0 {
+		i -= len(m.SignatureOnPid)
+		copy(dAtA[i:], m.SignatureOnPid)
+		i = encodeVarintPeerAuthentication(dAtA, i, uint64(len(m.SignatureOnPid)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.Pk) > 0 {
+		i -= len(m.Pk)
+		copy(dAtA[i:], m.Pk)
+		i = encodeVarintPeerAuthentication(dAtA, i, uint64(len(m.Pk)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.Timestamp != 0 {
+		i = encodeVarintPeerAuthentication(dAtA, i, uint64(m.Timestamp))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.NodeVersion) > 0 {
+		i -= len(m.NodeVersion)
+		copy(dAtA[i:], m.NodeVersion)
+		i = encodeVarintPeerAuthentication(dAtA, i, uint64(len(m.NodeVersion)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.PeerSubType != 0 {
+		i = encodeVarintPeerAuthentication(dAtA, i, uint64(m.PeerSubType))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.PeerType != 0 {
+		i = encodeVarintPeerAuthentication(dAtA, i, uint64(m.PeerType))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.PeerShard != nil {
+		{
+			size, err := m.PeerShard.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintPeerAuthentication(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Pid) > 0 {
+		i -= len(m.Pid)
+		copy(dAtA[i:], m.Pid)
+		i = encodeVarintPeerAuthentication(dAtA, i, uint64(len(m.Pid)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PeerAuthentication) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PeerAuthentication) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PeerAuthentication) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Signature) > 0 {
+		i -= len(m.Signature)
+		copy(dAtA[i:], m.Signature)
+		i = encodeVarintPeerAuthentication(dAtA, i, uint64(len(m.Signature)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Payload) > 0 {
+		i -= len(m.Payload)
+		copy(dAtA[i:], m.Payload)
+		i = encodeVarintPeerAuthentication(dAtA, i, uint64(len(m.Payload)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintPeerAuthentication(dAtA []byte, offset int, v uint64) int {
+	offset -= sovPeerAuthentication(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *PeerAuthenticationPayload) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Pid)
+	if l > 0 {
+		n += 1 + l + sovPeerAuthentication(uint64(l))
+	}
+	if m.PeerShard != nil {
+		l = m.PeerShard.Size()
+		n += 1 + l + sovPeerAuthentication(uint64(l))
+	}
+	if m.PeerType != 0 {
+		n += 1 + sovPeerAuthentication(uint64(m.PeerType))
+	}
+	if m.PeerSubType != 0 {
+		n += 1 + sovPeerAuthentication(uint64(m.PeerSubType))
+	}
+	l = len(m.NodeVersion)
+	if l > 0 {
+		n += 1 + l + sovPeerAuthentication(uint64(l))
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovPeerAuthentication(uint64(m.Timestamp))
+	}
+	l = len(m.Pk)
+	if l > 0 {
+		n += 1 + l + sovPeerAuthentication(uint64(l))
+	}
+	l = len(m.SignatureOnPid)
+	if l > 0 {
+		n += 1 + l + sovPeerAuthentication(uint64(l))
+	}
+	return n
+}
+
+func (m *PeerAuthentication) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Payload)
+	if l > 0 {
+		n += 1 + l + sovPeerAuthentication(uint64(l))
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovPeerAuthentication(uint64(l))
+	}
+	return n
+}
+
+func sovPeerAuthentication(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozPeerAuthentication(x uint64) (n int) {
+	return sovPeerAuthentication(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *PeerAuthenticationPayload) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PeerAuthenticationPayload{`,
+		`Pid:` + fmt.Sprintf("%v", this.Pid) + `,`,
+		`PeerShard:` + strings.Replace(fmt.Sprintf("%v", this.PeerShard), "PeerShard", "PeerShard", 1) + `,`,
+		`PeerType:` + fmt.Sprintf("%v", this.PeerType) + `,`,
+		`PeerSubType:` + fmt.Sprintf("%v", this.PeerSubType) + `,`,
+		`NodeVersion:` + fmt.Sprintf("%v", this.NodeVersion) + `,`,
+		`Timestamp:` + fmt.Sprintf("%v", this.Timestamp) + `,`,
+		`Pk:` + fmt.Sprintf("%v", this.Pk) + `,`,
+		`SignatureOnPid:` + fmt.Sprintf("%v", this.SignatureOnPid) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PeerAuthentication) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PeerAuthentication{`,
+		`Payload:` + fmt.Sprintf("%v", this.Payload) + `,`,
+		`Signature:` + fmt.Sprintf("%v", this.Signature) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringPeerAuthentication(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *PeerAuthenticationPayload) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPeerAuthentication
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PeerAuthenticationPayload: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PeerAuthenticationPayload: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pid", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeerAuthentication
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthPeerAuthentication
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPeerAuthentication
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Pid = append(m.Pid[:0], dAtA[iNdEx:postIndex]...)
+			if m.Pid == nil {
+				m.Pid = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PeerShard", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeerAuthentication
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPeerAuthentication
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPeerAuthentication
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.PeerShard == nil {
+				m.PeerShard = &PeerShard{}
+			}
+			if err := m.PeerShard.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PeerType", wireType)
+			}
+			m.PeerType = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeerAuthentication
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PeerType |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PeerSubType", wireType)
+			}
+			m.PeerSubType = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeerAuthentication
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PeerSubType |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NodeVersion", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeerAuthentication
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPeerAuthentication
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPeerAuthentication
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NodeVersion = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeerAuthentication
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pk", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeerAuthentication
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthPeerAuthentication
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPeerAuthentication
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Pk = append(m.Pk[:0], dAtA[iNdEx:postIndex]...)
+			if m.Pk == nil {
+				m.Pk = []byte{}
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SignatureOnPid", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeerAuthentication
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthPeerAuthentication
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPeerAuthentication
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SignatureOnPid = append(m.SignatureOnPid[:0], dAtA[iNdEx:postIndex]...)
+			if m.SignatureOnPid == nil {
+				m.SignatureOnPid = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPeerAuthentication(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPeerAuthentication
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPeerAuthentication
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PeerAuthentication) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPeerAuthentication
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PeerAuthentication: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PeerAuthentication: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeerAuthentication
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthPeerAuthentication
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPeerAuthentication
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
+			if m.Payload == nil {
+				m.Payload = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeerAuthentication
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthPeerAuthentication
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPeerAuthentication
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPeerAuthentication(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPeerAuthentication
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPeerAuthentication
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipPeerAuthentication(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowPeerAuthentication
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowPeerAuthentication
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowPeerAuthentication
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthPeerAuthentication
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupPeerAuthentication
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthPeerAuthentication
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthPeerAuthentication        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowPeerAuthentication          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupPeerAuthentication = fmt.Errorf("proto: unexpected end of group")
+)
@@ -0,0 +1,39 @@
+package messagecheck
+
+import (
+	"fmt"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+)
+
+// EvidenceType defines the misbehavior proven by an evidence
+type EvidenceType uint32
+
+const (
+	// EquivocationEvidence proves that a peer signed 2 different messages using the same sequence number
+	EquivocationEvidence EvidenceType = 1
+
+	// InvalidContentEvidence proves that a peer signed a message with an invalid content
+	InvalidContentEvidence EvidenceType = 2
+)
+
+// String returns the human readable name of the evidence type
+func (et EvidenceType) String() string {
+	switch et {
+	case EquivocationEvidence:
+		return "equivocation"
+	case InvalidContentEvidence:
+		return "invalid content"
+	default:
+		return fmt.Sprintf("unknown evidence type %d", uint32(et))
+	}
+}
+
+// Evidence is a verified proof that a peer misbehaved. The messages are signed by the offender
+type Evidence struct {
+	Type     EvidenceType
+	Offender core.PeerID
+	Messages []p2p.MessageP2P
+	Reason   string
+}
@@ -0,0 +1,438 @@
+package messagecheck
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/message"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+	"github.com/TerraDharitri/drt-go-chain-core/core/check"
+	"github.com/TerraDharitri/drt-go-chain-core/marshal"
+	"github.com/whyrusleeping/timecache"
+)
+
+const evidenceProcessorIdentifier = "misbehavior evidence processor"
+const seenEvidencesTTL = time.Hour
+
+type observedMessage struct {
+	msg       p2p.MessageP2P
+	timestamp time.Time
+}
+
+// ArgsEvidenceProcessor defines the arguments needed to create an evidenceProcessor
+type ArgsEvidenceProcessor struct {
+	MessageVerifier MessageVerifier
+	Marshaller      marshal.Marshalizer
+	EvidenceHandler EvidenceHandler
+	Logger          p2p.Logger
+	// ObservedMessagesTTL is the time an observed message is kept in order to detect the equivocations
+	ObservedMessagesTTL time.Duration
+	// MaxObservedMessages is the maximum number of observed messages kept. The oldest ones are removed first
+	MaxObservedMessages int
+	// EvidenceTopic, if set, is the topic on which the evidences are distributed. The Messenger is mandatory in
+	// this case as the processor creates the topic and registers itself as the topic's message processor
+	EvidenceTopic string
+	Messenger     evidenceMessenger
+}
+
+type evidenceProcessor struct {
+	messageVerifier     MessageVerifier
+	marshaller          marshal.Marshalizer
+	evidenceHandler     EvidenceHandler
+	log                 p2p.Logger
+	observedMessagesTTL time.Duration
+	maxObservedMessages int
+	evidenceTopic       string
+	messenger           evidenceMessenger
+
+	mutObservedMessages  sync.Mutex
+	observedMessages     map[string]*observedMessage
+	observedMessagesKeys []string
+	mutContentValidators sync.RWMutex
+	contentValidators    map[string]ContentValidator
+	mutSeenEvidences     sync.Mutex
+	seenEvidences        *timecache.TimeCache
+}
+
+// NewEvidenceProcessor creates the component that detects the misbehaving peers, packages the signed messages proving
+// the misbehavior as evidences and verifies the evidences received from other nodes
+func NewEvidenceProcessor(args ArgsEvidenceProcessor) (*evidenceProcessor, error) {
+	err := checkArgsEvidenceProcessor(args)
+	if err != nil {
+		return nil, err
+	}
+
+	ep := &evidenceProcessor{
+		messageVerifier:     args.MessageVerifier,
+		marshaller:          args.Marshaller,
+		evidenceHandler:     args.EvidenceHandler,
+		log:                 args.Logger,
+		observedMessagesTTL: args.ObservedMessagesTTL,
+		maxObservedMessages: args.MaxObservedMessages,
+		evidenceTopic:       args.EvidenceTopic,
+		messenger:           args.Messenger,
+		observedMessages:    make(map[string]*observedMessage),
+		contentValidators:   make(map[string]ContentValidator),
+		seenEvidences:       timecache.NewTimeCache(seenEvidencesTTL),
+	}
+
+	if len(ep.evidenceTopic) == 0 {
+		return ep, nil
+	}
+
+	err = ep.messenger.CreateTopic(ep.evidenceTopic, true)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ep.messenger.RegisterMessageProcessor(ep.evidenceTopic, evidenceProcessorIdentifier, ep)
+	if err != nil {
+		return nil, err
+	}
+
+	return ep, nil
+}
+
+func checkArgsEvidenceProcessor(args ArgsEvidenceProcessor) error {
+	if check.IfNil(args.MessageVerifier) {
+		return p2p.ErrNilMessageVerifier
+	}
+	if check.IfNil(args.Marshaller) {
+		return p2p.ErrNilMarshaller
+	}
+	if check.IfNil(args.EvidenceHandler) {
+		return p2p.ErrNilEvidenceHandler
+	}
+	if check.IfNil(args.Logger) {
+		return p2p.ErrNilLogger
+	}
+	if args.ObservedMessagesTTL <= 0 {
+		return fmt.Errorf("%w for ObservedMessagesTTL", p2p.ErrInvalidValue)
+	}
+	if args.MaxObservedMessages <= 0 {
+		return fmt.Errorf("%w for MaxObservedMessages", p2p.ErrInvalidValue)
+	}
+	if len(args.EvidenceTopic) > 0 && check.IfNil(args.Messenger) {
+		return p2p.ErrNilMessenger
+	}
+
+	return nil
+}
+
+// RegisterContentValidator sets the validator used when verifying the invalid content evidences of a topic
+func (ep *evidenceProcessor) RegisterContentValidator(topic string, validator ContentValidator) error {
+	if check.IfNil(validator) {
+		return p2p.ErrNilContentValidator
+	}
+
+	ep.mutContentValidators.Lock()
+	ep.contentValidators[topic] = validator
+	ep.mutContentValidators.Unlock()
+
+	return nil
+}
+
+// ObserveMessage keeps track of the signed messages in order to detect the peers that sign different messages using
+// the same sequence number. The evidence is handled, and distributed if the evidence topic is set, when such a
+// message is observed. Only the messages with an already verified signature should be provided, as they are kept
+func (ep *evidenceProcessor) ObserveMessage(msg p2p.MessageP2P) error {
+	if check.IfNil(msg) {
+		return p2p.ErrNilMessage
+	}
+
+	previous, found := ep.getOrAddObservedMessage(msg)
+	if !found {
+		return nil
+	}
+
+	return ep.checkObservedMessage(previous, msg)
+}
+
+// ObserveDuplicateMessage compares the provided message with the kept message having the same originator and sequence
+// number. It is meant for the messages dropped by pubsub as duplicates, before their signature is verified, so the
+// provided message is never kept. The signatures are verified only when an equivocation is suspected
+func (ep *evidenceProcessor) ObserveDuplicateMessage(msg p2p.MessageP2P) error {
+	if check.IfNil(msg) {
+		return p2p.ErrNilMessage
+	}
+
+	previous, found := ep.getObservedMessage(msg)
+	if !found {
+		return nil
+	}
+
+	return ep.checkObservedMessage(previous, msg)
+}
+
+func (ep *evidenceProcessor) checkObservedMessage(previous p2p.MessageP2P, msg p2p.MessageP2P) error {
+	isSameMessage := bytes.Equal(previous.Payload(), msg.Payload()) && previous.Topic() == msg.Topic()
+	if isSameMessage {
+		return nil
+	}
+
+	evidenceBytes, err := ep.CreateEquivocationEvidence(previous, msg)
+	if err != nil {
+		return err
+	}
+
+	return ep.handleLocalEvidence(evidenceBytes)
+}
+
+func observedMessageKey(msg p2p.MessageP2P) string {
+	return string(msg.From()) + string(msg.SeqNo())
+}
+
+func (ep *evidenceProcessor) getObservedMessage(msg p2p.MessageP2P) (p2p.MessageP2P, bool) {
+	ep.mutObservedMessages.Lock()
+	defer ep.mutObservedMessages.Unlock()
+
+	observed, found := ep.observedMessages[observedMessageKey(msg)]
+	if !found || time.Since(observed.timestamp) > ep.observedMessagesTTL {
+		return nil, false
+	}
+
+	return observed.msg, true
+}
+
+func (ep *evidenceProcessor) getOrAddObservedMessage(msg p2p.MessageP2P) (p2p.MessageP2P, bool) {
+	key := observedMessageKey(msg)
+	now := time.Now()
+
+	ep.mutObservedMessages.Lock()
+	defer ep.mutObservedMessages.Unlock()
+
+	ep.removeObservedMessages(func(oldest *observedMessage) bool {
+		return now.Sub(oldest.timestamp) > ep.observedMessagesTTL
+	})
+
+	previous, found := ep.observedMessages[key]
+	if found {
+		return previous.msg, true
+	}
+
+	ep.removeObservedMessages(func(_ *observedMessage) bool {
+		return len(ep.observedMessagesKeys) >= ep.maxObservedMessages
+	})
+
+	ep.observedMessages[key] = &observedMessage{
+		msg:       msg,
+		timestamp: now,
+	}
+	ep.observedMessagesKeys = append(ep.observedMessagesKeys, key)
+
+	return nil, false
+}
+
+// removeObservedMessages removes the oldest observed messages while the provided condition holds
+func (ep *evidenceProcessor) removeObservedMessages(shouldRemove func(oldest *observedMessage) bool) {
+	for len(ep.observedMessagesKeys) > 0 {
+		oldestKey := ep.observedMessagesKeys[0]
+		if !shouldRemove(ep.observedMessages[oldestKey]) {
+			return
+		}
+
+		delete(ep.observedMessages, oldestKey)
+		ep.observedMessagesKeys = ep.observedMessagesKeys[1:]
+	}
+}
+
+// ReportInvalidContent packages the provided signed message as an invalid content evidence. The evidence is handled,
+// and distributed if the evidence topic is set, only if the content validator of the message's topic rejects it
+func (ep *evidenceProcessor) ReportInvalidContent(msg p2p.MessageP2P, reason string) error {
+	evidenceBytes, err := ep.CreateInvalidContentEvidence(msg, reason)
+	if err != nil {
+		return err
+	}
+
+	return ep.handleLocalEvidence(evidenceBytes)
+}
+
+// CreateEquivocationEvidence packages the provided signed messages as an equivocation evidence
+func (ep *evidenceProcessor) CreateEquivocationEvidence(first p2p.MessageP2P, second p2p.MessageP2P) ([]byte, error) {
+	if check.IfNil(first) || check.IfNil(second) {
+		return nil, p2p.ErrNilMessage
+	}
+
+	reason := fmt.Sprintf("different messages signed using the sequence number %x", first.SeqNo())
+
+	return ep.createEvidence(EquivocationEvidence, []p2p.MessageP2P{first, second}, reason)
+}
+
+// CreateInvalidContentEvidence packages the provided signed message as an invalid content evidence
+func (ep *evidenceProcessor) CreateInvalidContentEvidence(msg p2p.MessageP2P, reason string) ([]byte, error) {
+	if check.IfNil(msg) {
+		return nil, p2p.ErrNilMessage
+	}
+
+	return ep.createEvidence(InvalidContentEvidence, []p2p.MessageP2P{msg}, reason)
+}
+
+func (ep *evidenceProcessor) createEvidence(evidenceType EvidenceType, messages []p2p.MessageP2P, reason string) ([]byte, error) {
+	messagesBytes, err := ep.messageVerifier.Serialize(messages)
+	if err != nil {
+		return nil, err
+	}
+
+	evidence := &message.MisbehaviorEvidence{
+		Type:     uint32(evidenceType),
+		Offender: messages[0].From(),
+		Messages: messagesBytes,
+		Reason:   reason,
+	}
+
+	return ep.marshaller.Marshal(evidence)
+}
+
+// VerifyEvidence checks that the provided evidence proves the claimed misbehavior: all the messages should be signed
+// by the offender and should be an equivocation or have a content rejected by the topic's content validator
+func (ep *evidenceProcessor) VerifyEvidence(evidenceBytes []byte) (*Evidence, error) {
+	evidence := &message.MisbehaviorEvidence{}
+	err := ep.marshaller.Unmarshal(evidence, evidenceBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := ep.messageVerifier.Deserialize(evidence.Messages)
+	if err != nil {
+		return nil, err
+	}
+
+	offender := core.PeerID(evidence.Offender)
+	for _, msg := range messages {
+		if core.PeerID(msg.From()) != offender {
+			return nil, fmt.Errorf("%w, message not originated by the offender", p2p.ErrInvalidEvidence)
+		}
+
+		err = ep.messageVerifier.Verify(msg)
+		if err != nil {
+			return nil, fmt.Errorf("%w, invalid message signature: %s", p2p.ErrInvalidEvidence, err.Error())
+		}
+	}
+
+	evidenceType := EvidenceType(evidence.Type)
+	switch evidenceType {
+	case EquivocationEvidence:
+		err = checkEquivocation(messages)
+	case InvalidContentEvidence:
+		err = ep.checkInvalidContent(messages)
+	default:
+		err = fmt.Errorf("%w, %s", p2p.ErrInvalidEvidence, evidenceType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Evidence{
+		Type:     evidenceType,
+		Offender: offender,
+		Messages: messages,
+		Reason:   evidence.Reason,
+	}, nil
+}
+
+func checkEquivocation(messages []p2p.MessageP2P) error {
+	if len(messages) != 2 {
+		return fmt.Errorf("%w, an equivocation needs 2 messages, got %d", p2p.ErrInvalidEvidence, len(messages))
+	}
+
+	first, second := messages[0], messages[1]
+	if !bytes.Equal(first.SeqNo(), second.SeqNo()) {
+		return fmt.Errorf("%w, the messages have different sequence numbers", p2p.ErrInvalidEvidence)
+	}
+	if bytes.Equal(first.Payload(), second.Payload()) && first.Topic() == second.Topic() {
+		return fmt.Errorf("%w, the messages are identical", p2p.ErrInvalidEvidence)
+	}
+
+	return nil
+}
+
+func (ep *evidenceProcessor) checkInvalidContent(messages []p2p.MessageP2P) error {
+	if len(messages) != 1 {
+		return fmt.Errorf("%w, an invalid content needs 1 message, got %d", p2p.ErrInvalidEvidence, len(messages))
+	}
+
+	msg := messages[0]
+	ep.mutContentValidators.RLock()
+	validator, found := ep.contentValidators[msg.Topic()]
+	ep.mutContentValidators.RUnlock()
+	if !found {
+		return fmt.Errorf("%w, no content validator for topic %s", p2p.ErrInvalidEvidence, msg.Topic())
+	}
+
+	err := validator.ValidateContent(msg)
+	if err == nil {
+		return fmt.Errorf("%w, the message content is valid", p2p.ErrInvalidEvidence)
+	}
+
+	return nil
+}
+
+func (ep *evidenceProcessor) handleLocalEvidence(evidenceBytes []byte) error {
+	evidence, err := ep.VerifyEvidence(evidenceBytes)
+	if err != nil {
+		return err
+	}
+
+	if !ep.handleEvidence(evidence) {
+		return nil
+	}
+
+	if len(ep.evidenceTopic) > 0 {
+		ep.messenger.Broadcast(ep.evidenceTopic, evidenceBytes)
+	}
+
+	return nil
+}
+
+// handleEvidence passes the evidence to the evidence handler, if not already done, and returns true if it did
+func (ep *evidenceProcessor) handleEvidence(evidence *Evidence) bool {
+	key := fmt.Sprintf("%d%s%s", evidence.Type, evidence.Offender, evidence.Messages[0].SeqNo())
+
+	ep.mutSeenEvidences.Lock()
+	isSeen := ep.seenEvidences.Has(key)
+	if !isSeen {
+		ep.seenEvidences.Add(key)
+	}
+	ep.mutSeenEvidences.Unlock()
+	if isSeen {
+		return false
+	}
+
+	ep.log.Debug("misbehavior evidence",
+		"type", evidence.Type.String(),
+		"offender", evidence.Offender.Pretty(),
+		"reason", evidence.Reason,
+	)
+	ep.evidenceHandler.HandleEvidence(evidence)
+
+	return true
+}
+
+// ProcessReceivedMessage verifies the evidences received on the evidence topic and passes the valid ones to the
+// evidence handler. The invalid evidences are rejected so they are not propagated further
+func (ep *evidenceProcessor) ProcessReceivedMessage(msg p2p.MessageP2P, fromConnectedPeer core.PeerID, _ p2p.MessageHandler) error {
+	if check.IfNil(msg) {
+		return p2p.ErrNilMessage
+	}
+
+	evidence, err := ep.VerifyEvidence(msg.Data())
+	if err != nil {
+		ep.log.Debug("invalid misbehavior evidence received",
+			"from connected peer", fromConnectedPeer.Pretty(),
+			"error", err.Error(),
+		)
+		return err
+	}
+
+	ep.handleEvidence(evidence)
+
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ep *evidenceProcessor) IsInterfaceNil() bool {
+	return ep == nil
+}
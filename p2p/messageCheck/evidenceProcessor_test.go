@@ -0,0 +1,646 @@
+package messagecheck_test
+
+import (
+	"crypto/rand"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/data"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/message"
+	messagecheck "github.com/TerraDharitri/drt-go-chain-communication/p2p/messageCheck"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/mock"
+	"github.com/TerraDharitri/drt-go-chain-communication/testscommon"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+	"github.com/TerraDharitri/drt-go-chain-core/core/check"
+	"github.com/TerraDharitri/drt-go-chain-core/data/batch"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsubPb "github.com/libp2p/go-libp2p-pubsub/pb"
+	libp2pCrypto "github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const evidenceTestTopic = "topic"
+
+type evidenceHandlerStub struct {
+	mut       sync.Mutex
+	evidences []*messagecheck.Evidence
+}
+
+// HandleEvidence -
+func (stub *evidenceHandlerStub) HandleEvidence(evidence *messagecheck.Evidence) {
+	stub.mut.Lock()
+	stub.evidences = append(stub.evidences, evidence)
+	stub.mut.Unlock()
+}
+
+func (stub *evidenceHandlerStub) getEvidences() []*messagecheck.Evidence {
+	stub.mut.Lock()
+	defer stub.mut.Unlock()
+
+	return stub.evidences
+}
+
+// IsInterfaceNil -
+func (stub *evidenceHandlerStub) IsInterfaceNil() bool {
+	return stub == nil
+}
+
+type contentValidatorStub struct {
+	err error
+}
+
+// ValidateContent -
+func (stub *contentValidatorStub) ValidateContent(_ p2p.MessageP2P) error {
+	return stub.err
+}
+
+// IsInterfaceNil -
+func (stub *contentValidatorStub) IsInterfaceNil() bool {
+	return stub == nil
+}
+
+type evidenceMessengerStub struct {
+	createTopicCalled              func(name string, createChannelForTopic bool) error
+	registerMessageProcessorCalled func(topic string, identifier string, handler p2p.MessageProcessor) error
+	broadcastCalled                func(topic string, buff []byte)
+}
+
+// CreateTopic -
+func (stub *evidenceMessengerStub) CreateTopic(name string, createChannelForTopic bool) error {
+	if stub.createTopicCalled != nil {
+		return stub.createTopicCalled(name, createChannelForTopic)
+	}
+	return nil
+}
+
+// RegisterMessageProcessor -
+func (stub *evidenceMessengerStub) RegisterMessageProcessor(topic string, identifier string, handler p2p.MessageProcessor) error {
+	if stub.registerMessageProcessorCalled != nil {
+		return stub.registerMessageProcessorCalled(topic, identifier, handler)
+	}
+	return nil
+}
+
+// Broadcast -
+func (stub *evidenceMessengerStub) Broadcast(topic string, buff []byte) {
+	if stub.broadcastCalled != nil {
+		stub.broadcastCalled(topic, buff)
+	}
+}
+
+// IsInterfaceNil -
+func (stub *evidenceMessengerStub) IsInterfaceNil() bool {
+	return stub == nil
+}
+
+func createLibp2pSignerVerifier() *mock.P2PSignerStub {
+	return &mock.P2PSignerStub{
+		VerifyCalled: func(payload []byte, pid core.PeerID, signature []byte) error {
+			pk, err := peer.ID(pid).ExtractPublicKey()
+			if err != nil {
+				return err
+			}
+
+			isValid, err := pk.Verify(payload, signature)
+			if err != nil {
+				return err
+			}
+			if !isValid {
+				return errors.New("invalid signature")
+			}
+
+			return nil
+		},
+	}
+}
+
+func createEvidenceProcessorArgs() messagecheck.ArgsEvidenceProcessor {
+	mv, _ := messagecheck.NewMessageVerifier(messagecheck.ArgsMessageVerifier{
+		Marshaller: &testscommon.ProtoMarshallerMock{},
+		P2PSigner:  createLibp2pSignerVerifier(),
+		Logger:     &testscommon.LoggerStub{},
+	})
+
+	return messagecheck.ArgsEvidenceProcessor{
+		MessageVerifier:     mv,
+		Marshaller:          &testscommon.ProtoMarshallerMock{},
+		EvidenceHandler:     &evidenceHandlerStub{},
+		Logger:              &testscommon.LoggerStub{},
+		ObservedMessagesTTL: time.Minute,
+		MaxObservedMessages: 100,
+	}
+}
+
+func createSignedMessage(t *testing.T, sk libp2pCrypto.PrivKey, seqNo string, payload string) p2p.MessageP2P {
+	topicMessage := &data.TopicMessage{
+		Version:   1,
+		Payload:   []byte(payload),
+		Timestamp: time.Now().Unix(),
+	}
+
+	return createSignedTopicMessage(t, sk, seqNo, topicMessage)
+}
+
+func createSignedTopicMessage(t *testing.T, sk libp2pCrypto.PrivKey, seqNo string, topicMessage *data.TopicMessage) p2p.MessageP2P {
+	marshaller := &testscommon.ProtoMarshallerMock{}
+	topicMessageBytes, err := marshaller.Marshal(topicMessage)
+	require.Nil(t, err)
+
+	pid, _ := peer.IDFromPublicKey(sk.GetPublic())
+	topic := evidenceTestTopic
+	pbMessage := &pubsubPb.Message{
+		From:  []byte(pid),
+		Data:  topicMessageBytes,
+		Seqno: []byte(seqNo),
+		Topic: &topic,
+	}
+	buff, err := pbMessage.Marshal()
+	require.Nil(t, err)
+
+	pbMessage.Signature, err = sk.Sign(append([]byte(pubsub.SignPrefix), buff...))
+	require.Nil(t, err)
+
+	msgs, err := libp2p.NewMessages(&pubsub.Message{Message: pbMessage}, marshaller, p2p.Broadcast)
+	require.Nil(t, err)
+
+	return msgs[0]
+}
+
+func generateLibp2pKey() (libp2pCrypto.PrivKey, core.PeerID) {
+	sk, _, _ := libp2pCrypto.GenerateSecp256k1Key(rand.Reader)
+	pid, _ := peer.IDFromPublicKey(sk.GetPublic())
+
+	return sk, core.PeerID(pid)
+}
+
+func TestNewEvidenceProcessor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil message verifier should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createEvidenceProcessorArgs()
+		args.MessageVerifier = nil
+		ep, err := messagecheck.NewEvidenceProcessor(args)
+		assert.Equal(t, p2p.ErrNilMessageVerifier, err)
+		assert.True(t, check.IfNil(ep))
+	})
+	t.Run("nil marshaller should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createEvidenceProcessorArgs()
+		args.Marshaller = nil
+		ep, err := messagecheck.NewEvidenceProcessor(args)
+		assert.Equal(t, p2p.ErrNilMarshaller, err)
+		assert.True(t, check.IfNil(ep))
+	})
+	t.Run("nil evidence handler should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createEvidenceProcessorArgs()
+		args.EvidenceHandler = nil
+		ep, err := messagecheck.NewEvidenceProcessor(args)
+		assert.Equal(t, p2p.ErrNilEvidenceHandler, err)
+		assert.True(t, check.IfNil(ep))
+	})
+	t.Run("nil logger should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createEvidenceProcessorArgs()
+		args.Logger = nil
+		ep, err := messagecheck.NewEvidenceProcessor(args)
+		assert.Equal(t, p2p.ErrNilLogger, err)
+		assert.True(t, check.IfNil(ep))
+	})
+	t.Run("invalid observed messages TTL should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createEvidenceProcessorArgs()
+		args.ObservedMessagesTTL = 0
+		ep, err := messagecheck.NewEvidenceProcessor(args)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidValue))
+		assert.True(t, check.IfNil(ep))
+	})
+	t.Run("invalid max observed messages should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createEvidenceProcessorArgs()
+		args.MaxObservedMessages = 0
+		ep, err := messagecheck.NewEvidenceProcessor(args)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidValue))
+		assert.True(t, check.IfNil(ep))
+	})
+	t.Run("evidence topic without messenger should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createEvidenceProcessorArgs()
+		args.EvidenceTopic = "evidence"
+		ep, err := messagecheck.NewEvidenceProcessor(args)
+		assert.Equal(t, p2p.ErrNilMessenger, err)
+		assert.True(t, check.IfNil(ep))
+	})
+	t.Run("create topic fails should error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		args := createEvidenceProcessorArgs()
+		args.EvidenceTopic = "evidence"
+		args.Messenger = &evidenceMessengerStub{
+			createTopicCalled: func(name string, createChannelForTopic bool) error {
+				return expectedErr
+			},
+		}
+		ep, err := messagecheck.NewEvidenceProcessor(args)
+		assert.Equal(t, expectedErr, err)
+		assert.True(t, check.IfNil(ep))
+	})
+	t.Run("should work with the evidence topic", func(t *testing.T) {
+		t.Parallel()
+
+		var registeredProcessor p2p.MessageProcessor
+		args := createEvidenceProcessorArgs()
+		args.EvidenceTopic = "evidence"
+		args.Messenger = &evidenceMessengerStub{
+			registerMessageProcessorCalled: func(topic string, identifier string, handler p2p.MessageProcessor) error {
+				assert.Equal(t, "evidence", topic)
+				registeredProcessor = handler
+				return nil
+			},
+		}
+		ep, err := messagecheck.NewEvidenceProcessor(args)
+		assert.Nil(t, err)
+		assert.False(t, check.IfNil(ep))
+		assert.True(t, ep == registeredProcessor)
+	})
+}
+
+func TestEvidenceProcessor_ObserveMessage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil message should error", func(t *testing.T) {
+		t.Parallel()
+
+		ep, _ := messagecheck.NewEvidenceProcessor(createEvidenceProcessorArgs())
+		assert.Equal(t, p2p.ErrNilMessage, ep.ObserveMessage(nil))
+	})
+	t.Run("same message observed twice should not be an equivocation", func(t *testing.T) {
+		t.Parallel()
+
+		args := createEvidenceProcessorArgs()
+		handler := &evidenceHandlerStub{}
+		args.EvidenceHandler = handler
+		ep, _ := messagecheck.NewEvidenceProcessor(args)
+
+		sk, _ := generateLibp2pKey()
+		msg := createSignedMessage(t, sk, "seq", "payload")
+		assert.Nil(t, ep.ObserveMessage(msg))
+		assert.Nil(t, ep.ObserveMessage(msg))
+		assert.Empty(t, handler.getEvidences())
+	})
+	t.Run("equivocation should be handled and distributed", func(t *testing.T) {
+		t.Parallel()
+
+		args := createEvidenceProcessorArgs()
+		handler := &evidenceHandlerStub{}
+		args.EvidenceHandler = handler
+		args.EvidenceTopic = "evidence"
+		var broadcastEvidences [][]byte
+		args.Messenger = &evidenceMessengerStub{
+			broadcastCalled: func(topic string, buff []byte) {
+				assert.Equal(t, "evidence", topic)
+				broadcastEvidences = append(broadcastEvidences, buff)
+			},
+		}
+		ep, _ := messagecheck.NewEvidenceProcessor(args)
+
+		sk, pid := generateLibp2pKey()
+		assert.Nil(t, ep.ObserveMessage(createSignedMessage(t, sk, "seq", "payload 1")))
+		assert.Nil(t, ep.ObserveMessage(createSignedMessage(t, sk, "seq", "payload 2")))
+		assert.Nil(t, ep.ObserveMessage(createSignedMessage(t, sk, "seq", "payload 3")))
+
+		evidences := handler.getEvidences()
+		require.Equal(t, 1, len(evidences))
+		assert.Equal(t, messagecheck.EquivocationEvidence, evidences[0].Type)
+		assert.Equal(t, pid, evidences[0].Offender)
+		assert.Equal(t, 2, len(evidences[0].Messages))
+		require.Equal(t, 1, len(broadcastEvidences))
+
+		evidence, err := ep.VerifyEvidence(broadcastEvidences[0])
+		assert.Nil(t, err)
+		assert.Equal(t, pid, evidence.Offender)
+	})
+	t.Run("forged message should not produce an evidence", func(t *testing.T) {
+		t.Parallel()
+
+		args := createEvidenceProcessorArgs()
+		handler := &evidenceHandlerStub{}
+		args.EvidenceHandler = handler
+		ep, _ := messagecheck.NewEvidenceProcessor(args)
+
+		sk, pid := generateLibp2pKey()
+		otherSk, _ := generateLibp2pKey()
+		msg := createSignedMessage(t, sk, "seq", "payload 1")
+		forgedMsg := createSignedMessage(t, otherSk, "seq", "payload 2").(*message.Message)
+		forgedMsg.FromField = pid.Bytes()
+		forgedMsg.PeerField = pid
+
+		assert.Nil(t, ep.ObserveMessage(msg))
+		err := ep.ObserveMessage(forgedMsg)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidEvidence))
+		assert.Empty(t, handler.getEvidences())
+	})
+	t.Run("expired and evicted messages should not be used", func(t *testing.T) {
+		t.Parallel()
+
+		args := createEvidenceProcessorArgs()
+		handler := &evidenceHandlerStub{}
+		args.EvidenceHandler = handler
+		args.MaxObservedMessages = 1
+		ep, _ := messagecheck.NewEvidenceProcessor(args)
+
+		sk, _ := generateLibp2pKey()
+		assert.Nil(t, ep.ObserveMessage(createSignedMessage(t, sk, "seq1", "payload 1")))
+		assert.Nil(t, ep.ObserveMessage(createSignedMessage(t, sk, "seq2", "payload 1")))
+		assert.Nil(t, ep.ObserveMessage(createSignedMessage(t, sk, "seq1", "payload 2")))
+		assert.Empty(t, handler.getEvidences())
+
+		args.MaxObservedMessages = 100
+		args.ObservedMessagesTTL = time.Millisecond
+		ep, _ = messagecheck.NewEvidenceProcessor(args)
+		assert.Nil(t, ep.ObserveMessage(createSignedMessage(t, sk, "seq1", "payload 1")))
+		time.Sleep(time.Millisecond * 10)
+		assert.Nil(t, ep.ObserveMessage(createSignedMessage(t, sk, "seq1", "payload 2")))
+		assert.Empty(t, handler.getEvidences())
+	})
+}
+
+func TestEvidenceProcessor_ObserveDuplicateMessage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil message should error", func(t *testing.T) {
+		t.Parallel()
+
+		ep, _ := messagecheck.NewEvidenceProcessor(createEvidenceProcessorArgs())
+		assert.Equal(t, p2p.ErrNilMessage, ep.ObserveDuplicateMessage(nil))
+	})
+	t.Run("duplicate message should not be kept", func(t *testing.T) {
+		t.Parallel()
+
+		args := createEvidenceProcessorArgs()
+		handler := &evidenceHandlerStub{}
+		args.EvidenceHandler = handler
+		ep, _ := messagecheck.NewEvidenceProcessor(args)
+
+		sk, _ := generateLibp2pKey()
+		assert.Nil(t, ep.ObserveDuplicateMessage(createSignedMessage(t, sk, "seq", "payload 1")))
+		assert.Nil(t, ep.ObserveMessage(createSignedMessage(t, sk, "seq", "payload 2")))
+		assert.Empty(t, handler.getEvidences())
+	})
+	t.Run("forged duplicate should not produce an evidence", func(t *testing.T) {
+		t.Parallel()
+
+		args := createEvidenceProcessorArgs()
+		handler := &evidenceHandlerStub{}
+		args.EvidenceHandler = handler
+		ep, _ := messagecheck.NewEvidenceProcessor(args)
+
+		sk, pid := generateLibp2pKey()
+		otherSk, _ := generateLibp2pKey()
+		forgedMsg := createSignedMessage(t, otherSk, "seq", "payload 2").(*message.Message)
+		forgedMsg.FromField = pid.Bytes()
+		forgedMsg.PeerField = pid
+
+		assert.Nil(t, ep.ObserveMessage(createSignedMessage(t, sk, "seq", "payload 1")))
+		err := ep.ObserveDuplicateMessage(forgedMsg)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidEvidence))
+		assert.Empty(t, handler.getEvidences())
+	})
+	t.Run("equivocation of attested and batched messages should be handled", func(t *testing.T) {
+		t.Parallel()
+
+		args := createEvidenceProcessorArgs()
+		handler := &evidenceHandlerStub{}
+		args.EvidenceHandler = handler
+		ep, _ := messagecheck.NewEvidenceProcessor(args)
+
+		marshaller := &testscommon.ProtoMarshallerMock{}
+		batchBytes, err := marshaller.Marshal(&batch.Batch{Data: [][]byte{[]byte("payload 1"), []byte("payload 2")}})
+		require.Nil(t, err)
+
+		sk, pid := generateLibp2pKey()
+		attestedMsg := createSignedTopicMessage(t, sk, "seq", &data.TopicMessage{
+			Version:        1,
+			Payload:        []byte("payload"),
+			Timestamp:      time.Now().Unix(),
+			Pk:             []byte("pk"),
+			SignatureOnPid: []byte("signature on pid"),
+		})
+		batchedMsg := createSignedTopicMessage(t, sk, "seq", &data.TopicMessage{
+			Version:   2,
+			Payload:   batchBytes,
+			Timestamp: time.Now().Unix(),
+		})
+
+		assert.Nil(t, ep.ObserveMessage(attestedMsg))
+		assert.Nil(t, ep.ObserveDuplicateMessage(attestedMsg))
+		assert.Nil(t, ep.ObserveDuplicateMessage(batchedMsg))
+
+		evidences := handler.getEvidences()
+		require.Equal(t, 1, len(evidences))
+		assert.Equal(t, messagecheck.EquivocationEvidence, evidences[0].Type)
+		assert.Equal(t, pid, evidences[0].Offender)
+		assert.Equal(t, 2, len(evidences[0].Messages))
+	})
+}
+
+func TestEvidenceProcessor_ReportInvalidContent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil message should error", func(t *testing.T) {
+		t.Parallel()
+
+		ep, _ := messagecheck.NewEvidenceProcessor(createEvidenceProcessorArgs())
+		assert.Equal(t, p2p.ErrNilMessage, ep.ReportInvalidContent(nil, "reason"))
+	})
+	t.Run("without content validator should error", func(t *testing.T) {
+		t.Parallel()
+
+		ep, _ := messagecheck.NewEvidenceProcessor(createEvidenceProcessorArgs())
+
+		sk, _ := generateLibp2pKey()
+		err := ep.ReportInvalidContent(createSignedMessage(t, sk, "seq", "payload"), "reason")
+		assert.True(t, errors.Is(err, p2p.ErrInvalidEvidence))
+	})
+	t.Run("valid content should error", func(t *testing.T) {
+		t.Parallel()
+
+		ep, _ := messagecheck.NewEvidenceProcessor(createEvidenceProcessorArgs())
+		_ = ep.RegisterContentValidator(evidenceTestTopic, &contentValidatorStub{})
+
+		sk, _ := generateLibp2pKey()
+		err := ep.ReportInvalidContent(createSignedMessage(t, sk, "seq", "payload"), "reason")
+		assert.True(t, errors.Is(err, p2p.ErrInvalidEvidence))
+	})
+	t.Run("invalid content should be handled", func(t *testing.T) {
+		t.Parallel()
+
+		args := createEvidenceProcessorArgs()
+		handler := &evidenceHandlerStub{}
+		args.EvidenceHandler = handler
+		ep, _ := messagecheck.NewEvidenceProcessor(args)
+		assert.Equal(t, p2p.ErrNilContentValidator, ep.RegisterContentValidator(evidenceTestTopic, nil))
+		_ = ep.RegisterContentValidator(evidenceTestTopic, &contentValidatorStub{err: errors.New("invalid")})
+
+		sk, pid := generateLibp2pKey()
+		err := ep.ReportInvalidContent(createSignedMessage(t, sk, "seq", "payload"), "reason")
+		assert.Nil(t, err)
+
+		evidences := handler.getEvidences()
+		require.Equal(t, 1, len(evidences))
+		assert.Equal(t, messagecheck.InvalidContentEvidence, evidences[0].Type)
+		assert.Equal(t, pid, evidences[0].Offender)
+		assert.Equal(t, "reason", evidences[0].Reason)
+	})
+}
+
+func TestEvidenceProcessor_VerifyEvidence(t *testing.T) {
+	t.Parallel()
+
+	ep, _ := messagecheck.NewEvidenceProcessor(createEvidenceProcessorArgs())
+	sk, _ := generateLibp2pKey()
+	otherSk, _ := generateLibp2pKey()
+
+	t.Run("invalid evidence bytes should error", func(t *testing.T) {
+		t.Parallel()
+
+		evidence, err := ep.VerifyEvidence([]byte("invalid evidence"))
+		assert.NotNil(t, err)
+		assert.Nil(t, evidence)
+	})
+	t.Run("messages from different peers should error", func(t *testing.T) {
+		t.Parallel()
+
+		evidenceBytes, err := ep.CreateEquivocationEvidence(
+			createSignedMessage(t, sk, "seq", "payload 1"),
+			createSignedMessage(t, otherSk, "seq", "payload 2"),
+		)
+		require.Nil(t, err)
+
+		evidence, err := ep.VerifyEvidence(evidenceBytes)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidEvidence))
+		assert.Nil(t, evidence)
+	})
+	t.Run("different sequence numbers should error", func(t *testing.T) {
+		t.Parallel()
+
+		evidenceBytes, _ := ep.CreateEquivocationEvidence(
+			createSignedMessage(t, sk, "seq1", "payload 1"),
+			createSignedMessage(t, sk, "seq2", "payload 2"),
+		)
+
+		evidence, err := ep.VerifyEvidence(evidenceBytes)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidEvidence))
+		assert.Nil(t, evidence)
+	})
+	t.Run("identical messages should error", func(t *testing.T) {
+		t.Parallel()
+
+		msg := createSignedMessage(t, sk, "seq", "payload")
+		evidenceBytes, _ := ep.CreateEquivocationEvidence(msg, msg)
+
+		evidence, err := ep.VerifyEvidence(evidenceBytes)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidEvidence))
+		assert.Nil(t, evidence)
+	})
+	t.Run("tampered message should error", func(t *testing.T) {
+		t.Parallel()
+
+		tamperedMsg := createSignedMessage(t, sk, "seq", "payload 2").(*message.Message)
+		tamperedMsg.SeqNoField = []byte("other seq")
+		evidenceBytes, _ := ep.CreateEquivocationEvidence(createSignedMessage(t, sk, "other seq", "payload 1"), tamperedMsg)
+
+		evidence, err := ep.VerifyEvidence(evidenceBytes)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidEvidence))
+		assert.Nil(t, evidence)
+	})
+	t.Run("unknown evidence type should error", func(t *testing.T) {
+		t.Parallel()
+
+		marshaller := &testscommon.ProtoMarshallerMock{}
+		evidenceBytes, _ := ep.CreateInvalidContentEvidence(createSignedMessage(t, sk, "seq", "payload"), "reason")
+		evidence := &message.MisbehaviorEvidence{}
+		_ = marshaller.Unmarshal(evidence, evidenceBytes)
+		evidence.Type = 100
+		evidenceBytes, _ = marshaller.Marshal(evidence)
+
+		verifiedEvidence, err := ep.VerifyEvidence(evidenceBytes)
+		assert.True(t, errors.Is(err, p2p.ErrInvalidEvidence))
+		assert.Nil(t, verifiedEvidence)
+	})
+	t.Run("equivocation should work", func(t *testing.T) {
+		t.Parallel()
+
+		evidenceBytes, _ := ep.CreateEquivocationEvidence(
+			createSignedMessage(t, sk, "seq", "payload 1"),
+			createSignedMessage(t, sk, "seq", "payload 2"),
+		)
+
+		evidence, err := ep.VerifyEvidence(evidenceBytes)
+		assert.Nil(t, err)
+		assert.Equal(t, messagecheck.EquivocationEvidence, evidence.Type)
+		assert.Equal(t, 2, len(evidence.Messages))
+	})
+}
+
+func TestEvidenceProcessor_ProcessReceivedMessage(t *testing.T) {
+	t.Parallel()
+
+	sk, pid := generateLibp2pKey()
+	senderArgs := createEvidenceProcessorArgs()
+	sender, _ := messagecheck.NewEvidenceProcessor(senderArgs)
+	evidenceBytes, _ := sender.CreateEquivocationEvidence(
+		createSignedMessage(t, sk, "seq", "payload 1"),
+		createSignedMessage(t, sk, "seq", "payload 2"),
+	)
+
+	t.Run("nil message should error", func(t *testing.T) {
+		t.Parallel()
+
+		ep, _ := messagecheck.NewEvidenceProcessor(createEvidenceProcessorArgs())
+		assert.Equal(t, p2p.ErrNilMessage, ep.ProcessReceivedMessage(nil, "", nil))
+	})
+	t.Run("invalid evidence should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createEvidenceProcessorArgs()
+		handler := &evidenceHandlerStub{}
+		args.EvidenceHandler = handler
+		ep, _ := messagecheck.NewEvidenceProcessor(args)
+
+		msg := &message.Message{DataField: []byte("invalid evidence")}
+		assert.NotNil(t, ep.ProcessReceivedMessage(msg, "", nil))
+		assert.Empty(t, handler.getEvidences())
+	})
+	t.Run("valid evidence should be handled once", func(t *testing.T) {
+		t.Parallel()
+
+		args := createEvidenceProcessorArgs()
+		handler := &evidenceHandlerStub{}
+		args.EvidenceHandler = handler
+		ep, _ := messagecheck.NewEvidenceProcessor(args)
+
+		msg := &message.Message{DataField: evidenceBytes}
+		assert.Nil(t, ep.ProcessReceivedMessage(msg, "", nil))
+		assert.Nil(t, ep.ProcessReceivedMessage(msg, "", nil))
+
+		evidences := handler.getEvidences()
+		require.Equal(t, 1, len(evidences))
+		assert.Equal(t, pid, evidences[0].Offender)
+	})
+}
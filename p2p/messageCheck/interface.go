@@ -1,8 +1,38 @@
 package messagecheck
 
-import "github.com/TerraDharitri/drt-go-chain-core/core"
+import (
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+)
 
 type p2pSigner interface {
 	Verify(payload []byte, pid core.PeerID, signature []byte) error
 	IsInterfaceNil() bool
 }
+
+// MessageVerifier defines the component able to verify the signature of p2p messages and to serialize them
+type MessageVerifier interface {
+	Verify(msg p2p.MessageP2P) error
+	Serialize(messages []p2p.MessageP2P) ([]byte, error)
+	Deserialize(messagesBytes []byte) ([]p2p.MessageP2P, error)
+	IsInterfaceNil() bool
+}
+
+// ContentValidator defines the component able to tell if the content of a message received on a topic is valid
+type ContentValidator interface {
+	ValidateContent(msg p2p.MessageP2P) error
+	IsInterfaceNil() bool
+}
+
+// EvidenceHandler defines the component that acts on the verified misbehavior evidences
+type EvidenceHandler interface {
+	HandleEvidence(evidence *Evidence)
+	IsInterfaceNil() bool
+}
+
+type evidenceMessenger interface {
+	CreateTopic(name string, createChannelForTopic bool) error
+	RegisterMessageProcessor(topic string, identifier string, handler p2p.MessageProcessor) error
+	Broadcast(topic string, buff []byte)
+	IsInterfaceNil() bool
+}
@@ -131,7 +131,13 @@ func convertPubSubMessagestoP2PMessage(msg *pubsubPb.Message, marshaller marshal
 		ValidatorData: nil,
 	}
 
-	return libp2p.NewMessage(pubsubMsg, marshaller, p2p.Broadcast)
+	// the messages of a batch share the signed pubsub fields, so the first one is enough to verify the signature
+	messages, err := libp2p.NewMessages(pubsubMsg, marshaller, p2p.Broadcast)
+	if err != nil {
+		return nil, err
+	}
+
+	return messages[0], nil
 }
 
 // Serialize will serialize a list of p2p messages
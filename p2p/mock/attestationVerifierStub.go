@@ -0,0 +1,21 @@
+package mock
+
+import "github.com/TerraDharitri/drt-go-chain-core/core"
+
+// AttestationVerifierStub -
+type AttestationVerifierStub struct {
+	VerifyAttestationCalled func(pk []byte, pid core.PeerID, signatureOnPid []byte) error
+}
+
+// VerifyAttestation -
+func (stub *AttestationVerifierStub) VerifyAttestation(pk []byte, pid core.PeerID, signatureOnPid []byte) error {
+	if stub.VerifyAttestationCalled != nil {
+		return stub.VerifyAttestationCalled(pk, pid, signatureOnPid)
+	}
+	return nil
+}
+
+// IsInterfaceNil -
+func (stub *AttestationVerifierStub) IsInterfaceNil() bool {
+	return stub == nil
+}
@@ -1,6 +1,7 @@
 package mock
 
 import (
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p/libp2p"
 )
 
@@ -9,7 +10,9 @@ type ChannelLoadBalancerStub struct {
 	AddChannelCalled                    func(pipe string) error
 	RemoveChannelCalled                 func(pipe string) error
 	GetChannelOrDefaultCalled           func(pipe string) chan *libp2p.SendableData
+	GetChannelWithDoneOrDefaultCalled   func(pipe string) (chan *libp2p.SendableData, <-chan struct{})
 	CollectOneElementFromChannelsCalled func() *libp2p.SendableData
+	GetChannelsMetricsCalled            func() map[string]p2p.OutgoingChannelMetrics
 	CloseCalled                         func() error
 }
 
@@ -28,11 +31,28 @@ func (clbs *ChannelLoadBalancerStub) GetChannelOrDefault(pipe string) chan *libp
 	return clbs.GetChannelOrDefaultCalled(pipe)
 }
 
+// GetChannelWithDoneOrDefault -
+func (clbs *ChannelLoadBalancerStub) GetChannelWithDoneOrDefault(pipe string) (chan *libp2p.SendableData, <-chan struct{}) {
+	if clbs.GetChannelWithDoneOrDefaultCalled != nil {
+		return clbs.GetChannelWithDoneOrDefaultCalled(pipe)
+	}
+
+	return clbs.GetChannelOrDefault(pipe), nil
+}
+
 // CollectOneElementFromChannels -
 func (clbs *ChannelLoadBalancerStub) CollectOneElementFromChannels() *libp2p.SendableData {
 	return clbs.CollectOneElementFromChannelsCalled()
 }
 
+// GetChannelsMetrics -
+func (clbs *ChannelLoadBalancerStub) GetChannelsMetrics() map[string]p2p.OutgoingChannelMetrics {
+	if clbs.GetChannelsMetricsCalled != nil {
+		return clbs.GetChannelsMetricsCalled()
+	}
+	return make(map[string]p2p.OutgoingChannelMetrics)
+}
+
 // Close -
 func (clbs *ChannelLoadBalancerStub) Close() error {
 	if clbs.CloseCalled != nil {
@@ -115,7 +115,7 @@ func (cs *ConnStub) ConnState() network.ConnectionState {
 
 // IsClosed -
 func (cs *ConnStub) IsClosed() bool {
-	if cs.CloseCalled != nil {
+	if cs.IsClosedCalled != nil {
 		return cs.IsClosedCalled()
 	}
 
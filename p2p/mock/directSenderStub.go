@@ -10,6 +10,7 @@ type DirectSenderStub struct {
 	NextSequenceNumberCalled             func() []byte
 	SendCalled                           func(topic string, buff []byte, peer core.PeerID) error
 	RegisterDirectMessageProcessorCalled func(handler p2p.MessageHandler) error
+	SignSelfMessageCalled                func(topic string, buff []byte, seqNo []byte) ([]byte, error)
 }
 
 // NextSequenceNumber -
@@ -36,6 +37,14 @@ func (stub *DirectSenderStub) RegisterDirectMessageProcessor(handler p2p.Message
 	return nil
 }
 
+// SignSelfMessage -
+func (stub *DirectSenderStub) SignSelfMessage(topic string, buff []byte, seqNo []byte) ([]byte, error) {
+	if stub.SignSelfMessageCalled != nil {
+		return stub.SignSelfMessageCalled(topic, buff, seqNo)
+	}
+	return nil, nil
+}
+
 // IsInterfaceNil -
 func (stub *DirectSenderStub) IsInterfaceNil() bool {
 	return stub == nil
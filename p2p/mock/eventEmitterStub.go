@@ -0,0 +1,25 @@
+package mock
+
+// EventEmitterStub -
+type EventEmitterStub struct {
+	EmitCalled  func(evt interface{}) error
+	CloseCalled func() error
+}
+
+// Emit -
+func (stub *EventEmitterStub) Emit(evt interface{}) error {
+	if stub.EmitCalled != nil {
+		return stub.EmitCalled(evt)
+	}
+
+	return nil
+}
+
+// Close -
+func (stub *EventEmitterStub) Close() error {
+	if stub.CloseCalled != nil {
+		return stub.CloseCalled()
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package mock
+
+import (
+	"time"
+
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+)
+
+// IncompatiblePeersHandlerStub -
+type IncompatiblePeersHandlerStub struct {
+	AddIncompatiblePeerCalled func(pid core.PeerID, duration time.Duration)
+	IsIncompatibleCalled      func(pid core.PeerID) bool
+}
+
+// AddIncompatiblePeer -
+func (stub *IncompatiblePeersHandlerStub) AddIncompatiblePeer(pid core.PeerID, duration time.Duration) {
+	if stub.AddIncompatiblePeerCalled != nil {
+		stub.AddIncompatiblePeerCalled(pid, duration)
+	}
+}
+
+// IsIncompatible -
+func (stub *IncompatiblePeersHandlerStub) IsIncompatible(pid core.PeerID) bool {
+	if stub.IsIncompatibleCalled != nil {
+		return stub.IsIncompatibleCalled(pid)
+	}
+
+	return false
+}
+
+// IsInterfaceNil -
+func (stub *IncompatiblePeersHandlerStub) IsInterfaceNil() bool {
+	return stub == nil
+}
@@ -1,6 +1,8 @@
 package mock
 
 import (
+	"context"
+
 	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
 	"github.com/TerraDharitri/drt-go-chain-core/core"
 )
@@ -8,6 +10,8 @@ import (
 // MessageHandlerStub -
 type MessageHandlerStub struct {
 	CreateTopicCalled                       func(name string, createChannelForTopic bool) error
+	CreatePublishOnlyTopicCalled            func(name string, createChannelForTopic bool) error
+	LeaveTopicCalled                        func(name string) error
 	HasTopicCalled                          func(name string) bool
 	RegisterMessageProcessorCalled          func(topic string, identifier string, handler p2p.MessageProcessor) error
 	UnregisterAllMessageProcessorsCalled    func() error
@@ -16,10 +20,20 @@ type MessageHandlerStub struct {
 	BroadcastOnChannelCalled                func(channel string, topic string, buff []byte)
 	BroadcastUsingPrivateKeyCalled          func(topic string, buff []byte, pid core.PeerID, skBytes []byte)
 	BroadcastOnChannelUsingPrivateKeyCalled func(channel string, topic string, buff []byte, pid core.PeerID, skBytes []byte)
+	BroadcastCtxCalled                      func(ctx context.Context, channel string, topic string, buff []byte) error
+	BroadcastUsingPrivateKeyCtxCalled       func(ctx context.Context, channel string, topic string, buff []byte, pid core.PeerID, skBytes []byte) error
+	AddIdentityCalled                       func(name string, skBytes []byte, pid core.PeerID) error
+	RemoveIdentityCalled                    func(name string) error
+	BroadcastAsCalled                       func(identityName string, topic string, buff []byte) error
+	BroadcastOnChannelAsCalled              func(channel string, identityName string, topic string, buff []byte) error
 	SendToConnectedPeerCalled               func(topic string, buff []byte, peerID core.PeerID) error
+	SubscribeCalled                         func(topic string) (<-chan p2p.MessageP2P, func())
 	UnJoinAllTopicsCalled                   func() error
 	ProcessReceivedMessageCalled            func(message p2p.MessageP2P, fromConnectedPeer core.PeerID, source p2p.MessageHandler) error
 	SetDebuggerCalled                       func(debugger p2p.Debugger) error
+	SetAttestationCalled                    func(pk []byte, signatureOnPid []byte) error
+	SetAttestationVerifierCalled            func(verifier p2p.AttestationVerifier) error
+	SetMessageObserverCalled                func(observer p2p.MessageObserver) error
 	CloseCalled                             func() error
 }
 
@@ -31,6 +45,22 @@ func (stub *MessageHandlerStub) CreateTopic(name string, createChannelForTopic b
 	return nil
 }
 
+// CreatePublishOnlyTopic -
+func (stub *MessageHandlerStub) CreatePublishOnlyTopic(name string, createChannelForTopic bool) error {
+	if stub.CreatePublishOnlyTopicCalled != nil {
+		return stub.CreatePublishOnlyTopicCalled(name, createChannelForTopic)
+	}
+	return nil
+}
+
+// LeaveTopic -
+func (stub *MessageHandlerStub) LeaveTopic(name string) error {
+	if stub.LeaveTopicCalled != nil {
+		return stub.LeaveTopicCalled(name)
+	}
+	return nil
+}
+
 // HasTopic -
 func (stub *MessageHandlerStub) HasTopic(name string) bool {
 	if stub.HasTopicCalled != nil {
@@ -91,6 +121,54 @@ func (stub *MessageHandlerStub) BroadcastOnChannelUsingPrivateKey(channel string
 	}
 }
 
+// BroadcastCtx -
+func (stub *MessageHandlerStub) BroadcastCtx(ctx context.Context, channel string, topic string, buff []byte) error {
+	if stub.BroadcastCtxCalled != nil {
+		return stub.BroadcastCtxCalled(ctx, channel, topic, buff)
+	}
+	return nil
+}
+
+// BroadcastUsingPrivateKeyCtx -
+func (stub *MessageHandlerStub) BroadcastUsingPrivateKeyCtx(ctx context.Context, channel string, topic string, buff []byte, pid core.PeerID, skBytes []byte) error {
+	if stub.BroadcastUsingPrivateKeyCtxCalled != nil {
+		return stub.BroadcastUsingPrivateKeyCtxCalled(ctx, channel, topic, buff, pid, skBytes)
+	}
+	return nil
+}
+
+// AddIdentity -
+func (stub *MessageHandlerStub) AddIdentity(name string, skBytes []byte, pid core.PeerID) error {
+	if stub.AddIdentityCalled != nil {
+		return stub.AddIdentityCalled(name, skBytes, pid)
+	}
+	return nil
+}
+
+// RemoveIdentity -
+func (stub *MessageHandlerStub) RemoveIdentity(name string) error {
+	if stub.RemoveIdentityCalled != nil {
+		return stub.RemoveIdentityCalled(name)
+	}
+	return nil
+}
+
+// BroadcastAs -
+func (stub *MessageHandlerStub) BroadcastAs(identityName string, topic string, buff []byte) error {
+	if stub.BroadcastAsCalled != nil {
+		return stub.BroadcastAsCalled(identityName, topic, buff)
+	}
+	return nil
+}
+
+// BroadcastOnChannelAs -
+func (stub *MessageHandlerStub) BroadcastOnChannelAs(channel string, identityName string, topic string, buff []byte) error {
+	if stub.BroadcastOnChannelAsCalled != nil {
+		return stub.BroadcastOnChannelAsCalled(channel, identityName, topic, buff)
+	}
+	return nil
+}
+
 // SendToConnectedPeer -
 func (stub *MessageHandlerStub) SendToConnectedPeer(topic string, buff []byte, peerID core.PeerID) error {
 	if stub.SendToConnectedPeerCalled != nil {
@@ -99,6 +177,14 @@ func (stub *MessageHandlerStub) SendToConnectedPeer(topic string, buff []byte, p
 	return nil
 }
 
+// Subscribe -
+func (stub *MessageHandlerStub) Subscribe(topic string) (<-chan p2p.MessageP2P, func()) {
+	if stub.SubscribeCalled != nil {
+		return stub.SubscribeCalled(topic)
+	}
+	return nil, func() {}
+}
+
 // UnJoinAllTopics -
 func (stub *MessageHandlerStub) UnJoinAllTopics() error {
 	if stub.UnJoinAllTopicsCalled != nil {
@@ -123,6 +209,30 @@ func (stub *MessageHandlerStub) SetDebugger(debugger p2p.Debugger) error {
 	return nil
 }
 
+// SetAttestation -
+func (stub *MessageHandlerStub) SetAttestation(pk []byte, signatureOnPid []byte) error {
+	if stub.SetAttestationCalled != nil {
+		return stub.SetAttestationCalled(pk, signatureOnPid)
+	}
+	return nil
+}
+
+// SetAttestationVerifier -
+func (stub *MessageHandlerStub) SetAttestationVerifier(verifier p2p.AttestationVerifier) error {
+	if stub.SetAttestationVerifierCalled != nil {
+		return stub.SetAttestationVerifierCalled(verifier)
+	}
+	return nil
+}
+
+// SetMessageObserver -
+func (stub *MessageHandlerStub) SetMessageObserver(observer p2p.MessageObserver) error {
+	if stub.SetMessageObserverCalled != nil {
+		return stub.SetMessageObserverCalled(observer)
+	}
+	return nil
+}
+
 // Close -
 func (stub *MessageHandlerStub) Close() error {
 	if stub.CloseCalled != nil {
@@ -0,0 +1,30 @@
+package mock
+
+import "github.com/TerraDharitri/drt-go-chain-communication/p2p"
+
+// MessageObserverStub -
+type MessageObserverStub struct {
+	ObserveMessageCalled          func(msg p2p.MessageP2P) error
+	ObserveDuplicateMessageCalled func(msg p2p.MessageP2P) error
+}
+
+// ObserveMessage -
+func (stub *MessageObserverStub) ObserveMessage(msg p2p.MessageP2P) error {
+	if stub.ObserveMessageCalled != nil {
+		return stub.ObserveMessageCalled(msg)
+	}
+	return nil
+}
+
+// ObserveDuplicateMessage -
+func (stub *MessageObserverStub) ObserveDuplicateMessage(msg p2p.MessageP2P) error {
+	if stub.ObserveDuplicateMessageCalled != nil {
+		return stub.ObserveDuplicateMessageCalled(msg)
+	}
+	return nil
+}
+
+// IsInterfaceNil -
+func (stub *MessageObserverStub) IsInterfaceNil() bool {
+	return stub == nil
+}
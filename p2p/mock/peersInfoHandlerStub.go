@@ -0,0 +1,28 @@
+package mock
+
+import "github.com/TerraDharitri/drt-go-chain-core/core"
+
+// PeersInfoHandlerStub -
+type PeersInfoHandlerStub struct {
+	UpdatePeerInfoCalled func(pid core.PeerID, peerInfo core.P2PPeerInfo, nodeVersion string)
+	RemovePeerCalled     func(pid core.PeerID)
+}
+
+// UpdatePeerInfo -
+func (stub *PeersInfoHandlerStub) UpdatePeerInfo(pid core.PeerID, peerInfo core.P2PPeerInfo, nodeVersion string) {
+	if stub.UpdatePeerInfoCalled != nil {
+		stub.UpdatePeerInfoCalled(pid, peerInfo, nodeVersion)
+	}
+}
+
+// RemovePeer -
+func (stub *PeersInfoHandlerStub) RemovePeer(pid core.PeerID) {
+	if stub.RemovePeerCalled != nil {
+		stub.RemovePeerCalled(pid)
+	}
+}
+
+// IsInterfaceNil -
+func (stub *PeersInfoHandlerStub) IsInterfaceNil() bool {
+	return stub == nil
+}
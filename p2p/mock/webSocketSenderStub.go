@@ -0,0 +1,19 @@
+package mock
+
+// WebSocketSenderStub -
+type WebSocketSenderStub struct {
+	SendCalled func(payload []byte, topic string) error
+}
+
+// Send -
+func (stub *WebSocketSenderStub) Send(payload []byte, topic string) error {
+	if stub.SendCalled != nil {
+		return stub.SendCalled(payload, topic)
+	}
+	return nil
+}
+
+// IsInterfaceNil -
+func (stub *WebSocketSenderStub) IsInterfaceNil() bool {
+	return stub == nil
+}
@@ -0,0 +1,15 @@
+package websocketbridge
+
+import "github.com/TerraDharitri/drt-go-chain-communication/p2p"
+
+// WebSocketSender defines the websocket host side of the bridge
+type WebSocketSender interface {
+	Send(payload []byte, topic string) error
+	IsInterfaceNil() bool
+}
+
+// MessageFilter decides if an accepted p2p message should be forwarded on the websocket host
+type MessageFilter interface {
+	ShouldForward(message p2p.MessageP2P) bool
+	IsInterfaceNil() bool
+}
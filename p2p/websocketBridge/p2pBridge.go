@@ -0,0 +1,342 @@
+package websocketbridge
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+	"github.com/TerraDharitri/drt-go-chain-core/core/check"
+	"github.com/TerraDharitri/drt-go-chain-core/marshal"
+)
+
+const defaultIdentifier = "websocket bridge"
+
+// TopicConfig holds the forwarding settings of a bridged topic
+type TopicConfig struct {
+	Topic string
+	// WebSocketTopic is the topic used on the websocket host. If empty, the p2p topic will be used
+	WebSocketTopic string
+	// SampleEvery forwards only one of every SampleEvery accepted messages. 0 or 1 will forward all messages
+	SampleEvery uint32
+	// MaxPayloadSizeInBytes skips the messages with larger payloads. 0 means no limit
+	MaxPayloadSizeInBytes int
+	// Filter is optional and can skip messages based on their content or originator
+	Filter MessageFilter
+}
+
+// ArgsP2PBridge holds the arguments needed to create a bridge from p2p topics to a websocket host
+type ArgsP2PBridge struct {
+	MessageHandler p2p.MessageHandler
+	WebSocketHost  WebSocketSender
+	Marshaller     marshal.Marshalizer
+	Logger         p2p.Logger
+	Topics         []TopicConfig
+	QueueSize      int
+	Identifier     string
+}
+
+// BridgedMessage is the data forwarded on the websocket host for each accepted p2p message
+type BridgedMessage struct {
+	Topic      string `json:"topic"`
+	Originator []byte `json:"originator"`
+	SeqNo      []byte `json:"seqNo"`
+	Timestamp  int64  `json:"timestamp"`
+	Payload    []byte `json:"payload"`
+}
+
+// BridgeMetrics holds the counters of a bridge
+type BridgeMetrics struct {
+	NumForwarded  uint64
+	NumSampledOut uint64
+	NumFiltered   uint64
+	NumDropped    uint64
+	NumSendErrors uint64
+}
+
+type bridgedTopic struct {
+	config  TopicConfig
+	counter uint64
+}
+
+type queuedMessage struct {
+	webSocketTopic string
+	message        *BridgedMessage
+}
+
+type p2pBridge struct {
+	messageHandler      p2p.MessageHandler
+	webSocketHost       WebSocketSender
+	marshaller          marshal.Marshalizer
+	log                 p2p.Logger
+	identifier          string
+	topics              map[string]*bridgedTopic
+	queue               chan queuedMessage
+	cancelSubscriptions []func()
+	cancel              func()
+
+	numForwarded  uint64
+	numSampledOut uint64
+	numFiltered   uint64
+	numDropped    uint64
+	numSendErrors uint64
+}
+
+// NewP2PBridge will create a bridge that forwards the messages accepted on the configured p2p topics to a websocket host.
+// The bridge creates the missing topics and registers itself as a message processor on each of them, so it can be used
+// by tools that do not run a full node. The bridge processor accepts all the messages: on the topics where it is the
+// only processor, the messages are accepted after the checks done by the messenger (format, timestamp and attestation).
+// Only the messages accepted by all the processors of a topic are forwarded.
+// The p2p validation is never blocked: if the websocket host is slower than the gossip, the messages that do not fit
+// in the queue are dropped
+func NewP2PBridge(args ArgsP2PBridge) (*p2pBridge, error) {
+	err := checkArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	identifier := args.Identifier
+	if identifier == "" {
+		identifier = defaultIdentifier
+	}
+
+	bridge := &p2pBridge{
+		messageHandler: args.MessageHandler,
+		webSocketHost:  args.WebSocketHost,
+		marshaller:     args.Marshaller,
+		log:            args.Logger,
+		identifier:     identifier,
+		topics:         make(map[string]*bridgedTopic, len(args.Topics)),
+		queue:          make(chan queuedMessage, args.QueueSize),
+	}
+	for _, topicConfig := range args.Topics {
+		bridge.topics[topicConfig.Topic] = &bridgedTopic{
+			config: topicConfig,
+		}
+	}
+
+	err = bridge.registerProcessors()
+	if err != nil {
+		bridge.unregisterProcessors()
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bridge.cancel = cancel
+	bridge.subscribe(ctx)
+	go bridge.processQueue(ctx)
+
+	return bridge, nil
+}
+
+func checkArgs(args ArgsP2PBridge) error {
+	if check.IfNil(args.MessageHandler) {
+		return p2p.ErrNilMessageHandler
+	}
+	if check.IfNil(args.WebSocketHost) {
+		return p2p.ErrNilWebSocketSender
+	}
+	if check.IfNil(args.Marshaller) {
+		return p2p.ErrNilMarshaller
+	}
+	if check.IfNil(args.Logger) {
+		return p2p.ErrNilLogger
+	}
+	if args.QueueSize < 1 {
+		return fmt.Errorf("%w, provided %d, minimum 1", p2p.ErrInvalidQueueSize, args.QueueSize)
+	}
+	if len(args.Topics) == 0 {
+		return p2p.ErrNoTopicsToBridge
+	}
+
+	topics := make(map[string]struct{}, len(args.Topics))
+	for _, topicConfig := range args.Topics {
+		if len(topicConfig.Topic) == 0 {
+			return p2p.ErrNilTopic
+		}
+		_, found := topics[topicConfig.Topic]
+		if found {
+			return fmt.Errorf("%w: %s", p2p.ErrDuplicatedTopic, topicConfig.Topic)
+		}
+		topics[topicConfig.Topic] = struct{}{}
+	}
+
+	return nil
+}
+
+func (bridge *p2pBridge) registerProcessors() error {
+	for topic := range bridge.topics {
+		if !bridge.messageHandler.HasTopic(topic) {
+			err := bridge.messageHandler.CreateTopic(topic, false)
+			if err != nil {
+				return fmt.Errorf("%w while creating topic %s", err, topic)
+			}
+		}
+
+		err := bridge.messageHandler.RegisterMessageProcessor(topic, bridge.identifier, bridge)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (bridge *p2pBridge) unregisterProcessors() {
+	for topic := range bridge.topics {
+		err := bridge.messageHandler.UnregisterMessageProcessor(topic, bridge.identifier)
+		if err != nil {
+			bridge.log.Debug("p2pBridge: cannot unregister message processor", "topic", topic, "error", err)
+		}
+	}
+}
+
+// ProcessReceivedMessage accepts all the messages. The messages are forwarded from the topic subscriptions, once the
+// other processors of the topic accepted them as well
+func (bridge *p2pBridge) ProcessReceivedMessage(_ p2p.MessageP2P, _ core.PeerID, _ p2p.MessageHandler) error {
+	return nil
+}
+
+func (bridge *p2pBridge) subscribe(ctx context.Context) {
+	for topic := range bridge.topics {
+		chMessages, cancelSubscription := bridge.messageHandler.Subscribe(topic)
+		bridge.cancelSubscriptions = append(bridge.cancelSubscriptions, cancelSubscription)
+		go bridge.consumeSubscription(ctx, chMessages)
+	}
+}
+
+func (bridge *p2pBridge) consumeSubscription(ctx context.Context, chMessages <-chan p2p.MessageP2P) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-chMessages:
+			if !ok {
+				return
+			}
+			bridge.handleMessage(message)
+		}
+	}
+}
+
+// handleMessage queues the validated message to be forwarded on the websocket host. It never blocks, so a slow
+// websocket host only causes the bridged messages to be dropped
+func (bridge *p2pBridge) handleMessage(message p2p.MessageP2P) {
+	if check.IfNil(message) {
+		return
+	}
+
+	topic, found := bridge.topics[message.Topic()]
+	if !found {
+		return
+	}
+	if !bridge.shouldForward(topic, message) {
+		return
+	}
+
+	webSocketTopic := topic.config.WebSocketTopic
+	if webSocketTopic == "" {
+		webSocketTopic = message.Topic()
+	}
+
+	queued := queuedMessage{
+		webSocketTopic: webSocketTopic,
+		message: &BridgedMessage{
+			Topic:      message.Topic(),
+			Originator: []byte(message.Peer()),
+			SeqNo:      message.SeqNo(),
+			Timestamp:  message.Timestamp(),
+			Payload:    message.Data(),
+		},
+	}
+
+	select {
+	case bridge.queue <- queued:
+	default:
+		atomic.AddUint64(&bridge.numDropped, 1)
+	}
+}
+
+func (bridge *p2pBridge) shouldForward(topic *bridgedTopic, message p2p.MessageP2P) bool {
+	maxPayloadSize := topic.config.MaxPayloadSizeInBytes
+	if maxPayloadSize > 0 && len(message.Data()) > maxPayloadSize {
+		atomic.AddUint64(&bridge.numFiltered, 1)
+		return false
+	}
+	if !check.IfNil(topic.config.Filter) && !topic.config.Filter.ShouldForward(message) {
+		atomic.AddUint64(&bridge.numFiltered, 1)
+		return false
+	}
+
+	sampleEvery := uint64(topic.config.SampleEvery)
+	if sampleEvery < 2 {
+		return true
+	}
+
+	counter := atomic.AddUint64(&topic.counter, 1)
+	if (counter-1)%sampleEvery != 0 {
+		atomic.AddUint64(&bridge.numSampledOut, 1)
+		return false
+	}
+
+	return true
+}
+
+func (bridge *p2pBridge) processQueue(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			bridge.log.Debug("p2pBridge's processQueue go routine is stopping...")
+			return
+		case queued := <-bridge.queue:
+			bridge.forward(queued)
+		}
+	}
+}
+
+func (bridge *p2pBridge) forward(queued queuedMessage) {
+	payload, err := bridge.marshaller.Marshal(queued.message)
+	if err != nil {
+		atomic.AddUint64(&bridge.numSendErrors, 1)
+		bridge.log.Debug("p2pBridge: cannot marshal message", "topic", queued.message.Topic, "error", err)
+		return
+	}
+
+	err = bridge.webSocketHost.Send(payload, queued.webSocketTopic)
+	if err != nil {
+		atomic.AddUint64(&bridge.numSendErrors, 1)
+		bridge.log.Trace("p2pBridge: cannot send message", "topic", queued.message.Topic, "error", err)
+		return
+	}
+
+	atomic.AddUint64(&bridge.numForwarded, 1)
+}
+
+// GetMetrics returns the counters of the bridge
+func (bridge *p2pBridge) GetMetrics() BridgeMetrics {
+	return BridgeMetrics{
+		NumForwarded:  atomic.LoadUint64(&bridge.numForwarded),
+		NumSampledOut: atomic.LoadUint64(&bridge.numSampledOut),
+		NumFiltered:   atomic.LoadUint64(&bridge.numFiltered),
+		NumDropped:    atomic.LoadUint64(&bridge.numDropped),
+		NumSendErrors: atomic.LoadUint64(&bridge.numSendErrors),
+	}
+}
+
+// Close will unregister the bridge processors, cancel the subscriptions to the p2p topics and stop forwarding messages.
+// The topics and the websocket host are not closed
+func (bridge *p2pBridge) Close() error {
+	bridge.unregisterProcessors()
+	for _, cancelSubscription := range bridge.cancelSubscriptions {
+		cancelSubscription()
+	}
+	bridge.cancel()
+
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (bridge *p2pBridge) IsInterfaceNil() bool {
+	return bridge == nil
+}
@@ -0,0 +1,400 @@
+package websocketbridge
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/message"
+	"github.com/TerraDharitri/drt-go-chain-communication/p2p/mock"
+	"github.com/TerraDharitri/drt-go-chain-communication/testscommon"
+	"github.com/stretchr/testify/require"
+)
+
+const testTopic = "transactions"
+
+type messageFilterStub struct {
+	shouldForwardCalled func(message p2p.MessageP2P) bool
+}
+
+func (stub *messageFilterStub) ShouldForward(message p2p.MessageP2P) bool {
+	return stub.shouldForwardCalled(message)
+}
+
+func (stub *messageFilterStub) IsInterfaceNil() bool {
+	return stub == nil
+}
+
+func createArgs() ArgsP2PBridge {
+	return ArgsP2PBridge{
+		MessageHandler: &mock.MessageHandlerStub{
+			HasTopicCalled: func(name string) bool {
+				return true
+			},
+		},
+		WebSocketHost: &mock.WebSocketSenderStub{},
+		Marshaller:    &testscommon.MarshallerMock{},
+		Logger:        &testscommon.LoggerStub{},
+		Topics:        []TopicConfig{{Topic: testTopic}},
+		QueueSize:     10,
+	}
+}
+
+func createMessage(topic string, data []byte) p2p.MessageP2P {
+	return &message.Message{
+		DataField:      data,
+		TopicField:     topic,
+		PeerField:      "originator",
+		TimestampField: 1234,
+		SeqNoField:     []byte("seq"),
+	}
+}
+
+func TestNewP2PBridge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil message handler should error", func(t *testing.T) {
+		args := createArgs()
+		args.MessageHandler = nil
+		bridge, err := NewP2PBridge(args)
+		require.Nil(t, bridge)
+		require.Equal(t, p2p.ErrNilMessageHandler, err)
+	})
+	t.Run("nil websocket host should error", func(t *testing.T) {
+		args := createArgs()
+		args.WebSocketHost = nil
+		bridge, err := NewP2PBridge(args)
+		require.Nil(t, bridge)
+		require.Equal(t, p2p.ErrNilWebSocketSender, err)
+	})
+	t.Run("nil marshaller should error", func(t *testing.T) {
+		args := createArgs()
+		args.Marshaller = nil
+		bridge, err := NewP2PBridge(args)
+		require.Nil(t, bridge)
+		require.Equal(t, p2p.ErrNilMarshaller, err)
+	})
+	t.Run("nil logger should error", func(t *testing.T) {
+		args := createArgs()
+		args.Logger = nil
+		bridge, err := NewP2PBridge(args)
+		require.Nil(t, bridge)
+		require.Equal(t, p2p.ErrNilLogger, err)
+	})
+	t.Run("invalid queue size should error", func(t *testing.T) {
+		args := createArgs()
+		args.QueueSize = 0
+		bridge, err := NewP2PBridge(args)
+		require.Nil(t, bridge)
+		require.True(t, errors.Is(err, p2p.ErrInvalidQueueSize))
+	})
+	t.Run("no topics should error", func(t *testing.T) {
+		args := createArgs()
+		args.Topics = nil
+		bridge, err := NewP2PBridge(args)
+		require.Nil(t, bridge)
+		require.Equal(t, p2p.ErrNoTopicsToBridge, err)
+	})
+	t.Run("empty topic should error", func(t *testing.T) {
+		args := createArgs()
+		args.Topics = []TopicConfig{{}}
+		bridge, err := NewP2PBridge(args)
+		require.Nil(t, bridge)
+		require.Equal(t, p2p.ErrNilTopic, err)
+	})
+	t.Run("duplicated topic should error", func(t *testing.T) {
+		args := createArgs()
+		args.Topics = []TopicConfig{{Topic: testTopic}, {Topic: testTopic}}
+		bridge, err := NewP2PBridge(args)
+		require.Nil(t, bridge)
+		require.True(t, errors.Is(err, p2p.ErrDuplicatedTopic))
+	})
+	t.Run("create topic fails should error", func(t *testing.T) {
+		expectedErr := errors.New("expected error")
+		args := createArgs()
+		args.MessageHandler = &mock.MessageHandlerStub{
+			CreateTopicCalled: func(name string, createChannelForTopic bool) error {
+				return expectedErr
+			},
+			SubscribeCalled: func(topic string) (<-chan p2p.MessageP2P, func()) {
+				require.Fail(t, "should not subscribe")
+				return nil, nil
+			},
+		}
+		bridge, err := NewP2PBridge(args)
+		require.Nil(t, bridge)
+		require.True(t, errors.Is(err, expectedErr))
+	})
+	t.Run("register processor fails should error", func(t *testing.T) {
+		expectedErr := errors.New("expected error")
+		args := createArgs()
+		args.MessageHandler = &mock.MessageHandlerStub{
+			HasTopicCalled: func(name string) bool {
+				return true
+			},
+			RegisterMessageProcessorCalled: func(topic string, identifier string, handler p2p.MessageProcessor) error {
+				return expectedErr
+			},
+		}
+		bridge, err := NewP2PBridge(args)
+		require.Nil(t, bridge)
+		require.Equal(t, expectedErr, err)
+	})
+	t.Run("should create the missing topics, register and subscribe", func(t *testing.T) {
+		createdTopics := make([]string, 0)
+		registered := make(map[string]string)
+		subscribedTopics := make([]string, 0)
+		args := createArgs()
+		args.Identifier = "observer"
+		args.MessageHandler = &mock.MessageHandlerStub{
+			HasTopicCalled: func(name string) bool {
+				return false
+			},
+			CreateTopicCalled: func(name string, createChannelForTopic bool) error {
+				require.False(t, createChannelForTopic)
+				createdTopics = append(createdTopics, name)
+				return nil
+			},
+			RegisterMessageProcessorCalled: func(topic string, identifier string, handler p2p.MessageProcessor) error {
+				registered[topic] = identifier
+				return nil
+			},
+			SubscribeCalled: func(topic string) (<-chan p2p.MessageP2P, func()) {
+				subscribedTopics = append(subscribedTopics, topic)
+				return make(chan p2p.MessageP2P), func() {}
+			},
+		}
+		bridge, err := NewP2PBridge(args)
+		require.Nil(t, err)
+		require.False(t, bridge.IsInterfaceNil())
+		require.Equal(t, []string{testTopic}, createdTopics)
+		require.Equal(t, map[string]string{testTopic: "observer"}, registered)
+		require.Equal(t, []string{testTopic}, subscribedTopics)
+		_ = bridge.Close()
+	})
+}
+
+func TestP2PBridge_ShouldForwardTheSubscriptionMessages(t *testing.T) {
+	t.Parallel()
+
+	chMessages := make(chan p2p.MessageP2P, 1)
+	chSent := make(chan []byte, 1)
+	args := createArgs()
+	args.MessageHandler = &mock.MessageHandlerStub{
+		HasTopicCalled: func(name string) bool {
+			return true
+		},
+		SubscribeCalled: func(topic string) (<-chan p2p.MessageP2P, func()) {
+			return chMessages, func() {}
+		},
+	}
+	args.WebSocketHost = &mock.WebSocketSenderStub{
+		SendCalled: func(payload []byte, topic string) error {
+			chSent <- payload
+			return nil
+		},
+	}
+	bridge, _ := NewP2PBridge(args)
+	defer func() {
+		_ = bridge.Close()
+	}()
+
+	chMessages <- createMessage(testTopic, []byte("tx"))
+	select {
+	case payload := <-chSent:
+		bridged := &BridgedMessage{}
+		require.Nil(t, json.Unmarshal(payload, bridged))
+		require.Equal(t, []byte("tx"), bridged.Payload)
+	case <-time.After(time.Second):
+		require.Fail(t, "timeout waiting for the message to be forwarded")
+	}
+}
+
+func TestP2PBridge_HandleMessageShouldForward(t *testing.T) {
+	t.Parallel()
+
+	chSent := make(chan []byte, 1)
+	args := createArgs()
+	args.Topics = []TopicConfig{{Topic: testTopic, WebSocketTopic: "ws topic"}}
+	args.WebSocketHost = &mock.WebSocketSenderStub{
+		SendCalled: func(payload []byte, topic string) error {
+			require.Equal(t, "ws topic", topic)
+			chSent <- payload
+			return nil
+		},
+	}
+	bridge, _ := NewP2PBridge(args)
+	defer func() {
+		_ = bridge.Close()
+	}()
+
+	bridge.handleMessage(createMessage(testTopic, []byte("tx")))
+
+	select {
+	case payload := <-chSent:
+		bridged := &BridgedMessage{}
+		require.Nil(t, json.Unmarshal(payload, bridged))
+		require.Equal(t, &BridgedMessage{
+			Topic:      testTopic,
+			Originator: []byte("originator"),
+			SeqNo:      []byte("seq"),
+			Timestamp:  1234,
+			Payload:    []byte("tx"),
+		}, bridged)
+	case <-time.After(time.Second):
+		require.Fail(t, "timeout waiting for the message to be forwarded")
+	}
+}
+
+func TestP2PBridge_HandleMessageShouldApplyFiltersAndSampling(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	args.QueueSize = 100
+	args.Topics = []TopicConfig{{
+		Topic:                 testTopic,
+		SampleEvery:           3,
+		MaxPayloadSizeInBytes: 4,
+		Filter: &messageFilterStub{
+			shouldForwardCalled: func(message p2p.MessageP2P) bool {
+				return string(message.Data()) != "skip"
+			},
+		},
+	}}
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	args.WebSocketHost = &mock.WebSocketSenderStub{
+		SendCalled: func(payload []byte, topic string) error {
+			wg.Done()
+			return nil
+		},
+	}
+	bridge, _ := NewP2PBridge(args)
+	defer func() {
+		_ = bridge.Close()
+	}()
+
+	bridge.handleMessage(createMessage("other topic", []byte("tx")))
+	bridge.handleMessage(createMessage(testTopic, []byte("too large")))
+	bridge.handleMessage(createMessage(testTopic, []byte("skip")))
+	for i := 0; i < 6; i++ {
+		bridge.handleMessage(createMessage(testTopic, []byte("tx")))
+	}
+
+	wg.Wait()
+	metrics := bridge.GetMetrics()
+	require.Equal(t, uint64(2), metrics.NumFiltered)
+	require.Equal(t, uint64(4), metrics.NumSampledOut)
+	require.Equal(t, uint64(0), metrics.NumDropped)
+}
+
+func TestP2PBridge_SlowWebSocketHostShouldNotBlock(t *testing.T) {
+	t.Parallel()
+
+	chRelease := make(chan struct{})
+	args := createArgs()
+	args.QueueSize = 2
+	args.WebSocketHost = &mock.WebSocketSenderStub{
+		SendCalled: func(payload []byte, topic string) error {
+			<-chRelease
+			return nil
+		},
+	}
+	bridge, _ := NewP2PBridge(args)
+
+	chDone := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			bridge.handleMessage(createMessage(testTopic, []byte("tx")))
+		}
+		close(chDone)
+	}()
+
+	select {
+	case <-chDone:
+	case <-time.After(time.Second):
+		require.Fail(t, "handleMessage should not block")
+	}
+
+	metrics := bridge.GetMetrics()
+	require.True(t, metrics.NumDropped >= 7)
+	close(chRelease)
+	_ = bridge.Close()
+}
+
+func TestP2PBridge_SendErrorsShouldBeCounted(t *testing.T) {
+	t.Parallel()
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	args := createArgs()
+	args.WebSocketHost = &mock.WebSocketSenderStub{
+		SendCalled: func(payload []byte, topic string) error {
+			defer wg.Done()
+			return errors.New("no clients connected")
+		},
+	}
+	bridge, _ := NewP2PBridge(args)
+	defer func() {
+		_ = bridge.Close()
+	}()
+
+	bridge.handleMessage(createMessage(testTopic, []byte("tx")))
+	wg.Wait()
+
+	require.Eventually(t, func() bool {
+		return bridge.GetMetrics().NumSendErrors == 1
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, uint64(0), bridge.GetMetrics().NumForwarded)
+}
+
+func TestP2PBridge_ProcessReceivedMessageShouldAcceptWithoutForwarding(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	args.WebSocketHost = &mock.WebSocketSenderStub{
+		SendCalled: func(payload []byte, topic string) error {
+			require.Fail(t, "should forward only the messages read from the subscription")
+			return nil
+		},
+	}
+	bridge, _ := NewP2PBridge(args)
+
+	err := bridge.ProcessReceivedMessage(createMessage(testTopic, []byte("tx")), "", nil)
+	require.Nil(t, err)
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, uint64(0), bridge.GetMetrics().NumForwarded)
+	_ = bridge.Close()
+}
+
+func TestP2PBridge_CloseShouldUnregisterAndCancelTheSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	numCancelled := 0
+	unregistered := make([]string, 0)
+	args := createArgs()
+	args.MessageHandler = &mock.MessageHandlerStub{
+		HasTopicCalled: func(name string) bool {
+			return true
+		},
+		SubscribeCalled: func(topic string) (<-chan p2p.MessageP2P, func()) {
+			return make(chan p2p.MessageP2P), func() {
+				numCancelled++
+			}
+		},
+		UnregisterMessageProcessorCalled: func(topic string, identifier string) error {
+			require.Equal(t, defaultIdentifier, identifier)
+			unregistered = append(unregistered, topic)
+			return nil
+		},
+	}
+	bridge, _ := NewP2PBridge(args)
+
+	err := bridge.Close()
+	require.Nil(t, err)
+	require.Equal(t, 1, numCancelled)
+	require.Equal(t, []string{testTopic}, unregistered)
+}
@@ -1,10 +1,14 @@
 package testscommon
 
-import "context"
+import (
+	"context"
+	"net"
+)
 
 // HttpServerStub -
 type HttpServerStub struct {
 	ListenAndServeCalled func() error
+	ServeCalled          func(listener net.Listener) error
 	ShutdownCalled       func(ctx context.Context) error
 }
 
@@ -17,6 +21,15 @@ func (h *HttpServerStub) ListenAndServe() error {
 	return nil
 }
 
+// Serve -
+func (h *HttpServerStub) Serve(listener net.Listener) error {
+	if h.ServeCalled != nil {
+		return h.ServeCalled(listener)
+	}
+
+	return nil
+}
+
 //Shutdown -
 func (h *HttpServerStub) Shutdown(ctx context.Context) error {
 	if h.ShutdownCalled != nil {
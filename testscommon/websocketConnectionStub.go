@@ -1,13 +1,16 @@
 package testscommon
 
+import "time"
+
 // WebsocketConnectionStub -
 type WebsocketConnectionStub struct {
-	OpenConnectionCalled func(url string) error
-	ReadMessageCalled    func() (messageType int, payload []byte, err error)
-	WriteMessageCalled   func(messageType int, data []byte) error
-	IsOpenCalled         func() bool
-	GetIDCalled          func() string
-	CloseCalled          func() error
+	OpenConnectionCalled  func(url string) error
+	ReadMessageCalled     func() (messageType int, payload []byte, err error)
+	SetReadDeadlineCalled func(deadline time.Time) error
+	WriteMessageCalled    func(messageType int, data []byte) error
+	IsOpenCalled          func() bool
+	GetIDCalled           func() string
+	CloseCalled           func() error
 }
 
 // IsOpen --
@@ -37,6 +40,15 @@ func (w *WebsocketConnectionStub) ReadMessage() (messageType int, payload []byte
 	return 0, nil, err
 }
 
+// SetReadDeadline -
+func (w *WebsocketConnectionStub) SetReadDeadline(deadline time.Time) error {
+	if w.SetReadDeadlineCalled != nil {
+		return w.SetReadDeadlineCalled(deadline)
+	}
+
+	return nil
+}
+
 // WriteMessage -
 func (w *WebsocketConnectionStub) WriteMessage(messageType int, data []byte) error {
 	if w.WriteMessageCalled != nil {
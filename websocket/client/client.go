@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"path"
 	"time"
 
 	"github.com/TerraDharitri/drt-go-chain-communication/websocket"
@@ -23,9 +24,14 @@ type ArgsWebSocketClient struct {
 	BlockingAckOnError         bool
 	DropMessagesIfNoConnection bool
 	URL                        string
+	Transport                  string
+	Route                      string
+	UseURLPathAsPrefix         bool
 	PayloadConverter           websocket.PayloadConverter
 	Log                        core.Logger
 	PayloadVersion             uint32
+	TopicPolicies              []data.TopicPolicy
+	Handshake                  data.HandshakeConfig
 }
 
 type client struct {
@@ -35,6 +41,7 @@ type client struct {
 	log                        core.Logger
 	wsConn                     websocket.WSConClient
 	transceiver                Transceiver
+	sendMetrics                websocket.SendMetricsHandler
 	dropMessagesIfNoConnection bool
 }
 
@@ -45,6 +52,7 @@ func NewWebSocketClient(args ArgsWebSocketClient) (*client, error) {
 		return nil, err
 	}
 
+	sendMetrics := websocket.NewSendMetrics()
 	argsTransceiver := transceiver.ArgsTransceiver{
 		PayloadConverter:   args.PayloadConverter,
 		Log:                args.Log,
@@ -53,25 +61,32 @@ func NewWebSocketClient(args ArgsWebSocketClient) (*client, error) {
 		BlockingAckOnError: args.BlockingAckOnError,
 		WithAcknowledge:    args.WithAcknowledge,
 		PayloadVersion:     args.PayloadVersion,
+		TopicPolicies:      args.TopicPolicies,
+		SendMetrics:        sendMetrics,
+		Handshake:          args.Handshake,
 	}
 	wsTransceiver, err := transceiver.NewTransceiver(argsTransceiver)
 	if err != nil {
 		return nil, err
 	}
 
-	wsUrl, err := url.Parse(args.URL)
-	if err != nil || (wsUrl.Scheme != "ws" && wsUrl.Scheme != "wss") {
-		return nil, fmt.Errorf("invalid WebSocket URL: %v", err)
+	wsUrl, err := createURL(args)
+	if err != nil {
+		return nil, err
 	}
 
-	wsUrl.Path = data.WSRoute
+	dialer, err := connection.NewDialer(args.Transport, args.URL)
+	if err != nil {
+		return nil, err
+	}
 
 	wsClient := &client{
 		url:                        wsUrl.String(),
-		wsConn:                     connection.NewWSConnClient(),
+		wsConn:                     connection.NewWSConnClientWithDialer(dialer),
 		retryDuration:              time.Duration(args.RetryDurationInSeconds) * time.Second,
 		safeCloser:                 closing.NewSafeChanCloser(),
 		transceiver:                wsTransceiver,
+		sendMetrics:                sendMetrics,
 		log:                        args.Log,
 		dropMessagesIfNoConnection: args.DropMessagesIfNoConnection,
 	}
@@ -94,7 +109,39 @@ func checkArgs(args ArgsWebSocketClient) error {
 	if args.RetryDurationInSeconds == 0 {
 		return data.ErrZeroValueRetryDuration
 	}
-	return nil
+	return connection.CheckTransport(args.Transport)
+}
+
+func createURL(args ArgsWebSocketClient) (*url.URL, error) {
+	route := args.Route
+	if route == "" {
+		route = data.WSRoute
+	}
+
+	isTCP := args.Transport == "" || args.Transport == data.TransportTCP
+	if !isTCP {
+		// the URL holds the socket path or the in-memory listener name, the connection being dialed by the transport dialer
+		return &url.URL{
+			Scheme: "ws",
+			Host:   "localhost",
+			Path:   route,
+		}, nil
+	}
+
+	wsUrl, err := url.Parse(args.URL)
+	if err != nil || (wsUrl.Scheme != "ws" && wsUrl.Scheme != "wss") {
+		return nil, fmt.Errorf("invalid WebSocket URL: %v", err)
+	}
+
+	if !args.UseURLPathAsPrefix {
+		wsUrl.Path = route
+		return wsUrl, nil
+	}
+
+	// the path from the provided URL is kept as prefix, so the client can sit behind a path-prefixing proxy
+	wsUrl.Path = path.Join("/", wsUrl.Path, route)
+
+	return wsUrl, nil
 }
 
 func (c *client) start() {
@@ -148,6 +195,11 @@ func (c *client) Send(payload []byte, topic string) error {
 	return c.transceiver.Send(payload, topic, c.wsConn)
 }
 
+// GetSendMetrics returns the counters of the send operations
+func (c *client) GetSendMetrics() data.SendMetrics {
+	return c.sendMetrics.GetSendMetrics()
+}
+
 // SetPayloadHandler set the payload handler
 func (c *client) SetPayloadHandler(handler websocket.PayloadHandler) error {
 	return c.transceiver.SetPayloadHandler(handler)
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"errors"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -60,6 +61,61 @@ func TestNewWebSocketServer(t *testing.T) {
 		require.Nil(t, ws)
 		require.Equal(t, data.ErrZeroValueRetryDuration, err)
 	})
+
+	t.Run("default route should be used", func(t *testing.T) {
+		args := createArgs()
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, err)
+		require.Equal(t, "ws://localhost:12354/save", ws.url)
+		_ = ws.Close()
+	})
+
+	t.Run("custom route should be used", func(t *testing.T) {
+		args := createArgs()
+		args.Route = "/blocks"
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, err)
+		require.Equal(t, "ws://localhost:12354/blocks", ws.url)
+		_ = ws.Close()
+	})
+
+	t.Run("path from url should be replaced by the route", func(t *testing.T) {
+		args := createArgs()
+		args.URL = "ws://localhost:12354/save"
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, err)
+		require.Equal(t, "ws://localhost:12354/save", ws.url)
+		_ = ws.Close()
+	})
+
+	t.Run("path from url should be kept as prefix if enabled", func(t *testing.T) {
+		args := createArgs()
+		args.URL = "ws://localhost:12354/proxy/"
+		args.Route = "/blocks"
+		args.UseURLPathAsPrefix = true
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, err)
+		require.Equal(t, "ws://localhost:12354/proxy/blocks", ws.url)
+		_ = ws.Close()
+	})
+	t.Run("invalid transport, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.Transport = "udp"
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, ws)
+		require.True(t, errors.Is(err, data.ErrInvalidTransport))
+	})
+
+	t.Run("memory transport should use the route on a local url", func(t *testing.T) {
+		args := createArgs()
+		args.URL = "indexer"
+		args.Transport = data.TransportMemory
+		args.Route = "/blocks"
+		ws, err := NewWebSocketClient(args)
+		require.Nil(t, err)
+		require.Equal(t, "ws://localhost/blocks", ws.url)
+		_ = ws.Close()
+	})
 }
 
 func TestClient_SendAndClose(t *testing.T) {
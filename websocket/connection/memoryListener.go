@@ -0,0 +1,111 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/websocket/data"
+)
+
+const memoryNetwork = "memory"
+
+var (
+	mutMemoryListeners sync.Mutex
+	memoryListeners    = make(map[string]*memoryListener)
+)
+
+type memoryAddr string
+
+// Network returns the name of the network
+func (addr memoryAddr) Network() string {
+	return memoryNetwork
+}
+
+// String returns the address
+func (addr memoryAddr) String() string {
+	return string(addr)
+}
+
+// memoryListener is a net.Listener that accepts the in-process connections dialed on its address
+type memoryListener struct {
+	address   string
+	chConns   chan net.Conn
+	chClosed  chan struct{}
+	closeOnce sync.Once
+}
+
+func newMemoryListener(address string) (*memoryListener, error) {
+	mutMemoryListeners.Lock()
+	defer mutMemoryListeners.Unlock()
+
+	_, found := memoryListeners[address]
+	if found {
+		return nil, fmt.Errorf("%w: %s", data.ErrInMemoryListenerAlreadyExists, address)
+	}
+
+	ml := &memoryListener{
+		address:  address,
+		chConns:  make(chan net.Conn),
+		chClosed: make(chan struct{}),
+	}
+	memoryListeners[address] = ml
+
+	return ml, nil
+}
+
+// Accept waits for and returns the next connection
+func (ml *memoryListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-ml.chConns:
+		return conn, nil
+	case <-ml.chClosed:
+		return nil, data.ErrListenerClosed
+	}
+}
+
+// Close will unregister the listener. The already accepted connections are not closed
+func (ml *memoryListener) Close() error {
+	ml.closeOnce.Do(func() {
+		mutMemoryListeners.Lock()
+		delete(memoryListeners, ml.address)
+		mutMemoryListeners.Unlock()
+
+		close(ml.chClosed)
+	})
+
+	return nil
+}
+
+// Addr returns the listener's address
+func (ml *memoryListener) Addr() net.Addr {
+	return memoryAddr(ml.address)
+}
+
+func (ml *memoryListener) dial(ctx context.Context) (net.Conn, error) {
+	clientConn, serverConn := net.Pipe()
+
+	select {
+	case ml.chConns <- serverConn:
+		return clientConn, nil
+	case <-ml.chClosed:
+	case <-ctx.Done():
+	}
+
+	_ = clientConn.Close()
+	_ = serverConn.Close()
+
+	return nil, fmt.Errorf("%w: %s", data.ErrInMemoryListenerNotFound, ml.address)
+}
+
+func dialMemory(ctx context.Context, address string) (net.Conn, error) {
+	mutMemoryListeners.Lock()
+	ml, found := memoryListeners[address]
+	mutMemoryListeners.Unlock()
+	if !found {
+		return nil, fmt.Errorf("%w: %s", data.ErrInMemoryListenerNotFound, address)
+	}
+
+	return ml.dial(ctx)
+}
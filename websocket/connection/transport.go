@@ -0,0 +1,87 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/websocket/data"
+	"github.com/gorilla/websocket"
+)
+
+// CheckTransport returns an error if the provided transport is not supported
+func CheckTransport(transport string) error {
+	switch transport {
+	case "", data.TransportTCP, data.TransportUnix, data.TransportMemory:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", data.ErrInvalidTransport, transport)
+	}
+}
+
+// NewListener creates a listener on the provided address, for the provided transport
+func NewListener(transport string, address string) (net.Listener, error) {
+	switch transport {
+	case "", data.TransportTCP:
+		return net.Listen("tcp", address)
+	case data.TransportUnix:
+		err := removeStaleSocket(address)
+		if err != nil {
+			return nil, err
+		}
+		return net.Listen("unix", address)
+	case data.TransportMemory:
+		return newMemoryListener(address)
+	default:
+		return nil, fmt.Errorf("%w: %s", data.ErrInvalidTransport, transport)
+	}
+}
+
+// removeStaleSocket removes the socket file left behind by a previous run. Any other file found at the address is kept
+func removeStaleSocket(address string) error {
+	fileInfo, err := os.Lstat(address)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if fileInfo.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%w: %s", data.ErrNotUnixSocket, address)
+	}
+
+	conn, err := net.Dial("unix", address)
+	if err == nil {
+		// the socket is still in use, the listen call will report the address as taken
+		_ = conn.Close()
+		return nil
+	}
+
+	return os.Remove(address)
+}
+
+// NewDialer creates a websocket dialer that connects to the provided address, for the provided transport.
+// For the tcp transport, the address from the dialed URL is used
+func NewDialer(transport string, address string) (*websocket.Dialer, error) {
+	dialer := *websocket.DefaultDialer
+
+	switch transport {
+	case "", data.TransportTCP:
+		return &dialer, nil
+	case data.TransportUnix:
+		dialer.NetDialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var netDialer net.Dialer
+			return netDialer.DialContext(ctx, "unix", address)
+		}
+		return &dialer, nil
+	case data.TransportMemory:
+		dialer.NetDialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialMemory(ctx, address)
+		}
+		return &dialer, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", data.ErrInvalidTransport, transport)
+	}
+}
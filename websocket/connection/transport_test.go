@@ -0,0 +1,134 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/websocket/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckTransport(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, CheckTransport(""))
+	require.Nil(t, CheckTransport(data.TransportTCP))
+	require.Nil(t, CheckTransport(data.TransportUnix))
+	require.Nil(t, CheckTransport(data.TransportMemory))
+	require.True(t, errors.Is(CheckTransport("udp"), data.ErrInvalidTransport))
+}
+
+func TestNewListener(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid transport, should return error", func(t *testing.T) {
+		listener, err := NewListener("udp", "address")
+		require.Nil(t, listener)
+		require.True(t, errors.Is(err, data.ErrInvalidTransport))
+	})
+
+	t.Run("unix transport should work", func(t *testing.T) {
+		socketPath := filepath.Join(t.TempDir(), "ws.sock")
+		listener, err := NewListener(data.TransportUnix, socketPath)
+		require.Nil(t, err)
+		require.Equal(t, "unix", listener.Addr().Network())
+		_ = listener.Close()
+	})
+
+	t.Run("unix transport on a stale socket should work", func(t *testing.T) {
+		socketPath := filepath.Join(t.TempDir(), "ws.sock")
+		listener, err := NewListener(data.TransportUnix, socketPath)
+		require.Nil(t, err)
+		listener.(*net.UnixListener).SetUnlinkOnClose(false)
+		_ = listener.Close()
+
+		listener, err = NewListener(data.TransportUnix, socketPath)
+		require.Nil(t, err)
+		_ = listener.Close()
+	})
+
+	t.Run("unix transport on a socket in use, should return error", func(t *testing.T) {
+		socketPath := filepath.Join(t.TempDir(), "ws.sock")
+		listener, err := NewListener(data.TransportUnix, socketPath)
+		require.Nil(t, err)
+
+		listener2, err := NewListener(data.TransportUnix, socketPath)
+		require.Nil(t, listener2)
+		require.NotNil(t, err)
+		_ = listener.Close()
+	})
+
+	t.Run("unix transport on a file that is not a socket, should return error and keep the file", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "data.txt")
+		require.Nil(t, os.WriteFile(filePath, []byte("data"), 0600))
+
+		listener, err := NewListener(data.TransportUnix, filePath)
+		require.Nil(t, listener)
+		require.True(t, errors.Is(err, data.ErrNotUnixSocket))
+		require.FileExists(t, filePath)
+	})
+
+	t.Run("memory transport on an address already in use, should return error", func(t *testing.T) {
+		listener, err := NewListener(data.TransportMemory, "listener-duplicated")
+		require.Nil(t, err)
+
+		listener2, err := NewListener(data.TransportMemory, "listener-duplicated")
+		require.Nil(t, listener2)
+		require.True(t, errors.Is(err, data.ErrInMemoryListenerAlreadyExists))
+
+		_ = listener.Close()
+		listener2, err = NewListener(data.TransportMemory, "listener-duplicated")
+		require.Nil(t, err)
+		_ = listener2.Close()
+	})
+}
+
+func TestMemoryListener_AcceptAndDial(t *testing.T) {
+	t.Parallel()
+
+	listener, err := NewListener(data.TransportMemory, "listener-accept")
+	require.Nil(t, err)
+	defer func() {
+		_ = listener.Close()
+	}()
+
+	chServerConn := make(chan []byte)
+	go func() {
+		conn, errAccept := listener.Accept()
+		require.Nil(t, errAccept)
+
+		buff := make([]byte, 4)
+		_, _ = conn.Read(buff)
+		chServerConn <- buff
+	}()
+
+	clientConn, err := dialMemory(context.Background(), "listener-accept")
+	require.Nil(t, err)
+	_, err = clientConn.Write([]byte("test"))
+	require.Nil(t, err)
+	require.Equal(t, []byte("test"), <-chServerConn)
+}
+
+func TestMemoryListener_DialShouldErrorIfNoListener(t *testing.T) {
+	t.Parallel()
+
+	conn, err := dialMemory(context.Background(), "missing listener")
+	require.Nil(t, conn)
+	require.True(t, errors.Is(err, data.ErrInMemoryListenerNotFound))
+}
+
+func TestMemoryListener_AcceptShouldErrorAfterClose(t *testing.T) {
+	t.Parallel()
+
+	listener, err := NewListener(data.TransportMemory, "listener-closed")
+	require.Nil(t, err)
+	_ = listener.Close()
+
+	conn, err := listener.Accept()
+	require.Nil(t, conn)
+	require.Equal(t, data.ErrListenerClosed, err)
+}
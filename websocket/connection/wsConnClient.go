@@ -3,6 +3,7 @@ package connection
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/TerraDharitri/drt-go-chain-communication/websocket/data"
 	logger "github.com/TerraDharitri/drt-go-chain-logger"
@@ -14,18 +15,29 @@ var log = logger.GetOrCreate("connection")
 type wsConnClient struct {
 	mut      sync.RWMutex
 	conn     *websocket.Conn
+	dialer   *websocket.Dialer
 	clientID string
 }
 
 // NewWSConnClient creates a new wrapper over a websocket connection
 func NewWSConnClient() *wsConnClient {
-	return &wsConnClient{}
+	return &wsConnClient{
+		dialer: websocket.DefaultDialer,
+	}
+}
+
+// NewWSConnClientWithDialer creates a new wrapper over a websocket connection that will be opened with the provided dialer
+func NewWSConnClientWithDialer(dialer *websocket.Dialer) *wsConnClient {
+	return &wsConnClient{
+		dialer: dialer,
+	}
 }
 
 // NewWSConnClientWithConn creates a new wrapper over a provided websocket connection
 func NewWSConnClientWithConn(conn *websocket.Conn) *wsConnClient {
 	wsc := &wsConnClient{
-		conn: conn,
+		conn:   conn,
+		dialer: websocket.DefaultDialer,
 	}
 	wsc.clientID = fmt.Sprintf("%p", wsc)
 
@@ -42,7 +54,7 @@ func (wsc *wsConnClient) OpenConnection(url string) error {
 	}
 
 	var err error
-	wsc.conn, _, err = websocket.DefaultDialer.Dial(url, nil)
+	wsc.conn, _, err = wsc.dialer.Dial(url, nil)
 	if err != nil {
 		return err
 	}
@@ -60,6 +72,16 @@ func (wsc *wsConnClient) ReadMessage() (messageType int, p []byte, err error) {
 	return conn.ReadMessage()
 }
 
+// SetReadDeadline sets the read deadline of the underlying ws connection. A zero value means no deadline
+func (wsc *wsConnClient) SetReadDeadline(deadline time.Time) error {
+	conn, err := wsc.getConn()
+	if err != nil {
+		return err
+	}
+
+	return conn.SetReadDeadline(deadline)
+}
+
 // WriteMessage calls the underlying write message ws connection func
 func (wsc *wsConnClient) WriteMessage(messageType int, payload []byte) error {
 	wsc.mut.Lock()
@@ -40,3 +40,66 @@ var ErrNoClientsConnected = errors.New("no client connected")
 
 // ErrAckTimeout signals that an acknowledgment timeout has been reached
 var ErrAckTimeout = errors.New("acknowledge waiting timeout occurred")
+
+// ErrEmptyEndpointName signals that an endpoint without a name has been provided
+var ErrEmptyEndpointName = errors.New("empty endpoint name provided")
+
+// ErrDuplicatedEndpointName signals that the same endpoint name has been provided more than once
+var ErrDuplicatedEndpointName = errors.New("duplicated endpoint name")
+
+// ErrDuplicatedRoute signals that the same route has been provided more than once
+var ErrDuplicatedRoute = errors.New("duplicated route")
+
+// ErrEndpointNotFound signals that the requested endpoint does not exist
+var ErrEndpointNotFound = errors.New("endpoint not found")
+
+// ErrEmptyRoute signals that an empty route has been provided
+var ErrEmptyRoute = errors.New("empty route provided")
+
+// ErrEmptyTopic signals that an empty topic has been provided
+var ErrEmptyTopic = errors.New("empty topic provided")
+
+// ErrDuplicatedTopicPolicy signals that more than one delivery policy has been provided for the same topic
+var ErrDuplicatedTopicPolicy = errors.New("duplicated topic policy")
+
+// ErrInvalidDeliveryPolicy signals that an invalid delivery policy has been provided
+var ErrInvalidDeliveryPolicy = errors.New("invalid delivery policy")
+
+// ErrNegativeMaxRetries signals that a negative number of retries has been provided
+var ErrNegativeMaxRetries = errors.New("negative max retries provided")
+
+// ErrNegativeTimeToLive signals that a negative time to live has been provided
+var ErrNegativeTimeToLive = errors.New("negative time to live provided")
+
+// ErrUnknownFeature signals that an unknown handshake feature has been provided
+var ErrUnknownFeature = errors.New("unknown feature")
+
+// ErrHandshakeNotDone signals that the handshake with the peer was not done yet
+var ErrHandshakeNotDone = errors.New("handshake not done")
+
+// ErrInvalidHandshake signals that an invalid handshake message has been received
+var ErrInvalidHandshake = errors.New("invalid handshake")
+
+// ErrProtocolVersionMismatch signals that the peers use different protocol versions
+var ErrProtocolVersionMismatch = errors.New("protocol version mismatch")
+
+// ErrMarshallerTypeMismatch signals that the peers use different marshallers
+var ErrMarshallerTypeMismatch = errors.New("marshaller type mismatch")
+
+// ErrPayloadVersionNotSupported signals that a payload version is not supported by the peer
+var ErrPayloadVersionNotSupported = errors.New("payload version not supported")
+
+// ErrInvalidTransport signals that an invalid transport has been provided
+var ErrInvalidTransport = errors.New("invalid transport")
+
+// ErrNotUnixSocket signals that the address of a unix socket holds a file that is not a socket
+var ErrNotUnixSocket = errors.New("the address holds a file that is not a unix socket")
+
+// ErrInMemoryListenerNotFound signals that no in-memory listener was registered on the provided address
+var ErrInMemoryListenerNotFound = errors.New("in-memory listener not found")
+
+// ErrInMemoryListenerAlreadyExists signals that an in-memory listener was already registered on the provided address
+var ErrInMemoryListenerAlreadyExists = errors.New("in-memory listener already exists")
+
+// ErrListenerClosed signals that the listener was closed
+var ErrListenerClosed = errors.New("listener closed")
@@ -0,0 +1,22 @@
+package data
+
+const (
+	// ProtocolVersion is the version of the protocol advertised in the handshake
+	ProtocolVersion = 1
+	// FeatureCompression is the handshake feature that signals support for compressed payloads
+	FeatureCompression = "compression"
+	// FeatureWindowedAck is the handshake feature that signals support for acknowledging a window of messages at once
+	FeatureWindowedAck = "windowed-ack"
+	// FeatureSubscriptions is the handshake feature that signals support for topic subscriptions
+	FeatureSubscriptions = "subscriptions"
+)
+
+// Handshake is the first message exchanged by two peers after a connection is established.
+// It is always JSON encoded, regardless of the marshaller used for the rest of the messages.
+// The marshaller type is derived from the marshaller instance used for the rest of the messages.
+type Handshake struct {
+	ProtocolVersion uint32   `json:"protocolVersion"`
+	MarshallerType  string   `json:"marshallerType"`
+	PayloadVersions []uint32 `json:"payloadVersions"`
+	Features        []string `json:"features,omitempty"`
+}
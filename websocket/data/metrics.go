@@ -0,0 +1,7 @@
+package data
+
+// SendMetrics holds the counters of the send operations of a web socket host
+type SendMetrics struct {
+	NumExpiredDropped   uint64 // The number of messages dropped because their deadline has passed.
+	NumHighPrioritySent uint64 // The number of messages with a non-zero priority that were sent.
+}
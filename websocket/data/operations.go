@@ -1,22 +1,72 @@
 package data
 
 const (
-	// WSRoute is the route which data will be sent over websocket
+	// WSRoute is the default route which data will be sent over websocket
 	WSRoute = "/save"
 	// ModeServer is a constant value that is used to indicate that the WebSocket host should start in server mode, meaning it will listen for incoming connections from clients and respond to them.
 	ModeServer = "server"
 	// ModeClient is a constant value that is used to indicate that the WebSocket host should start in client mode, meaning it will initiate connections to a remote server.
 	ModeClient = "client"
+	// TransportTCP is the transport for which the WebSocket connections are established over TCP. The URL holds the network address.
+	TransportTCP = "tcp"
+	// TransportUnix is the transport for which the WebSocket connections are established over a Unix domain socket. The URL holds the socket path.
+	TransportUnix = "unix"
+	// TransportMemory is the transport for which the WebSocket connections are established over in-memory pipes, inside the same process.
+	// The URL holds the name of the in-memory listener.
+	TransportMemory = "memory"
+	// DeliveryAtMostOnce is the delivery policy for which a message is sent once, without waiting for an acknowledgement
+	DeliveryAtMostOnce = "at-most-once"
+	// DeliveryAtLeastOnce is the delivery policy for which a message is resent until it is acknowledged or the retry budget is exhausted.
+	// The receiver acknowledges the message even if its processing fails.
+	DeliveryAtLeastOnce = "at-least-once"
+	// DeliveryBlockingAck is the delivery policy for which a message is resent until it is acknowledged or the retry budget is exhausted.
+	// The receiver acknowledges the message only if its processing succeeds.
+	DeliveryBlockingAck = "blocking-ack"
 )
 
 // WebSocketConfig holds the configuration needed for instantiating a new web socket server
 type WebSocketConfig struct {
-	URL                        string // The WebSocket URL to connect to.
-	Mode                       string // The host operation mode: 'client' or 'server'.
-	RetryDurationInSec         int    // The duration in seconds to wait before retrying the connection in case of failure.
-	WithAcknowledge            bool   // Set to `true` to enable message acknowledgment mechanism.
-	AcknowledgeTimeoutInSec    int    // The duration in seconds to wait for an acknowledgement message
-	BlockingAckOnError         bool   // Set to `true` to send the acknowledgment message only if the processing part of a message succeeds. If an error occurs during processing, the acknowledgment will not be sent.
-	DropMessagesIfNoConnection bool   // Set to `true` to drop messages if there is no active WebSocket connection to send to.
-	Version                    uint32 // Defines the payload version.
+	URL                        string           // The WebSocket URL to connect to.
+	Transport                  string           // The transport used for the connections: 'tcp', 'unix' or 'memory'. If empty, 'tcp' will be used.
+	Mode                       string           // The host operation mode: 'client' or 'server'.
+	RetryDurationInSec         int              // The duration in seconds to wait before retrying the connection in case of failure.
+	WithAcknowledge            bool             // Set to `true` to enable message acknowledgment mechanism.
+	AcknowledgeTimeoutInSec    int              // The duration in seconds to wait for an acknowledgement message
+	BlockingAckOnError         bool             // Set to `true` to send the acknowledgment message only if the processing part of a message succeeds. If an error occurs during processing, the acknowledgment will not be sent. The setting of the sender is carried in the messages and applied by the receiver.
+	DropMessagesIfNoConnection bool             // Set to `true` to drop messages if there is no active WebSocket connection to send to.
+	Version                    uint32           // Defines the payload version.
+	Route                      string           // The route on which the data is exchanged. If empty, WSRoute will be used.
+	UseURLPathAsPrefix         bool             // Set to `true` to keep the path of the client URL as a prefix of the route, e.g. behind a path-prefixing proxy.
+	Endpoints                  []EndpointConfig // Additional named endpoints exposed by a server. Ignored in client mode.
+	TopicPolicies              []TopicPolicy    // Per-topic delivery policies. Topics without a policy use the global acknowledge settings.
+	Handshake                  HandshakeConfig  // The configuration of the handshake exchanged after a connection is established.
+}
+
+// EndpointConfig holds the configuration of a named endpoint exposed by a web socket server
+type EndpointConfig struct {
+	Name                    string        // The unique name of the endpoint.
+	Route                   string        // The route on which the endpoint is exposed.
+	WithAcknowledge         bool          // Set to `true` to enable message acknowledgment mechanism on this endpoint.
+	AcknowledgeTimeoutInSec int           // The duration in seconds to wait for an acknowledgement message
+	BlockingAckOnError      bool          // Set to `true` to send the acknowledgment message only if the processing part of a message succeeds.
+	Version                 uint32        // Defines the payload version used on this endpoint.
+	TopicPolicies           []TopicPolicy // Per-topic delivery policies used on this endpoint.
+}
+
+// HandshakeConfig holds the configuration of the handshake exchanged by the peers after a connection is established
+type HandshakeConfig struct {
+	Enabled                  bool     // Set to `true` to exchange a handshake before any other message. Both peers must enable it. The advertised marshaller type is derived from the marshaller in use.
+	SupportedPayloadVersions []uint32 // The payload versions that can be processed. If empty, only the configured payload version is supported.
+	Features                 []string // The optional features supported: 'compression', 'windowed-ack', 'subscriptions'.
+	TimeoutInSec             int      // The duration in seconds to wait for the handshake of the peer. If 0, the default timeout will be used.
+}
+
+// TopicPolicy holds the delivery policy of a topic
+type TopicPolicy struct {
+	Topic                   string // The topic the policy applies to.
+	Delivery                string // The delivery policy: 'at-most-once', 'at-least-once' or 'blocking-ack'.
+	AcknowledgeTimeoutInSec int    // The duration in seconds to wait for an acknowledgement message. Mandatory for acknowledged deliveries.
+	MaxRetries              int    // The number of times a message is resent if no acknowledgement is received in time.
+	TimeToLiveInSec         int    // The duration in seconds after which an unsent message is dropped. 0 means the messages never expire.
+	Priority                uint32 // The priority of the messages. Pending messages with a higher priority are sent first.
 }
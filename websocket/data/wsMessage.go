@@ -8,3 +8,14 @@ const (
 	// PayloadMessage holds the identifier for a payload message
 	PayloadMessage = 2
 )
+
+const (
+	// DeliveryModeUnspecified is the delivery mode of the messages sent by the peers that do not carry it. The receiver applies its own policy of the topic
+	DeliveryModeUnspecified = 0
+	// DeliveryModeAtMostOnce is the delivery mode of the messages sent without waiting for an acknowledgement
+	DeliveryModeAtMostOnce = 1
+	// DeliveryModeAtLeastOnce is the delivery mode of the messages acknowledged even if their processing fails
+	DeliveryModeAtLeastOnce = 2
+	// DeliveryModeBlockingAck is the delivery mode of the messages acknowledged only if their processing succeeds
+	DeliveryModeBlockingAck = 3
+)
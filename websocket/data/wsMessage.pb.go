@@ -34,6 +34,9 @@ type WsMessage struct {
 	Payload         []byte `protobuf:"bytes,4,opt,name=Payload,proto3" json:"payload,omitempty"`
 	Topic           string `protobuf:"bytes,5,opt,name=Topic,proto3" json:"topic,omitempty"`
 	Version         uint32 `protobuf:"varint,6,opt,name=Version,proto3" json:"version,omitempty"`
+	Deadline        int64  `protobuf:"varint,7,opt,name=Deadline,proto3" json:"deadline,omitempty"`
+	Priority        uint32 `protobuf:"varint,8,opt,name=Priority,proto3" json:"priority,omitempty"`
+	DeliveryMode    uint32 `protobuf:"varint,9,opt,name=DeliveryMode,proto3" json:"deliveryMode,omitempty"`
 }
 
 func (m *WsMessage) Reset()      { *m = WsMessage{} }
@@ -106,6 +109,27 @@ func (m *WsMessage) GetVersion() uint32 {
 	return 0
 }
 
+func (m *WsMessage) GetDeadline() int64 {
+	if m != nil {
+		return m.Deadline
+	}
+	return 0
+}
+
+func (m *WsMessage) GetPriority() uint32 {
+	if m != nil {
+		return m.Priority
+	}
+	return 0
+}
+
+func (m *WsMessage) GetDeliveryMode() uint32 {
+	if m != nil {
+		return m.DeliveryMode
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*WsMessage)(nil), "proto.WsMessage")
 }
@@ -113,31 +137,35 @@ func init() {
 func init() { proto.RegisterFile("wsMessage.proto", fileDescriptor_5e88e8c2dafbb96c) }
 
 var fileDescriptor_5e88e8c2dafbb96c = []byte{
-	// 381 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x91, 0xb1, 0x6e, 0xa3, 0x40,
-	0x10, 0x86, 0x59, 0x9f, 0xf1, 0x9d, 0xd1, 0xdd, 0x59, 0xc7, 0xe9, 0x24, 0xee, 0xa4, 0x5b, 0x50,
-	0x8a, 0x88, 0x48, 0xc1, 0x14, 0x29, 0x53, 0x05, 0x17, 0xa9, 0x22, 0x45, 0x91, 0x15, 0x4b, 0xe9,
-	0x60, 0xbd, 0xc1, 0x2b, 0x1b, 0x06, 0x99, 0x25, 0x98, 0x22, 0x52, 0x1e, 0x21, 0x8f, 0x91, 0x67,
-	0xc8, 0x13, 0xa4, 0x74, 0xe9, 0x0a, 0xc5, 0xeb, 0x26, 0xa2, 0xf2, 0x23, 0x44, 0x80, 0x2d, 0x91,
-	0x34, 0xbb, 0x33, 0xff, 0xfc, 0xff, 0x37, 0xc5, 0x28, 0xbd, 0x34, 0xbe, 0xa0, 0x71, 0xec, 0xfa,
-	0xb4, 0x1f, 0xcd, 0x81, 0x83, 0x2a, 0x57, 0xdf, 0x3f, 0xcb, 0x67, 0x7c, 0x92, 0x78, 0x7d, 0x02,
-	0x81, 0xed, 0x83, 0x0f, 0x76, 0x25, 0x7b, 0xc9, 0x6d, 0xd5, 0x55, 0x4d, 0x55, 0xd5, 0xa9, 0x83,
-	0xe7, 0x96, 0xd2, 0x1d, 0xed, 0x49, 0xea, 0xb9, 0xd2, 0x1b, 0x31, 0x3e, 0x39, 0x23, 0xd3, 0x10,
-	0xd2, 0x19, 0x1d, 0xfb, 0x54, 0x43, 0x06, 0x32, 0xbf, 0x39, 0xff, 0x8b, 0x5c, 0xff, 0x9b, 0x7e,
-	0x1c, 0x1d, 0x43, 0xc0, 0x38, 0x0d, 0x22, 0x9e, 0x5d, 0x7d, 0x4e, 0xa9, 0xb6, 0xf2, 0x75, 0x00,
-	0x49, 0xc8, 0xe9, 0x5c, 0x6b, 0x19, 0xc8, 0x6c, 0x3b, 0x7f, 0x8a, 0x5c, 0xff, 0x45, 0x6a, 0xa9,
-	0x11, 0xdc, 0xbb, 0xd4, 0x43, 0xa5, 0x3d, 0xcc, 0x22, 0xaa, 0x7d, 0x31, 0x90, 0x29, 0x3b, 0x6a,
-	0x91, 0xeb, 0x3f, 0x79, 0x16, 0x35, 0x77, 0x54, 0xf3, 0x12, 0x7c, 0xe9, 0x66, 0x33, 0x70, 0xc7,
-	0x5a, 0xdb, 0x40, 0xe6, 0xf7, 0x1a, 0x1c, 0xd5, 0x52, 0x13, 0xbc, 0x73, 0xa9, 0x47, 0x8a, 0x3c,
-	0x84, 0x88, 0x11, 0x4d, 0x36, 0x90, 0xd9, 0x75, 0x7e, 0x17, 0xb9, 0xde, 0xe3, 0xa5, 0xd0, 0x30,
-	0xd7, 0x8e, 0x92, 0x7d, 0x4d, 0xe7, 0x31, 0x83, 0x50, 0xeb, 0x18, 0xc8, 0xfc, 0x51, 0xb3, 0xef,
-	0x6a, 0xa9, 0xc9, 0xde, 0xb9, 0x9c, 0xfb, 0xe5, 0x1a, 0x4b, 0xab, 0x35, 0x96, 0xb6, 0x6b, 0x8c,
-	0x1e, 0x04, 0x46, 0x4f, 0x02, 0xa3, 0x17, 0x81, 0xd1, 0x52, 0x60, 0xb4, 0x12, 0x18, 0xbd, 0x0a,
-	0x8c, 0xde, 0x04, 0x96, 0xb6, 0x02, 0xa3, 0xc7, 0x0d, 0x96, 0x96, 0x1b, 0x2c, 0xad, 0x36, 0x58,
-	0xba, 0x19, 0x34, 0xae, 0x14, 0x24, 0x33, 0xce, 0xca, 0x2d, 0x0b, 0x3b, 0x58, 0x58, 0x64, 0xe2,
-	0xb2, 0xd0, 0x22, 0x10, 0x04, 0x49, 0xc8, 0x88, 0xcb, 0x19, 0x84, 0x96, 0x0f, 0x76, 0x4a, 0xbd,
-	0x18, 0xc8, 0x94, 0x72, 0x7b, 0xec, 0x72, 0xf7, 0xb4, 0x7c, 0xbc, 0x4e, 0x75, 0xc2, 0x93, 0xf7,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0xad, 0x9e, 0xfb, 0xcb, 0x0b, 0x02, 0x00, 0x00,
+	// 446 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x92, 0xc1, 0x6a, 0xd4, 0x40,
+	0x18, 0xc7, 0x33, 0xee, 0x6e, 0xbb, 0x1b, 0xaa, 0x8b, 0x23, 0x96, 0xb1, 0xe0, 0x24, 0x78, 0x90,
+	0x08, 0x6e, 0x03, 0x7a, 0x14, 0x04, 0xd3, 0x82, 0xa7, 0x42, 0x91, 0x62, 0xc1, 0x5b, 0x36, 0x19,
+	0xb3, 0x43, 0x93, 0x7c, 0x21, 0x99, 0x74, 0x9b, 0x83, 0xe0, 0x23, 0xf8, 0x18, 0x3e, 0x8a, 0xc7,
+	0x3d, 0xee, 0x29, 0xb8, 0xb3, 0x17, 0xc9, 0xa9, 0x8f, 0x20, 0x99, 0x64, 0x65, 0xd6, 0x4b, 0x32,
+	0xf3, 0x9f, 0xff, 0xef, 0xf7, 0x5d, 0x3e, 0x73, 0xba, 0x2c, 0x2e, 0x58, 0x51, 0xf8, 0x11, 0x3b,
+	0xcd, 0x72, 0x10, 0x80, 0x47, 0xea, 0x77, 0x32, 0x8b, 0xb8, 0x58, 0x94, 0xf3, 0xd3, 0x00, 0x12,
+	0x37, 0x82, 0x08, 0x5c, 0x15, 0xcf, 0xcb, 0xaf, 0xea, 0xa6, 0x2e, 0xea, 0xd4, 0x51, 0x2f, 0xe4,
+	0xc0, 0x9c, 0x5c, 0xef, 0x4c, 0xf8, 0xa3, 0x39, 0xbd, 0xe6, 0x62, 0xf1, 0x21, 0xb8, 0x49, 0x61,
+	0x19, 0xb3, 0x30, 0x62, 0x04, 0xd9, 0xc8, 0x19, 0x7b, 0xcf, 0x9b, 0xda, 0x7a, 0xb6, 0xdc, 0x7f,
+	0x7a, 0x0d, 0x09, 0x17, 0x2c, 0xc9, 0x44, 0xf5, 0xe9, 0x7f, 0x0a, 0xbb, 0xe6, 0xe1, 0x19, 0x94,
+	0xa9, 0x60, 0x39, 0x79, 0x60, 0x23, 0x67, 0xe8, 0x3d, 0x6d, 0x6a, 0xeb, 0x71, 0xd0, 0x45, 0x1a,
+	0xb8, 0x6b, 0xe1, 0x97, 0xe6, 0xf0, 0xaa, 0xca, 0x18, 0x19, 0xd8, 0xc8, 0x19, 0x79, 0xb8, 0xa9,
+	0xad, 0x47, 0xa2, 0xca, 0xf4, 0x19, 0xea, 0xbd, 0x15, 0x5f, 0xfa, 0x55, 0x0c, 0x7e, 0x48, 0x86,
+	0x36, 0x72, 0x8e, 0x3a, 0x71, 0xd6, 0x45, 0xba, 0xb8, 0x6f, 0xe1, 0x57, 0xe6, 0xe8, 0x0a, 0x32,
+	0x1e, 0x90, 0x91, 0x8d, 0x9c, 0x89, 0xf7, 0xa4, 0xa9, 0xad, 0xa9, 0x68, 0x03, 0xad, 0xdc, 0x35,
+	0x5a, 0xf7, 0x67, 0x96, 0x17, 0x1c, 0x52, 0x72, 0x60, 0x23, 0xe7, 0x61, 0xe7, 0xbe, 0xed, 0x22,
+	0xdd, 0xdd, 0xb7, 0xf0, 0x1b, 0x73, 0x7c, 0xce, 0xfc, 0x30, 0xe6, 0x29, 0x23, 0x87, 0x36, 0x72,
+	0x06, 0xde, 0x71, 0x53, 0x5b, 0x38, 0xec, 0x33, 0x0d, 0xf9, 0xd7, 0x6b, 0x99, 0xcb, 0x9c, 0x43,
+	0xce, 0x45, 0x45, 0xc6, 0x6a, 0x8a, 0x62, 0xb2, 0x3e, 0xd3, 0x99, 0x5d, 0x0f, 0xbf, 0x37, 0x8f,
+	0xce, 0x59, 0xcc, 0x6f, 0x59, 0x5e, 0x5d, 0x40, 0xc8, 0xc8, 0x44, 0x71, 0x27, 0x4d, 0x6d, 0x1d,
+	0x87, 0x5a, 0xae, 0xb1, 0x7b, 0x7d, 0xef, 0xdb, 0x6a, 0x43, 0x8d, 0xf5, 0x86, 0x1a, 0xf7, 0x1b,
+	0x8a, 0xbe, 0x4b, 0x8a, 0x7e, 0x4a, 0x8a, 0x7e, 0x49, 0x8a, 0x56, 0x92, 0xa2, 0xb5, 0xa4, 0xe8,
+	0xb7, 0xa4, 0xe8, 0x8f, 0xa4, 0xc6, 0xbd, 0xa4, 0xe8, 0xc7, 0x96, 0x1a, 0xab, 0x2d, 0x35, 0xd6,
+	0x5b, 0x6a, 0x7c, 0x39, 0xd3, 0xb6, 0x29, 0x29, 0x63, 0xd1, 0x3a, 0x8b, 0x3b, 0x37, 0xb9, 0x9b,
+	0x05, 0x0b, 0x9f, 0xa7, 0xb3, 0x00, 0x92, 0xa4, 0x4c, 0x79, 0xe0, 0x0b, 0x0e, 0xe9, 0x2c, 0x02,
+	0x77, 0xc9, 0xe6, 0x05, 0x04, 0x37, 0x4c, 0xb8, 0xa1, 0x2f, 0xfc, 0x77, 0xed, 0x67, 0x7e, 0xa0,
+	0x56, 0xed, 0xed, 0xdf, 0x01, 0x00, 0x37, 0x9e, 0x5a, 0x6c, 0xb3, 0x02, 0x00, 0x00,
 }
 
 func (this *WsMessage) Equal(that interface{}) bool {
@@ -177,13 +205,22 @@ func (this *WsMessage) Equal(that interface{}) bool {
 	if this.Version != that1.Version {
 		return false
 	}
+	if this.Deadline != that1.Deadline {
+		return false
+	}
+	if this.Priority != that1.Priority {
+		return false
+	}
+	if this.DeliveryMode != that1.DeliveryMode {
+		return false
+	}
 	return true
 }
 func (this *WsMessage) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 10)
+	s := make([]string, 0, 13)
 	s = append(s, "&data.WsMessage{")
 	s = append(s, "WithAcknowledge: "+fmt.Sprintf("%#v", this.WithAcknowledge)+",\n")
 	s = append(s, "Counter: "+fmt.Sprintf("%#v", this.Counter)+",\n")
@@ -191,6 +228,9 @@ func (this *WsMessage) GoString() string {
 	s = append(s, "Payload: "+fmt.Sprintf("%#v", this.Payload)+",\n")
 	s = append(s, "Topic: "+fmt.Sprintf("%#v", this.Topic)+",\n")
 	s = append(s, "Version: "+fmt.Sprintf("%#v", this.Version)+",\n")
+	s = append(s, "Deadline: "+fmt.Sprintf("%#v", this.Deadline)+",\n")
+	s = append(s, "Priority: "+fmt.Sprintf("%#v", this.Priority)+",\n")
+	s = append(s, "DeliveryMode: "+fmt.Sprintf("%#v", this.DeliveryMode)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -222,6 +262,21 @@ func (m *WsMessage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.DeliveryMode != 0 {
+		i = encodeVarintWsMessage(dAtA, i, uint64(m.DeliveryMode))
+		i--
+		dAtA[i] = 0x48
+	}
+	if m.Priority != 0 {
+		i = encodeVarintWsMessage(dAtA, i, uint64(m.Priority))
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.Deadline != 0 {
+		i = encodeVarintWsMessage(dAtA, i, uint64(m.Deadline))
+		i--
+		dAtA[i] = 0x38
+	}
 	if m.Version != 0 {
 		i = encodeVarintWsMessage(dAtA, i, uint64(m.Version))
 		i--
@@ -301,6 +356,15 @@ func (m *WsMessage) Size() (n int) {
 	if m.Version != 0 {
 		n += 1 + sovWsMessage(uint64(m.Version))
 	}
+	if m.Deadline != 0 {
+		n += 1 + sovWsMessage(uint64(m.Deadline))
+	}
+	if m.Priority != 0 {
+		n += 1 + sovWsMessage(uint64(m.Priority))
+	}
+	if m.DeliveryMode != 0 {
+		n += 1 + sovWsMessage(uint64(m.DeliveryMode))
+	}
 	return n
 }
 
@@ -321,6 +385,9 @@ func (this *WsMessage) String() string {
 		`Payload:` + fmt.Sprintf("%v", this.Payload) + `,`,
 		`Topic:` + fmt.Sprintf("%v", this.Topic) + `,`,
 		`Version:` + fmt.Sprintf("%v", this.Version) + `,`,
+		`Deadline:` + fmt.Sprintf("%v", this.Deadline) + `,`,
+		`Priority:` + fmt.Sprintf("%v", this.Priority) + `,`,
+		`DeliveryMode:` + fmt.Sprintf("%v", this.DeliveryMode) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -505,6 +572,63 @@ func (m *WsMessage) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Deadline", wireType)
+			}
+			m.Deadline = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWsMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Deadline |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Priority", wireType)
+			}
+			m.Priority = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWsMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Priority |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DeliveryMode", wireType)
+			}
+			m.DeliveryMode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWsMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DeliveryMode |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipWsMessage(dAtA[iNdEx:])
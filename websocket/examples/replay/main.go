@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/websocket"
+	"github.com/TerraDharitri/drt-go-chain-communication/websocket/data"
+	factoryHost "github.com/TerraDharitri/drt-go-chain-communication/websocket/factory"
+	"github.com/TerraDharitri/drt-go-chain-communication/websocket/recording"
+	"github.com/TerraDharitri/drt-go-chain-core/marshal/factory"
+	logger "github.com/TerraDharitri/drt-go-chain-logger"
+)
+
+var log = logger.GetOrCreate("replay")
+
+var (
+	directory      = flag.String("directory", "recordings", "The directory containing the recording files")
+	filePrefix     = flag.String("prefix", "outport", "The prefix of the recording files")
+	marshallerType = flag.String("marshaller", "gogo protobuf", "The marshaller used for the recording and for the websocket messages")
+	mode           = flag.String("mode", data.ModeClient, "The websocket host mode: 'client' or 'server'")
+	url            = flag.String("url", "ws://localhost:22111", "The websocket URL to connect to or to listen on")
+	route          = flag.String("route", data.WSRoute, "The websocket route")
+	speed          = flag.Float64("speed", 1, "The replay speed factor. 1 is the original speed, 0 sends the messages as fast as possible")
+	withAck        = flag.Bool("ack", true, "Wait for the acknowledgement of each message")
+	ackTimeout     = flag.Int("ack-timeout", 10, "The duration in seconds to wait for an acknowledgement")
+)
+
+func main() {
+	flag.Parse()
+
+	err := replay()
+	if err != nil {
+		log.Error("replay failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func replay() error {
+	marshaller, err := factory.NewMarshalizer(*marshallerType)
+	if err != nil {
+		return err
+	}
+
+	payloadConverter, err := websocket.NewWebSocketPayloadConverter(marshaller)
+	if err != nil {
+		return err
+	}
+
+	// the host sends all the messages with the same payload version, so a recording can be replayed only if its
+	// messages share the version
+	version, err := getRecordingVersion(payloadConverter)
+	if err != nil {
+		return err
+	}
+
+	recordsReader, err := recording.NewReader(*directory, *filePrefix, payloadConverter)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = recordsReader.Close()
+	}()
+
+	firstRecord, err := recordsReader.Next()
+	if err != nil {
+		return err
+	}
+
+	host, err := factoryHost.CreateWebSocketHost(factoryHost.ArgsWebSocketHost{
+		WebSocketConfig: data.WebSocketConfig{
+			URL:                     *url,
+			Mode:                    *mode,
+			Route:                   *route,
+			RetryDurationInSec:      1,
+			WithAcknowledge:         *withAck,
+			AcknowledgeTimeoutInSec: *ackTimeout,
+			Version:                 version,
+		},
+		Marshaller: marshaller,
+		Log:        log,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = host.Close()
+	}()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+
+	startTime := time.Now()
+	numSent := 0
+	for record := firstRecord; ; {
+		if *speed > 0 {
+			offset := time.Duration(float64(record.TimestampInNanoseconds-firstRecord.TimestampInNanoseconds) / *speed)
+			select {
+			case <-time.After(time.Until(startTime.Add(offset))):
+			case <-interrupt:
+				return nil
+			}
+		}
+
+		err = sendUntilSuccessful(host, record, interrupt)
+		if err != nil {
+			return err
+		}
+		numSent++
+
+		record, err = recordsReader.Next()
+		if errors.Is(err, io.EOF) {
+			log.Info("replay finished", "messages", numSent, "duration", time.Since(startTime))
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func getRecordingVersion(payloadConverter websocket.PayloadConverter) (uint32, error) {
+	recordsReader, err := recording.NewReader(*directory, *filePrefix, payloadConverter)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = recordsReader.Close()
+	}()
+
+	firstRecord, err := recordsReader.Next()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		record, errNext := recordsReader.Next()
+		if errors.Is(errNext, io.EOF) {
+			return firstRecord.Message.Version, nil
+		}
+		if errNext != nil {
+			return 0, errNext
+		}
+		if record.Message.Version != firstRecord.Message.Version {
+			return 0, fmt.Errorf("the recording mixes the payload versions %d and %d, counter %d",
+				firstRecord.Message.Version, record.Message.Version, record.Message.Counter)
+		}
+	}
+}
+
+func sendUntilSuccessful(host factoryHost.FullDuplexHost, record *recording.Record, interrupt chan os.Signal) error {
+	for {
+		err := host.Send(record.Message.Payload, record.Message.Topic)
+		if err == nil {
+			log.Debug("message sent", "counter", record.Message.Counter, "topic", record.Message.Topic)
+			return nil
+		}
+
+		log.Debug("cannot send message, retrying", "counter", record.Message.Counter, "error", err)
+		select {
+		case <-time.After(time.Second):
+		case <-interrupt:
+			return errors.New("replay interrupted")
+		}
+	}
+}
@@ -28,6 +28,30 @@ func CreateWebSocketHost(args ArgsWebSocketHost) (FullDuplexHost, error) {
 	}
 }
 
+// CreateInMemoryHostPair will create a connected server and client pair that exchange messages over an in-memory pipe.
+// The URL from the provided config is used as the in-memory address and the mode and transport are ignored
+func CreateInMemoryHostPair(args ArgsWebSocketHost) (FullDuplexHost, FullDuplexHost, error) {
+	args.WebSocketConfig.Transport = data.TransportMemory
+
+	serverArgs := args
+	serverArgs.WebSocketConfig.Mode = data.ModeServer
+	wsServer, err := createWebSocketServer(serverArgs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clientArgs := args
+	clientArgs.WebSocketConfig.Mode = data.ModeClient
+	clientArgs.WebSocketConfig.Endpoints = nil
+	wsClient, err := createWebSocketClient(clientArgs)
+	if err != nil {
+		_ = wsServer.Close()
+		return nil, nil, err
+	}
+
+	return wsServer, wsClient, nil
+}
+
 func createWebSocketClient(args ArgsWebSocketHost) (FullDuplexHost, error) {
 	payloadConverter, err := websocket.NewWebSocketPayloadConverter(args.Marshaller)
 	if err != nil {
@@ -38,12 +62,17 @@ func createWebSocketClient(args ArgsWebSocketHost) (FullDuplexHost, error) {
 		RetryDurationInSeconds:     args.WebSocketConfig.RetryDurationInSec,
 		WithAcknowledge:            args.WebSocketConfig.WithAcknowledge,
 		URL:                        args.WebSocketConfig.URL,
+		Transport:                  args.WebSocketConfig.Transport,
+		Route:                      args.WebSocketConfig.Route,
+		UseURLPathAsPrefix:         args.WebSocketConfig.UseURLPathAsPrefix,
 		PayloadConverter:           payloadConverter,
 		Log:                        args.Log,
 		BlockingAckOnError:         args.WebSocketConfig.BlockingAckOnError,
 		DropMessagesIfNoConnection: args.WebSocketConfig.DropMessagesIfNoConnection,
 		AckTimeoutInSeconds:        args.WebSocketConfig.AcknowledgeTimeoutInSec,
 		PayloadVersion:             args.WebSocketConfig.Version,
+		TopicPolicies:              args.WebSocketConfig.TopicPolicies,
+		Handshake:                  args.WebSocketConfig.Handshake,
 	})
 }
 
@@ -57,12 +86,17 @@ func createWebSocketServer(args ArgsWebSocketHost) (FullDuplexHost, error) {
 		RetryDurationInSeconds:     args.WebSocketConfig.RetryDurationInSec,
 		WithAcknowledge:            args.WebSocketConfig.WithAcknowledge,
 		URL:                        args.WebSocketConfig.URL,
+		Transport:                  args.WebSocketConfig.Transport,
+		Route:                      args.WebSocketConfig.Route,
 		PayloadConverter:           payloadConverter,
 		Log:                        args.Log,
 		BlockingAckOnError:         args.WebSocketConfig.BlockingAckOnError,
 		DropMessagesIfNoConnection: args.WebSocketConfig.DropMessagesIfNoConnection,
 		AckTimeoutInSeconds:        args.WebSocketConfig.AcknowledgeTimeoutInSec,
 		PayloadVersion:             args.WebSocketConfig.Version,
+		TopicPolicies:              args.WebSocketConfig.TopicPolicies,
+		Handshake:                  args.WebSocketConfig.Handshake,
+		Endpoints:                  createEndpointsArgs(args.WebSocketConfig.Endpoints),
 	})
 	if err != nil {
 		return nil, err
@@ -70,3 +104,20 @@ func createWebSocketServer(args ArgsWebSocketHost) (FullDuplexHost, error) {
 
 	return host, nil
 }
+
+func createEndpointsArgs(endpointsConfig []data.EndpointConfig) []server.ArgsEndpoint {
+	argsEndpoints := make([]server.ArgsEndpoint, 0, len(endpointsConfig))
+	for _, endpointConfig := range endpointsConfig {
+		argsEndpoints = append(argsEndpoints, server.ArgsEndpoint{
+			Name:                endpointConfig.Name,
+			Route:               endpointConfig.Route,
+			AckTimeoutInSeconds: endpointConfig.AcknowledgeTimeoutInSec,
+			BlockingAckOnError:  endpointConfig.BlockingAckOnError,
+			WithAcknowledge:     endpointConfig.WithAcknowledge,
+			PayloadVersion:      endpointConfig.Version,
+			TopicPolicies:       endpointConfig.TopicPolicies,
+		})
+	}
+
+	return argsEndpoints
+}
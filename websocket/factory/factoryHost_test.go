@@ -42,3 +42,39 @@ func TestCreateServer(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, "*server.server", fmt.Sprintf("%T", webSocketsClient))
 }
+
+func TestCreateServerWithEndpoints(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	args.WebSocketConfig.Mode = data.ModeServer
+	args.WebSocketConfig.Endpoints = []data.EndpointConfig{
+		{
+			Name:  "blocks",
+			Route: "/blocks",
+		},
+	}
+	webSocketsServer, err := CreateWebSocketHost(args)
+	require.Nil(t, err)
+
+	multiEndpointHost, ok := webSocketsServer.(MultiEndpointHost)
+	require.True(t, ok)
+	blocksEndpoint, err := multiEndpointHost.GetEndpoint("blocks")
+	require.Nil(t, err)
+	require.False(t, blocksEndpoint.IsInterfaceNil())
+	_ = webSocketsServer.Close()
+}
+
+func TestCreateInMemoryHostPair(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	args.WebSocketConfig.URL = "factory-in-memory-pair"
+	wsServer, wsClient, err := CreateInMemoryHostPair(args)
+	require.Nil(t, err)
+	require.Equal(t, "*server.server", fmt.Sprintf("%T", wsServer))
+	require.Equal(t, "*client.client", fmt.Sprintf("%T", wsClient))
+
+	_ = wsClient.Close()
+	_ = wsServer.Close()
+}
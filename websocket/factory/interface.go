@@ -1,6 +1,9 @@
 package factory
 
-import "github.com/TerraDharitri/drt-go-chain-communication/websocket"
+import (
+	"github.com/TerraDharitri/drt-go-chain-communication/websocket"
+	"github.com/TerraDharitri/drt-go-chain-communication/websocket/server"
+)
 
 // FullDuplexHost defines what a full duplex host should be able to do
 type FullDuplexHost interface {
@@ -9,3 +12,9 @@ type FullDuplexHost interface {
 	Close() error
 	IsInterfaceNil() bool
 }
+
+// MultiEndpointHost defines what a full duplex host exposing several named endpoints should be able to do
+type MultiEndpointHost interface {
+	FullDuplexHost
+	GetEndpoint(name string) (server.Endpoint, error)
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"fmt"
 	"log"
+	"path/filepath"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -11,7 +12,10 @@ import (
 	"time"
 
 	"github.com/TerraDharitri/drt-go-chain-communication/testscommon"
+	"github.com/TerraDharitri/drt-go-chain-communication/websocket/data"
+	hostFactory "github.com/TerraDharitri/drt-go-chain-communication/websocket/factory"
 	"github.com/TerraDharitri/drt-go-chain-core/data/outport"
+	"github.com/TerraDharitri/drt-go-chain-core/marshal/factory"
 	"github.com/stretchr/testify/require"
 )
 
@@ -274,6 +278,249 @@ func TestStartServerStartClientAndSendMultipleGoRoutines(t *testing.T) {
 	mutex.RUnlock()
 }
 
+func TestStartServerWithMultipleEndpointsAndSendData(t *testing.T) {
+	port := getFreePort()
+	serverConfig := data.WebSocketConfig{
+		URL:                     "localhost:" + port,
+		Mode:                    data.ModeServer,
+		RetryDurationInSec:      retryDurationInSeconds,
+		WithAcknowledge:         true,
+		AcknowledgeTimeoutInSec: retryDurationInSeconds,
+		Route:                   "/accounts",
+		Version:                 1,
+		Endpoints: []data.EndpointConfig{
+			{
+				Name:    "blocks",
+				Route:   "/blocks",
+				Version: 2,
+			},
+		},
+	}
+	host, err := hostFactory.CreateWebSocketHost(hostFactory.ArgsWebSocketHost{
+		WebSocketConfig: serverConfig,
+		Marshaller:      marshaller,
+		Log:             &testscommon.LoggerMock{},
+	})
+	require.Nil(t, err)
+	wsServer := host.(hostFactory.MultiEndpointHost)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	_ = wsServer.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			require.Equal(t, []byte("accounts"), payload)
+			require.Equal(t, uint32(1), version)
+			wg.Done()
+			return nil
+		},
+	})
+	blocksEndpoint, err := wsServer.GetEndpoint("blocks")
+	require.Nil(t, err)
+	_ = blocksEndpoint.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			require.Equal(t, []byte("blocks"), payload)
+			require.Equal(t, uint32(2), version)
+			wg.Done()
+			return nil
+		},
+	})
+
+	createRouteClient := func(route string, withAcknowledge bool, version uint32) hostFactory.FullDuplexHost {
+		wsClient, errCreate := hostFactory.CreateWebSocketHost(hostFactory.ArgsWebSocketHost{
+			WebSocketConfig: data.WebSocketConfig{
+				URL:                     "ws://localhost:" + port,
+				Mode:                    data.ModeClient,
+				RetryDurationInSec:      retryDurationInSeconds,
+				WithAcknowledge:         withAcknowledge,
+				AcknowledgeTimeoutInSec: retryDurationInSeconds,
+				Route:                   route,
+				Version:                 version,
+			},
+			Marshaller: marshaller,
+			Log:        &testscommon.LoggerMock{},
+		})
+		require.Nil(t, errCreate)
+		return wsClient
+	}
+	accountsClient := createRouteClient("/accounts", true, 1)
+	blocksClient := createRouteClient("/blocks", false, 2)
+
+	sendUntilSuccessful := func(wsClient hostFactory.FullDuplexHost, payload []byte) {
+		for {
+			errSend := wsClient.Send(payload, outport.TopicSaveBlock)
+			if errSend == nil {
+				return
+			}
+			time.Sleep(300 * time.Millisecond)
+		}
+	}
+	sendUntilSuccessful(accountsClient, []byte("accounts"))
+	sendUntilSuccessful(blocksClient, []byte("blocks"))
+
+	wg.Wait()
+	_ = accountsClient.Close()
+	_ = blocksClient.Close()
+	_ = wsServer.Close()
+}
+
+func createHostWithHandshake(mode string, url string, marshallerType string) (hostFactory.FullDuplexHost, error) {
+	hostMarshaller, err := factory.NewMarshalizer(marshallerType)
+	if err != nil {
+		return nil, err
+	}
+
+	return hostFactory.CreateWebSocketHost(hostFactory.ArgsWebSocketHost{
+		WebSocketConfig: data.WebSocketConfig{
+			URL:                     url,
+			Mode:                    mode,
+			RetryDurationInSec:      retryDurationInSeconds,
+			WithAcknowledge:         true,
+			AcknowledgeTimeoutInSec: retryDurationInSeconds,
+			Version:                 1,
+			Handshake: data.HandshakeConfig{
+				Enabled:  true,
+				Features: []string{data.FeatureSubscriptions},
+			},
+		},
+		Marshaller: hostMarshaller,
+		Log:        &testscommon.LoggerMock{},
+	})
+}
+
+func TestStartServerAndClientWithHandshakeAndSendData(t *testing.T) {
+	port := getFreePort()
+	wsServer, err := createHostWithHandshake(data.ModeServer, "localhost:"+port, "gogo protobuf")
+	require.Nil(t, err)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	_ = wsServer.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			require.Equal(t, []byte("test"), payload)
+			wg.Done()
+			return nil
+		},
+	})
+
+	wsClient, err := createHostWithHandshake(data.ModeClient, "ws://localhost:"+port, "gogo protobuf")
+	require.Nil(t, err)
+
+	for {
+		err = wsClient.Send([]byte("test"), outport.TopicSaveBlock)
+		if err == nil {
+			break
+		}
+		time.Sleep(300 * time.Millisecond)
+	}
+
+	wg.Wait()
+	_ = wsClient.Close()
+	_ = wsServer.Close()
+}
+
+func TestStartServerAndClientWithHandshakeMismatchShouldNotSendData(t *testing.T) {
+	port := getFreePort()
+	wsServer, err := createHostWithHandshake(data.ModeServer, "localhost:"+port, "json")
+	require.Nil(t, err)
+
+	_ = wsServer.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			require.Fail(t, "should have not received payloads")
+			return nil
+		},
+	})
+
+	wsClient, err := createHostWithHandshake(data.ModeClient, "ws://localhost:"+port, "gogo protobuf")
+	require.Nil(t, err)
+
+	for i := 0; i < 10; i++ {
+		err = wsClient.Send([]byte("test"), outport.TopicSaveBlock)
+		require.NotNil(t, err)
+		time.Sleep(300 * time.Millisecond)
+	}
+
+	_ = wsClient.Close()
+	_ = wsServer.Close()
+}
+
+func TestInMemoryHostPairSendDataWithAcknowledge(t *testing.T) {
+	wsServer, wsClient, err := hostFactory.CreateInMemoryHostPair(hostFactory.ArgsWebSocketHost{
+		WebSocketConfig: createTransportConfig("integration-in-memory"),
+		Marshaller:      marshaller,
+		Log:             &testscommon.LoggerMock{},
+	})
+	require.Nil(t, err)
+
+	testSendDataBothWays(t, wsServer, wsClient)
+}
+
+func TestUnixSocketServerAndClientSendDataWithAcknowledge(t *testing.T) {
+	config := createTransportConfig(filepath.Join(t.TempDir(), "ws.sock"))
+	config.Transport = data.TransportUnix
+
+	config.Mode = data.ModeServer
+	wsServer, err := hostFactory.CreateWebSocketHost(hostFactory.ArgsWebSocketHost{
+		WebSocketConfig: config,
+		Marshaller:      marshaller,
+		Log:             &testscommon.LoggerMock{},
+	})
+	require.Nil(t, err)
+
+	config.Mode = data.ModeClient
+	wsClient, err := hostFactory.CreateWebSocketHost(hostFactory.ArgsWebSocketHost{
+		WebSocketConfig: config,
+		Marshaller:      marshaller,
+		Log:             &testscommon.LoggerMock{},
+	})
+	require.Nil(t, err)
+
+	testSendDataBothWays(t, wsServer, wsClient)
+}
+
+func createTransportConfig(url string) data.WebSocketConfig {
+	return data.WebSocketConfig{
+		URL:                     url,
+		Transport:               data.TransportMemory,
+		RetryDurationInSec:      retryDurationInSeconds,
+		WithAcknowledge:         true,
+		AcknowledgeTimeoutInSec: retryDurationInSeconds,
+		Version:                 1,
+	}
+}
+
+func testSendDataBothWays(t *testing.T, wsServer hostFactory.FullDuplexHost, wsClient hostFactory.FullDuplexHost) {
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	_ = wsServer.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			require.Equal(t, []byte("from client"), payload)
+			wg.Done()
+			return nil
+		},
+	})
+	_ = wsClient.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			require.Equal(t, []byte("from server"), payload)
+			wg.Done()
+			return nil
+		},
+	})
+
+	for _, host := range []hostFactory.FullDuplexHost{wsClient, wsServer} {
+		payload := []byte("from client")
+		if host == wsServer {
+			payload = []byte("from server")
+		}
+		for host.Send(payload, outport.TopicSaveBlock) != nil {
+			time.Sleep(300 * time.Millisecond)
+		}
+	}
+
+	wg.Wait()
+	_ = wsClient.Close()
+	_ = wsServer.Close()
+}
+
 func generateLargeByteArray(size int) []byte {
 	bytes := make([]byte, size)
 	_, err := rand.Read(bytes)
@@ -3,6 +3,8 @@ package websocket
 import (
 	"context"
 	"io"
+	"net"
+	"time"
 
 	"github.com/TerraDharitri/drt-go-chain-communication/websocket/data"
 )
@@ -18,6 +20,15 @@ type PayloadHandler interface {
 type PayloadConverter interface {
 	ExtractWsMessage(payload []byte) (*data.WsMessage, error)
 	ConstructPayload(wsMessage *data.WsMessage) ([]byte, error)
+	MarshallerType() string
+	IsInterfaceNil() bool
+}
+
+// SendMetricsHandler defines what a send metrics handler should be able to do
+type SendMetricsHandler interface {
+	AddExpiredDropped()
+	AddHighPrioritySent()
+	GetSendMetrics() data.SendMetrics
 	IsInterfaceNil() bool
 }
 
@@ -28,6 +39,7 @@ type WSConClient interface {
 	IsOpen() bool
 	WriteMessage(messageType int, data []byte) error
 	ReadMessage() (int, []byte, error)
+	SetReadDeadline(deadline time.Time) error
 	GetID() string
 	IsInterfaceNil() bool
 }
@@ -35,5 +47,6 @@ type WSConClient interface {
 // HttpServerHandler defines the minimum behaviour of a http server
 type HttpServerHandler interface {
 	ListenAndServe() error
+	Serve(listener net.Listener) error
 	Shutdown(ctx context.Context) error
 }
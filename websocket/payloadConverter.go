@@ -1,13 +1,17 @@
 package websocket
 
 import (
+	"fmt"
+
 	"github.com/TerraDharitri/drt-go-chain-communication/websocket/data"
 	"github.com/TerraDharitri/drt-go-chain-core/core/check"
 	"github.com/TerraDharitri/drt-go-chain-core/marshal"
+	marshalFactory "github.com/TerraDharitri/drt-go-chain-core/marshal/factory"
 )
 
 type webSocketsPayloadConverter struct {
-	marshaller marshal.Marshalizer
+	marshaller     marshal.Marshalizer
+	marshallerType string
 }
 
 // NewWebSocketPayloadConverter returns a new instance of websocketPayloadParser
@@ -17,10 +21,24 @@ func NewWebSocketPayloadConverter(marshaller marshal.Marshalizer) (*webSocketsPa
 	}
 
 	return &webSocketsPayloadConverter{
-		marshaller: marshaller,
+		marshaller:     marshaller,
+		marshallerType: getMarshallerType(marshaller),
 	}, nil
 }
 
+func getMarshallerType(marshaller marshal.Marshalizer) string {
+	switch marshaller.(type) {
+	case *marshal.GogoProtoMarshalizer:
+		return marshalFactory.GogoProtobuf
+	case *marshal.JsonMarshalizer:
+		return marshalFactory.JsonMarshalizer
+	case *marshal.TxJsonMarshalizer:
+		return marshalFactory.TxJsonMarshalizer
+	default:
+		return fmt.Sprintf("%T", marshaller)
+	}
+}
+
 // ExtractWsMessage will extract the provided payload in a *data.WsMessage
 func (wpc *webSocketsPayloadConverter) ExtractWsMessage(payload []byte) (*data.WsMessage, error) {
 	wsMessage := &data.WsMessage{}
@@ -37,6 +55,11 @@ func (wpc *webSocketsPayloadConverter) ConstructPayload(wsMessage *data.WsMessag
 	return wpc.marshaller.Marshal(wsMessage)
 }
 
+// MarshallerType returns the type of the marshaller used for the payloads, derived from the marshaller instance
+func (wpc *webSocketsPayloadConverter) MarshallerType() string {
+	return wpc.marshallerType
+}
+
 // IsInterfaceNil -
 func (wpc *webSocketsPayloadConverter) IsInterfaceNil() bool {
 	return wpc == nil
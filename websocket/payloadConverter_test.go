@@ -6,6 +6,7 @@ import (
 	"github.com/TerraDharitri/drt-go-chain-communication/testscommon"
 	"github.com/TerraDharitri/drt-go-chain-communication/websocket/data"
 	"github.com/TerraDharitri/drt-go-chain-core/data/outport"
+	"github.com/TerraDharitri/drt-go-chain-core/marshal/factory"
 	"github.com/stretchr/testify/require"
 )
 
@@ -51,3 +52,47 @@ func TestWebSocketsPayloadConverter_ConstructPayload(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, wsMessage, newWsMessage)
 }
+
+func TestWebSocketsPayloadConverter_DeadlineAndPriority(t *testing.T) {
+	t.Parallel()
+
+	payloadConverter, _ := NewWebSocketPayloadConverter(&testscommon.ProtoMarshallerMock{})
+
+	wsMessage := &data.WsMessage{
+		Payload:  []byte("test"),
+		Topic:    outport.TopicSaveBlock,
+		Counter:  10,
+		Type:     data.PayloadMessage,
+		Deadline: 1700000000000,
+		Priority: 5,
+	}
+
+	payload, err := payloadConverter.ConstructPayload(wsMessage)
+	require.Nil(t, err)
+
+	newWsMessage, err := payloadConverter.ExtractWsMessage(payload)
+	require.Nil(t, err)
+	require.Equal(t, wsMessage, newWsMessage)
+
+	// messages without the new fields are encoded exactly as before, so old peers can decode them
+	wsMessage.Deadline = 0
+	wsMessage.Priority = 0
+	payloadWithoutNewFields, err := payloadConverter.ConstructPayload(wsMessage)
+	require.Nil(t, err)
+	require.Equal(t, payload[:len(payloadWithoutNewFields)], payloadWithoutNewFields)
+}
+
+func TestWebSocketsPayloadConverter_MarshallerType(t *testing.T) {
+	t.Parallel()
+
+	gogoMarshaller, _ := factory.NewMarshalizer(factory.GogoProtobuf)
+	payloadConverter, _ := NewWebSocketPayloadConverter(gogoMarshaller)
+	require.Equal(t, factory.GogoProtobuf, payloadConverter.MarshallerType())
+
+	jsonMarshaller, _ := factory.NewMarshalizer(factory.JsonMarshalizer)
+	payloadConverter, _ = NewWebSocketPayloadConverter(jsonMarshaller)
+	require.Equal(t, factory.JsonMarshalizer, payloadConverter.MarshallerType())
+
+	payloadConverter, _ = NewWebSocketPayloadConverter(&testscommon.MarshallerMock{})
+	require.Equal(t, "*testscommon.MarshallerMock", payloadConverter.MarshallerType())
+}
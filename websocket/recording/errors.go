@@ -0,0 +1,21 @@
+package recording
+
+import "errors"
+
+// ErrEmptyDirectory signals that an empty directory has been provided
+var ErrEmptyDirectory = errors.New("empty directory provided")
+
+// ErrEmptyFilePrefix signals that an empty file prefix has been provided
+var ErrEmptyFilePrefix = errors.New("empty file prefix provided")
+
+// ErrZeroMaxFileSize signals that a zero value for the maximum file size has been provided
+var ErrZeroMaxFileSize = errors.New("zero value provided for max file size")
+
+// ErrNegativeMaxNumFiles signals that a negative value for the maximum number of files has been provided
+var ErrNegativeMaxNumFiles = errors.New("negative value provided for max number of files")
+
+// ErrRecorderClosed signals that the recorder was closed
+var ErrRecorderClosed = errors.New("recorder is closed")
+
+// ErrCorruptedRecord signals that a record could not be read
+var ErrCorruptedRecord = errors.New("corrupted record")
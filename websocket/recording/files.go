@@ -0,0 +1,58 @@
+package recording
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	recordingFileExtension = ".rec"
+	// each record starts with the timestamp in nanoseconds (8 bytes) and the length of the record (4 bytes)
+	recordHeaderSize = 12
+)
+
+type recordingFile struct {
+	index int
+	path  string
+}
+
+func recordingFileName(prefix string, index int) string {
+	return fmt.Sprintf("%s_%06d%s", prefix, index, recordingFileExtension)
+}
+
+// getRecordingFiles returns the recording files with the provided prefix, sorted by their index
+func getRecordingFiles(directory string, prefix string) ([]recordingFile, error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]recordingFile, 0)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix+"_") || !strings.HasSuffix(name, recordingFileExtension) {
+			continue
+		}
+
+		indexString := strings.TrimSuffix(strings.TrimPrefix(name, prefix+"_"), recordingFileExtension)
+		index, errConvert := strconv.Atoi(indexString)
+		if errConvert != nil {
+			continue
+		}
+
+		files = append(files, recordingFile{
+			index: index,
+			path:  filepath.Join(directory, name),
+		})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].index < files[j].index
+	})
+
+	return files, nil
+}
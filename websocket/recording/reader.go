@@ -0,0 +1,118 @@
+package recording
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/websocket"
+	"github.com/TerraDharitri/drt-go-chain-communication/websocket/data"
+	"github.com/TerraDharitri/drt-go-chain-core/core/check"
+)
+
+// Record holds a recorded message together with the moment it was received
+type Record struct {
+	TimestampInNanoseconds int64
+	Message                *data.WsMessage
+}
+
+type reader struct {
+	payloadConverter websocket.PayloadConverter
+	files            []recordingFile
+	file             *os.File
+	bufReader        *bufio.Reader
+}
+
+// NewReader will create a reader that returns, in order, the records from all the recording files with the provided prefix
+func NewReader(directory string, filePrefix string, payloadConverter websocket.PayloadConverter) (*reader, error) {
+	if check.IfNil(payloadConverter) {
+		return nil, data.ErrNilPayloadConverter
+	}
+
+	files, err := getRecordingFiles(directory, filePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reader{
+		payloadConverter: payloadConverter,
+		files:            files,
+	}, nil
+}
+
+// Next returns the next record. It returns io.EOF when all the records were read
+func (r *reader) Next() (*Record, error) {
+	for {
+		if r.bufReader == nil {
+			err := r.openNextFile()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		record, err := r.readRecord()
+		if err == io.EOF {
+			_ = r.file.Close()
+			r.bufReader = nil
+			continue
+		}
+
+		return record, err
+	}
+}
+
+func (r *reader) openNextFile() error {
+	if len(r.files) == 0 {
+		return io.EOF
+	}
+
+	file, err := os.Open(r.files[0].path)
+	if err != nil {
+		return err
+	}
+
+	r.files = r.files[1:]
+	r.file = file
+	r.bufReader = bufio.NewReader(file)
+
+	return nil
+}
+
+func (r *reader) readRecord() (*Record, error) {
+	header := make([]byte, recordHeaderSize)
+	_, err := io.ReadFull(r.bufReader, header)
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w in file %s: %s", ErrCorruptedRecord, r.file.Name(), err.Error())
+	}
+
+	recordBytes := make([]byte, binary.BigEndian.Uint32(header[8:]))
+	_, err = io.ReadFull(r.bufReader, recordBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w in file %s: %s", ErrCorruptedRecord, r.file.Name(), err.Error())
+	}
+
+	wsMessage, err := r.payloadConverter.ExtractWsMessage(recordBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Record{
+		TimestampInNanoseconds: int64(binary.BigEndian.Uint64(header)),
+		Message:                wsMessage,
+	}, nil
+}
+
+// Close will close the file that is currently read
+func (r *reader) Close() error {
+	if r.bufReader == nil {
+		return nil
+	}
+
+	r.bufReader = nil
+	return r.file.Close()
+}
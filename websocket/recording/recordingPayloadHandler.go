@@ -0,0 +1,214 @@
+package recording
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/websocket"
+	"github.com/TerraDharitri/drt-go-chain-communication/websocket/data"
+	"github.com/TerraDharitri/drt-go-chain-core/core/check"
+	"github.com/TerraDharitri/drt-go-chain-core/marshal"
+)
+
+// ArgsRecordingPayloadHandler holds the arguments needed for creating a recording payload handler
+type ArgsRecordingPayloadHandler struct {
+	Directory          string
+	FilePrefix         string
+	MaxFileSizeInBytes uint64
+	MaxNumFiles        int
+	Marshaller         marshal.Marshalizer
+}
+
+type recordingPayloadHandler struct {
+	mut                sync.Mutex
+	directory          string
+	filePrefix         string
+	maxFileSizeInBytes uint64
+	maxNumFiles        int
+	payloadConverter   websocket.PayloadConverter
+	counter            uint64
+	fileIndex          int
+	file               *os.File
+	writer             *bufio.Writer
+	currentFileSize    uint64
+	closed             bool
+}
+
+// NewRecordingPayloadHandler will create a payload handler that records every received payload in rotating local files
+func NewRecordingPayloadHandler(args ArgsRecordingPayloadHandler) (*recordingPayloadHandler, error) {
+	err := checkArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	payloadConverter, err := websocket.NewWebSocketPayloadConverter(args.Marshaller)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(args.Directory, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	existingFiles, err := getRecordingFiles(args.Directory, args.FilePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	rph := &recordingPayloadHandler{
+		directory:          args.Directory,
+		filePrefix:         args.FilePrefix,
+		maxFileSizeInBytes: args.MaxFileSizeInBytes,
+		maxNumFiles:        args.MaxNumFiles,
+		payloadConverter:   payloadConverter,
+	}
+	if len(existingFiles) > 0 {
+		rph.fileIndex = existingFiles[len(existingFiles)-1].index
+	}
+
+	err = rph.openNextFile()
+	if err != nil {
+		return nil, err
+	}
+
+	return rph, nil
+}
+
+func checkArgs(args ArgsRecordingPayloadHandler) error {
+	if check.IfNil(args.Marshaller) {
+		return data.ErrNilMarshaller
+	}
+	if args.Directory == "" {
+		return ErrEmptyDirectory
+	}
+	if args.FilePrefix == "" {
+		return ErrEmptyFilePrefix
+	}
+	if args.MaxFileSizeInBytes == 0 {
+		return ErrZeroMaxFileSize
+	}
+	if args.MaxNumFiles < 0 {
+		return ErrNegativeMaxNumFiles
+	}
+
+	return nil
+}
+
+// ProcessPayload will record the provided payload
+func (rph *recordingPayloadHandler) ProcessPayload(payload []byte, topic string, version uint32) error {
+	rph.mut.Lock()
+	defer rph.mut.Unlock()
+
+	if rph.closed {
+		return ErrRecorderClosed
+	}
+
+	rph.counter++
+	recordBytes, err := rph.payloadConverter.ConstructPayload(&data.WsMessage{
+		Counter: rph.counter,
+		Type:    data.PayloadMessage,
+		Payload: payload,
+		Topic:   topic,
+		Version: version,
+	})
+	if err != nil {
+		return err
+	}
+
+	header := make([]byte, recordHeaderSize)
+	binary.BigEndian.PutUint64(header, uint64(time.Now().UnixNano()))
+	binary.BigEndian.PutUint32(header[8:], uint32(len(recordBytes)))
+
+	_, err = rph.writer.Write(header)
+	if err != nil {
+		return err
+	}
+	_, err = rph.writer.Write(recordBytes)
+	if err != nil {
+		return err
+	}
+	err = rph.writer.Flush()
+	if err != nil {
+		return err
+	}
+
+	rph.currentFileSize += uint64(len(header) + len(recordBytes))
+	if rph.currentFileSize < rph.maxFileSizeInBytes {
+		return nil
+	}
+
+	err = rph.closeCurrentFile()
+	if err != nil {
+		return err
+	}
+
+	return rph.openNextFile()
+}
+
+func (rph *recordingPayloadHandler) openNextFile() error {
+	rph.fileIndex++
+	file, err := os.OpenFile(filepath.Join(rph.directory, recordingFileName(rph.filePrefix, rph.fileIndex)), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	rph.file = file
+	rph.writer = bufio.NewWriter(file)
+	rph.currentFileSize = 0
+
+	return rph.removeOldFiles()
+}
+
+func (rph *recordingPayloadHandler) removeOldFiles() error {
+	if rph.maxNumFiles == 0 {
+		return nil
+	}
+
+	files, err := getRecordingFiles(rph.directory, rph.filePrefix)
+	if err != nil {
+		return err
+	}
+
+	for len(files) > rph.maxNumFiles {
+		err = os.Remove(files[0].path)
+		if err != nil {
+			return fmt.Errorf("%w while removing old recording file %s", err, files[0].path)
+		}
+		files = files[1:]
+	}
+
+	return nil
+}
+
+func (rph *recordingPayloadHandler) closeCurrentFile() error {
+	err := rph.writer.Flush()
+	if err != nil {
+		return err
+	}
+
+	return rph.file.Close()
+}
+
+// Close will flush and close the current recording file
+func (rph *recordingPayloadHandler) Close() error {
+	rph.mut.Lock()
+	defer rph.mut.Unlock()
+
+	if rph.closed {
+		return nil
+	}
+	rph.closed = true
+
+	return rph.closeCurrentFile()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (rph *recordingPayloadHandler) IsInterfaceNil() bool {
+	return rph == nil
+}
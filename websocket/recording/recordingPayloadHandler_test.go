@@ -0,0 +1,134 @@
+package recording
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/testscommon"
+	"github.com/TerraDharitri/drt-go-chain-communication/websocket"
+	"github.com/TerraDharitri/drt-go-chain-communication/websocket/data"
+	"github.com/stretchr/testify/require"
+)
+
+func createArgs(directory string) ArgsRecordingPayloadHandler {
+	return ArgsRecordingPayloadHandler{
+		Directory:          directory,
+		FilePrefix:         "outport",
+		MaxFileSizeInBytes: 1024,
+		MaxNumFiles:        0,
+		Marshaller:         &testscommon.ProtoMarshallerMock{},
+	}
+}
+
+func TestNewRecordingPayloadHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil marshaller, should return error", func(t *testing.T) {
+		args := createArgs(t.TempDir())
+		args.Marshaller = nil
+		rph, err := NewRecordingPayloadHandler(args)
+		require.Nil(t, rph)
+		require.Equal(t, data.ErrNilMarshaller, err)
+	})
+
+	t.Run("empty directory, should return error", func(t *testing.T) {
+		args := createArgs("")
+		rph, err := NewRecordingPayloadHandler(args)
+		require.Nil(t, rph)
+		require.Equal(t, ErrEmptyDirectory, err)
+	})
+
+	t.Run("empty file prefix, should return error", func(t *testing.T) {
+		args := createArgs(t.TempDir())
+		args.FilePrefix = ""
+		rph, err := NewRecordingPayloadHandler(args)
+		require.Nil(t, rph)
+		require.Equal(t, ErrEmptyFilePrefix, err)
+	})
+
+	t.Run("zero max file size, should return error", func(t *testing.T) {
+		args := createArgs(t.TempDir())
+		args.MaxFileSizeInBytes = 0
+		rph, err := NewRecordingPayloadHandler(args)
+		require.Nil(t, rph)
+		require.Equal(t, ErrZeroMaxFileSize, err)
+	})
+
+	t.Run("negative max number of files, should return error", func(t *testing.T) {
+		args := createArgs(t.TempDir())
+		args.MaxNumFiles = -1
+		rph, err := NewRecordingPayloadHandler(args)
+		require.Nil(t, rph)
+		require.Equal(t, ErrNegativeMaxNumFiles, err)
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		rph, err := NewRecordingPayloadHandler(createArgs(t.TempDir()))
+		require.Nil(t, err)
+		require.False(t, rph.IsInterfaceNil())
+		require.Nil(t, rph.Close())
+	})
+}
+
+func TestRecordingPayloadHandler_ProcessPayloadAndRead(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs(t.TempDir())
+	rph, _ := NewRecordingPayloadHandler(args)
+
+	numRecords := 50
+	for i := 0; i < numRecords; i++ {
+		err := rph.ProcessPayload([]byte(fmt.Sprintf("payload %d", i)), fmt.Sprintf("topic %d", i%3), uint32(i%2))
+		require.Nil(t, err)
+	}
+	require.Nil(t, rph.Close())
+	require.Equal(t, ErrRecorderClosed, rph.ProcessPayload([]byte("payload"), "topic", 1))
+
+	files, _ := getRecordingFiles(args.Directory, args.FilePrefix)
+	require.Greater(t, len(files), 1)
+
+	payloadConverter, _ := websocket.NewWebSocketPayloadConverter(args.Marshaller)
+	recordsReader, err := NewReader(args.Directory, args.FilePrefix, payloadConverter)
+	require.Nil(t, err)
+
+	lastTimestamp := int64(0)
+	for i := 0; i < numRecords; i++ {
+		record, errNext := recordsReader.Next()
+		require.Nil(t, errNext)
+		require.Equal(t, []byte(fmt.Sprintf("payload %d", i)), record.Message.Payload)
+		require.Equal(t, fmt.Sprintf("topic %d", i%3), record.Message.Topic)
+		require.Equal(t, uint32(i%2), record.Message.Version)
+		require.Equal(t, uint64(i+1), record.Message.Counter)
+		require.GreaterOrEqual(t, record.TimestampInNanoseconds, lastTimestamp)
+		lastTimestamp = record.TimestampInNanoseconds
+	}
+
+	_, err = recordsReader.Next()
+	require.Equal(t, io.EOF, err)
+	require.Nil(t, recordsReader.Close())
+}
+
+func TestRecordingPayloadHandler_RotationShouldRemoveOldFiles(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs(t.TempDir())
+	args.MaxFileSizeInBytes = 10
+	args.MaxNumFiles = 3
+	rph, _ := NewRecordingPayloadHandler(args)
+
+	for i := 0; i < 10; i++ {
+		_ = rph.ProcessPayload([]byte("payload"), "topic", 1)
+	}
+	_ = rph.Close()
+
+	files, _ := getRecordingFiles(args.Directory, args.FilePrefix)
+	require.Len(t, files, 3)
+	require.Equal(t, 11, files[len(files)-1].index)
+
+	// a new recorder continues the numbering
+	rph, _ = NewRecordingPayloadHandler(args)
+	_ = rph.Close()
+	files, _ = getRecordingFiles(args.Directory, args.FilePrefix)
+	require.Equal(t, 12, files[len(files)-1].index)
+}
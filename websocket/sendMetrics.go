@@ -0,0 +1,40 @@
+package websocket
+
+import (
+	"sync/atomic"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/websocket/data"
+)
+
+type sendMetrics struct {
+	numExpiredDropped   uint64
+	numHighPrioritySent uint64
+}
+
+// NewSendMetrics will create a new instance of sendMetrics
+func NewSendMetrics() *sendMetrics {
+	return &sendMetrics{}
+}
+
+// AddExpiredDropped increments the number of messages dropped because they expired
+func (sm *sendMetrics) AddExpiredDropped() {
+	atomic.AddUint64(&sm.numExpiredDropped, 1)
+}
+
+// AddHighPrioritySent increments the number of high priority messages sent
+func (sm *sendMetrics) AddHighPrioritySent() {
+	atomic.AddUint64(&sm.numHighPrioritySent, 1)
+}
+
+// GetSendMetrics returns a snapshot of the counters
+func (sm *sendMetrics) GetSendMetrics() data.SendMetrics {
+	return data.SendMetrics{
+		NumExpiredDropped:   atomic.LoadUint64(&sm.numExpiredDropped),
+		NumHighPrioritySent: atomic.LoadUint64(&sm.numHighPrioritySent),
+	}
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (sm *sendMetrics) IsInterfaceNil() bool {
+	return sm == nil
+}
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"sync"
+
+	webSocket "github.com/TerraDharitri/drt-go-chain-communication/websocket"
+	"github.com/TerraDharitri/drt-go-chain-communication/websocket/data"
+	"github.com/TerraDharitri/drt-go-chain-communication/websocket/transceiver"
+	"github.com/TerraDharitri/drt-go-chain-core/core"
+	"github.com/TerraDharitri/drt-go-chain-core/core/check"
+)
+
+// ArgsEndpoint holds the arguments needed for exposing a named endpoint on a server
+type ArgsEndpoint struct {
+	Name                string
+	Route               string
+	AckTimeoutInSeconds int
+	BlockingAckOnError  bool
+	WithAcknowledge     bool
+	PayloadVersion      uint32
+	TopicPolicies       []data.TopicPolicy
+}
+
+type argsNewEndpoint struct {
+	ArgsEndpoint
+	RetryDurationInSeconds     int
+	DropMessagesIfNoConnection bool
+	Handshake                  data.HandshakeConfig
+	PayloadConverter           webSocket.PayloadConverter
+	Log                        core.Logger
+}
+
+type endpoint struct {
+	name                       string
+	route                      string
+	blockingAckOnError         bool
+	withAcknowledge            bool
+	dropMessagesIfNoConnection bool
+	ackTimeoutInSec            int
+	retryDurationInSec         int
+	payloadVersion             uint32
+	topicPolicies              []data.TopicPolicy
+	handshake                  data.HandshakeConfig
+	payloadConverter           webSocket.PayloadConverter
+	log                        core.Logger
+	transceiversAndConn        transceiversAndConnHandler
+	mutPayloadHandler          sync.RWMutex
+	payloadHandler             webSocket.PayloadHandler
+	sendMetrics                webSocket.SendMetricsHandler
+}
+
+func newEndpoint(args argsNewEndpoint) *endpoint {
+	return &endpoint{
+		name:                       args.Name,
+		route:                      args.Route,
+		blockingAckOnError:         args.BlockingAckOnError,
+		withAcknowledge:            args.WithAcknowledge,
+		dropMessagesIfNoConnection: args.DropMessagesIfNoConnection,
+		ackTimeoutInSec:            args.AckTimeoutInSeconds,
+		retryDurationInSec:         args.RetryDurationInSeconds,
+		payloadVersion:             args.PayloadVersion,
+		topicPolicies:              args.TopicPolicies,
+		handshake:                  args.Handshake,
+		payloadConverter:           args.PayloadConverter,
+		log:                        args.Log,
+		transceiversAndConn:        newTransceiversAndConnHolder(),
+		payloadHandler:             webSocket.NewNilPayloadHandler(),
+		sendMetrics:                webSocket.NewSendMetrics(),
+	}
+}
+
+func (e *endpoint) connectionHandler(connection webSocket.WSConClient) {
+	webSocketTransceiver, err := transceiver.NewTransceiver(transceiver.ArgsTransceiver{
+		PayloadConverter:   e.payloadConverter,
+		Log:                e.log,
+		RetryDurationInSec: e.retryDurationInSec,
+		AckTimeoutInSec:    e.ackTimeoutInSec,
+		BlockingAckOnError: e.blockingAckOnError,
+		WithAcknowledge:    e.withAcknowledge,
+		PayloadVersion:     e.payloadVersion,
+		TopicPolicies:      e.topicPolicies,
+		SendMetrics:        e.sendMetrics,
+		Handshake:          e.handshake,
+	})
+	if err != nil {
+		e.log.Warn("e.connectionHandler cannot create transceiver", "route", e.route, "error", err)
+		return
+	}
+	err = webSocketTransceiver.SetPayloadHandler(e.getPayloadHandler())
+	if err != nil {
+		e.log.Warn("e.SetPayloadHandler cannot set payload handler", "route", e.route, "error", err)
+	}
+
+	go func() {
+		e.transceiversAndConn.addTransceiverAndConn(webSocketTransceiver, connection)
+		// this method is blocking
+		_ = webSocketTransceiver.Listen(connection)
+		e.log.Info("connection closed", "client id", connection.GetID(), "route", e.route)
+		// if method listen will end, the client was disconnected, and we should remove the listener from the list
+		e.transceiversAndConn.remove(connection.GetID())
+	}()
+}
+
+// Send will send the provided payload to all the clients connected on this endpoint
+func (e *endpoint) Send(payload []byte, topic string) error {
+	transceiversAndCon := e.transceiversAndConn.getAll()
+	noClients := len(transceiversAndCon) == 0
+	if noClients && !e.dropMessagesIfNoConnection {
+		return data.ErrNoClientsConnected
+	}
+
+	for _, tuple := range transceiversAndCon {
+		err := tuple.transceiver.Send(payload, topic, tuple.conn)
+		if err != nil {
+			e.log.Debug("e.Send() cannot send message", "route", e.route, "id", tuple.conn.GetID(), "error", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// GetSendMetrics returns the counters of the send operations done on this endpoint
+func (e *endpoint) GetSendMetrics() data.SendMetrics {
+	return e.sendMetrics.GetSendMetrics()
+}
+
+// SetPayloadHandler will set the payload handler used for the connections accepted from now on
+func (e *endpoint) SetPayloadHandler(handler webSocket.PayloadHandler) error {
+	if check.IfNil(handler) {
+		return data.ErrNilPayloadProcessor
+	}
+
+	e.mutPayloadHandler.Lock()
+	e.payloadHandler = handler
+	e.mutPayloadHandler.Unlock()
+
+	return nil
+}
+
+func (e *endpoint) getPayloadHandler() webSocket.PayloadHandler {
+	e.mutPayloadHandler.RLock()
+	defer e.mutPayloadHandler.RUnlock()
+
+	return e.payloadHandler
+}
+
+// Close will close all the transceivers and connections of this endpoint
+func (e *endpoint) Close() error {
+	var lastError error
+	for _, tuple := range e.transceiversAndConn.getAll() {
+		err := tuple.transceiver.Close()
+		if err != nil {
+			e.log.Debug("endpoint.Close() cannot close transceiver", "route", e.route, "error", err)
+			lastError = err
+		}
+		err = tuple.conn.Close()
+		if err != nil {
+			e.log.Debug("endpoint.Close() cannot close connection", "route", e.route, "id", tuple.conn.GetID(), "error", err.Error())
+			lastError = err
+		}
+	}
+
+	return lastError
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (e *endpoint) IsInterfaceNil() bool {
+	return e == nil
+}
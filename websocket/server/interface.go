@@ -2,6 +2,7 @@ package server
 
 import (
 	"github.com/TerraDharitri/drt-go-chain-communication/websocket"
+	"github.com/TerraDharitri/drt-go-chain-communication/websocket/data"
 )
 
 type transceiversAndConnHandler interface {
@@ -17,3 +18,12 @@ type Transceiver interface {
 	Listen(connection websocket.WSConClient) (closed bool)
 	Close() error
 }
+
+// Endpoint defines what a named server endpoint should be able to do
+type Endpoint interface {
+	Send(payload []byte, topic string) error
+	SetPayloadHandler(handler websocket.PayloadHandler) error
+	GetSendMetrics() data.SendMetrics
+	Close() error
+	IsInterfaceNil() bool
+}
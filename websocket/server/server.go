@@ -2,9 +2,10 @@ package server
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"net/http"
 	"strings"
-	"time"
 
 	webSocket "github.com/TerraDharitri/drt-go-chain-communication/websocket"
 	"github.com/TerraDharitri/drt-go-chain-communication/websocket/connection"
@@ -24,23 +25,22 @@ type ArgsWebSocketServer struct {
 	WithAcknowledge            bool
 	DropMessagesIfNoConnection bool
 	URL                        string
+	Transport                  string
+	Route                      string
+	Endpoints                  []ArgsEndpoint
 	PayloadConverter           webSocket.PayloadConverter
 	Log                        core.Logger
 	PayloadVersion             uint32
+	TopicPolicies              []data.TopicPolicy
+	Handshake                  data.HandshakeConfig
 }
 
 type server struct {
-	blockingAckOnError         bool
-	withAcknowledge            bool
-	dropMessagesIfNoConnection bool
-	ackTimeoutInSec            int
-	payloadConverter           webSocket.PayloadConverter
-	retryDuration              time.Duration
-	log                        core.Logger
-	httpServer                 webSocket.HttpServerHandler
-	transceiversAndConn        transceiversAndConnHandler
-	payloadHandler             webSocket.PayloadHandler
-	payloadVersion             uint32
+	log             core.Logger
+	httpServer      webSocket.HttpServerHandler
+	listener        net.Listener
+	defaultEndpoint *endpoint
+	endpoints       map[string]*endpoint
 }
 
 // NewWebSocketServer will create a new instance of server
@@ -49,20 +49,55 @@ func NewWebSocketServer(args ArgsWebSocketServer) (*server, error) {
 		return nil, err
 	}
 
+	route := args.Route
+	if route == "" {
+		route = data.WSRoute
+	}
+
 	wsServer := &server{
-		transceiversAndConn:        newTransceiversAndConnHolder(),
-		blockingAckOnError:         args.BlockingAckOnError,
-		log:                        args.Log,
-		retryDuration:              time.Duration(args.RetryDurationInSeconds) * time.Second,
-		payloadConverter:           args.PayloadConverter,
-		payloadHandler:             webSocket.NewNilPayloadHandler(),
-		withAcknowledge:            args.WithAcknowledge,
-		dropMessagesIfNoConnection: args.DropMessagesIfNoConnection,
-		ackTimeoutInSec:            args.AckTimeoutInSeconds,
-		payloadVersion:             args.PayloadVersion,
+		log:       args.Log,
+		endpoints: make(map[string]*endpoint),
 	}
+	wsServer.defaultEndpoint = newEndpoint(argsNewEndpoint{
+		ArgsEndpoint: ArgsEndpoint{
+			Route:               route,
+			AckTimeoutInSeconds: args.AckTimeoutInSeconds,
+			BlockingAckOnError:  args.BlockingAckOnError,
+			WithAcknowledge:     args.WithAcknowledge,
+			PayloadVersion:      args.PayloadVersion,
+			TopicPolicies:       args.TopicPolicies,
+		},
+		RetryDurationInSeconds:     args.RetryDurationInSeconds,
+		DropMessagesIfNoConnection: args.DropMessagesIfNoConnection,
+		Handshake:                  args.Handshake,
+		PayloadConverter:           args.PayloadConverter,
+		Log:                        args.Log,
+	})
 
-	wsServer.initializeServer(args.URL, data.WSRoute)
+	routes := map[string]struct{}{route: {}}
+	for _, argsEndpoint := range args.Endpoints {
+		err := checkEndpointArgs(argsEndpoint, wsServer.endpoints, routes)
+		if err != nil {
+			return nil, fmt.Errorf("%w for endpoint %s", err, argsEndpoint.Name)
+		}
+
+		wsServer.endpoints[argsEndpoint.Name] = newEndpoint(argsNewEndpoint{
+			ArgsEndpoint:               argsEndpoint,
+			RetryDurationInSeconds:     args.RetryDurationInSeconds,
+			DropMessagesIfNoConnection: args.DropMessagesIfNoConnection,
+			Handshake:                  args.Handshake,
+			PayloadConverter:           args.PayloadConverter,
+			Log:                        args.Log,
+		})
+		routes[argsEndpoint.Route] = struct{}{}
+	}
+
+	err := wsServer.createListener(args.Transport, args.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	wsServer.initializeServer(args.URL)
 
 	return wsServer, nil
 }
@@ -80,56 +115,86 @@ func checkArgs(args ArgsWebSocketServer) error {
 	if args.RetryDurationInSeconds == 0 {
 		return data.ErrZeroValueRetryDuration
 	}
-	return nil
+	err := connection.CheckTransport(args.Transport)
+	if err != nil {
+		return err
+	}
+	err = transceiver.CheckHandshakeConfig(args.Handshake)
+	if err != nil {
+		return err
+	}
+	return transceiver.CheckTopicPolicies(args.TopicPolicies)
 }
 
-func (s *server) connectionHandler(connection webSocket.WSConClient) {
-	webSocketTransceiver, err := transceiver.NewTransceiver(transceiver.ArgsTransceiver{
-		PayloadConverter:   s.payloadConverter,
-		Log:                s.log,
-		RetryDurationInSec: int(s.retryDuration.Seconds()),
-		AckTimeoutInSec:    s.ackTimeoutInSec,
-		BlockingAckOnError: s.blockingAckOnError,
-		WithAcknowledge:    s.withAcknowledge,
-		PayloadVersion:     s.payloadVersion,
-	})
-	if err != nil {
-		s.log.Warn("s.connectionHandler cannot create transceiver", "error", err)
-		return
+func checkEndpointArgs(args ArgsEndpoint, endpoints map[string]*endpoint, routes map[string]struct{}) error {
+	if args.Name == "" {
+		return data.ErrEmptyEndpointName
+	}
+	_, found := endpoints[args.Name]
+	if found {
+		return data.ErrDuplicatedEndpointName
+	}
+	if args.Route == "" {
+		return data.ErrEmptyRoute
+	}
+	_, found = routes[args.Route]
+	if found {
+		return data.ErrDuplicatedRoute
+	}
+	if args.WithAcknowledge && args.AckTimeoutInSeconds == 0 {
+		return data.ErrZeroValueAckTimeout
+	}
+	return transceiver.CheckTopicPolicies(args.TopicPolicies)
+}
+
+// createListener opens the listener up front for the non-TCP transports, so that an invalid address is reported
+// on construction and the in-memory clients can dial as soon as the server is created
+func (s *server) createListener(transport string, address string) error {
+	if transport == "" || transport == data.TransportTCP {
+		return nil
 	}
-	err = webSocketTransceiver.SetPayloadHandler(s.payloadHandler)
+
+	listener, err := connection.NewListener(transport, address)
 	if err != nil {
-		s.log.Warn("s.SetPayloadHandler cannot set payload handler", "error", err)
+		return err
 	}
 
-	go func() {
-		s.transceiversAndConn.addTransceiverAndConn(webSocketTransceiver, connection)
-		// this method is blocking
-		_ = webSocketTransceiver.Listen(connection)
-		s.log.Info("connection closed", "client id", connection.GetID())
-		// if method listen will end, the client was disconnected, and we should remove the listener from the list
-		s.transceiversAndConn.remove(connection.GetID())
-	}()
+	s.listener = listener
+	return nil
+}
+
+func (s *server) connectionHandler(connection webSocket.WSConClient) {
+	s.defaultEndpoint.connectionHandler(connection)
 }
 
-func (s *server) initializeServer(wsURL string, wsPath string) {
+func (s *server) initializeServer(wsURL string) {
 	router := mux.NewRouter()
 	httpServer := &http.Server{
 		Addr:    wsURL,
 		Handler: router,
 	}
 
+	s.log.Info("wsServer.initializeServer(): initializing WebSocket server", "url", wsURL, "path", s.defaultEndpoint.route)
+	s.handleRoute(router, s.defaultEndpoint)
+	for name, namedEndpoint := range s.endpoints {
+		s.log.Info("wsServer.initializeServer(): exposing endpoint", "name", name, "path", namedEndpoint.route)
+		s.handleRoute(router, namedEndpoint)
+	}
+
+	s.httpServer = httpServer
+
+	s.start()
+}
+
+func (s *server) handleRoute(router *mux.Router, wsEndpoint *endpoint) {
 	upgrader := websocket.Upgrader{
 		ReadBufferSize:  1024,
 		WriteBufferSize: 1024,
+		CheckOrigin:     func(r *http.Request) bool { return true },
 	}
 
-	s.log.Info("wsServer.initializeServer(): initializing WebSocket server", "url", wsURL, "path", wsPath)
-
 	addClientFunc := func(writer http.ResponseWriter, r *http.Request) {
-		s.log.Info("new connection", "route", wsPath, "remote address", r.RemoteAddr)
-
-		upgrader.CheckOrigin = func(r *http.Request) bool { return true }
+		s.log.Info("new connection", "route", wsEndpoint.route, "remote address", r.RemoteAddr)
 
 		ws, errUpgrade := upgrader.Upgrade(writer, r, nil)
 		if errUpgrade != nil {
@@ -137,42 +202,33 @@ func (s *server) initializeServer(wsURL string, wsPath string) {
 			return
 		}
 		client := connection.NewWSConnClientWithConn(ws)
-		s.connectionHandler(client)
+		wsEndpoint.connectionHandler(client)
 	}
 
-	routeSendData := router.HandleFunc(wsPath, addClientFunc)
+	routeSendData := router.HandleFunc(wsEndpoint.route, addClientFunc)
 	if routeSendData.GetError() != nil {
 		s.log.Error("sender router failed to handle send data",
-			"route", routeSendData.GetName(),
+			"route", wsEndpoint.route,
 			"error", routeSendData.GetError())
 	}
-
-	s.httpServer = httpServer
-
-	s.start()
 }
 
-// Send will send the provided payload from args
+// Send will send the provided payload from args on the default endpoint
 func (s *server) Send(payload []byte, topic string) error {
-	transceiversAndCon := s.transceiversAndConn.getAll()
-	noClients := len(transceiversAndCon) == 0
-	if noClients && !s.dropMessagesIfNoConnection {
-		return data.ErrNoClientsConnected
-	}
+	return s.defaultEndpoint.Send(payload, topic)
+}
 
-	for _, tuple := range transceiversAndCon {
-		err := tuple.transceiver.Send(payload, topic, tuple.conn)
-		if err != nil {
-			s.log.Debug("s.Send() cannot send message", "id", tuple.conn.GetID(), "error", err.Error())
-		}
+func (s *server) serve() error {
+	if s.listener == nil {
+		return s.httpServer.ListenAndServe()
 	}
 
-	return nil
+	return s.httpServer.Serve(s.listener)
 }
 
 func (s *server) start() {
 	go func() {
-		err := s.httpServer.ListenAndServe()
+		err := s.serve()
 		shouldLogError := err != nil && !strings.Contains(err.Error(), data.ErrServerIsClosed.Error())
 		if shouldLogError {
 			s.log.Error("could not initialize webserver", "error", err)
@@ -183,10 +239,24 @@ func (s *server) start() {
 	}()
 }
 
-// SetPayloadHandler will set the provided payload handler
+// SetPayloadHandler will set the provided payload handler on the default endpoint
 func (s *server) SetPayloadHandler(handler webSocket.PayloadHandler) error {
-	s.payloadHandler = handler
-	return nil
+	return s.defaultEndpoint.SetPayloadHandler(handler)
+}
+
+// GetSendMetrics returns the counters of the send operations done on the default endpoint
+func (s *server) GetSendMetrics() data.SendMetrics {
+	return s.defaultEndpoint.GetSendMetrics()
+}
+
+// GetEndpoint returns the named endpoint
+func (s *server) GetEndpoint(name string) (Endpoint, error) {
+	namedEndpoint, found := s.endpoints[name]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", data.ErrEndpointNotFound, name)
+	}
+
+	return namedEndpoint, nil
 }
 
 // Close will close the server
@@ -199,15 +269,14 @@ func (s *server) Close() error {
 		lastError = err
 	}
 
-	for _, tuple := range s.transceiversAndConn.getAll() {
-		err = tuple.transceiver.Close()
-		if err != nil {
-			s.log.Debug("server.Close() cannot close transceiver", "error", err)
-			lastError = err
-		}
-		err = tuple.conn.Close()
+	err = s.defaultEndpoint.Close()
+	if err != nil {
+		lastError = err
+	}
+
+	for _, namedEndpoint := range s.endpoints {
+		err = namedEndpoint.Close()
 		if err != nil {
-			s.log.Debug("server.Close() cannot close connection", "id", tuple.conn.GetID(), "error", err.Error())
 			lastError = err
 		}
 	}
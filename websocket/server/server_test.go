@@ -59,6 +59,96 @@ func TestNewWebSocketsServer(t *testing.T) {
 		require.Nil(t, ws)
 		require.Equal(t, data.ErrZeroValueRetryDuration, err)
 	})
+
+	t.Run("endpoint with empty name, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.Endpoints = []ArgsEndpoint{{Route: "/blocks"}}
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.True(t, errors.Is(err, data.ErrEmptyEndpointName))
+	})
+
+	t.Run("endpoint with empty route, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.Endpoints = []ArgsEndpoint{{Name: "blocks"}}
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.True(t, errors.Is(err, data.ErrEmptyRoute))
+	})
+
+	t.Run("duplicated endpoint name, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.Endpoints = []ArgsEndpoint{{Name: "blocks", Route: "/blocks"}, {Name: "blocks", Route: "/other"}}
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.True(t, errors.Is(err, data.ErrDuplicatedEndpointName))
+	})
+
+	t.Run("endpoint route equal to the default route, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.Endpoints = []ArgsEndpoint{{Name: "blocks", Route: data.WSRoute}}
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.True(t, errors.Is(err, data.ErrDuplicatedRoute))
+	})
+
+	t.Run("endpoint with acknowledge and zero ack timeout, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.Endpoints = []ArgsEndpoint{{Name: "blocks", Route: "/blocks", WithAcknowledge: true}}
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.True(t, errors.Is(err, data.ErrZeroValueAckTimeout))
+	})
+
+	t.Run("invalid topic policy, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.TopicPolicies = []data.TopicPolicy{{Topic: "topic", Delivery: "invalid"}}
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.True(t, errors.Is(err, data.ErrInvalidDeliveryPolicy))
+	})
+	t.Run("invalid transport, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.Transport = "udp"
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, ws)
+		require.True(t, errors.Is(err, data.ErrInvalidTransport))
+	})
+
+	t.Run("memory transport with an address already in use, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.URL = "server-test-duplicated-address"
+		args.Transport = data.TransportMemory
+		ws, err := NewWebSocketServer(args)
+		require.Nil(t, err)
+
+		ws2, err := NewWebSocketServer(args)
+		require.Nil(t, ws2)
+		require.True(t, errors.Is(err, data.ErrInMemoryListenerAlreadyExists))
+		_ = ws.Close()
+	})
+}
+
+func TestServer_GetEndpoint(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	args.Route = "/default"
+	args.Endpoints = []ArgsEndpoint{{Name: "blocks", Route: "/blocks", PayloadVersion: 2}}
+	wsServer, err := NewWebSocketServer(args)
+	require.Nil(t, err)
+	require.Equal(t, "/default", wsServer.defaultEndpoint.route)
+
+	blocksEndpoint, err := wsServer.GetEndpoint("blocks")
+	require.Nil(t, err)
+	require.Equal(t, "/blocks", blocksEndpoint.(*endpoint).route)
+	require.Equal(t, uint32(2), blocksEndpoint.(*endpoint).payloadVersion)
+
+	missingEndpoint, err := wsServer.GetEndpoint("missing")
+	require.Nil(t, missingEndpoint)
+	require.True(t, errors.Is(err, data.ErrEndpointNotFound))
+
+	_ = wsServer.Close()
 }
 
 func TestServer_ListenAndClose(t *testing.T) {
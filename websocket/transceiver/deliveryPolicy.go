@@ -0,0 +1,88 @@
+package transceiver
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/websocket/data"
+)
+
+type deliveryPolicy struct {
+	withAcknowledge    bool
+	blockingAckOnError bool
+	ackTimeout         time.Duration
+	maxRetries         int
+	timeToLive         time.Duration
+	priority           uint32
+}
+
+// CheckTopicPolicies returns an error if the provided topic policies are not valid
+func CheckTopicPolicies(topicPolicies []data.TopicPolicy) error {
+	_, err := createTopicsDeliveryPolicies(topicPolicies)
+	return err
+}
+
+func createTopicsDeliveryPolicies(topicPolicies []data.TopicPolicy) (map[string]deliveryPolicy, error) {
+	policies := make(map[string]deliveryPolicy, len(topicPolicies))
+	for _, topicPolicy := range topicPolicies {
+		err := checkTopicPolicy(topicPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("%w for topic %s", err, topicPolicy.Topic)
+		}
+
+		_, found := policies[topicPolicy.Topic]
+		if found {
+			return nil, fmt.Errorf("%w for topic %s", data.ErrDuplicatedTopicPolicy, topicPolicy.Topic)
+		}
+
+		policies[topicPolicy.Topic] = deliveryPolicy{
+			withAcknowledge:    topicPolicy.Delivery != data.DeliveryAtMostOnce,
+			blockingAckOnError: topicPolicy.Delivery == data.DeliveryBlockingAck,
+			ackTimeout:         time.Duration(topicPolicy.AcknowledgeTimeoutInSec) * time.Second,
+			maxRetries:         topicPolicy.MaxRetries,
+			timeToLive:         time.Duration(topicPolicy.TimeToLiveInSec) * time.Second,
+			priority:           topicPolicy.Priority,
+		}
+	}
+
+	return policies, nil
+}
+
+func checkTopicPolicy(topicPolicy data.TopicPolicy) error {
+	if topicPolicy.Topic == "" {
+		return data.ErrEmptyTopic
+	}
+	if topicPolicy.MaxRetries < 0 {
+		return data.ErrNegativeMaxRetries
+	}
+	if topicPolicy.TimeToLiveInSec < 0 {
+		return data.ErrNegativeTimeToLive
+	}
+
+	switch topicPolicy.Delivery {
+	case data.DeliveryAtMostOnce:
+		return nil
+	case data.DeliveryAtLeastOnce, data.DeliveryBlockingAck:
+		if topicPolicy.AcknowledgeTimeoutInSec == 0 {
+			return data.ErrZeroValueAckTimeout
+		}
+		return nil
+	default:
+		return data.ErrInvalidDeliveryPolicy
+	}
+}
+
+func (dp deliveryPolicy) deliveryMode() uint32 {
+	switch {
+	case !dp.withAcknowledge:
+		return data.DeliveryModeAtMostOnce
+	case dp.blockingAckOnError:
+		return data.DeliveryModeBlockingAck
+	default:
+		return data.DeliveryModeAtLeastOnce
+	}
+}
+
+func isExpired(deadline int64) bool {
+	return deadline != 0 && time.Now().UnixMilli() > deadline
+}
@@ -0,0 +1,188 @@
+package transceiver
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	webSocket "github.com/TerraDharitri/drt-go-chain-communication/websocket"
+	"github.com/TerraDharitri/drt-go-chain-communication/websocket/data"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// the close reason of a websocket close frame can have at most 123 bytes
+	maxCloseReasonLength    = 123
+	defaultHandshakeTimeout = 10 * time.Second
+)
+
+var knownFeatures = map[string]struct{}{
+	data.FeatureCompression:   {},
+	data.FeatureWindowedAck:   {},
+	data.FeatureSubscriptions: {},
+}
+
+// CheckHandshakeConfig returns an error if the provided handshake configuration is not valid
+func CheckHandshakeConfig(config data.HandshakeConfig) error {
+	if !config.Enabled {
+		return nil
+	}
+	for _, feature := range config.Features {
+		_, found := knownFeatures[feature]
+		if !found {
+			return fmt.Errorf("%w: %s", data.ErrUnknownFeature, feature)
+		}
+	}
+
+	return nil
+}
+
+func getHandshakeTimeout(config data.HandshakeConfig) time.Duration {
+	if config.TimeoutInSec <= 0 {
+		return defaultHandshakeTimeout
+	}
+
+	return time.Duration(config.TimeoutInSec) * time.Second
+}
+
+func createLocalHandshake(config data.HandshakeConfig, payloadVersion uint32, marshallerType string) data.Handshake {
+	payloadVersions := config.SupportedPayloadVersions
+	if len(payloadVersions) == 0 {
+		payloadVersions = []uint32{payloadVersion}
+	}
+
+	return data.Handshake{
+		ProtocolVersion: data.ProtocolVersion,
+		MarshallerType:  marshallerType,
+		PayloadVersions: payloadVersions,
+		Features:        config.Features,
+	}
+}
+
+// negotiateHandshake returns the common subset of the two handshakes, or an error if the peers are not compatible
+func negotiateHandshake(local data.Handshake, remote data.Handshake, payloadVersion uint32) (data.Handshake, error) {
+	if local.ProtocolVersion != remote.ProtocolVersion {
+		return data.Handshake{}, fmt.Errorf("%w: local %d, remote %d", data.ErrProtocolVersionMismatch, local.ProtocolVersion, remote.ProtocolVersion)
+	}
+	if local.MarshallerType != remote.MarshallerType {
+		return data.Handshake{}, fmt.Errorf("%w: local %s, remote %s", data.ErrMarshallerTypeMismatch, local.MarshallerType, remote.MarshallerType)
+	}
+	if !containsPayloadVersion(remote.PayloadVersions, payloadVersion) {
+		return data.Handshake{}, fmt.Errorf("%w: version %d, remote supports %v", data.ErrPayloadVersionNotSupported, payloadVersion, remote.PayloadVersions)
+	}
+
+	negotiated := data.Handshake{
+		ProtocolVersion: local.ProtocolVersion,
+		MarshallerType:  local.MarshallerType,
+		PayloadVersions: make([]uint32, 0),
+		Features:        make([]string, 0),
+	}
+	for _, version := range local.PayloadVersions {
+		if containsPayloadVersion(remote.PayloadVersions, version) {
+			negotiated.PayloadVersions = append(negotiated.PayloadVersions, version)
+		}
+	}
+	for _, feature := range local.Features {
+		if containsFeature(remote.Features, feature) {
+			negotiated.Features = append(negotiated.Features, feature)
+		}
+	}
+
+	return negotiated, nil
+}
+
+func containsPayloadVersion(versions []uint32, version uint32) bool {
+	for _, v := range versions {
+		if v == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsFeature(features []string, feature string) bool {
+	for _, f := range features {
+		if f == feature {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (wt *wsTransceiver) doHandshake(connection webSocket.WSConClient) error {
+	localHandshakeBytes, err := json.Marshal(wt.localHandshake)
+	if err != nil {
+		return err
+	}
+
+	err = connection.WriteMessage(websocket.TextMessage, localHandshakeBytes)
+	if err != nil {
+		return err
+	}
+
+	// a peer that stays silent should not hold the connection forever
+	err = connection.SetReadDeadline(time.Now().Add(wt.handshakeTimeout))
+	if err != nil {
+		return err
+	}
+
+	_, remoteHandshakeBytes, err := connection.ReadMessage()
+	if err != nil {
+		return err
+	}
+
+	remoteHandshake := data.Handshake{}
+	err = json.Unmarshal(remoteHandshakeBytes, &remoteHandshake)
+	if err != nil {
+		err = fmt.Errorf("%w: %s", data.ErrInvalidHandshake, err.Error())
+		wt.closeWithReason(connection, err)
+		return err
+	}
+
+	negotiated, err := negotiateHandshake(wt.localHandshake, remoteHandshake, wt.payloadVersion)
+	if err != nil {
+		wt.closeWithReason(connection, err)
+		return err
+	}
+
+	err = connection.SetReadDeadline(time.Time{})
+	if err != nil {
+		return err
+	}
+
+	wt.mutHandshake.Lock()
+	wt.negotiatedHandshake = &negotiated
+	wt.mutHandshake.Unlock()
+
+	wt.log.Debug("wt.doHandshake(): handshake done", "payload versions", negotiated.PayloadVersions, "features", negotiated.Features)
+
+	return nil
+}
+
+func (wt *wsTransceiver) closeWithReason(connection webSocket.WSConClient, reason error) {
+	reasonString := reason.Error()
+	if len(reasonString) > maxCloseReasonLength {
+		reasonString = reasonString[:maxCloseReasonLength]
+	}
+
+	closeMessage := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reasonString)
+	err := connection.WriteMessage(websocket.CloseMessage, closeMessage)
+	if err != nil {
+		wt.log.Debug("wt.closeWithReason(): cannot send close message", "error", err)
+	}
+}
+
+func (wt *wsTransceiver) resetHandshake() {
+	wt.mutHandshake.Lock()
+	wt.negotiatedHandshake = nil
+	wt.mutHandshake.Unlock()
+}
+
+func (wt *wsTransceiver) getNegotiatedHandshake() *data.Handshake {
+	wt.mutHandshake.RLock()
+	defer wt.mutHandshake.RUnlock()
+
+	return wt.negotiatedHandshake
+}
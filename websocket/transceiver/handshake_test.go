@@ -0,0 +1,189 @@
+package transceiver
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TerraDharitri/drt-go-chain-communication/testscommon"
+	"github.com/TerraDharitri/drt-go-chain-communication/websocket/data"
+	"github.com/TerraDharitri/drt-go-chain-core/data/outport"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func createHandshake() data.Handshake {
+	return data.Handshake{
+		ProtocolVersion: data.ProtocolVersion,
+		MarshallerType:  "gogo protobuf",
+		PayloadVersions: []uint32{1, 2},
+		Features:        []string{data.FeatureCompression, data.FeatureSubscriptions},
+	}
+}
+
+func TestCheckHandshakeConfig(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, CheckHandshakeConfig(data.HandshakeConfig{}))
+
+	err := CheckHandshakeConfig(data.HandshakeConfig{Enabled: true, Features: []string{"unknown"}})
+	require.True(t, errors.Is(err, data.ErrUnknownFeature))
+
+	err = CheckHandshakeConfig(data.HandshakeConfig{Enabled: true, Features: []string{data.FeatureWindowedAck}})
+	require.Nil(t, err)
+}
+
+func TestNegotiateHandshake(t *testing.T) {
+	t.Parallel()
+
+	t.Run("protocol version mismatch", func(t *testing.T) {
+		remote := createHandshake()
+		remote.ProtocolVersion++
+		_, err := negotiateHandshake(createHandshake(), remote, 1)
+		require.True(t, errors.Is(err, data.ErrProtocolVersionMismatch))
+	})
+
+	t.Run("marshaller type mismatch", func(t *testing.T) {
+		remote := createHandshake()
+		remote.MarshallerType = "json"
+		_, err := negotiateHandshake(createHandshake(), remote, 1)
+		require.True(t, errors.Is(err, data.ErrMarshallerTypeMismatch))
+	})
+
+	t.Run("payload version not supported by the remote", func(t *testing.T) {
+		remote := createHandshake()
+		remote.PayloadVersions = []uint32{2}
+		_, err := negotiateHandshake(createHandshake(), remote, 1)
+		require.True(t, errors.Is(err, data.ErrPayloadVersionNotSupported))
+	})
+
+	t.Run("should negotiate the common subset", func(t *testing.T) {
+		remote := createHandshake()
+		remote.PayloadVersions = []uint32{2, 3, 1}
+		remote.Features = []string{data.FeatureSubscriptions, data.FeatureWindowedAck}
+		negotiated, err := negotiateHandshake(createHandshake(), remote, 1)
+		require.Nil(t, err)
+		require.Equal(t, []uint32{1, 2}, negotiated.PayloadVersions)
+		require.Equal(t, []string{data.FeatureSubscriptions}, negotiated.Features)
+	})
+}
+
+func TestWsTransceiver_Handshake(t *testing.T) {
+	t.Parallel()
+
+	t.Run("send before the handshake should error", func(t *testing.T) {
+		args := createArgs()
+		args.Handshake = data.HandshakeConfig{Enabled: true}
+		webSocketTransceiver, _ := NewTransceiver(args)
+
+		err := webSocketTransceiver.Send([]byte("message"), outport.TopicSaveBlock, &testscommon.WebsocketConnectionStub{})
+		require.Equal(t, data.ErrHandshakeNotDone, err)
+	})
+
+	t.Run("mismatch should close the connection with the reason", func(t *testing.T) {
+		args := createArgs()
+		args.PayloadVersion = 1
+		args.Handshake = data.HandshakeConfig{Enabled: true}
+		webSocketTransceiver, _ := NewTransceiver(args)
+
+		remoteHandshake := createHandshake()
+		remoteHandshakeBytes, _ := json.Marshal(remoteHandshake)
+		var closeMessage []byte
+		connectionClosed := false
+		conn := &testscommon.WebsocketConnectionStub{
+			ReadMessageCalled: func() (int, []byte, error) {
+				return websocket.TextMessage, remoteHandshakeBytes, nil
+			},
+			WriteMessageCalled: func(messageType int, payload []byte) error {
+				if messageType == websocket.CloseMessage {
+					closeMessage = payload
+				}
+				return nil
+			},
+			CloseCalled: func() error {
+				connectionClosed = true
+				return nil
+			},
+		}
+
+		closed := webSocketTransceiver.Listen(conn)
+		require.True(t, closed)
+		require.True(t, connectionClosed)
+		require.Contains(t, string(closeMessage), data.ErrMarshallerTypeMismatch.Error())
+		require.Nil(t, webSocketTransceiver.getNegotiatedHandshake())
+	})
+
+	t.Run("silent peer should time out and close the connection", func(t *testing.T) {
+		args := createArgs()
+		args.Handshake = data.HandshakeConfig{Enabled: true, TimeoutInSec: 2}
+		webSocketTransceiver, _ := NewTransceiver(args)
+
+		var deadline time.Time
+		connectionClosed := false
+		conn := &testscommon.WebsocketConnectionStub{
+			SetReadDeadlineCalled: func(readDeadline time.Time) error {
+				deadline = readDeadline
+				return nil
+			},
+			ReadMessageCalled: func() (int, []byte, error) {
+				return 0, nil, errors.New("i/o timeout")
+			},
+			CloseCalled: func() error {
+				connectionClosed = true
+				return nil
+			},
+		}
+
+		start := time.Now()
+		_ = webSocketTransceiver.Listen(conn)
+		require.True(t, connectionClosed)
+		require.True(t, deadline.After(start.Add(time.Second)))
+		require.True(t, deadline.Before(start.Add(3*time.Second)))
+		require.Nil(t, webSocketTransceiver.getNegotiatedHandshake())
+	})
+
+	t.Run("payload with a version that was not negotiated should be dropped", func(t *testing.T) {
+		args := createArgs()
+		args.PayloadVersion = 1
+		args.Handshake = data.HandshakeConfig{Enabled: true}
+		webSocketTransceiver, _ := NewTransceiver(args)
+
+		remoteHandshake := createHandshake()
+		remoteHandshake.MarshallerType = args.PayloadConverter.MarshallerType()
+		remoteHandshakeBytes, _ := json.Marshal(remoteHandshake)
+		payloadV1, _ := args.PayloadConverter.ConstructPayload(&data.WsMessage{Type: data.PayloadMessage, Version: 1})
+		payloadV2, _ := args.PayloadConverter.ConstructPayload(&data.WsMessage{Type: data.PayloadMessage, Version: 2})
+		messages := [][]byte{remoteHandshakeBytes, payloadV1, payloadV2}
+		deadlines := make([]time.Time, 0)
+		conn := &testscommon.WebsocketConnectionStub{
+			SetReadDeadlineCalled: func(deadline time.Time) error {
+				deadlines = append(deadlines, deadline)
+				return nil
+			},
+			ReadMessageCalled: func() (int, []byte, error) {
+				if len(messages) == 0 {
+					return 0, nil, errors.New("closed")
+				}
+				message := messages[0]
+				messages = messages[1:]
+				return websocket.BinaryMessage, message, nil
+			},
+		}
+
+		processedVersions := make([]uint32, 0)
+		_ = webSocketTransceiver.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+			ProcessPayloadCalled: func(_ []byte, _ string, version uint32) error {
+				processedVersions = append(processedVersions, version)
+				return nil
+			},
+		})
+
+		_ = webSocketTransceiver.Listen(conn)
+		require.Equal(t, []uint32{1}, processedVersions)
+		require.Equal(t, []uint32{1}, webSocketTransceiver.getNegotiatedHandshake().PayloadVersions)
+		require.Equal(t, 2, len(deadlines))
+		require.False(t, deadlines[0].IsZero())
+		require.True(t, deadlines[1].IsZero())
+	})
+}
@@ -0,0 +1,65 @@
+package transceiver
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+// expiredDeadlineRetention is how long the deadline of an expired message is kept, so that the message is still
+// dropped if the caller resends it shortly after it expired
+const expiredDeadlineRetention = time.Minute
+
+// pendingDeadlines remembers the deadlines of the messages that were not delivered yet, so that a caller resending
+// the same message, e.g. while the connection is re-established, does not extend its time to live
+type pendingDeadlines struct {
+	mut       sync.Mutex
+	deadlines map[string]int64
+}
+
+func newPendingDeadlines() *pendingDeadlines {
+	return &pendingDeadlines{
+		deadlines: make(map[string]int64),
+	}
+}
+
+func createDeadlineKey(topic string, payload []byte) string {
+	payloadHash := sha256.Sum256(payload)
+
+	return topic + string(payloadHash[:])
+}
+
+// stamp returns the deadline of the message, computing it only when the message is enqueued for the first time
+func (pd *pendingDeadlines) stamp(key string, timeToLive time.Duration) int64 {
+	pd.mut.Lock()
+	defer pd.mut.Unlock()
+
+	now := time.Now()
+	deadline, found := pd.deadlines[key]
+	if found {
+		return deadline
+	}
+
+	pd.sweep(now)
+
+	deadline = now.Add(timeToLive).UnixMilli()
+	pd.deadlines[key] = deadline
+
+	return deadline
+}
+
+func (pd *pendingDeadlines) remove(key string) {
+	pd.mut.Lock()
+	delete(pd.deadlines, key)
+	pd.mut.Unlock()
+}
+
+// sweep removes the deadlines of the messages that expired a while ago without being resent, so the map stays bounded
+func (pd *pendingDeadlines) sweep(now time.Time) {
+	cutoff := now.Add(-expiredDeadlineRetention).UnixMilli()
+	for key, deadline := range pd.deadlines {
+		if cutoff > deadline {
+			delete(pd.deadlines, key)
+		}
+	}
+}
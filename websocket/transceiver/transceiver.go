@@ -23,22 +23,32 @@ type ArgsTransceiver struct {
 	BlockingAckOnError bool
 	WithAcknowledge    bool
 	PayloadVersion     uint32
+	TopicPolicies      []data.TopicPolicy
+	SendMetrics        webSocket.SendMetricsHandler
+	Handshake          data.HandshakeConfig
 }
 
 type wsTransceiver struct {
-	payloadParser      webSocket.PayloadConverter
-	payloadHandler     webSocket.PayloadHandler
-	mutPayloadHandler  sync.RWMutex
-	log                core.Logger
-	safeCloser         core.SafeCloser
-	retryDuration      time.Duration
-	ackTimeout         time.Duration
-	mapAck             map[uint64]chan struct{}
-	mutMapAck          sync.Mutex
-	counter            uint64
-	blockingAckOnError bool
-	withAcknowledge    bool
-	payloadVersion     uint32
+	payloadParser       webSocket.PayloadConverter
+	payloadHandler      webSocket.PayloadHandler
+	mutPayloadHandler   sync.RWMutex
+	log                 core.Logger
+	safeCloser          core.SafeCloser
+	retryDuration       time.Duration
+	mapAck              map[uint64]chan struct{}
+	mutMapAck           sync.Mutex
+	counter             uint64
+	defaultPolicy       deliveryPolicy
+	topicPolicies       map[string]deliveryPolicy
+	payloadVersion      uint32
+	writeScheduler      *writeScheduler
+	sendMetrics         webSocket.SendMetricsHandler
+	pendingDeadlines    *pendingDeadlines
+	withHandshake       bool
+	localHandshake      data.Handshake
+	handshakeTimeout    time.Duration
+	negotiatedHandshake *data.Handshake
+	mutHandshake        sync.RWMutex
 }
 
 // NewTransceiver will create a new instance of transceiver
@@ -48,17 +58,36 @@ func NewTransceiver(args ArgsTransceiver) (*wsTransceiver, error) {
 		return nil, err
 	}
 
+	topicPolicies, err := createTopicsDeliveryPolicies(args.TopicPolicies)
+	if err != nil {
+		return nil, err
+	}
+
+	sendMetrics := args.SendMetrics
+	if check.IfNil(sendMetrics) {
+		sendMetrics = webSocket.NewSendMetrics()
+	}
+
 	return &wsTransceiver{
-		log:                args.Log,
-		retryDuration:      time.Duration(args.RetryDurationInSec) * time.Second,
-		ackTimeout:         time.Duration(args.AckTimeoutInSec) * time.Second,
-		blockingAckOnError: args.BlockingAckOnError,
-		safeCloser:         closing.NewSafeChanCloser(),
-		payloadHandler:     webSocket.NewNilPayloadHandler(),
-		payloadParser:      args.PayloadConverter,
-		withAcknowledge:    args.WithAcknowledge,
-		payloadVersion:     args.PayloadVersion,
-		mapAck:             make(map[uint64]chan struct{}),
+		log:            args.Log,
+		retryDuration:  time.Duration(args.RetryDurationInSec) * time.Second,
+		safeCloser:     closing.NewSafeChanCloser(),
+		payloadHandler: webSocket.NewNilPayloadHandler(),
+		payloadParser:  args.PayloadConverter,
+		defaultPolicy: deliveryPolicy{
+			withAcknowledge:    args.WithAcknowledge,
+			blockingAckOnError: args.BlockingAckOnError,
+			ackTimeout:         time.Duration(args.AckTimeoutInSec) * time.Second,
+		},
+		topicPolicies:    topicPolicies,
+		payloadVersion:   args.PayloadVersion,
+		mapAck:           make(map[uint64]chan struct{}),
+		writeScheduler:   newWriteScheduler(),
+		sendMetrics:      sendMetrics,
+		pendingDeadlines: newPendingDeadlines(),
+		withHandshake:    args.Handshake.Enabled,
+		localHandshake:   createLocalHandshake(args.Handshake, args.PayloadVersion, args.PayloadConverter.MarshallerType()),
+		handshakeTimeout: getHandshakeTimeout(args.Handshake),
 	}, nil
 }
 
@@ -75,7 +104,7 @@ func checkArgs(args ArgsTransceiver) error {
 	if args.WithAcknowledge && args.AckTimeoutInSec == 0 {
 		return data.ErrZeroValueAckTimeout
 	}
-	return nil
+	return CheckHandshakeConfig(args.Handshake)
 }
 
 // SetPayloadHandler will set the payload handler
@@ -91,8 +120,22 @@ func (wt *wsTransceiver) SetPayloadHandler(handler webSocket.PayloadHandler) err
 	return nil
 }
 
-// Listen will listen for messages from the provided connection
+// Listen will listen for messages from the provided connection. If enabled, the handshake is done first.
 func (wt *wsTransceiver) Listen(connection webSocket.WSConClient) bool {
+	if wt.withHandshake {
+		wt.resetHandshake()
+		err := wt.doHandshake(connection)
+		if err != nil {
+			if !strings.Contains(err.Error(), data.ErrConnectionNotOpen.Error()) {
+				wt.log.Warn("wt.Listen()-> handshake failed", "error", err.Error())
+			}
+			// nothing will read from this connection anymore, so it should not be left open
+			_ = connection.Close()
+
+			return wt.isClosedByPeer()
+		}
+	}
+
 	for {
 		_, message, err := connection.ReadMessage()
 		if err == nil {
@@ -106,15 +149,19 @@ func (wt *wsTransceiver) Listen(connection webSocket.WSConClient) bool {
 			wt.log.Warn("wt.Listen()-> connection problem", "error", err.Error())
 		}
 		if isConnectionClosed {
-			wt.log.Info("received connection close")
+			wt.log.Info("received connection close", "code", closeError.Code, "reason", closeError.Text)
 		}
 
-		select {
-		case <-wt.safeCloser.ChanClose():
-			return false
-		default:
-			return true
-		}
+		return wt.isClosedByPeer()
+	}
+}
+
+func (wt *wsTransceiver) isClosedByPeer() bool {
+	select {
+	case <-wt.safeCloser.ChanClose():
+		return false
+	default:
+		return true
 	}
 }
 
@@ -140,8 +187,14 @@ func (wt *wsTransceiver) verifyPayloadAndSendAckIfNeeded(connection webSocket.WS
 		return
 	}
 
+	negotiatedHandshake := wt.getNegotiatedHandshake()
+	if negotiatedHandshake != nil && !containsPayloadVersion(negotiatedHandshake.PayloadVersions, wsMessage.Version) {
+		wt.log.Warn("wt.verifyPayloadAndSendAckIfNeeded: payload version not negotiated", "version", wsMessage.Version)
+		return
+	}
+
 	err = wt.payloadHandler.ProcessPayload(wsMessage.Payload, wsMessage.Topic, wsMessage.Version)
-	if err != nil && wt.blockingAckOnError {
+	if err != nil && wt.isBlockingAckOnError(wsMessage) {
 		wt.log.Warn("wt.payloadHandler.ProcessPayload: cannot handle payload", "error", err)
 		return
 	}
@@ -149,6 +202,15 @@ func (wt *wsTransceiver) verifyPayloadAndSendAckIfNeeded(connection webSocket.WS
 	wt.sendAckIfNeeded(connection, wsMessage)
 }
 
+// isBlockingAckOnError follows the delivery mode chosen by the sender, so that the two peers can not disagree on it
+func (wt *wsTransceiver) isBlockingAckOnError(wsMessage *data.WsMessage) bool {
+	if wsMessage.DeliveryMode == data.DeliveryModeUnspecified {
+		return wt.getDeliveryPolicy(wsMessage.Topic).blockingAckOnError
+	}
+
+	return wsMessage.DeliveryMode == data.DeliveryModeBlockingAck
+}
+
 func (wt *wsTransceiver) handleAckMessage(counter uint64) {
 	wt.mutMapAck.Lock()
 	defer wt.mutMapAck.Unlock()
@@ -203,32 +265,69 @@ func (wt *wsTransceiver) sendAckIfNeeded(connection webSocket.WSConClient, wsMes
 	}
 }
 
-// Send will prepare and send the provided WsSendArgs
+// Send will prepare and send the provided WsSendArgs, applying the delivery policy of the topic
 func (wt *wsTransceiver) Send(payload []byte, topic string, connection webSocket.WSConClient) error {
-	ch, localCounter := wt.prepareChanAndCounter()
+	if wt.withHandshake && wt.getNegotiatedHandshake() == nil {
+		return data.ErrHandshakeNotDone
+	}
+
+	policy := wt.getDeliveryPolicy(topic)
+	deadline, deadlineKey := wt.stampDeadline(payload, topic, policy)
+	ch, localCounter := wt.prepareChanAndCounter(policy.withAcknowledge)
 	wsMessage := &data.WsMessage{
-		WithAcknowledge: wt.withAcknowledge,
+		WithAcknowledge: policy.withAcknowledge,
 		Counter:         localCounter,
 		Type:            data.PayloadMessage,
 		Payload:         payload,
 		Topic:           topic,
 		Version:         wt.payloadVersion,
+		Deadline:        deadline,
+		Priority:        policy.priority,
+		DeliveryMode:    policy.deliveryMode(),
 	}
 	newPayload, err := wt.payloadParser.ConstructPayload(wsMessage)
 	if err != nil {
+		wt.removeAckChan(localCounter)
 		return err
 	}
 
-	return wt.sendPayload(newPayload, connection, ch)
+	err = wt.sendPayload(newPayload, connection, ch, wsMessage, policy)
+	wt.removeAckChan(localCounter)
+	if err == nil && deadlineKey != "" {
+		wt.pendingDeadlines.remove(deadlineKey)
+	}
+
+	return err
 }
 
-func (wt *wsTransceiver) prepareChanAndCounter() (chan struct{}, uint64) {
+// stampDeadline returns the deadline of the message, reusing the one stamped when the message was first enqueued
+// if the caller resends a message that was not delivered
+func (wt *wsTransceiver) stampDeadline(payload []byte, topic string, policy deliveryPolicy) (int64, string) {
+	if policy.timeToLive == 0 {
+		return 0, ""
+	}
+
+	deadlineKey := createDeadlineKey(topic, payload)
+
+	return wt.pendingDeadlines.stamp(deadlineKey, policy.timeToLive), deadlineKey
+}
+
+func (wt *wsTransceiver) getDeliveryPolicy(topic string) deliveryPolicy {
+	policy, found := wt.topicPolicies[topic]
+	if !found {
+		return wt.defaultPolicy
+	}
+
+	return policy
+}
+
+func (wt *wsTransceiver) prepareChanAndCounter(withAcknowledge bool) (chan struct{}, uint64) {
 	wt.mutMapAck.Lock()
 	wt.counter++
 	localCounter := wt.counter
 
 	ch := make(chan struct{})
-	if wt.withAcknowledge {
+	if withAcknowledge {
 		wt.mapAck[localCounter] = ch
 	}
 	wt.mutMapAck.Unlock()
@@ -236,21 +335,64 @@ func (wt *wsTransceiver) prepareChanAndCounter() (chan struct{}, uint64) {
 	return ch, localCounter
 }
 
-func (wt *wsTransceiver) sendPayload(payload []byte, connection webSocket.WSConClient, ch chan struct{}) error {
-	errSend := connection.WriteMessage(websocket.BinaryMessage, payload)
-	if errSend != nil {
-		return errSend
+func (wt *wsTransceiver) removeAckChan(counter uint64) {
+	wt.mutMapAck.Lock()
+	delete(wt.mapAck, counter)
+	wt.mutMapAck.Unlock()
+}
+
+func (wt *wsTransceiver) sendPayload(
+	payload []byte,
+	connection webSocket.WSConClient,
+	ch chan struct{},
+	wsMessage *data.WsMessage,
+	policy deliveryPolicy,
+) error {
+	for attempt := 0; ; attempt++ {
+		written, errSend := wt.writeIfNotExpired(payload, connection, wsMessage.Deadline, wsMessage.Priority)
+		if errSend != nil {
+			return errSend
+		}
+		if !written {
+			wt.log.Debug("wt.sendPayload(): message expired, dropping it", "topic", wsMessage.Topic, "counter", wsMessage.Counter)
+			return nil
+		}
+		if attempt == 0 && wsMessage.Priority > 0 {
+			wt.sendMetrics.AddHighPrioritySent()
+		}
+
+		if !policy.withAcknowledge {
+			return nil
+		}
+
+		err := wt.waitForAck(ch, policy.ackTimeout)
+		if err != data.ErrAckTimeout || attempt >= policy.maxRetries {
+			return err
+		}
+
+		wt.log.Debug("wt.sendPayload(): ack not received, resending message", "topic", wsMessage.Topic, "counter", wsMessage.Counter, "attempt", attempt+1)
 	}
+}
 
-	if !wt.withAcknowledge {
-		return nil
+func (wt *wsTransceiver) writeIfNotExpired(payload []byte, connection webSocket.WSConClient, deadline int64, priority uint32) (bool, error) {
+	wt.writeScheduler.acquire(priority)
+	defer wt.writeScheduler.release()
+
+	if isExpired(deadline) {
+		wt.sendMetrics.AddExpiredDropped()
+		return false, nil
+	}
+
+	err := connection.WriteMessage(websocket.BinaryMessage, payload)
+	if err != nil {
+		return false, err
 	}
 
-	return wt.waitForAck(ch)
+	return true, nil
 }
 
-func (wt *wsTransceiver) waitForAck(ch chan struct{}) error {
-	timer := time.NewTimer(wt.ackTimeout)
+func (wt *wsTransceiver) waitForAck(ch chan struct{}, ackTimeout time.Duration) error {
+	timer := time.NewTimer(ackTimeout)
 	defer timer.Stop()
 
 	select {
@@ -11,6 +11,7 @@ import (
 	webSocket "github.com/TerraDharitri/drt-go-chain-communication/websocket"
 	"github.com/TerraDharitri/drt-go-chain-communication/websocket/data"
 	"github.com/TerraDharitri/drt-go-chain-core/core"
+	"github.com/TerraDharitri/drt-go-chain-core/core/check"
 	"github.com/TerraDharitri/drt-go-chain-core/data/outport"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/require"
@@ -25,6 +26,7 @@ func createArgs() ArgsTransceiver {
 		RetryDurationInSec: 1,
 		AckTimeoutInSec:    2,
 		WithAcknowledge:    false,
+		SendMetrics:        webSocket.NewSendMetrics(),
 	}
 }
 
@@ -54,6 +56,14 @@ func TestNewReceiver(t *testing.T) {
 		require.Equal(t, data.ErrNilPayloadConverter, err)
 	})
 
+	t.Run("nil send metrics, should use a new instance", func(t *testing.T) {
+		args := createArgs()
+		args.SendMetrics = nil
+		ws, err := NewTransceiver(args)
+		require.Nil(t, err)
+		require.False(t, check.IfNil(ws.sendMetrics))
+	})
+
 	t.Run("zero retry duration in seconds, should return error", func(t *testing.T) {
 		args := createArgs()
 		args.RetryDurationInSec = 0
@@ -249,7 +259,7 @@ func TestWsTransceiverWaitForAck(t *testing.T) {
 	wg := &sync.WaitGroup{}
 	wg.Add(1)
 	go func() {
-		err := webSocketTransceiver.waitForAck(ch)
+		err := webSocketTransceiver.waitForAck(ch, time.Duration(args.AckTimeoutInSec)*time.Second)
 		require.Equal(t, data.ErrExpectedAckWasNotReceivedOnClose, err)
 		wg.Done()
 	}()
@@ -303,3 +313,288 @@ func TestWsTransceiver_ListenReturnsTrue(t *testing.T) {
 	closed := webSocketTransceiver.Listen(conn)
 	require.True(t, closed)
 }
+
+func TestNewTransceiver_InvalidTopicPolicies(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty topic, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.TopicPolicies = []data.TopicPolicy{{Delivery: data.DeliveryAtMostOnce}}
+		ws, err := NewTransceiver(args)
+		require.Nil(t, ws)
+		require.True(t, errors.Is(err, data.ErrEmptyTopic))
+	})
+
+	t.Run("invalid delivery, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.TopicPolicies = []data.TopicPolicy{{Topic: outport.TopicSaveBlock, Delivery: "exactly-once"}}
+		ws, err := NewTransceiver(args)
+		require.Nil(t, ws)
+		require.True(t, errors.Is(err, data.ErrInvalidDeliveryPolicy))
+	})
+
+	t.Run("acknowledged delivery with zero timeout, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.TopicPolicies = []data.TopicPolicy{{Topic: outport.TopicSaveBlock, Delivery: data.DeliveryBlockingAck}}
+		ws, err := NewTransceiver(args)
+		require.Nil(t, ws)
+		require.True(t, errors.Is(err, data.ErrZeroValueAckTimeout))
+	})
+
+	t.Run("negative max retries, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.TopicPolicies = []data.TopicPolicy{{Topic: outport.TopicSaveBlock, Delivery: data.DeliveryAtMostOnce, MaxRetries: -1}}
+		ws, err := NewTransceiver(args)
+		require.Nil(t, ws)
+		require.True(t, errors.Is(err, data.ErrNegativeMaxRetries))
+	})
+
+	t.Run("duplicated topic, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.TopicPolicies = []data.TopicPolicy{
+			{Topic: outport.TopicSaveBlock, Delivery: data.DeliveryAtMostOnce},
+			{Topic: outport.TopicSaveBlock, Delivery: data.DeliveryAtMostOnce},
+		}
+		ws, err := NewTransceiver(args)
+		require.Nil(t, ws)
+		require.True(t, errors.Is(err, data.ErrDuplicatedTopicPolicy))
+	})
+}
+
+func TestWsTransceiver_SendWithTopicPolicies(t *testing.T) {
+	t.Parallel()
+
+	t.Run("at most once topic should not wait for ack", func(t *testing.T) {
+		args := createArgs()
+		args.WithAcknowledge = true
+		args.TopicPolicies = []data.TopicPolicy{{Topic: outport.TopicSaveBlock, Delivery: data.DeliveryAtMostOnce}}
+		webSocketTransceiver, _ := NewTransceiver(args)
+		defer func() {
+			_ = webSocketTransceiver.Close()
+		}()
+
+		var sentMessage *data.WsMessage
+		conn := &testscommon.WebsocketConnectionStub{
+			WriteMessageCalled: func(messageType int, payload []byte) error {
+				sentMessage, _ = args.PayloadConverter.ExtractWsMessage(payload)
+				return nil
+			},
+		}
+
+		err := webSocketTransceiver.Send([]byte("message"), outport.TopicSaveBlock, conn)
+		require.Nil(t, err)
+		require.False(t, sentMessage.WithAcknowledge)
+		require.Equal(t, uint32(data.DeliveryModeAtMostOnce), sentMessage.DeliveryMode)
+	})
+
+	t.Run("at least once topic should resend until the retry budget is exhausted", func(t *testing.T) {
+		args := createArgs()
+		args.TopicPolicies = []data.TopicPolicy{
+			{
+				Topic:                   outport.TopicSaveBlock,
+				Delivery:                data.DeliveryAtLeastOnce,
+				AcknowledgeTimeoutInSec: 1,
+				MaxRetries:              1,
+			},
+		}
+		webSocketTransceiver, _ := NewTransceiver(args)
+		defer func() {
+			_ = webSocketTransceiver.Close()
+		}()
+
+		numWrites := uint32(0)
+		conn := &testscommon.WebsocketConnectionStub{
+			WriteMessageCalled: func(messageType int, payload []byte) error {
+				atomic.AddUint32(&numWrites, 1)
+				return nil
+			},
+		}
+
+		err := webSocketTransceiver.Send([]byte("message"), outport.TopicSaveBlock, conn)
+		require.Equal(t, data.ErrAckTimeout, err)
+		require.Equal(t, uint32(2), atomic.LoadUint32(&numWrites))
+		require.Empty(t, webSocketTransceiver.mapAck)
+	})
+}
+
+func TestWsTransceiver_ReceiveWithTopicPolicies(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	args.TopicPolicies = []data.TopicPolicy{
+		{Topic: outport.TopicSaveBlock, Delivery: data.DeliveryBlockingAck, AcknowledgeTimeoutInSec: 1},
+		{Topic: outport.TopicSaveAccounts, Delivery: data.DeliveryAtLeastOnce, AcknowledgeTimeoutInSec: 1},
+	}
+	webSocketTransceiver, _ := NewTransceiver(args)
+	_ = webSocketTransceiver.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(_ []byte, _ string, _ uint32) error {
+			return errors.New("processing error")
+		},
+	})
+
+	numAcks := 0
+	conn := &testscommon.WebsocketConnectionStub{
+		WriteMessageCalled: func(messageType int, payload []byte) error {
+			numAcks++
+			return nil
+		},
+	}
+
+	for _, topic := range []string{outport.TopicSaveBlock, outport.TopicSaveAccounts} {
+		payload, _ := args.PayloadConverter.ConstructPayload(&data.WsMessage{
+			WithAcknowledge: true,
+			Counter:         1,
+			Type:            data.PayloadMessage,
+			Topic:           topic,
+		})
+		webSocketTransceiver.verifyPayloadAndSendAckIfNeeded(conn, payload)
+	}
+
+	require.Equal(t, 1, numAcks)
+	_ = webSocketTransceiver.Close()
+}
+
+func TestWsTransceiver_ReceiveShouldApplyTheDeliveryModeOfTheSender(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	args.TopicPolicies = []data.TopicPolicy{
+		{Topic: outport.TopicSaveBlock, Delivery: data.DeliveryBlockingAck, AcknowledgeTimeoutInSec: 1},
+	}
+	webSocketTransceiver, _ := NewTransceiver(args)
+	_ = webSocketTransceiver.SetPayloadHandler(&testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(_ []byte, _ string, _ uint32) error {
+			return errors.New("processing error")
+		},
+	})
+
+	ackedCounters := make([]uint64, 0)
+	conn := &testscommon.WebsocketConnectionStub{
+		WriteMessageCalled: func(messageType int, payload []byte) error {
+			ackMessage, _ := args.PayloadConverter.ExtractWsMessage(payload)
+			ackedCounters = append(ackedCounters, ackMessage.Counter)
+			return nil
+		},
+	}
+
+	messages := []*data.WsMessage{
+		{Counter: 1, Topic: outport.TopicSaveBlock, DeliveryMode: data.DeliveryModeAtLeastOnce},
+		{Counter: 2, Topic: outport.TopicSaveAccounts, DeliveryMode: data.DeliveryModeBlockingAck},
+		{Counter: 3, Topic: outport.TopicSaveBlock},
+		{Counter: 4, Topic: outport.TopicSaveAccounts},
+	}
+	for _, message := range messages {
+		message.WithAcknowledge = true
+		message.Type = data.PayloadMessage
+		payload, _ := args.PayloadConverter.ConstructPayload(message)
+		webSocketTransceiver.verifyPayloadAndSendAckIfNeeded(conn, payload)
+	}
+
+	require.Equal(t, []uint64{1, 4}, ackedCounters)
+	_ = webSocketTransceiver.Close()
+}
+
+func TestWsTransceiver_SendExpiredAndHighPriorityMessages(t *testing.T) {
+	t.Parallel()
+
+	t.Run("expired message should be dropped and counted", func(t *testing.T) {
+		args := createArgs()
+		args.TopicPolicies = []data.TopicPolicy{
+			{
+				Topic:                   outport.TopicSaveBlock,
+				Delivery:                data.DeliveryAtLeastOnce,
+				AcknowledgeTimeoutInSec: 2,
+				MaxRetries:              3,
+				TimeToLiveInSec:         1,
+			},
+		}
+		webSocketTransceiver, _ := NewTransceiver(args)
+		defer func() {
+			_ = webSocketTransceiver.Close()
+		}()
+
+		numWrites := uint32(0)
+		var sentMessage *data.WsMessage
+		conn := &testscommon.WebsocketConnectionStub{
+			WriteMessageCalled: func(messageType int, payload []byte) error {
+				atomic.AddUint32(&numWrites, 1)
+				sentMessage, _ = args.PayloadConverter.ExtractWsMessage(payload)
+				return nil
+			},
+		}
+
+		err := webSocketTransceiver.Send([]byte("message"), outport.TopicSaveBlock, conn)
+		require.Nil(t, err)
+		require.Equal(t, uint32(1), atomic.LoadUint32(&numWrites))
+		require.NotZero(t, sentMessage.Deadline)
+		require.Equal(t, uint64(1), args.SendMetrics.GetSendMetrics().NumExpiredDropped)
+		require.Empty(t, webSocketTransceiver.mapAck)
+	})
+
+	t.Run("resent message should keep the deadline stamped when it was first enqueued", func(t *testing.T) {
+		args := createArgs()
+		args.TopicPolicies = []data.TopicPolicy{
+			{
+				Topic:           outport.TopicSaveBlock,
+				Delivery:        data.DeliveryAtMostOnce,
+				TimeToLiveInSec: 1,
+			},
+		}
+		webSocketTransceiver, _ := NewTransceiver(args)
+		defer func() {
+			_ = webSocketTransceiver.Close()
+		}()
+
+		numWrites := uint32(0)
+		conn := &testscommon.WebsocketConnectionStub{
+			WriteMessageCalled: func(messageType int, payload []byte) error {
+				atomic.AddUint32(&numWrites, 1)
+				return data.ErrConnectionNotOpen
+			},
+		}
+
+		err := webSocketTransceiver.Send([]byte("message"), outport.TopicSaveBlock, conn)
+		require.Equal(t, data.ErrConnectionNotOpen, err)
+		require.Len(t, webSocketTransceiver.pendingDeadlines.deadlines, 1)
+
+		time.Sleep(time.Millisecond * 1100)
+
+		err = webSocketTransceiver.Send([]byte("message"), outport.TopicSaveBlock, conn)
+		require.Nil(t, err)
+		require.Equal(t, uint32(1), atomic.LoadUint32(&numWrites))
+		require.Equal(t, uint64(1), args.SendMetrics.GetSendMetrics().NumExpiredDropped)
+		require.Empty(t, webSocketTransceiver.pendingDeadlines.deadlines)
+	})
+
+	t.Run("high priority message should be counted", func(t *testing.T) {
+		args := createArgs()
+		args.TopicPolicies = []data.TopicPolicy{
+			{
+				Topic:    outport.TopicSaveBlock,
+				Delivery: data.DeliveryAtMostOnce,
+				Priority: 1,
+			},
+		}
+		webSocketTransceiver, _ := NewTransceiver(args)
+		defer func() {
+			_ = webSocketTransceiver.Close()
+		}()
+
+		var sentMessage *data.WsMessage
+		conn := &testscommon.WebsocketConnectionStub{
+			WriteMessageCalled: func(messageType int, payload []byte) error {
+				sentMessage, _ = args.PayloadConverter.ExtractWsMessage(payload)
+				return nil
+			},
+		}
+
+		err := webSocketTransceiver.Send([]byte("message"), outport.TopicSaveBlock, conn)
+		require.Nil(t, err)
+		require.Equal(t, uint32(1), sentMessage.Priority)
+		require.Zero(t, sentMessage.Deadline)
+
+		err = webSocketTransceiver.Send([]byte("message"), outport.TopicSaveAccounts, conn)
+		require.Nil(t, err)
+		require.Equal(t, uint64(1), args.SendMetrics.GetSendMetrics().NumHighPrioritySent)
+	})
+}
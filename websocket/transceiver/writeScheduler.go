@@ -0,0 +1,97 @@
+package transceiver
+
+import (
+	"container/heap"
+	"sync"
+)
+
+type pendingWrite struct {
+	priority uint32
+	index    uint64
+	ready    chan struct{}
+}
+
+// pendingWrites is a max-heap on priority, FIFO for equal priorities
+type pendingWrites []*pendingWrite
+
+// Len returns the number of pending writes
+func (pw pendingWrites) Len() int {
+	return len(pw)
+}
+
+// Less returns true if the write on position i should be done before the one on position j
+func (pw pendingWrites) Less(i, j int) bool {
+	if pw[i].priority != pw[j].priority {
+		return pw[i].priority > pw[j].priority
+	}
+
+	return pw[i].index < pw[j].index
+}
+
+// Swap swaps the writes from the provided positions
+func (pw pendingWrites) Swap(i, j int) {
+	pw[i], pw[j] = pw[j], pw[i]
+}
+
+// Push adds a new pending write
+func (pw *pendingWrites) Push(x interface{}) {
+	*pw = append(*pw, x.(*pendingWrite))
+}
+
+// Pop removes and returns the last pending write
+func (pw *pendingWrites) Pop() interface{} {
+	old := *pw
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*pw = old[:n-1]
+
+	return item
+}
+
+// writeScheduler grants the right to write on the connection one caller at a time, the callers with higher priority first
+type writeScheduler struct {
+	mut     sync.Mutex
+	busy    bool
+	index   uint64
+	pending pendingWrites
+}
+
+func newWriteScheduler() *writeScheduler {
+	return &writeScheduler{
+		pending: make(pendingWrites, 0),
+	}
+}
+
+func (ws *writeScheduler) acquire(priority uint32) {
+	ws.mut.Lock()
+	if !ws.busy {
+		ws.busy = true
+		ws.mut.Unlock()
+		return
+	}
+
+	ws.index++
+	write := &pendingWrite{
+		priority: priority,
+		index:    ws.index,
+		ready:    make(chan struct{}),
+	}
+	heap.Push(&ws.pending, write)
+	ws.mut.Unlock()
+
+	<-write.ready
+}
+
+func (ws *writeScheduler) release() {
+	ws.mut.Lock()
+	defer ws.mut.Unlock()
+
+	if ws.pending.Len() == 0 {
+		ws.busy = false
+		return
+	}
+
+	next := heap.Pop(&ws.pending).(*pendingWrite)
+	close(next.ready)
+}
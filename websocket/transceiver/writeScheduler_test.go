@@ -0,0 +1,40 @@
+package transceiver
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteScheduler_HigherPriorityFirst(t *testing.T) {
+	t.Parallel()
+
+	scheduler := newWriteScheduler()
+	scheduler.acquire(0)
+
+	mut := sync.Mutex{}
+	order := make([]uint32, 0)
+	wg := sync.WaitGroup{}
+	for _, priority := range []uint32{1, 3, 2, 3} {
+		wg.Add(1)
+		go func(p uint32) {
+			defer wg.Done()
+
+			scheduler.acquire(p)
+			mut.Lock()
+			order = append(order, p)
+			mut.Unlock()
+			scheduler.release()
+		}(priority)
+		// make sure the goroutines are queued in order
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	scheduler.release()
+	wg.Wait()
+
+	require.Equal(t, []uint32{3, 3, 2, 1}, order)
+	require.False(t, scheduler.busy)
+}